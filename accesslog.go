@@ -0,0 +1,235 @@
+package logger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// AccessLogFormat selects the line format of the access log.
+type AccessLogFormat int
+
+// Supported access log formats.
+const (
+	// AccessLogCommon is the NCSA Common Log Format:
+	//	host ident user [time] "method uri proto" status bytes
+	AccessLogCommon AccessLogFormat = iota
+	// AccessLogCombined is the Common Log Format followed by the quoted
+	// Referer and User-Agent headers, as written by Apache and nginx.
+	AccessLogCombined
+	// AccessLogJSON writes one AccessLogEntry JSON object per line.
+	AccessLogJSON
+)
+
+const (
+	accessLogTimeFormat  = "02/Jan/2006:15:04:05 -0700"
+	accessLogCommonFmt   = "%s - %s [%s] \"%s %s %s\" %d %s"
+	accessLogCombinedFmt = " %q %q"
+	accessLogEmpty       = "-"
+	accessLogLineEnd     = "\n"
+	headerReferer        = "Referer"
+	headerUserAgent      = "User-Agent"
+	errAccessLogMsg      = "access log needs a Filename or a Writer"
+	errFmtAccessLog      = "access log: %w"
+	errFmtWriteAccessLog = "write access log: %w"
+)
+
+// ErrAccessLogTarget is returned when an access log has no destination.
+var ErrAccessLogTarget = errors.New(errAccessLogMsg)
+
+// AccessLog configures the access log written by Middleware. Filename names a
+// file in the logger's directory, validated like the main log filename; once
+// it would grow beyond MaxSize bytes it is renamed to Filename.1, older files
+// shift to .2 and so on, and at most MaxBackups of them are kept. A MaxSize of
+// zero disables rotation. Writer is used instead of a file when Filename is
+// empty and is never closed.
+type AccessLog struct {
+	Writer     io.Writer
+	Filename   string
+	MaxSize    int64
+	MaxBackups int
+	Format     AccessLogFormat
+}
+
+// AccessLogEntry is the schema of AccessLogJSON lines.
+type AccessLogEntry struct {
+	Time       time.Time `json:"time"`
+	RemoteAddr string    `json:"remote_addr"`
+	User       string    `json:"user,omitempty"`
+	Method     string    `json:"method"`
+	URI        string    `json:"uri"`
+	Proto      string    `json:"proto"`
+	Referer    string    `json:"referer,omitempty"`
+	UserAgent  string    `json:"user_agent,omitempty"`
+	RequestID  string    `json:"request_id"`
+	Status     int       `json:"status"`
+	Bytes      int       `json:"bytes"`
+	DurationMS float64   `json:"duration_ms"`
+}
+
+// accessLogger writes access lines; it has its own lock so that requests do
+// not contend with regular log entries.
+type accessLogger struct {
+	writer io.Writer
+	file   *rotatingFile
+	mu     sync.Mutex
+	format AccessLogFormat
+}
+
+// WithAccessLog makes Middleware write one line per request to a dedicated
+// access log instead of logging a completion entry, so that the logger can
+// replace a reverse proxy's access logging.
+func WithAccessLog(accessLog AccessLog) Option {
+	return func(config *options) {
+		config.accessLog = &accessLog
+	}
+}
+
+// openAccessLogger opens the access log under logDir. An empty logDir allows
+// only writer access logs.
+func openAccessLogger(logDir string, accessLog *AccessLog) (*accessLogger, error) {
+	if accessLog == nil {
+		return nil, nil
+	}
+
+	target := &accessLogger{writer: accessLog.Writer, file: nil, mu: sync.Mutex{}, format: accessLog.Format}
+	if accessLog.Filename == "" || logDir == "" {
+		if accessLog.Writer == nil {
+			return nil, fmt.Errorf(errFmtAccessLog, ErrAccessLogTarget)
+		}
+
+		return target, nil
+	}
+
+	err := ValidateFilename(accessLog.Filename)
+	if err != nil {
+		return nil, fmt.Errorf(errFmtAccessLog, err)
+	}
+
+	path, err := setupAndValidatePath(logDir, accessLog.Filename)
+	if err != nil {
+		return nil, fmt.Errorf(errFmtAccessLog, err)
+	}
+
+	target.file, err = openRotatingFile(path, accessLog.MaxSize, accessLog.MaxBackups)
+	if err != nil {
+		return nil, fmt.Errorf(errFmtAccessLog, err)
+	}
+
+	target.writer = target.file
+
+	return target, nil
+}
+
+func (access *accessLogger) write(accessEntry *AccessLogEntry) error {
+	line := access.formatLine(accessEntry) + accessLogLineEnd
+
+	access.mu.Lock()
+	defer access.mu.Unlock()
+
+	_, err := io.WriteString(access.writer, line)
+	if err != nil {
+		return fmt.Errorf(errFmtWriteAccessLog, err)
+	}
+
+	return nil
+}
+
+// writeAccessLog writes accessEntry, recording a failure for Err.
+func (c *loggerCore) writeAccessLog(accessEntry *AccessLogEntry) {
+	err := c.access.write(accessEntry)
+	if err != nil {
+		c.mu.Lock()
+		c.lastErr = err
+		c.mu.Unlock()
+	}
+}
+
+func (access *accessLogger) close() error {
+	if access == nil || access.file == nil {
+		return nil
+	}
+
+	access.mu.Lock()
+	defer access.mu.Unlock()
+
+	return access.file.Close()
+}
+
+func (access *accessLogger) formatLine(accessEntry *AccessLogEntry) string {
+	if access.format == AccessLogJSON {
+		data, err := json.Marshal(accessEntry)
+		if err != nil {
+			return ""
+		}
+
+		return string(data)
+	}
+
+	line := fmt.Sprintf(accessLogCommonFmt,
+		orDash(accessEntry.RemoteAddr),
+		orDash(accessEntry.User),
+		accessEntry.Time.Format(accessLogTimeFormat),
+		accessEntry.Method,
+		accessEntry.URI,
+		accessEntry.Proto,
+		accessEntry.Status,
+		orDash(bytesText(accessEntry.Bytes)),
+	)
+
+	if access.format == AccessLogCombined {
+		line += fmt.Sprintf(accessLogCombinedFmt, orDash(accessEntry.Referer), orDash(accessEntry.UserAgent))
+	}
+
+	return line
+}
+
+// newAccessLogEntry describes a completed request.
+func newAccessLogEntry(r *http.Request, recorder *responseRecorder, start time.Time, id string) *AccessLogEntry {
+	user, _, _ := r.BasicAuth()
+	if r.URL.User != nil {
+		user = r.URL.User.Username()
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	return &AccessLogEntry{
+		Time:       start,
+		RemoteAddr: host,
+		User:       user,
+		Method:     r.Method,
+		URI:        r.RequestURI,
+		Proto:      r.Proto,
+		Referer:    r.Header.Get(headerReferer),
+		UserAgent:  r.Header.Get(headerUserAgent),
+		RequestID:  id,
+		Status:     recorder.status,
+		Bytes:      recorder.bytes,
+		DurationMS: float64(time.Since(start)) / float64(time.Millisecond),
+	}
+}
+
+func bytesText(n int) string {
+	if n == 0 {
+		return ""
+	}
+
+	return strconv.Itoa(n)
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return accessLogEmpty
+	}
+
+	return value
+}
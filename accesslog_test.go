@@ -0,0 +1,196 @@
+package logger_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	accessLogFile     = "access.log"
+	accessMainLog     = "app.log"
+	accessPath        = "/books/42?format=pdf"
+	accessReferer     = "https://example.com/"
+	accessUserAgent   = "curl/8.0"
+	accessUser        = "alice"
+	accessBody        = "hello"
+	accessMaxSize     = 200
+	accessBackups     = 2
+	accessRequests    = 10
+	accessBackupFmt   = "expected backup %s: %v"
+	accessNoBackupFmt = "expected at most %d backups, found %s"
+	accessCommonRegex = `^192\.0\.2\.1 - alice \[\d{2}/\w{3}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4}\] ` +
+		`"GET /books/42\?format=pdf HTTP/1\.1" 200 5$`
+	accessCombinedTail = ` 200 5 "https://example.com/" "curl/8.0"`
+	accessCompletedMsg = "request completed"
+	accessUnexpected   = "expected no completion entry with an access log, got %q"
+	accessBlockedFile  = "blocker"
+	accessLinesFmt     = "access logs hold %d requests; want %d"
+	accessSetupFmt     = "setup: %v"
+)
+
+func serveAccessRequest(t *testing.T, loggerInstance *logger.Logger) {
+	t.Helper()
+
+	handler := loggerInstance.Middleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(accessBody))
+	}))
+
+	request := httptest.NewRequest(http.MethodGet, accessPath, nil)
+	request.SetBasicAuth(accessUser, accessUser)
+	request.Header.Set("Referer", accessReferer)
+	request.Header.Set("User-Agent", accessUserAgent)
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+}
+
+func newAccessLogger(format logger.AccessLogFormat) (*logger.Logger, *bytes.Buffer, *bytes.Buffer) {
+	var stdout, access bytes.Buffer
+
+	loggerInstance := logger.NewStreamLogger(&stdout,
+		logger.WithAccessLog(logger.AccessLog{Writer: &access, Format: format}))
+
+	return loggerInstance, &stdout, &access
+}
+
+func TestAccessLog_CommonFormat(t *testing.T) {
+	t.Parallel()
+
+	loggerInstance, stdout, access := newAccessLogger(logger.AccessLogCommon)
+	serveAccessRequest(t, loggerInstance)
+
+	line := strings.TrimSpace(access.String())
+	if !regexp.MustCompile(accessCommonRegex).MatchString(line) {
+		t.Errorf(layoutOutputErrFmt, accessCommonRegex, line)
+	}
+
+	if strings.Contains(stdout.String(), accessCompletedMsg) {
+		t.Errorf(accessUnexpected, stdout.String())
+	}
+}
+
+func TestAccessLog_CombinedFormat(t *testing.T) {
+	t.Parallel()
+
+	loggerInstance, _, access := newAccessLogger(logger.AccessLogCombined)
+	serveAccessRequest(t, loggerInstance)
+
+	line := strings.TrimSpace(access.String())
+	if !strings.HasSuffix(line, accessCombinedTail) {
+		t.Errorf(layoutOutputErrFmt, accessCombinedTail, line)
+	}
+}
+
+func TestAccessLog_JSONFormat(t *testing.T) {
+	t.Parallel()
+
+	loggerInstance, _, access := newAccessLogger(logger.AccessLogJSON)
+	serveAccessRequest(t, loggerInstance)
+
+	var accessEntry logger.AccessLogEntry
+
+	err := json.Unmarshal(access.Bytes(), &accessEntry)
+	if err != nil {
+		t.Fatalf(decodeJSONErrFmt, access.String(), err)
+	}
+
+	if accessEntry.User != accessUser || accessEntry.Bytes != len(accessBody) || accessEntry.RequestID == "" {
+		t.Errorf(layoutOutputErrFmt, accessUser, access.String())
+	}
+}
+
+func TestAccessLog_RotationFailureKeepsLogging(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	// A non-empty directory in place of the backup makes the rename fail.
+	blocker := filepath.Join(dir, accessLogFile+".1")
+
+	err := os.MkdirAll(blocker, 0o700)
+	if err == nil {
+		err = os.WriteFile(filepath.Join(blocker, accessBlockedFile), nil, 0o600)
+	}
+
+	if err != nil {
+		t.Fatalf(accessSetupFmt, err)
+	}
+
+	loggerInstance, err := logger.New(dir, accessMainLog, logger.WithoutStdout(), logger.WithAccessLog(logger.AccessLog{
+		Filename:   accessLogFile,
+		MaxSize:    accessMaxSize,
+		MaxBackups: 1,
+		Format:     logger.AccessLogCombined,
+	}))
+	if err != nil {
+		t.Fatalf(newLoggerError, err)
+	}
+
+	for range accessRequests {
+		serveAccessRequest(t, loggerInstance)
+	}
+
+	err = os.RemoveAll(blocker)
+	if err != nil {
+		t.Fatalf(accessSetupFmt, err)
+	}
+
+	serveAccessRequest(t, loggerInstance)
+	closeTestLogger(t, loggerInstance)
+
+	requests := 0
+
+	for _, name := range []string{accessLogFile, accessLogFile + ".1"} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf(accessBackupFmt, name, err)
+		}
+
+		requests += strings.Count(string(data), "\n")
+	}
+
+	if requests != accessRequests+1 {
+		t.Errorf(accessLinesFmt, requests, accessRequests+1)
+	}
+}
+
+func TestAccessLog_RotatesFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	loggerInstance, err := logger.New(dir, accessMainLog, logger.WithAccessLog(logger.AccessLog{
+		Filename:   accessLogFile,
+		MaxSize:    accessMaxSize,
+		MaxBackups: accessBackups,
+		Format:     logger.AccessLogCombined,
+	}))
+	if err != nil {
+		t.Fatalf(newLoggerError, err)
+	}
+
+	for range accessRequests {
+		serveAccessRequest(t, loggerInstance)
+	}
+
+	closeTestLogger(t, loggerInstance)
+
+	for _, name := range []string{accessLogFile, accessLogFile + ".1", accessLogFile + ".2"} {
+		info, err := os.Stat(filepath.Join(dir, name))
+		if err != nil || info.Size() > accessMaxSize {
+			t.Errorf(accessBackupFmt, name, err)
+		}
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(dir, accessLogFile+".3"))
+	if len(matches) != 0 {
+		t.Errorf(accessNoBackupFmt, accessBackups, matches[0])
+	}
+}
@@ -0,0 +1,283 @@
+package logger
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultAggregationWindow is the rollup window of WithAggregation.
+	DefaultAggregationWindow = time.Minute
+
+	// maxRollupGroups bounds the groups counted per window; entries of
+	// further groups are counted under the template rollupOther.
+	maxRollupGroups = 4096
+	rollupOther     = "other"
+
+	errFmtOpenAggregation  = "open aggregation file %q: %w"
+	errFmtWriteAggregation = "write aggregation rollup: %w"
+	errFmtCloseAggregation = "close aggregation sink: %w"
+	aggregationErrorFormat = "aggregation: %v"
+)
+
+// Aggregation configures WithAggregation. Rollups are written to Sink, or to
+// Filename in the log directory. Entries at or below MaxLevel are counted per
+// Window, by level, message fingerprint and the value of TagField.
+type Aggregation struct {
+	Sink     Sink
+	Filename string
+	TagField string
+	Window   time.Duration
+	MaxLevel Level
+}
+
+// Rollup is a line written by WithAggregation: the number of entries of one
+// level, message template and tag logged in the window starting at Start.
+type Rollup struct {
+	Start       time.Time `json:"start"`
+	Level       string    `json:"level"`
+	Fingerprint string    `json:"fingerprint"`
+	Template    string    `json:"template"`
+	Tag         string    `json:"tag,omitempty"`
+	Seconds     float64   `json:"window_seconds"`
+	Count       uint64    `json:"count"`
+}
+
+// rollupKey groups the entries of a window.
+type rollupKey struct {
+	start    time.Time
+	level    string
+	template string
+	tag      string
+}
+
+// aggregator counts the entries of WithAggregation and writes their rollups.
+type aggregator struct {
+	sink   Sink
+	counts map[rollupKey]uint64
+	done   chan struct{}
+	config Aggregation
+	mu     sync.Mutex // guards counts
+	write  sync.Mutex // orders the writes of the rollup writer and Close
+	stop   sync.Once
+}
+
+// WithAggregation replaces the entries at or below aggregation.MaxLevel, such
+// as a very high-volume INFO stream whose raw lines need not be kept, with
+// rollups: at the end of every Window (DefaultAggregationWindow when zero),
+// one JSON Rollup line per level, message fingerprint and tag counts the
+// entries logged in it. The tag is the value of TagField, ProducerField when
+// empty. Rollups are written to aggregation.Sink, for example a remote sink of
+// OpenSink, or to aggregation.Filename in the log directory; the logger closes
+// either. Mandatory entries are always written raw. Entries of a window
+// already written are counted in a further rollup of that window, and Close
+// writes the rollups of the current window. Without a Sink, loggers that have
+// no log directory write every entry raw.
+func WithAggregation(aggregation Aggregation) Option {
+	return func(config *options) {
+		aggregation.Window = cmp.Or(aggregation.Window, DefaultAggregationWindow)
+		aggregation.TagField = cmp.Or(aggregation.TagField, ProducerField)
+		config.aggregation = &aggregation
+	}
+}
+
+// openAggregation opens the destination of the rollups of config under
+// logDir, or only its Sink without a log directory. It returns nil when
+// there is none.
+func openAggregation(logDir string, config *Aggregation) (*aggregator, error) {
+	if config == nil {
+		return nil, nil
+	}
+
+	sink := config.Sink
+
+	if sink == nil && config.Filename != "" && logDir != "" {
+		err := ValidateFilename(config.Filename)
+		if err != nil {
+			return nil, fmt.Errorf(errFmtOpenAggregation, config.Filename, err)
+		}
+
+		aggregationPath, err := setupAndValidatePath(logDir, config.Filename)
+		if err != nil {
+			return nil, fmt.Errorf(errFmtOpenAggregation, config.Filename, err)
+		}
+
+		file, err := openLogFile(aggregationPath)
+		if err != nil {
+			return nil, fmt.Errorf(errFmtOpenAggregation, config.Filename, err)
+		}
+
+		sink = file
+	}
+
+	if sink == nil {
+		return nil, nil
+	}
+
+	return &aggregator{
+		sink:   sink,
+		counts: make(map[rollupKey]uint64),
+		done:   make(chan struct{}),
+		config: *config,
+		mu:     sync.Mutex{},
+		write:  sync.Mutex{},
+		stop:   sync.Once{},
+	}, nil
+}
+
+// startAggregation writes the rollups of every finished window in the
+// background until the logger is closed.
+func (c *loggerCore) startAggregation() {
+	if c.aggregator == nil {
+		return
+	}
+
+	ticker := time.NewTicker(c.aggregator.config.Window)
+
+	c.goTask(ProfileTaskAggregation, func() {
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				err := c.aggregator.writeRollups(c.clock(), false)
+				if err != nil {
+					c.writeEntryf(LevelError, nil, "", targetAll, aggregationErrorFormat, err)
+				}
+			case <-c.aggregator.done:
+				return
+			}
+		}
+	})
+}
+
+// aggregate counts logEntry and reports whether it replaces the raw entry.
+func (c *loggerCore) aggregate(logEntry *Entry) bool {
+	agg := c.aggregator
+	if agg == nil || logEntry.Mandatory || logEntry.Level > agg.config.MaxLevel {
+		return false
+	}
+
+	key := rollupKey{
+		start:    logEntry.Time.Truncate(agg.config.Window),
+		level:    logEntry.Label,
+		template: messageTemplate(logEntry.Message),
+		tag:      fieldText(logEntry.Fields, agg.config.TagField),
+	}
+
+	agg.mu.Lock()
+	defer agg.mu.Unlock()
+
+	_, exists := agg.counts[key]
+	if !exists && len(agg.counts) >= maxRollupGroups {
+		key.template = rollupOther
+	}
+
+	agg.counts[key]++
+
+	return true
+}
+
+// writeRollups writes the rollups of the windows ended by now, or of every
+// window when all is set.
+func (agg *aggregator) writeRollups(now time.Time, all bool) error {
+	agg.write.Lock()
+	defer agg.write.Unlock()
+
+	rollups := agg.take(now, all)
+	if len(rollups) == 0 {
+		return nil
+	}
+
+	var lines []byte
+
+	for _, rollup := range rollups {
+		line, err := json.Marshal(rollup)
+		if err != nil {
+			continue
+		}
+
+		lines = append(append(lines, line...), lineEnd)
+	}
+
+	_, err := agg.sink.Write(lines)
+	if err != nil {
+		return fmt.Errorf(errFmtWriteAggregation, err)
+	}
+
+	return nil
+}
+
+// take removes the counts of the windows ended by now, or every count when
+// all is set, and returns them as rollups in time order.
+func (agg *aggregator) take(now time.Time, all bool) []Rollup {
+	agg.mu.Lock()
+	defer agg.mu.Unlock()
+
+	var rollups []Rollup
+
+	for _, key := range slices.Collect(maps.Keys(agg.counts)) {
+		if !all && key.start.Add(agg.config.Window).After(now) {
+			continue
+		}
+
+		template := key.template
+		rollups = append(rollups, Rollup{
+			Start:       key.start,
+			Level:       key.level,
+			Fingerprint: fingerprint(template),
+			Template:    template,
+			Tag:         key.tag,
+			Seconds:     agg.config.Window.Seconds(),
+			Count:       agg.counts[key],
+		})
+
+		delete(agg.counts, key)
+	}
+
+	slices.SortFunc(rollups, func(a, b Rollup) int {
+		return cmp.Or(a.Start.Compare(b.Start), cmp.Compare(a.Level, b.Level),
+			cmp.Compare(a.Tag, b.Tag), cmp.Compare(b.Count, a.Count), cmp.Compare(a.Template, b.Template))
+	})
+
+	return rollups
+}
+
+// stopAggregation writes the remaining rollups and closes their sink.
+func (c *loggerCore) stopAggregation() error {
+	agg := c.aggregator
+	if agg == nil {
+		return nil
+	}
+
+	var errs []error
+
+	agg.stop.Do(func() {
+		close(agg.done)
+		errs = append(errs, agg.writeRollups(c.clock(), true))
+
+		err := agg.sink.Close()
+		if err != nil {
+			errs = append(errs, fmt.Errorf(errFmtCloseAggregation, err))
+		}
+	})
+
+	return errors.Join(errs...)
+}
+
+// fieldText returns the value of the field key of fields as text, or "".
+func fieldText(fields []Field, key string) string {
+	for _, field := range fields {
+		if field.Key == key {
+			return fmt.Sprint(field.Value)
+		}
+	}
+
+	return ""
+}
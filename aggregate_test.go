@@ -0,0 +1,132 @@
+package logger_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	aggregateProducer  = "ocr"
+	aggregatePageFmt   = "page %d converted"
+	aggregateTemplate  = "page # converted"
+	aggregateReadyMsg  = "ready"
+	aggregateWarnMsg   = "disk almost full"
+	aggregateFile      = "rollups.jsonl"
+	aggregateLogFile   = "app.log"
+	aggregatePages     = 3
+	aggregateWindow    = 20 * time.Millisecond
+	aggregateTimeout   = 5 * time.Second
+	aggregateRawFmt    = "raw output = %q; want only the WARN entry"
+	aggregateDecodeFmt = "decode rollup %q: %v"
+	aggregateRollupFmt = "rollups = %+v; want %+v"
+	aggregateWaitFmt   = "no rollup within %s"
+)
+
+func TestWithAggregation(t *testing.T) {
+	t.Parallel()
+
+	var raw, rollups bytes.Buffer
+
+	start := time.Date(2025, time.March, 1, 8, 0, 30, 0, time.UTC)
+	loggerInstance := logger.NewStreamLogger(&raw,
+		logger.WithClock(func() time.Time { return start }),
+		logger.WithAggregation(logger.Aggregation{
+			Sink:     logger.WriterSink(&rollups),
+			Filename: "",
+			TagField: "",
+			Window:   0,
+			MaxLevel: logger.LevelInfo,
+		}),
+	)
+
+	ocr := loggerInstance.Named(aggregateProducer)
+	for page := range aggregatePages {
+		ocr.Infof(aggregatePageFmt, page)
+	}
+
+	loggerInstance.Infof(aggregateReadyMsg)
+	loggerInstance.Warnf(aggregateWarnMsg)
+	closeTestLogger(t, loggerInstance)
+
+	if strings.Count(raw.String(), "\n") != 1 || !strings.Contains(raw.String(), aggregateWarnMsg) {
+		t.Errorf(aggregateRawFmt, raw.String())
+	}
+
+	got := decodeRollups(t, rollups.String())
+	window := start.Truncate(time.Minute)
+	want := []logger.Rollup{
+		{Start: window, Level: "INFO", Fingerprint: got[0].Fingerprint, Template: aggregateReadyMsg, Tag: "", Seconds: 60, Count: 1},
+		{
+			Start: window, Level: "INFO", Fingerprint: got[1].Fingerprint, Template: aggregateTemplate,
+			Tag: aggregateProducer, Seconds: 60, Count: aggregatePages,
+		},
+	}
+
+	if len(got) != len(want) || !got[0].Start.Equal(want[0].Start) || got[0].Template != want[0].Template ||
+		got[1].Template != want[1].Template || got[1].Tag != want[1].Tag || got[1].Count != want[1].Count ||
+		got[1].Fingerprint == "" {
+		t.Errorf(aggregateRollupFmt, got, want)
+	}
+}
+
+func TestWithAggregation_WritesEveryWindow(t *testing.T) {
+	t.Parallel()
+
+	var rollups syncBuffer
+
+	loggerInstance, err := logger.New(t.TempDir(), aggregateLogFile,
+		logger.WithoutStdout(),
+		logger.WithAggregation(logger.Aggregation{
+			Sink:     logger.WriterSink(&rollups),
+			Filename: aggregateFile,
+			TagField: "",
+			Window:   aggregateWindow,
+			MaxLevel: logger.LevelInfo,
+		}),
+	)
+	if err != nil {
+		t.Fatalf(newLoggerError, err)
+	}
+
+	loggerInstance.Infof(aggregateReadyMsg)
+
+	deadline := time.Now().Add(aggregateTimeout)
+	for rollups.String() == "" && time.Now().Before(deadline) {
+		time.Sleep(aggregateWindow)
+	}
+
+	if rollups.String() == "" {
+		t.Errorf(aggregateWaitFmt, aggregateTimeout)
+	}
+
+	closeTestLogger(t, loggerInstance)
+
+	got := decodeRollups(t, rollups.String())
+	if len(got) != 1 || got[0].Template != aggregateReadyMsg || got[0].Count != 1 {
+		t.Errorf(aggregateRollupFmt, got, aggregateReadyMsg)
+	}
+}
+
+func decodeRollups(t *testing.T, output string) []logger.Rollup {
+	t.Helper()
+
+	var rollups []logger.Rollup
+
+	for line := range strings.SplitSeq(strings.TrimSpace(output), "\n") {
+		var rollup logger.Rollup
+
+		err := json.Unmarshal([]byte(line), &rollup)
+		if err != nil {
+			t.Fatalf(aggregateDecodeFmt, line, err)
+		}
+
+		rollups = append(rollups, rollup)
+	}
+
+	return rollups
+}
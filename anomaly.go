@@ -0,0 +1,227 @@
+package logger
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// RateAnomalyField marks the warnings of WithRateAnomalies; its value is
+	// RateRose or RateFell. Entries carrying it are not counted.
+	RateAnomalyField = "rate_anomaly"
+	RateRose         = "rose"
+	RateFell         = "fell"
+
+	// Defaults of RateAnomalies.
+	DefaultRateInterval = time.Minute
+	DefaultRateFactor   = 3.0
+	DefaultRateAlpha    = 0.2
+	DefaultRateMinimum  = 10.0
+	DefaultRateWarmup   = 10
+
+	// maxRateKeys bounds the level and tag pairs with a baseline.
+	maxRateKeys       = 1024
+	rateLevelField    = "rate_level"
+	rateTagField      = "rate_tag"
+	rateBaselineField = "baseline_per_minute"
+	rateObservedField = "observed_per_minute"
+	rateAnomalyFormat = "rate of %s entries%s %s from %.1f to %.1f per minute"
+	rateTagFormat     = " from %s"
+)
+
+// RateAnomalies configures WithRateAnomalies. Zero fields take the Default
+// values: the rates are sampled every Interval, the baseline of a level and
+// tag starts at its first sample and moves by Alpha of the difference each
+// sample, and a rate is anomalous once Warmup samples of it were learned when
+// it exceeds Factor times its baseline, or at least Factor times Minimum, or
+// falls below its baseline divided by Factor while the baseline is at least
+// Minimum entries per minute.
+type RateAnomalies struct {
+	Interval time.Duration
+	Factor   float64
+	Alpha    float64
+	Minimum  float64
+	Warmup   int
+}
+
+// rateKey identifies the entries of a level and tag.
+type rateKey struct {
+	level string
+	tag   string
+}
+
+// rateBaseline is the learned rate of a level and tag.
+type rateBaseline struct {
+	perMinute float64
+	samples   int
+}
+
+// rateDetector holds the counts of the current interval, guarded by
+// loggerCore.mu, and the baselines, replaced under loggerCore.mu by the
+// sampling goroutine, which alone reads them without it.
+type rateDetector struct {
+	counts    map[rateKey]uint64
+	baselines map[rateKey]rateBaseline
+	done      chan struct{}
+	config    RateAnomalies
+	stop      sync.Once
+}
+
+// WithRateAnomalies learns the baseline rate of entries per minute of every
+// level and producer tag, an exponentially weighted moving average, and
+// writes a WARN entry when a rate rises or falls beyond a factor of it, a
+// basic "something changed" signal from the logger itself: a component that
+// starts failing in a loop, or one that went silent. The warning carries
+// RateAnomalyField and the level, tag, baseline and observed rate.
+func WithRateAnomalies(anomalies RateAnomalies) Option {
+	return func(config *options) {
+		anomalies.Interval = cmp.Or(anomalies.Interval, DefaultRateInterval)
+		anomalies.Factor = cmp.Or(anomalies.Factor, DefaultRateFactor)
+		anomalies.Alpha = cmp.Or(anomalies.Alpha, DefaultRateAlpha)
+		anomalies.Minimum = cmp.Or(anomalies.Minimum, DefaultRateMinimum)
+		anomalies.Warmup = cmp.Or(anomalies.Warmup, DefaultRateWarmup)
+		config.rateAnomalies = &anomalies
+	}
+}
+
+func newRateDetector(anomalies *RateAnomalies) *rateDetector {
+	if anomalies == nil {
+		return nil
+	}
+
+	return &rateDetector{
+		counts:    make(map[rateKey]uint64),
+		baselines: make(map[rateKey]rateBaseline),
+		done:      make(chan struct{}),
+		config:    *anomalies,
+		stop:      sync.Once{},
+	}
+}
+
+// startRateAnomalies samples the rates every interval until the logger is
+// closed.
+func (c *loggerCore) startRateAnomalies() {
+	if c.rates == nil {
+		return
+	}
+
+	ticker := time.NewTicker(c.rates.config.Interval)
+
+	c.goTask(ProfileTaskRateAnomalies, func() {
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				c.sampleRates()
+			case <-c.rates.done:
+				return
+			}
+		}
+	})
+}
+
+// countRate counts logEntry for the current interval. The caller holds mu.
+func (c *loggerCore) countRate(logEntry *Entry) {
+	if c.rates == nil || hasField(logEntry.Fields, RateAnomalyField, RouteAnyValue) {
+		return
+	}
+
+	key := rateKey{level: logEntry.Label, tag: fieldText(logEntry.Fields, ProducerField)}
+
+	_, known := c.rates.baselines[key]
+	if !known && len(c.rates.counts) >= maxRateKeys {
+		return
+	}
+
+	c.rates.counts[key]++
+}
+
+// sampleRates compares the rates of the interval that ended with their
+// baselines, warns about the anomalous ones and updates the baselines. A
+// level and tag seen for the first time is learned for Warmup samples before
+// it is compared, so that its first busy interval is not taken for a rise.
+func (c *loggerCore) sampleRates() {
+	c.mu.Lock()
+	counts := c.rates.counts
+	c.rates.counts = make(map[rateKey]uint64, len(counts))
+	c.mu.Unlock()
+
+	detector := c.rates
+	perMinute := float64(time.Minute) / float64(detector.config.Interval)
+
+	keys := slices.Collect(maps.Keys(detector.baselines))
+	for key := range counts {
+		_, known := detector.baselines[key]
+		if !known && len(keys) < maxRateKeys {
+			keys = append(keys, key)
+		}
+	}
+
+	// countRate reads the baselines under mu, so they are replaced, not
+	// updated in place.
+	baselines := make(map[rateKey]rateBaseline, len(keys))
+
+	for _, key := range keys {
+		baseline, known := detector.baselines[key]
+		observed := float64(counts[key]) * perMinute
+
+		if !known {
+			baseline.perMinute = observed
+		}
+
+		if baseline.samples >= detector.config.Warmup {
+			c.checkRate(key, baseline.perMinute, observed)
+		}
+
+		baseline.perMinute += detector.config.Alpha * (observed - baseline.perMinute)
+		baseline.samples++
+		baselines[key] = baseline
+	}
+
+	c.mu.Lock()
+	detector.baselines = baselines
+	c.mu.Unlock()
+}
+
+// checkRate warns when observed deviates from baseline beyond the factor.
+func (c *loggerCore) checkRate(key rateKey, baseline, observed float64) {
+	config := c.rates.config
+
+	direction := ""
+
+	switch {
+	case observed > max(baseline, config.Minimum)*config.Factor:
+		direction = RateRose
+	case baseline >= config.Minimum && observed < baseline/config.Factor:
+		direction = RateFell
+	default:
+		return
+	}
+
+	tag := ""
+	if key.tag != "" {
+		tag = c.safeFormat(rateTagFormat, key.tag)
+	}
+
+	fields := []Field{
+		F(RateAnomalyField, direction),
+		F(rateLevelField, key.level),
+		F(rateTagField, key.tag),
+		F(rateBaselineField, baseline),
+		F(rateObservedField, observed),
+	}
+	c.writeEntryf(LevelWarn, fields, "", targetAll, rateAnomalyFormat, key.level, tag, direction, baseline, observed)
+}
+
+// stopRateAnomalies stops the sampling goroutine.
+func (c *loggerCore) stopRateAnomalies() {
+	if c.rates == nil {
+		return
+	}
+
+	c.rates.stop.Do(func() { close(c.rates.done) })
+}
@@ -0,0 +1,82 @@
+package logger_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	anomalyProducer = "ocr"
+	anomalyMsg      = "page converted"
+	anomalyBurst    = 50
+	anomalyInterval = 20 * time.Millisecond
+	anomalyDecay    = 15
+	anomalyRoseMsg  = "rate of INFO entries from ocr rose"
+	anomalyFellMsg  = "rate of INFO entries from ocr fell"
+	anomalyRoseTag  = "rate_anomaly=rose"
+	anomalyFellTag  = "rate_anomaly=fell"
+	anomalyNoneFmt  = "unexpected rate warning:\n%s"
+)
+
+func TestWithRateAnomalies(t *testing.T) {
+	t.Parallel()
+
+	var buf syncBuffer
+
+	loggerInstance := logger.NewStreamLogger(&buf, logger.WithRateAnomalies(logger.RateAnomalies{
+		Interval: anomalyInterval,
+		Factor:   0,
+		Alpha:    0,
+		Minimum:  1,
+		Warmup:   2,
+	}))
+	defer closeTestLogger(t, loggerInstance)
+
+	// The producer appears after the first samples; its first burst is
+	// learned, not reported, and its silence afterwards is.
+	time.Sleep(3 * anomalyInterval)
+
+	ocr := loggerInstance.Named(anomalyProducer)
+	for range anomalyBurst {
+		ocr.Infof(anomalyMsg)
+	}
+
+	waitForOutput(t, &buf, anomalyFellMsg)
+	waitForOutput(t, &buf, anomalyFellTag)
+
+	if strings.Contains(buf.String(), anomalyRoseTag) {
+		t.Fatalf(anomalyNoneFmt, buf.String())
+	}
+
+	// Once the baseline decayed, the same burst is a rise.
+	time.Sleep(anomalyDecay * anomalyInterval)
+
+	for range anomalyBurst {
+		ocr.Infof(anomalyMsg)
+	}
+
+	waitForOutput(t, &buf, anomalyRoseMsg)
+	waitForOutput(t, &buf, anomalyRoseTag)
+}
+
+func TestWithRateAnomalies_Disabled(t *testing.T) {
+	t.Parallel()
+
+	var buf syncBuffer
+
+	loggerInstance := logger.NewStreamLogger(&buf)
+
+	for range anomalyBurst {
+		loggerInstance.Infof(anomalyMsg)
+	}
+
+	time.Sleep(3 * anomalyInterval)
+	closeTestLogger(t, loggerInstance)
+
+	if strings.Contains(buf.String(), logger.RateAnomalyField) {
+		t.Errorf(anomalyNoneFmt, buf.String())
+	}
+}
@@ -0,0 +1,57 @@
+package logger
+
+const (
+	// CallerField carries the caller of a failed AssertTruef or CheckErr when
+	// no output renders the caller already.
+	CallerField = "caller"
+
+	checkErrFmt = "%s: %v"
+)
+
+// AssertTruef logs the message at ERROR, with the caller's file and line,
+// unless cond holds, and returns cond, so that a broken invariant can be
+// reported and handled in one statement:
+//
+//	if !log.AssertTruef(len(pages) > 0, "document %s has no pages", id) {
+//		return
+//	}
+func (l *Logger) AssertTruef(cond bool, format string, args ...any) bool {
+	if !cond {
+		l.writeFailure(format, args...)
+	}
+
+	return cond
+}
+
+// CheckErr logs msg and err at ERROR, with the caller's file and line, when
+// err is not nil, and returns err unchanged:
+//
+//	if err := log.CheckErr(convert(page), "convert page"); err != nil {
+//		return err
+//	}
+func (l *Logger) CheckErr(err error, msg string) error {
+	if err != nil {
+		l.writeFailure(checkErrFmt, msg, err)
+	}
+
+	return err
+}
+
+// writeFailure writes an ERROR entry naming the caller of AssertTruef or
+// CheckErr, which is always resolved: in CallerField when no output renders
+// the caller, and in Entry.Caller for the outputs that do.
+func (l *Logger) writeFailure(format string, args ...any) {
+	if !l.core.enabled(LevelError) {
+		return
+	}
+
+	// writeFailure is called by the helper, which is called by user code.
+	caller := callerAt(callerSkip)
+
+	var fields []Field
+	if !l.core.needsCaller.Load() {
+		fields = []Field{F(CallerField, caller)}
+	}
+
+	l.writeEntryf(LevelError, fields, caller, targetAll, format, args...)
+}
@@ -0,0 +1,74 @@
+package logger_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/book-expert/logger"
+	"github.com/book-expert/logger/loggertest"
+)
+
+const (
+	assertFile       = "assert_test.go:"
+	assertFormat     = "document %s has no pages"
+	assertDocument   = "doc-42"
+	assertMessage    = "document doc-42 has no pages"
+	assertCheckMsg   = "convert page"
+	assertCauseMsg   = "disk full"
+	assertCheckEntry = "convert page: disk full"
+	assertResultFmt  = "result = %v; want %v"
+	assertEntriesFmt = "messages = %q; want %q"
+	assertEntryFmt   = "entry = %+v; want ERROR with caller %s*"
+)
+
+var errAssertCause = errors.New(assertCauseMsg)
+
+func TestLogger_AssertTruef(t *testing.T) {
+	t.Parallel()
+
+	loggerInstance, observer := loggertest.NewObservedLogger(t)
+
+	if !loggerInstance.AssertTruef(true, assertFormat, assertDocument) {
+		t.Errorf(assertResultFmt, false, true)
+	}
+
+	if loggerInstance.AssertTruef(false, assertFormat, assertDocument) {
+		t.Errorf(assertResultFmt, true, false)
+	}
+
+	expectFailureEntry(t, observer, assertMessage)
+}
+
+func TestLogger_CheckErr(t *testing.T) {
+	t.Parallel()
+
+	loggerInstance, observer := loggertest.NewObservedLogger(t)
+
+	err := loggerInstance.CheckErr(nil, assertCheckMsg)
+	if err != nil {
+		t.Errorf(assertResultFmt, err, nil)
+	}
+
+	err = loggerInstance.CheckErr(errAssertCause, assertCheckMsg)
+	if !errors.Is(err, errAssertCause) {
+		t.Errorf(assertResultFmt, err, errAssertCause)
+	}
+
+	expectFailureEntry(t, observer, assertCheckEntry)
+}
+
+func expectFailureEntry(t *testing.T, observer *loggertest.Observer, message string) {
+	t.Helper()
+
+	entries := observer.Entries()
+	if len(entries) != 1 || entries[0].Message != message {
+		t.Fatalf(assertEntriesFmt, observer.Messages(), message)
+	}
+
+	entry := entries[0]
+	if entry.Level != logger.LevelError || !strings.HasPrefix(entry.Caller, assertFile) ||
+		len(entry.Fields) != 1 || entry.Fields[0] != logger.F(logger.CallerField, entry.Caller) {
+		t.Errorf(assertEntryFmt, entry, assertFile)
+	}
+}
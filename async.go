@@ -0,0 +1,271 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// asyncItem is a queued entry, or a flush marker when flushed is set. When
+// acked is set, the entry's write error is sent on it.
+type asyncItem struct {
+	entry   *Entry
+	flushed chan struct{}
+	acked   chan error
+	target  writeTarget
+}
+
+// asyncQueue is the channel-based entryWriter: it hands entries to a single
+// writer goroutine. The queue is one FIFO channel, so entries are written in
+// the order they were submitted and entries from the same goroutine are never
+// reordered.
+type asyncQueue struct {
+	core      *loggerCore
+	items     chan asyncItem
+	done      chan struct{}
+	batch     []asyncItem
+	mu        sync.RWMutex
+	batchSize int
+	stopped   bool
+}
+
+// WithAsync makes logging calls queue their entries for a background writer
+// instead of writing them before returning. Up to bufferSize entries are
+// queued; when the queue is full, logging calls block until the writer catches
+// up, so no entry is lost. Entries are formatted and timestamped when they are
+// logged and are written in submission order: entries logged by one goroutine
+// always appear in the order that goroutine logged them. Close writes every
+// queued entry before closing the outputs; Flush waits for the queue to drain,
+// after which Err reports the write errors of the flushed entries. Escalation
+// hooks run on their own goroutines. A bufferSize of zero or less keeps
+// logging synchronous.
+func WithAsync(bufferSize int) Option {
+	return func(config *options) {
+		config.asyncBuffer = bufferSize
+	}
+}
+
+// WithCoalescing lets the async writer coalesce up to batchSize queued
+// entries into a single write to stdout and a single write to the log file,
+// which raises throughput when many goroutines log at once. The writer never
+// waits to fill a batch: it takes the entries already queued, so a lone entry
+// is written at once. Entries logged with Securityf, and Flush, still wait
+// until their batch is written. It has no effect without WithAsync; a
+// batchSize of one or less writes every entry on its own.
+func WithCoalescing(batchSize int) Option {
+	return func(config *options) {
+		config.coalesceBatch = batchSize
+	}
+}
+
+// startAsync starts the background writer when async mode is configured.
+func (c *loggerCore) startAsync(bufferSize, batchSize int) {
+	if bufferSize <= 0 {
+		return
+	}
+
+	queue := &asyncQueue{
+		core:      c,
+		items:     make(chan asyncItem, bufferSize),
+		done:      make(chan struct{}),
+		batch:     make([]asyncItem, 0, max(batchSize, 1)),
+		mu:        sync.RWMutex{},
+		batchSize: max(batchSize, 1),
+		stopped:   false,
+	}
+	c.writer = queue
+
+	c.goTask(ProfileTaskAsyncWriter, queue.run)
+}
+
+func (queue *asyncQueue) run() {
+	defer close(queue.done)
+
+	for item := range queue.items {
+		queue.writeBatch(queue.collect(item))
+	}
+}
+
+// collect returns item followed by the entries already queued, up to the
+// batch size. A batch ends at a flush marker or an entry waited for, so that
+// their waiters are released once their batch is written.
+func (queue *asyncQueue) collect(item asyncItem) []asyncItem {
+	batch := append(queue.batch[:0], item)
+
+	for len(batch) < queue.batchSize && item.flushed == nil && item.acked == nil {
+		var ok bool
+
+		select {
+		case item, ok = <-queue.items:
+			if !ok {
+				return batch
+			}
+
+			batch = append(batch, item)
+		default:
+			return batch
+		}
+	}
+
+	return batch
+}
+
+// writeBatch writes the entries of batch, coalescing their lines, and then
+// releases the waiter of its last item.
+func (queue *asyncQueue) writeBatch(batch []asyncItem) {
+	c := queue.core
+	coalesced := len(batch) > 1
+
+	if coalesced {
+		c.bufferLines(true)
+	}
+
+	var err error
+
+	for _, item := range batch {
+		if item.entry == nil {
+			continue
+		}
+
+		var (
+			hooks  []func()
+			report *crashReport
+		)
+
+		hooks, report, err = c.writeLocked(item.entry, item.target)
+		c.writeCrashReport(report)
+
+		// Hooks run on their own goroutines: a hook that logs could otherwise
+		// block on the full queue that this goroutine drains.
+		for _, hook := range hooks {
+			c.goTask(ProfileTaskHook, hook)
+		}
+	}
+
+	if coalesced {
+		err = errors.Join(err, c.bufferLines(false))
+	}
+
+	last := batch[len(batch)-1]
+	if last.flushed != nil {
+		close(last.flushed)
+	}
+
+	if last.acked != nil {
+		last.acked <- err
+	}
+}
+
+// bufferLines starts or ends buffering the lines of stdout and the log file.
+// Ending it writes the buffered lines and records their error for Err.
+func (c *loggerCore) bufferLines(buffered bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, writer := range []*lineWriter{c.std, c.file} {
+		if writer != nil {
+			writer.buffered = buffered
+		}
+	}
+
+	if buffered {
+		return nil
+	}
+
+	err := c.flushLines()
+	if err != nil {
+		c.lastErr = err
+	}
+
+	return err
+}
+
+// flushLines writes the lines buffered for stdout and the log file. The
+// caller holds mu.
+func (c *loggerCore) flushLines() error {
+	var errs []error
+
+	if c.std != nil {
+		err := c.std.flush()
+		if err != nil {
+			errs = append(errs, fmt.Errorf(errFmtWrapCause, ErrWriteStdout, err))
+		}
+	}
+
+	if c.file != nil {
+		err := c.file.flush()
+		if err != nil {
+			errs = append(errs, fmt.Errorf(errFmtWrapCause, ErrWriteLogFile, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// submit queues logEntry. Once the queue is stopped it waits until every
+// queued entry is written and writes synchronously, so that the caller's
+// entry cannot overtake its earlier ones.
+func (queue *asyncQueue) submit(logEntry *Entry, target writeTarget) {
+	if !queue.send(asyncItem{entry: logEntry, flushed: nil, acked: nil, target: target}) {
+		_ = queue.core.write(logEntry, target) // Recorded for Err.
+	}
+}
+
+// submitAndWait queues logEntry and waits for its write error.
+func (queue *asyncQueue) submitAndWait(logEntry *Entry, target writeTarget) error {
+	acked := make(chan error, 1)
+	if !queue.send(asyncItem{entry: logEntry, flushed: nil, acked: acked, target: target}) {
+		return queue.core.write(logEntry, target)
+	}
+
+	return <-acked
+}
+
+// send queues item and reports whether it was accepted. Once the queue is
+// stopped it waits for the writer to drain it and returns false.
+func (queue *asyncQueue) send(item asyncItem) bool {
+	queue.mu.RLock()
+
+	if queue.stopped {
+		queue.mu.RUnlock()
+		<-queue.done
+
+		return false
+	}
+
+	queue.items <- item
+	queue.mu.RUnlock()
+
+	return true
+}
+
+// stop closes the queue and waits for the writer to drain it.
+func (queue *asyncQueue) stop() {
+	queue.mu.Lock()
+
+	if !queue.stopped {
+		queue.stopped = true
+		close(queue.items)
+	}
+
+	queue.mu.Unlock()
+	<-queue.done
+}
+
+// flush waits for a marker queued behind every earlier entry.
+func (queue *asyncQueue) flush() {
+	flushed := make(chan struct{})
+	if queue.send(asyncItem{entry: nil, flushed: flushed, acked: nil, target: targetAll}) {
+		<-flushed
+	}
+}
+
+// Flush blocks until every entry logged before the call has been written,
+// including the route queues of WithSinkWorkers, then flushes route sinks
+// that buffer lines, such as BatchSink, recording their errors for Err. It
+// returns immediately after Close.
+func (l *Logger) Flush() {
+	l.core.writer.flush()
+	l.core.sinks.flush()
+	l.core.flushSinks()
+}
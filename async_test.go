@@ -0,0 +1,197 @@
+package logger_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	asyncProducers   = 8
+	asyncEntries     = 200
+	asyncBufferSize  = 16
+	asyncMsg         = "step"
+	asyncFlushMsg    = "flushed"
+	asyncProducerKey = "producer"
+	asyncSeqKey      = "seq"
+	asyncOrderErrFmt = "producer %d: expected seq %d, got line %q"
+	asyncCountErrFmt = "producer %d: expected %d entries, got %d"
+	asyncParseErrFmt = "parse %q: %v"
+	asyncFlushErrFmt = "expected %q after Flush, got %q"
+	asyncFieldsFmt   = " producer=%d seq=%d"
+	asyncBatchSize   = 64
+	asyncWriteDelay  = 100 * time.Microsecond
+	asyncBatchFile   = "batch.log"
+	asyncSecurityMsg = "key rotated"
+	asyncLinesErrFmt = "expected %d whole lines, got %d"
+	asyncWritesFmt   = "expected fewer than %d writes with coalescing, got %d"
+	asyncDurableFmt  = "expected %q in the file when Securityf returns, got %q"
+)
+
+// slowWriter counts its writes, each taking asyncWriteDelay, so that entries
+// queue up behind it.
+type slowWriter struct {
+	syncBuffer
+
+	writes int
+}
+
+func (w *slowWriter) Write(p []byte) (int, error) {
+	time.Sleep(asyncWriteDelay)
+
+	w.mu.Lock()
+	w.writes++
+	w.mu.Unlock()
+
+	return w.syncBuffer.Write(p)
+}
+
+func TestAsync_PreservesPerGoroutineOrder(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	loggerInstance := logger.NewStreamLogger(&buf, logger.WithAsync(asyncBufferSize))
+
+	var wg sync.WaitGroup
+
+	for producer := range asyncProducers {
+		wg.Go(func() {
+			child := loggerInstance.With(logger.F(asyncProducerKey, producer))
+			for seq := range asyncEntries {
+				child.With(logger.F(asyncSeqKey, seq)).Infof(asyncMsg)
+			}
+		})
+	}
+
+	wg.Wait()
+	closeTestLogger(t, loggerInstance)
+
+	next := make([]int, asyncProducers)
+
+	for line := range strings.SplitSeq(strings.TrimSpace(buf.String()), "\n") {
+		_, fields, _ := strings.Cut(line, asyncMsg)
+
+		var producer, seq int
+
+		_, err := fmt.Sscanf(fields, asyncFieldsFmt, &producer, &seq)
+		if err != nil {
+			t.Fatalf(asyncParseErrFmt, line, err)
+		}
+
+		if seq != next[producer] {
+			t.Fatalf(asyncOrderErrFmt, producer, next[producer], line)
+		}
+
+		next[producer]++
+	}
+
+	for producer, count := range next {
+		if count != asyncEntries {
+			t.Errorf(asyncCountErrFmt, producer, asyncEntries, count)
+		}
+	}
+}
+
+func TestAsync_FlushAndClose(t *testing.T) {
+	t.Parallel()
+
+	var buf syncBuffer
+
+	loggerInstance := logger.NewStreamLogger(&buf, logger.WithAsync(asyncBufferSize))
+	loggerInstance.Infof(asyncFlushMsg)
+	loggerInstance.Flush()
+
+	if !strings.Contains(buf.String(), asyncFlushMsg) {
+		t.Errorf(asyncFlushErrFmt, asyncFlushMsg, buf.String())
+	}
+
+	closeTestLogger(t, loggerInstance)
+	loggerInstance.Flush()
+	loggerInstance.Infof(asyncFlushMsg)
+
+	err := loggerInstance.Err()
+	if !errors.Is(err, logger.ErrLoggerClosed) {
+		t.Errorf(expectedErrFmt, logger.ErrLoggerClosed, err)
+	}
+}
+
+func TestAsync_CoalescesQueuedEntries(t *testing.T) {
+	t.Parallel()
+
+	writer := &slowWriter{syncBuffer: syncBuffer{builder: strings.Builder{}, mu: sync.Mutex{}}, writes: 0}
+	loggerInstance := logger.NewStreamLogger(writer,
+		logger.WithAsync(asyncBatchSize),
+		logger.WithCoalescing(asyncBatchSize),
+	)
+
+	var wg sync.WaitGroup
+
+	for producer := range asyncProducers {
+		wg.Go(func() {
+			for seq := range asyncEntries {
+				loggerInstance.Infof(asyncMsg+asyncFieldsFmt, producer, seq)
+			}
+		})
+	}
+
+	wg.Wait()
+	closeTestLogger(t, loggerInstance)
+
+	total := asyncProducers * asyncEntries
+
+	lines := strings.Split(strings.TrimSpace(writer.String()), "\n")
+	if len(lines) != total {
+		t.Fatalf(asyncLinesErrFmt, total, len(lines))
+	}
+
+	for _, line := range lines {
+		if strings.Count(line, asyncMsg) != 1 {
+			t.Fatalf(asyncLinesErrFmt, total, len(lines))
+		}
+	}
+
+	if writer.writes >= total {
+		t.Errorf(asyncWritesFmt, total, writer.writes)
+	}
+}
+
+func TestAsync_CoalescingKeepsSecurityDurable(t *testing.T) {
+	t.Parallel()
+
+	logDir := t.TempDir()
+
+	loggerInstance, err := logger.New(logDir, asyncBatchFile,
+		logger.WithoutStdout(),
+		logger.WithAsync(asyncBatchSize),
+		logger.WithCoalescing(asyncBatchSize),
+	)
+	if err != nil {
+		t.Fatalf(newLoggerWithDirErrFmt, err)
+	}
+
+	for seq := range asyncEntries {
+		loggerInstance.Infof(asyncMsg+asyncFieldsFmt, 0, seq)
+	}
+
+	err = loggerInstance.Securityf(asyncSecurityMsg)
+	if err != nil {
+		t.Fatalf(expectedErrFmt, nil, err)
+	}
+
+	// #nosec G304 -- the file is created by the test.
+	content, err := os.ReadFile(filepath.Join(logDir, asyncBatchFile))
+	if err != nil || !strings.Contains(string(content), asyncSecurityMsg) {
+		t.Errorf(asyncDurableFmt, asyncSecurityMsg, content)
+	}
+
+	closeTestLogger(t, loggerInstance)
+}
@@ -0,0 +1,212 @@
+package logger
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// AttachmentsDir is the subdirectory of the log directory holding
+	// attachments.
+	AttachmentsDir = "attachments"
+	// AttachmentField is the field holding the ID of an attachment, which is
+	// also its filename in AttachmentsDir.
+	AttachmentField = "attachment"
+	// MaxAttachmentSize is the largest attachment WithAttachment stores.
+	MaxAttachmentSize = 1 << 20
+
+	// DefaultAttachmentMaxAge and DefaultAttachmentMaxFiles are the retention
+	// of attachments without WithAttachmentRetention.
+	DefaultAttachmentMaxAge   = 7 * 24 * time.Hour
+	DefaultAttachmentMaxFiles = 1000
+
+	attachmentHashLength     = 16
+	attachmentIDSeparator    = "-"
+	attachmentTempPrefix     = ".attachment-"
+	attachmentTempPattern    = attachmentTempPrefix + "*"
+	errAttachmentTooLargeMsg = "attachment exceeds MaxAttachmentSize"
+	errFmtAttach             = "attach %q: %w"
+	errFmtPruneAttachments   = "prune attachments: %w"
+)
+
+// ErrAttachmentTooLarge is recorded by WithAttachment for data larger than
+// MaxAttachmentSize.
+var ErrAttachmentTooLarge = errors.New(errAttachmentTooLargeMsg)
+
+// AttachmentRetention bounds the attachments kept in the log directory:
+// attachments older than MaxAge are removed, and the oldest beyond MaxFiles.
+// Zero disables a bound.
+type AttachmentRetention struct {
+	MaxAge   time.Duration
+	MaxFiles int
+}
+
+// WithAttachmentRetention sets how long and how many attachments are kept.
+// The default keeps DefaultAttachmentMaxFiles attachments for up to
+// DefaultAttachmentMaxAge.
+func WithAttachmentRetention(retention AttachmentRetention) Option {
+	return func(config *options) {
+		config.attachmentRetention = retention
+	}
+}
+
+// WithAttachment stores data, a small binary artifact such as a screenshot,
+// the failing page of a PDF or a request body, in the attachments directory
+// of the log directory and returns a child logger whose entries reference it
+// by ID in the attachment field:
+//
+//	log.WithAttachment("page-12.pdf", page).Errorf("OCR failed")
+//	// [ERROR] OCR failed attachment=3f7a9c0e5b1d2a48-page-12.pdf
+//
+// The ID is a hash of data followed by name, which must be a valid
+// filename; attaching the same data again reuses its file. Attachments are
+// removed as WithAttachmentRetention sets. When data cannot be stored, for
+// example because the logger has no log directory or data is larger than
+// MaxAttachmentSize, the logger is returned unchanged and the error is
+// reported by Err.
+func (l *Logger) WithAttachment(name string, data []byte) *Logger {
+	attachmentID, err := l.core.storeAttachment(name, data)
+	if err != nil {
+		l.core.mu.Lock()
+		l.core.lastErr = fmt.Errorf(errFmtAttach, name, err)
+		l.core.mu.Unlock()
+
+		return l
+	}
+
+	return l.With(F(AttachmentField, attachmentID))
+}
+
+// storeAttachment writes data to the attachments directory and returns the
+// attachment's ID. Attachments past the retention are then removed; a
+// failure to remove them is reported by Err.
+func (c *loggerCore) storeAttachment(name string, data []byte) (string, error) {
+	if len(data) > MaxAttachmentSize {
+		return "", ErrAttachmentTooLarge
+	}
+
+	err := ValidateFilename(name)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	attachmentID := hex.EncodeToString(sum[:])[:attachmentHashLength] + attachmentIDSeparator + name
+
+	// The files are written without mu, so that logging goroutines do not
+	// wait for the disk.
+	c.mu.Lock()
+	logDir, retention, now := c.logDir, c.attachments, c.clock()
+	c.mu.Unlock()
+
+	if logDir == "" {
+		return "", ErrNoLogDir
+	}
+
+	dir := filepath.Join(logDir, AttachmentsDir)
+
+	attachmentPath, err := setupAndValidatePath(dir, attachmentID)
+	if err != nil {
+		return "", err
+	}
+
+	err = writeAttachment(attachmentPath, data, now)
+	if err != nil {
+		return "", err
+	}
+
+	err = pruneAttachments(dir, retention, now)
+	if err != nil {
+		c.mu.Lock()
+		c.lastErr = fmt.Errorf(errFmtPruneAttachments, err)
+		c.mu.Unlock()
+	}
+
+	return attachmentID, nil
+}
+
+// writeAttachment writes data to attachmentPath through a temporary file, so
+// that an attachment is never seen half written. An existing attachment is
+// kept and its modification time set to now, restarting its retention.
+func writeAttachment(attachmentPath string, data []byte, now time.Time) error {
+	_, err := os.Stat(attachmentPath)
+	if err == nil {
+		return os.Chtimes(attachmentPath, now, now)
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(attachmentPath), attachmentTempPattern)
+	if err != nil {
+		return err
+	}
+
+	_, err = temp.Write(data)
+	err = errors.Join(err, temp.Close())
+
+	if err == nil {
+		err = os.Chtimes(temp.Name(), now, now)
+	}
+
+	if err == nil {
+		err = os.Rename(temp.Name(), attachmentPath)
+	}
+
+	if err != nil {
+		_ = os.Remove(temp.Name())
+	}
+
+	return err
+}
+
+// pruneAttachments removes the attachments in dir that retention no longer
+// keeps. The temporary files of attachments being written and attachments
+// another goroutine removed first are skipped.
+func pruneAttachments(dir string, retention AttachmentRetention, now time.Time) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	infos := make([]fs.FileInfo, 0, len(entries))
+
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), attachmentTempPrefix) {
+			continue
+		}
+
+		info, infoErr := entry.Info()
+		if infoErr == nil && info.Mode().IsRegular() {
+			infos = append(infos, info)
+		}
+	}
+
+	slices.SortFunc(infos, func(a, b fs.FileInfo) int {
+		return cmp.Compare(b.ModTime().UnixNano(), a.ModTime().UnixNano())
+	})
+
+	var errs []error
+
+	for index, info := range infos {
+		expired := retention.MaxAge > 0 && now.Sub(info.ModTime()) > retention.MaxAge
+		excess := retention.MaxFiles > 0 && index >= retention.MaxFiles
+
+		if !expired && !excess {
+			continue
+		}
+
+		err := os.Remove(filepath.Join(dir, info.Name()))
+		if !errors.Is(err, fs.ErrNotExist) {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,229 @@
+package logger_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	attachmentFile       = "app.log"
+	attachmentName       = "page-12.pdf"
+	attachmentData       = "%PDF-1.7 page 12"
+	attachmentOtherData  = "%PDF-1.7 page 13"
+	attachmentThirdData  = "%PDF-1.7 page 14"
+	attachmentMsg        = "OCR failed"
+	attachmentBadName    = "../escape.pdf"
+	attachmentAge        = time.Hour
+	attachmentNewErrFmt  = "New: %v"
+	attachmentMissingFmt = "log lacks %q:\n%s"
+	attachmentExtraFmt   = "log holds %q:\n%s"
+	attachmentDataFmt    = "attachment %s holds %q; want %q"
+	attachmentCountFmt   = "attachments = %v; want %d files"
+	attachmentErrFmt     = "Err() = %v; want %v"
+	attachmentNoErrFmt   = "Err() = %v; want nil"
+	attachmentWorkers    = 8
+	attachmentPerWorker  = 20
+	attachmentMaxFiles   = 4
+)
+
+func TestLogger_WithAttachment(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	loggerInstance := newAttachmentLogger(t, dir)
+
+	loggerInstance.WithAttachment(attachmentName, []byte(attachmentData)).Errorf(attachmentMsg)
+	loggerInstance.WithAttachment(attachmentName, []byte(attachmentData)).Errorf(attachmentMsg)
+
+	if loggerInstance.Err() != nil {
+		t.Errorf(attachmentNoErrFmt, loggerInstance.Err())
+	}
+
+	closeOutputsLogger(t, loggerInstance)
+
+	names := attachmentNames(t, dir)
+	if len(names) != 1 || !strings.HasSuffix(names[0], "-"+attachmentName) {
+		t.Fatalf(attachmentCountFmt, names, 1)
+	}
+
+	text := readOutputsFile(t, filepath.Join(dir, attachmentFile))
+	want := attachmentMsg + " " + logger.AttachmentField + "=" + names[0]
+
+	if strings.Count(text, want) != 2 {
+		t.Errorf(attachmentMissingFmt, want, text)
+	}
+
+	stored := readOutputsFile(t, filepath.Join(dir, logger.AttachmentsDir, names[0]))
+	if stored != attachmentData {
+		t.Errorf(attachmentDataFmt, names[0], stored, attachmentData)
+	}
+}
+
+func TestLogger_WithAttachmentErrors(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		want error
+		name string
+		data []byte
+	}{
+		{want: logger.ErrAttachmentTooLarge, name: attachmentName, data: make([]byte, logger.MaxAttachmentSize+1)},
+		{want: logger.ErrFilenameContainsInvalid, name: attachmentBadName, data: []byte(attachmentData)},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.want.Error(), func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			loggerInstance := newAttachmentLogger(t, dir)
+			loggerInstance.WithAttachment(testCase.name, testCase.data).Errorf(attachmentMsg)
+
+			if !errors.Is(loggerInstance.Err(), testCase.want) {
+				t.Errorf(attachmentErrFmt, loggerInstance.Err(), testCase.want)
+			}
+
+			closeOutputsLogger(t, loggerInstance)
+
+			text := readOutputsFile(t, filepath.Join(dir, attachmentFile))
+			if !strings.Contains(text, attachmentMsg) || strings.Contains(text, logger.AttachmentField) {
+				t.Errorf(attachmentExtraFmt, logger.AttachmentField, text)
+			}
+		})
+	}
+}
+
+func TestLogger_WithAttachmentWithoutLogDir(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	loggerInstance := logger.NewStreamLogger(&buf)
+	loggerInstance.WithAttachment(attachmentName, []byte(attachmentData)).Errorf(attachmentMsg)
+
+	if !errors.Is(loggerInstance.Err(), logger.ErrNoLogDir) {
+		t.Errorf(attachmentErrFmt, loggerInstance.Err(), logger.ErrNoLogDir)
+	}
+
+	closeTestLogger(t, loggerInstance)
+}
+
+func TestWithAttachmentRetention(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name      string
+		retention logger.AttachmentRetention
+	}{
+		{name: "max files", retention: logger.AttachmentRetention{MaxAge: 0, MaxFiles: 2}},
+		{name: "max age", retention: logger.AttachmentRetention{MaxAge: attachmentAge + time.Minute, MaxFiles: 0}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			current := time.Date(2025, time.May, 6, 7, 8, 9, 0, time.UTC)
+
+			loggerInstance, err := logger.New(dir, attachmentFile,
+				logger.WithoutStdout(),
+				logger.WithClock(func() time.Time { return current }),
+				logger.WithAttachmentRetention(testCase.retention),
+			)
+			if err != nil {
+				t.Fatalf(attachmentNewErrFmt, err)
+			}
+
+			for _, data := range []string{attachmentData, attachmentOtherData, attachmentThirdData} {
+				loggerInstance.WithAttachment(attachmentName, []byte(data)).Errorf(attachmentMsg)
+
+				current = current.Add(attachmentAge)
+			}
+
+			closeOutputsLogger(t, loggerInstance)
+
+			names := attachmentNames(t, dir)
+			if len(names) != 2 {
+				t.Errorf(attachmentCountFmt, names, 2)
+			}
+		})
+	}
+}
+
+func TestLogger_WithAttachmentConcurrent(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	loggerInstance, err := logger.New(dir, attachmentFile,
+		logger.WithoutStdout(),
+		logger.WithAttachmentRetention(logger.AttachmentRetention{MaxAge: 0, MaxFiles: attachmentMaxFiles}),
+	)
+	if err != nil {
+		t.Fatalf(attachmentNewErrFmt, err)
+	}
+
+	var wg sync.WaitGroup
+
+	for worker := range attachmentWorkers {
+		wg.Go(func() {
+			for index := range attachmentPerWorker {
+				data := attachmentData + strconv.Itoa(worker*attachmentPerWorker+index)
+				loggerInstance.WithAttachment(attachmentName, []byte(data)).Errorf(attachmentMsg)
+				loggerInstance.Infof(attachmentMsg)
+			}
+		})
+	}
+
+	wg.Wait()
+
+	// Attachments stored and pruned concurrently, outside the logger's lock,
+	// report no error.
+	if loggerInstance.Err() != nil {
+		t.Errorf(attachmentNoErrFmt, loggerInstance.Err())
+	}
+
+	closeOutputsLogger(t, loggerInstance)
+
+	text := readOutputsFile(t, filepath.Join(dir, attachmentFile))
+	if strings.Count(text, logger.AttachmentField+"=") != attachmentWorkers*attachmentPerWorker {
+		t.Errorf(attachmentMissingFmt, logger.AttachmentField, text)
+	}
+}
+
+func newAttachmentLogger(t *testing.T, dir string) *logger.Logger {
+	t.Helper()
+
+	loggerInstance, err := logger.New(dir, attachmentFile, logger.WithoutStdout())
+	if err != nil {
+		t.Fatalf(attachmentNewErrFmt, err)
+	}
+
+	return loggerInstance
+}
+
+func attachmentNames(t *testing.T, dir string) []string {
+	t.Helper()
+
+	entries, err := os.ReadDir(filepath.Join(dir, logger.AttachmentsDir))
+	if err != nil {
+		t.Fatalf(attachmentCountFmt, err, 0)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+
+	return names
+}
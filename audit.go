@@ -0,0 +1,326 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	auditSeqMarker       = " audit_seq="
+	auditKeyMarker       = " audit_key="
+	auditMACMarker       = " audit_mac="
+	auditKeyIDChars      = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789._-"
+	auditTailSize        = 64 << 10
+	auditMaxLineSize     = 1 << 20
+	auditLineEnd         = '\n'
+	auditLineBreak       = "\n"
+	auditEscapedBreak    = `\n`
+	auditSequenceBase    = 10
+	auditSequenceBits    = 64
+	errAuditKeyEmptyMsg  = "audit key cannot be empty"
+	errAuditKeyIDMsg     = "audit key ID may only contain letters, digits, '.', '_' and '-'"
+	errAuditTamperedMsg  = "audit entry tampered"
+	errAuditMissingMsg   = "audit entry missing"
+	errAuditUnsealedMsg  = "audit entry not sealed"
+	errAuditUnknownMsg   = "unknown audit key"
+	errFmtAuditLine      = "line %d: %w"
+	errFmtAuditMissing   = "line %d: %w: expected sequence %d, found %d"
+	errFmtAuditUnknown   = "line %d: %w %q"
+	errFmtAuditResume    = "resume audit chain: %w"
+	errFmtAuditRead      = "read audit log: %w"
+	errFmtAuditWrite     = "write audit log: %w"
+	errFmtAuditKeyID     = "%w: %q"
+	errFmtAuditKeyringID = "audit key %q: %w"
+)
+
+// Predefined errors for audit mode.
+var (
+	ErrAuditKeyEmpty     = errors.New(errAuditKeyEmptyMsg)
+	ErrAuditKeyIDInvalid = errors.New(errAuditKeyIDMsg)
+	ErrAuditTampered     = errors.New(errAuditTamperedMsg)
+	ErrAuditMissing      = errors.New(errAuditMissingMsg)
+	ErrAuditUnsealed     = errors.New(errAuditUnsealedMsg)
+	ErrAuditUnknownKey   = errors.New(errAuditUnknownMsg)
+)
+
+// AuditKeyring maps audit key IDs to their secrets. It holds every key that
+// sealed part of a log, so that archives stay verifiable after keys rotate.
+// The empty ID names the key given to WithAuditKey.
+type AuditKeyring map[string][]byte
+
+// auditChain seals or verifies log file lines as an HMAC-SHA256 hash chain.
+type auditChain struct {
+	key     []byte
+	keyID   string
+	prevMAC string
+	seq     uint64
+}
+
+// auditSeal is the parsed seal of one line.
+type auditSeal struct {
+	body  string
+	keyID string
+	mac   string
+	seq   uint64
+}
+
+// WithAuditKey enables audit mode: every line written to the log file is
+// sealed with a sequence number and an HMAC-SHA256 over the line and the
+// previous line's MAC, for example
+//
+//	2025/01/02 15:04:05 [INFO] login user=alice audit_seq=7 audit_mac=3f9c...
+//
+// so that modified, reordered or deleted entries can be detected with
+// VerifyAuditLog or the logger verify command. The chain resumes from the last
+// sealed line when an existing log file is reopened. Line breaks in messages
+// are escaped in the file. Stdout and routes are not sealed.
+func WithAuditKey(key []byte) Option {
+	return WithAuditKeyID("", key)
+}
+
+// WithAuditKeyID is like WithAuditKey but records id in every seal as
+// audit_key=id. Rotating keys means reopening the log with a new ID: the chain
+// continues across the rotation and VerifyAuditLogKeyring picks each line's
+// key by its ID.
+func WithAuditKeyID(id string, key []byte) Option {
+	return func(config *options) {
+		config.auditKeyID = id
+		config.auditKey = bytes.Clone(key)
+		config.audit = true
+	}
+}
+
+// openAuditChain resumes the chain from the last sealed line of the log file.
+func openAuditChain(logPath, keyID string, key []byte) (*auditChain, error) {
+	chain, err := newAuditChain(keyID, key)
+	if err != nil {
+		return nil, err
+	}
+
+	tail, err := readFileTail(logPath, auditTailSize)
+	if err != nil {
+		return nil, fmt.Errorf(errFmtAuditResume, err)
+	}
+
+	tail = bytes.TrimRight(tail, auditLineBreak)
+	lastLine := tail[bytes.LastIndexByte(tail, auditLineEnd)+1:]
+
+	seal, ok := parseAuditSeal(string(lastLine))
+	if ok {
+		chain.seq = seal.seq
+		chain.prevMAC = seal.mac
+	}
+
+	return chain, nil
+}
+
+func newAuditChain(keyID string, key []byte) (*auditChain, error) {
+	err := validateAuditKey(keyID, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &auditChain{key: key, keyID: keyID, prevMAC: "", seq: 0}, nil
+}
+
+func validateAuditKey(keyID string, key []byte) error {
+	if len(key) == 0 {
+		return ErrAuditKeyEmpty
+	}
+
+	if strings.Trim(keyID, auditKeyIDChars) != "" {
+		return fmt.Errorf(errFmtAuditKeyID, ErrAuditKeyIDInvalid, keyID)
+	}
+
+	return nil
+}
+
+// seal appends the next sequence number, key ID and MAC to line. Line breaks
+// are escaped so that every sealed entry occupies exactly one line.
+func (chain *auditChain) seal(line string) string {
+	chain.seq++
+
+	var builder strings.Builder
+
+	builder.WriteString(strings.ReplaceAll(line, auditLineBreak, auditEscapedBreak))
+	builder.WriteString(auditSeqMarker)
+	builder.WriteString(strconv.FormatUint(chain.seq, auditSequenceBase))
+
+	if chain.keyID != "" {
+		builder.WriteString(auditKeyMarker)
+		builder.WriteString(chain.keyID)
+	}
+
+	body := builder.String()
+	chain.prevMAC = auditMAC(chain.key, chain.prevMAC, body)
+
+	return body + auditMACMarker + chain.prevMAC
+}
+
+func auditMAC(key []byte, prevMAC, body string) string {
+	hash := hmac.New(sha256.New, key)
+	_, _ = io.WriteString(hash, prevMAC)
+	_, _ = io.WriteString(hash, body)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// parseAuditSeal splits line into its sealed body and seal fields.
+func parseAuditSeal(line string) (auditSeal, bool) {
+	body, mac, ok := cutLast(line, auditMACMarker)
+	if !ok {
+		return auditSeal{}, false
+	}
+
+	_, seqText, ok := cutLast(body, auditSeqMarker)
+	if !ok {
+		return auditSeal{}, false
+	}
+
+	seqText, keyID, _ := strings.Cut(seqText, auditKeyMarker)
+
+	seq, err := strconv.ParseUint(seqText, auditSequenceBase, auditSequenceBits)
+	if err != nil {
+		return auditSeal{}, false
+	}
+
+	return auditSeal{body: body, keyID: keyID, mac: mac, seq: seq}, true
+}
+
+func cutLast(text, separator string) (string, string, bool) {
+	index := strings.LastIndex(text, separator)
+	if index < 0 {
+		return text, "", false
+	}
+
+	return text[:index], text[index+len(separator):], true
+}
+
+// VerifyAuditLog walks the hash chain of an audit-mode log file sealed with a
+// single key and returns the number of verified entries. See
+// VerifyAuditLogKeyring.
+func VerifyAuditLog(reader io.Reader, key []byte) (int, error) {
+	return VerifyAuditLogKeyring(reader, AuditKeyring{"": key})
+}
+
+// VerifyAuditLogKeyring walks the hash chain of an audit-mode log file and
+// returns the number of verified entries. Each line is checked with the key
+// named by its audit_key. The first broken link is reported with its line
+// number and wraps ErrAuditTampered for a modified or reordered entry,
+// ErrAuditMissing for a gap in the sequence, ErrAuditUnsealed for a line
+// without a seal, or ErrAuditUnknownKey for a key missing from keyring. Audit
+// mode should therefore be enabled on a new log file.
+func VerifyAuditLogKeyring(reader io.Reader, keyring AuditKeyring) (int, error) {
+	return walkAuditLog(reader, keyring, func(string) error { return nil })
+}
+
+// RewrapAuditLog verifies the audit log read from reader with keyring and
+// writes it to writer resealed with keyID and key, so that an archive whose
+// old keys are being retired stays verifiable with the new key alone. Entry
+// text and sequence numbers are preserved. It stops at the first broken link;
+// writer may then hold part of the output and should be discarded.
+func RewrapAuditLog(
+	reader io.Reader,
+	writer io.Writer,
+	keyring AuditKeyring,
+	keyID string,
+	key []byte,
+) (int, error) {
+	resealed, err := newAuditChain(keyID, key)
+	if err != nil {
+		return 0, err
+	}
+
+	output := bufio.NewWriter(writer)
+
+	count, err := walkAuditLog(reader, keyring, func(text string) error {
+		_, err := output.WriteString(resealed.seal(text) + auditLineBreak)
+
+		return err
+	})
+	if err != nil {
+		return count, err
+	}
+
+	err = output.Flush()
+	if err != nil {
+		return count, fmt.Errorf(errFmtAuditWrite, err)
+	}
+
+	return count, nil
+}
+
+// walkAuditLog verifies every line of reader and passes the entry text without
+// its seal to visit.
+func walkAuditLog(reader io.Reader, keyring AuditKeyring, visit func(text string) error) (int, error) {
+	for keyID, key := range keyring {
+		err := validateAuditKey(keyID, key)
+		if err != nil {
+			return 0, fmt.Errorf(errFmtAuditKeyringID, keyID, err)
+		}
+	}
+
+	chain := &auditChain{key: nil, keyID: "", prevMAC: "", seq: 0}
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(nil, auditMaxLineSize)
+
+	verified := 0
+
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		seal, err := chain.verify(scanner.Text(), lineNumber, keyring)
+		if err != nil {
+			return verified, err
+		}
+
+		text, _, _ := cutLast(seal.body, auditSeqMarker)
+
+		err = visit(text)
+		if err != nil {
+			return verified, fmt.Errorf(errFmtAuditWrite, err)
+		}
+
+		verified++
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return verified, fmt.Errorf(errFmtAuditRead, err)
+	}
+
+	return verified, nil
+}
+
+// verify checks that line is the next link of the chain and advances it.
+func (chain *auditChain) verify(line string, lineNumber int, keyring AuditKeyring) (auditSeal, error) {
+	seal, ok := parseAuditSeal(line)
+	if !ok {
+		return auditSeal{}, fmt.Errorf(errFmtAuditLine, lineNumber, ErrAuditUnsealed)
+	}
+
+	if seal.seq != chain.seq+1 {
+		return auditSeal{}, fmt.Errorf(errFmtAuditMissing, lineNumber, ErrAuditMissing, chain.seq+1, seal.seq)
+	}
+
+	key, ok := keyring[seal.keyID]
+	if !ok {
+		return auditSeal{}, fmt.Errorf(errFmtAuditUnknown, lineNumber, ErrAuditUnknownKey, seal.keyID)
+	}
+
+	expected := auditMAC(key, chain.prevMAC, seal.body)
+	if !hmac.Equal([]byte(expected), []byte(seal.mac)) {
+		return auditSeal{}, fmt.Errorf(errFmtAuditLine, lineNumber, ErrAuditTampered)
+	}
+
+	chain.seq = seal.seq
+	chain.prevMAC = seal.mac
+
+	return seal, nil
+}
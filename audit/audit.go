@@ -0,0 +1,143 @@
+// Package audit records structured audit events through a logger.
+//
+// Audit events describe who (actor) did what (action) to which resource
+// (target) and with which outcome. They are logged with
+// logger.Logger.LogMandatory, so level filters and sampling never suppress
+// them, and they can be forced to a dedicated sink with Route.
+package audit
+
+import (
+	"strings"
+
+	"github.com/book-expert/logger"
+)
+
+// Fields carried by every audit event.
+const (
+	ActionField  = "audit_action"
+	ActorField   = "actor"
+	TargetField  = "target"
+	OutcomeField = "outcome"
+)
+
+// Built-in actions and outcomes.
+const (
+	ActionLogin        = "login"
+	ActionConfigChange = "config_change"
+	OutcomeSuccess     = "success"
+	OutcomeFailure     = "failure"
+
+	oldValueField    = "old_value"
+	newValueField    = "new_value"
+	messagePrefix    = "audit: "
+	messageSeparator = " "
+)
+
+// Event is a single audit event. Action is required; the other fields are
+// omitted from the entry when empty. Message defaults to a summary of the
+// action, actor and target.
+type Event struct {
+	Fields  []logger.Field
+	Action  string
+	Actor   string
+	Target  string
+	Outcome string
+	Message string
+}
+
+// Login describes a login attempt by actor.
+func Login(actor string, success bool) Event {
+	outcome := OutcomeFailure
+	if success {
+		outcome = OutcomeSuccess
+	}
+
+	return Event{Fields: nil, Action: ActionLogin, Actor: actor, Target: "", Outcome: outcome, Message: ""}
+}
+
+// ConfigChange describes actor changing the configuration key from oldValue
+// to newValue.
+func ConfigChange(actor, key string, oldValue, newValue any) Event {
+	return Event{
+		Fields:  []logger.Field{logger.F(oldValueField, oldValue), logger.F(newValueField, newValue)},
+		Action:  ActionConfigChange,
+		Actor:   actor,
+		Target:  key,
+		Outcome: OutcomeSuccess,
+		Message: "",
+	}
+}
+
+// Custom describes an application-defined action with extra fields.
+func Custom(action, actor, target string, fields ...logger.Field) Event {
+	return Event{Fields: fields, Action: action, Actor: actor, Target: target, Outcome: "", Message: ""}
+}
+
+// Recorder writes audit events to a logger.
+type Recorder struct {
+	logger *logger.Logger
+	level  logger.Level
+}
+
+// Option configures a Recorder.
+type Option func(*Recorder)
+
+// WithLevel sets the level of audit entries. The default is
+// logger.LevelSystem; applications may register a dedicated AUDIT level.
+func WithLevel(level logger.Level) Option {
+	return func(recorder *Recorder) {
+		recorder.level = level
+	}
+}
+
+// NewRecorder returns a Recorder writing to l.
+func NewRecorder(l *logger.Logger, opts ...Option) *Recorder {
+	recorder := &Recorder{logger: l, level: logger.LevelSystem}
+	for _, opt := range opts {
+		opt(recorder)
+	}
+
+	return recorder
+}
+
+// Record logs event. It is never suppressed by level filters or sampling.
+func (recorder *Recorder) Record(event Event) {
+	recorder.logger.LogMandatory(recorder.level, event.message(), event.fields()...)
+}
+
+// Route returns a route copying every audit event to filename in the
+// logger's directory, for use with logger.WithRoute.
+func Route(filename string) logger.Route {
+	return logger.Route{Field: ActionField, Value: logger.RouteAnyValue, Filename: filename}
+}
+
+func (event *Event) fields() []logger.Field {
+	fields := []logger.Field{logger.F(ActionField, event.Action)}
+
+	for _, field := range []logger.Field{
+		logger.F(ActorField, event.Actor),
+		logger.F(TargetField, event.Target),
+		logger.F(OutcomeField, event.Outcome),
+	} {
+		if field.Value != "" {
+			fields = append(fields, field)
+		}
+	}
+
+	return append(fields, event.Fields...)
+}
+
+func (event *Event) message() string {
+	if event.Message != "" {
+		return event.Message
+	}
+
+	parts := []string{event.Action}
+	for _, part := range []string{event.Actor, event.Target, event.Outcome} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	return messagePrefix + strings.Join(parts, messageSeparator)
+}
@@ -0,0 +1,77 @@
+package audit_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/book-expert/logger"
+	"github.com/book-expert/logger/audit"
+)
+
+const (
+	testActor     = "alice"
+	testKey       = "max_connections"
+	testAuditFile = "audit.log"
+	testMainFile  = "app.log"
+	testAction    = "export"
+	testTarget    = "books.csv"
+	testRows      = "rows"
+	loginLine     = "[SYSTEM] audit: login alice failure audit_action=login actor=alice outcome=failure"
+	configLine    = "audit_action=config_change actor=alice target=max_connections " +
+		"outcome=success old_value=10 new_value=20"
+	customLine      = "audit: export alice books.csv audit_action=export actor=alice target=books.csv rows=3"
+	routedLine      = "[WARN] audit: login alice success"
+	outputErrFmt    = "expected %q in output, got %q"
+	newLoggerErrFmt = "New: %v"
+	readErrFmt      = "read audit log: %v"
+)
+
+func TestRecorder_TypedEvents(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	recorder := audit.NewRecorder(logger.NewStreamLogger(&buf))
+	recorder.Record(audit.Login(testActor, false))
+	recorder.Record(audit.ConfigChange(testActor, testKey, 10, 20))
+	recorder.Record(audit.Custom(testAction, testActor, testTarget, logger.F(testRows, 3)))
+
+	for _, want := range []string{loginLine, configLine, customLine} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf(outputErrFmt, want, buf.String())
+		}
+	}
+}
+
+func TestRoute_DedicatedSink(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	loggerInstance, err := logger.New(dir, testMainFile, logger.WithRoute(audit.Route(testAuditFile)))
+	if err != nil {
+		t.Fatalf(newLoggerErrFmt, err)
+	}
+
+	loggerInstance.Infof(testAction)
+	audit.NewRecorder(loggerInstance, audit.WithLevel(logger.LevelWarn)).Record(audit.Login(testActor, true))
+
+	err = loggerInstance.Close()
+	if err != nil {
+		t.Fatalf(newLoggerErrFmt, err)
+	}
+
+	// #nosec G304
+	content, err := os.ReadFile(filepath.Join(dir, testAuditFile))
+	if err != nil {
+		t.Fatalf(readErrFmt, err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if len(lines) != 1 || !strings.Contains(lines[0], routedLine) {
+		t.Errorf(outputErrFmt, routedLine, string(content))
+	}
+}
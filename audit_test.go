@@ -0,0 +1,256 @@
+package logger_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	auditLogFile       = "audit.log"
+	auditMsgLogin      = "login user=%s"
+	auditMsgMultiline  = "first\nsecond"
+	auditUser          = "alice"
+	auditTamperedUser  = "mallory"
+	auditKey           = "test-key"
+	auditWrongKey      = "other-key"
+	auditLineSeparator = "\n"
+	auditVerifiedFmt   = "expected %d verified entries, got %d"
+	auditErrLineFmt    = "expected error on line %d, got %v"
+	auditLinePrefixFmt = "line %d:"
+	auditKeyIDOld      = "2024"
+	auditKeyIDNew      = "2025"
+	auditKeyIDBad      = "a b"
+	auditNewKey        = "new-key"
+	auditKeyIDMarker   = "audit_key=" + auditKeyIDNew
+	auditRewrapLineFmt = "expected %q in resealed line, got %q"
+)
+
+func writeAuditLog(t *testing.T, dir string, entries int) []string {
+	t.Helper()
+
+	loggerInstance, err := logger.New(dir, auditLogFile, logger.WithAuditKey([]byte(auditKey)))
+	if err != nil {
+		t.Fatalf(newLoggerError, err)
+	}
+
+	for range entries {
+		loggerInstance.Infof(auditMsgLogin, auditUser)
+	}
+
+	closeTestLogger(t, loggerInstance)
+
+	return readAuditLines(t, dir)
+}
+
+func writeRotatedAuditLog(t *testing.T, dir string) []string {
+	t.Helper()
+
+	for _, keyID := range []string{auditKeyIDOld, auditKeyIDNew} {
+		loggerInstance, err := logger.New(dir, auditLogFile,
+			logger.WithAuditKeyID(keyID, []byte(keyID+auditKey)))
+		if err != nil {
+			t.Fatalf(newLoggerError, err)
+		}
+
+		loggerInstance.Infof(auditMsgLogin, auditUser)
+		closeTestLogger(t, loggerInstance)
+	}
+
+	return readAuditLines(t, dir)
+}
+
+func rotatedKeyring() logger.AuditKeyring {
+	return logger.AuditKeyring{
+		auditKeyIDOld: []byte(auditKeyIDOld + auditKey),
+		auditKeyIDNew: []byte(auditKeyIDNew + auditKey),
+	}
+}
+
+func readAuditLines(t *testing.T, dir string) []string {
+	t.Helper()
+
+	// #nosec G304
+	content, err := os.ReadFile(filepath.Join(dir, auditLogFile))
+	if err != nil {
+		t.Fatalf(readLogFileErr, err)
+	}
+
+	return strings.Split(strings.TrimSuffix(string(content), auditLineSeparator), auditLineSeparator)
+}
+
+func verifyAuditLines(lines []string, key string) (int, error) {
+	reader := strings.NewReader(strings.Join(lines, auditLineSeparator) + auditLineSeparator)
+
+	return logger.VerifyAuditLog(reader, []byte(key))
+}
+
+func expectAuditError(t *testing.T, err, target error, line int) {
+	t.Helper()
+
+	if !errors.Is(err, target) {
+		t.Fatalf(expectedErrFmt, target, err)
+	}
+
+	if !strings.HasPrefix(err.Error(), fmt.Sprintf(auditLinePrefixFmt, line)) {
+		t.Errorf(auditErrLineFmt, line, err)
+	}
+}
+
+func TestAudit_ChainVerifies(t *testing.T) {
+	t.Parallel()
+
+	lines := writeAuditLog(t, t.TempDir(), 3)
+
+	verified, err := verifyAuditLines(lines, auditKey)
+	if err != nil {
+		t.Fatalf(unexpectedErrFmt, err)
+	}
+
+	if verified != len(lines) {
+		t.Errorf(auditVerifiedFmt, len(lines), verified)
+	}
+}
+
+func TestAudit_DetectsTamperedEntry(t *testing.T) {
+	t.Parallel()
+
+	lines := writeAuditLog(t, t.TempDir(), 3)
+	lines[1] = strings.Replace(lines[1], auditUser, auditTamperedUser, 1)
+
+	verified, err := verifyAuditLines(lines, auditKey)
+	expectAuditError(t, err, logger.ErrAuditTampered, 2)
+
+	if verified != 1 {
+		t.Errorf(auditVerifiedFmt, 1, verified)
+	}
+}
+
+func TestAudit_DetectsMissingEntry(t *testing.T) {
+	t.Parallel()
+
+	lines := writeAuditLog(t, t.TempDir(), 3)
+
+	_, err := verifyAuditLines([]string{lines[0], lines[2]}, auditKey)
+	expectAuditError(t, err, logger.ErrAuditMissing, 2)
+}
+
+func TestAudit_WrongKeyFailsFirstLine(t *testing.T) {
+	t.Parallel()
+
+	lines := writeAuditLog(t, t.TempDir(), 2)
+
+	_, err := verifyAuditLines(lines, auditWrongKey)
+	expectAuditError(t, err, logger.ErrAuditTampered, 1)
+}
+
+func TestAudit_ResumesChainOnReopen(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeAuditLog(t, dir, 2)
+	lines := writeAuditLog(t, dir, 2)
+
+	verified, err := verifyAuditLines(lines, auditKey)
+	if err != nil {
+		t.Fatalf(unexpectedErrFmt, err)
+	}
+
+	if verified != len(lines) {
+		t.Errorf(auditVerifiedFmt, len(lines), verified)
+	}
+}
+
+func TestAudit_EscapesLineBreaks(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	loggerInstance, err := logger.New(dir, auditLogFile, logger.WithAuditKey([]byte(auditKey)))
+	if err != nil {
+		t.Fatalf(newLoggerError, err)
+	}
+
+	loggerInstance.Infof(auditMsgMultiline)
+	closeTestLogger(t, loggerInstance)
+
+	lines := readAuditLines(t, dir)
+
+	_, err = verifyAuditLines(lines, auditKey)
+	if err != nil || len(lines) != 1 {
+		t.Errorf(unexpectedErrFmt, err)
+	}
+}
+
+func TestAudit_EmptyKeyRejected(t *testing.T) {
+	t.Parallel()
+
+	for _, key := range [][]byte{nil, {}} {
+		_, err := logger.New(t.TempDir(), auditLogFile, logger.WithAuditKey(key))
+		if !errors.Is(err, logger.ErrAuditKeyEmpty) {
+			t.Errorf(expectedErrFmt, logger.ErrAuditKeyEmpty, err)
+		}
+	}
+}
+
+func TestAudit_KeyringVerifiesAcrossRotation(t *testing.T) {
+	t.Parallel()
+
+	lines := writeRotatedAuditLog(t, t.TempDir())
+	reader := strings.NewReader(strings.Join(lines, auditLineSeparator))
+
+	verified, err := logger.VerifyAuditLogKeyring(reader, rotatedKeyring())
+	if err != nil {
+		t.Fatalf(unexpectedErrFmt, err)
+	}
+
+	if verified != len(lines) {
+		t.Errorf(auditVerifiedFmt, len(lines), verified)
+	}
+
+	keyring := rotatedKeyring()
+	delete(keyring, auditKeyIDOld)
+
+	_, err = logger.VerifyAuditLogKeyring(strings.NewReader(lines[0]), keyring)
+	expectAuditError(t, err, logger.ErrAuditUnknownKey, 1)
+}
+
+func TestAudit_RewrapReseals(t *testing.T) {
+	t.Parallel()
+
+	lines := writeRotatedAuditLog(t, t.TempDir())
+
+	var resealed strings.Builder
+
+	count, err := logger.RewrapAuditLog(strings.NewReader(strings.Join(lines, auditLineSeparator)),
+		&resealed, rotatedKeyring(), auditKeyIDNew, []byte(auditNewKey))
+	if err != nil || count != len(lines) {
+		t.Fatalf(unexpectedErrFmt, err)
+	}
+
+	for line := range strings.SplitSeq(strings.TrimSpace(resealed.String()), auditLineSeparator) {
+		if !strings.Contains(line, auditKeyIDMarker) {
+			t.Errorf(auditRewrapLineFmt, auditKeyIDMarker, line)
+		}
+	}
+
+	verified, err := logger.VerifyAuditLogKeyring(strings.NewReader(resealed.String()),
+		logger.AuditKeyring{auditKeyIDNew: []byte(auditNewKey)})
+	if err != nil || verified != len(lines) {
+		t.Errorf(auditVerifiedFmt, len(lines), verified)
+	}
+}
+
+func TestAudit_InvalidKeyIDRejected(t *testing.T) {
+	t.Parallel()
+
+	_, err := logger.New(t.TempDir(), auditLogFile, logger.WithAuditKeyID(auditKeyIDBad, []byte(auditKey)))
+	if !errors.Is(err, logger.ErrAuditKeyIDInvalid) {
+		t.Errorf(expectedErrFmt, logger.ErrAuditKeyIDInvalid, err)
+	}
+}
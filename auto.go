@@ -0,0 +1,56 @@
+package logger
+
+import (
+	"os"
+	"slices"
+)
+
+const (
+	// AutoModeEnv overrides the environment detection of NewAuto. Its values
+	// are AutoModeContainer and AutoModeHost.
+	AutoModeEnv       = "LOGGER_MODE"
+	AutoModeContainer = "container"
+	AutoModeHost      = "host"
+
+	kubernetesHostEnv = "KUBERNETES_SERVICE_HOST"
+	dockerEnvFile     = "/.dockerenv"
+	podmanEnvFile     = "/run/.containerenv"
+)
+
+// NewAuto creates a logger suited to the environment it runs in. In a
+// container, as reported by InContainer, it writes JSON lines to stdout
+// without a log file, as with WithoutFile, leaving collection to the container
+// runtime. On a host it behaves like New. Options apply after the defaults, so
+// WithEncoding(EncodingText) keeps text lines in a container.
+func NewAuto(logDir, filename string, opts ...Option) (*Logger, error) {
+	if InContainer() {
+		opts = append([]Option{WithEncoding(EncodingJSON), WithoutFile()}, slices.Clip(opts)...)
+	}
+
+	return New(logDir, filename, opts...)
+}
+
+// InContainer reports whether the process appears to run in a container:
+// AutoModeEnv is set to AutoModeContainer, KUBERNETES_SERVICE_HOST is set, a
+// Docker or Podman marker file exists, or stdout is not a terminal. Setting
+// AutoModeEnv to AutoModeHost disables detection.
+func InContainer() bool {
+	switch os.Getenv(AutoModeEnv) {
+	case AutoModeContainer:
+		return true
+	case AutoModeHost:
+		return false
+	}
+
+	if os.Getenv(kubernetesHostEnv) != "" || fileExists(dockerEnvFile) || fileExists(podmanEnvFile) {
+		return true
+	}
+
+	return !isTerminal(os.Stdout)
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+
+	return err == nil
+}
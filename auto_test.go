@@ -0,0 +1,90 @@
+package logger_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	autoLogFile      = "auto.log"
+	autoLogSubdir    = "logs"
+	autoMessage      = "auto mode"
+	autoDetectFmt    = "InContainer() = %v with %s=%s"
+	autoStatFmt      = "stat %s: %v"
+	autoCloseFmt     = "close: %v"
+	autoNewFmt       = "NewAuto: %v"
+	autoFileEmptyMsg = "host mode should write the log file"
+)
+
+// The tests below set environment variables and therefore do not run in
+// parallel.
+
+func TestInContainer_Override(t *testing.T) {
+	t.Setenv(logger.AutoModeEnv, logger.AutoModeContainer)
+
+	if !logger.InContainer() {
+		t.Errorf(autoDetectFmt, false, logger.AutoModeEnv, logger.AutoModeContainer)
+	}
+
+	t.Setenv(logger.AutoModeEnv, logger.AutoModeHost)
+
+	if logger.InContainer() {
+		t.Errorf(autoDetectFmt, true, logger.AutoModeEnv, logger.AutoModeHost)
+	}
+}
+
+func TestNewAuto_ContainerSkipsLogFile(t *testing.T) {
+	t.Setenv(logger.AutoModeEnv, logger.AutoModeContainer)
+
+	logDir := filepath.Join(t.TempDir(), autoLogSubdir)
+	loggerInstance := newAutoLogger(t, logDir)
+	loggerInstance.Infof(autoMessage)
+	closeAutoLogger(t, loggerInstance)
+
+	_, err := os.Stat(logDir)
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf(autoStatFmt, logDir, err)
+	}
+}
+
+func TestNewAuto_HostWritesLogFile(t *testing.T) {
+	t.Setenv(logger.AutoModeEnv, logger.AutoModeHost)
+
+	logDir := t.TempDir()
+	loggerInstance := newAutoLogger(t, logDir)
+	loggerInstance.Infof(autoMessage)
+	closeAutoLogger(t, loggerInstance)
+
+	info, err := os.Stat(filepath.Join(logDir, autoLogFile))
+	if err != nil {
+		t.Fatalf(autoStatFmt, autoLogFile, err)
+	}
+
+	if info.Size() == 0 {
+		t.Error(autoFileEmptyMsg)
+	}
+}
+
+func newAutoLogger(t *testing.T, logDir string) *logger.Logger {
+	t.Helper()
+
+	loggerInstance, err := logger.NewAuto(logDir, autoLogFile)
+	if err != nil {
+		t.Fatalf(autoNewFmt, err)
+	}
+
+	return loggerInstance
+}
+
+func closeAutoLogger(t *testing.T, loggerInstance *logger.Logger) {
+	t.Helper()
+
+	err := loggerInstance.Close()
+	if err != nil {
+		t.Errorf(autoCloseFmt, err)
+	}
+}
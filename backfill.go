@@ -0,0 +1,152 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// OutOfOrderField marks ingested entries that WithBackfill found older
+	// than the newest entry written.
+	OutOfOrderField = "out_of_order"
+
+	errFmtOpenBackfill   = "open backfill file %q: %w"
+	errFmtWriteBackfill  = "write backfill file: %w"
+	errFmtCloseBackfill  = "close backfill file: %w"
+	metricOutOfOrderHelp = "# HELP logger_out_of_order_entries_total Ingested entries older than the newest entry " +
+		"written by more than the backfill threshold.\n"
+	metricOutOfOrderType = "# TYPE logger_out_of_order_entries_total counter\n"
+	metricOutOfOrderFmt  = "logger_out_of_order_entries_total %d\n"
+)
+
+// Backfill configures WithBackfill: entries older than the newest entry
+// written by more than Threshold are out of order, and written to Filename
+// in the log directory when it is set.
+type Backfill struct {
+	Filename  string
+	Threshold time.Duration
+}
+
+// backfillState is the write position and backfill file of WithBackfill. It
+// is guarded by loggerCore.mu.
+type backfillState struct {
+	newest    time.Time
+	file      *os.File
+	filename  string
+	threshold time.Duration
+	count     uint64
+}
+
+// WithBackfill detects entries ingested from timestamped input, by an
+// IngestSink or the Docker and Kubernetes collectors, that are older than the
+// newest entry written by more than backfill.Threshold, such as the lines of
+// an old file shipped late. They carry OutOfOrderField=true and are counted in
+// Stats().OutOfOrderEntries. When backfill.Filename is set, they are written
+// to that file in the log directory instead of the log file, stdout and
+// routes, which keeps the primary files in time order for queries; loggers
+// without a log directory only tag them. A non-positive Threshold disables
+// the detection.
+func WithBackfill(backfill Backfill) Option {
+	return func(config *options) {
+		config.backfill = backfill
+	}
+}
+
+func newBackfillState(backfill Backfill) backfillState {
+	return backfillState{
+		newest:    time.Time{},
+		file:      nil,
+		filename:  backfill.Filename,
+		threshold: backfill.Threshold,
+		count:     0,
+	}
+}
+
+// openBackfillFile opens the backfill file under logDir, if one is set.
+func (c *loggerCore) openBackfillFile(logDir string) error {
+	if c.backfill.threshold <= 0 || c.backfill.filename == "" {
+		return nil
+	}
+
+	err := ValidateFilename(c.backfill.filename)
+	if err != nil {
+		return fmt.Errorf(errFmtOpenBackfill, c.backfill.filename, err)
+	}
+
+	backfillPath, err := setupAndValidatePath(logDir, c.backfill.filename)
+	if err != nil {
+		return fmt.Errorf(errFmtOpenBackfill, c.backfill.filename, err)
+	}
+
+	c.backfill.file, err = openLogFile(backfillPath)
+	if err != nil {
+		return fmt.Errorf(errFmtOpenBackfill, c.backfill.filename, err)
+	}
+
+	return nil
+}
+
+// checkOrder tags an ingested entry that is older than the newest entry
+// written by more than the threshold and reports whether it did; any other
+// entry advances the newest time. The caller holds mu.
+func (c *loggerCore) checkOrder(logEntry *Entry) bool {
+	if c.backfill.threshold <= 0 {
+		return false
+	}
+
+	if logEntry.ingested && logEntry.Time.Before(c.backfill.newest.Add(-c.backfill.threshold)) {
+		logEntry.Fields = append(slices.Clip(logEntry.Fields), F(OutOfOrderField, true))
+		c.backfill.count++
+
+		return true
+	}
+
+	if logEntry.Time.After(c.backfill.newest) {
+		c.backfill.newest = logEntry.Time
+	}
+
+	return false
+}
+
+// writeBackfill writes the line of an out-of-order entry to the backfill
+// file. The caller holds mu.
+func (c *loggerCore) writeBackfill(msg string) error {
+	_, err := c.backfill.file.WriteString(msg + string(lineEnd))
+	if err != nil {
+		return fmt.Errorf(errFmtWriteBackfill, err)
+	}
+
+	return nil
+}
+
+// closeBackfillFile closes the backfill file. The caller holds mu.
+func (c *loggerCore) closeBackfillFile() error {
+	if c.backfill.file == nil {
+		return nil
+	}
+
+	err := c.backfill.file.Close()
+	c.backfill.file = nil
+
+	if err != nil {
+		return fmt.Errorf(errFmtCloseBackfill, err)
+	}
+
+	return nil
+}
+
+func (c *loggerCore) outOfOrderCount() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.backfill.count
+}
+
+func writeOutOfOrderMetrics(builder *strings.Builder, count uint64) {
+	builder.WriteString(metricOutOfOrderHelp)
+	builder.WriteString(metricOutOfOrderType)
+	fmt.Fprintf(builder, metricOutOfOrderFmt, count)
+}
@@ -0,0 +1,103 @@
+package logger_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	backfillLogFile  = "app.log"
+	backfillFile     = "backfill.log"
+	backfillNewLine  = "2025-01-02T10:00:00Z ERROR newest"
+	backfillOldLine  = "2025-01-02T08:00:00Z WARN shipped late"
+	backfillNearLine = "2025-01-02T09:30:00Z INFO within threshold"
+	backfillLines    = backfillNewLine + "\n" + backfillOldLine + "\n" + backfillNearLine + "\n"
+	backfillOldMsg   = "shipped late"
+	backfillNearMsg  = "within threshold"
+	backfillTag      = "out_of_order=true"
+	backfillLayout   = "[{level}] {msg} {fields}"
+	backfillHoldsFmt = "%s holds %q:\n%s"
+	backfillLacksFmt = "%s lacks %q:\n%s"
+	backfillCountFmt = "OutOfOrderEntries = %d; want 1"
+)
+
+func TestWithBackfill_Tags(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	loggerInstance := logger.NewStreamLogger(&buf,
+		logger.WithLayout(logger.MustParseLayout(backfillLayout)),
+		logger.WithBackfill(logger.Backfill{Filename: "", Threshold: time.Hour}),
+	)
+
+	_, err := loggerInstance.IngestSink(newLineParser(t, "", "")).Write([]byte(backfillLines))
+	if err != nil {
+		t.Fatalf(ingestWriteFmt, err)
+	}
+
+	stats := loggerInstance.Stats()
+	closeTestLogger(t, loggerInstance)
+
+	output := buf.String()
+	if !strings.Contains(output, "[WARN] "+backfillOldMsg+" "+backfillTag) {
+		t.Errorf(backfillLacksFmt, "output", backfillTag, output)
+	}
+
+	if strings.Count(output, backfillTag) != 1 {
+		t.Errorf(backfillHoldsFmt, "output", backfillNearMsg+" "+backfillTag, output)
+	}
+
+	if stats.OutOfOrderEntries != 1 {
+		t.Errorf(backfillCountFmt, stats.OutOfOrderEntries)
+	}
+}
+
+func TestWithBackfill_Diverts(t *testing.T) {
+	t.Parallel()
+
+	logDir := t.TempDir()
+
+	loggerInstance, err := logger.New(logDir, backfillLogFile,
+		logger.WithoutStdout(),
+		logger.WithBackfill(logger.Backfill{Filename: backfillFile, Threshold: time.Hour}),
+	)
+	if err != nil {
+		t.Fatalf(newLoggerError, err)
+	}
+
+	_, err = loggerInstance.IngestSink(newLineParser(t, "", "")).Write([]byte(backfillLines))
+	if err != nil {
+		t.Fatalf(ingestWriteFmt, err)
+	}
+
+	closeTestLogger(t, loggerInstance)
+
+	primary := readBackfillFile(t, logDir, backfillLogFile)
+	if strings.Contains(primary, backfillOldMsg) || !strings.Contains(primary, backfillNearMsg) {
+		t.Errorf(backfillHoldsFmt, backfillLogFile, backfillOldMsg, primary)
+	}
+
+	backfill := readBackfillFile(t, logDir, backfillFile)
+	if !strings.Contains(backfill, backfillOldMsg) || !strings.Contains(backfill, backfillTag) {
+		t.Errorf(backfillLacksFmt, backfillFile, backfillOldMsg, backfill)
+	}
+}
+
+func readBackfillFile(t *testing.T, dir, name string) string {
+	t.Helper()
+
+	// #nosec G304 -- test files under t.TempDir.
+	content, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatalf(readLogFileErr, err)
+	}
+
+	return string(content)
+}
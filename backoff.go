@@ -0,0 +1,133 @@
+package logger
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// BackoffKeyField carries the key given to Backoff.
+	BackoffKeyField = "backoff_key"
+	// FailuresField carries the number of failures reported under a backoff key.
+	FailuresField = "failures"
+
+	backoffInitial      = time.Second
+	backoffMax          = 5 * time.Minute
+	backoffRecoveredFmt = "recovered after %d failures"
+)
+
+// Backoff logs the failures of a retry loop exponentially less often. It is
+// returned by Logger.Backoff.
+type Backoff struct {
+	logger *Logger
+	state  *backoffState
+	key    string
+}
+
+// backoffState tracks the failures reported under one key.
+type backoffState struct {
+	next       time.Time
+	mu         sync.Mutex
+	interval   time.Duration
+	failures   uint64
+	suppressed uint64
+}
+
+// Backoff returns the backoff logger for key, for repeated identical failures
+// such as a retry loop that cannot reach a server. The first failure is
+// logged immediately and the following ones after 1s, 2s, 4s and so on, up to
+// one entry every five minutes; logged entries carry the key, the number of
+// failures so far and the number suppressed since the previous entry. Calling
+// Recovered once the operation succeeds logs how many failures preceded it and
+// resets the backoff. The state of each key is shared by the logger and its
+// child loggers.
+func (l *Logger) Backoff(key string) *Backoff {
+	state, ok := l.core.backoffs.Load(key)
+	if !ok {
+		state, _ = l.core.backoffs.LoadOrStore(key, &backoffState{
+			next:       time.Time{},
+			mu:         sync.Mutex{},
+			interval:   0,
+			failures:   0,
+			suppressed: 0,
+		})
+	}
+
+	backoffState, _ := state.(*backoffState)
+
+	return &Backoff{logger: l, state: backoffState, key: key}
+}
+
+// Warnf reports a failure at WARN, logged when the backoff interval allows.
+func (b *Backoff) Warnf(format string, args ...any) {
+	b.failf(LevelWarn, format, args...)
+}
+
+// Errorf reports a failure at ERROR, logged when the backoff interval allows.
+func (b *Backoff) Errorf(format string, args ...any) {
+	b.failf(LevelError, format, args...)
+}
+
+// Recovered logs an INFO entry with the number of failures reported since the
+// last recovery and resets the backoff. It logs nothing when no failure was
+// reported.
+func (b *Backoff) Recovered() {
+	b.state.mu.Lock()
+	failures := b.state.failures
+	b.state.reset()
+	b.state.mu.Unlock()
+
+	if failures == 0 {
+		return
+	}
+
+	b.logger.writeEntryf(LevelInfo, []Field{
+		F(BackoffKeyField, b.key),
+		F(FailuresField, failures),
+	}, b.logger.resolveDirectCaller(), targetAll, backoffRecoveredFmt, failures)
+}
+
+func (b *Backoff) failf(level Level, format string, args ...any) {
+	now := time.Now()
+
+	b.state.mu.Lock()
+
+	b.state.failures++
+	if now.Before(b.state.next) {
+		b.state.suppressed++
+		b.state.mu.Unlock()
+
+		return
+	}
+
+	fields := []Field{F(BackoffKeyField, b.key), F(FailuresField, b.state.failures)}
+	if b.state.suppressed > 0 {
+		fields = append(fields, F(SuppressedField, b.state.suppressed))
+	}
+
+	b.state.advance(now)
+	b.state.mu.Unlock()
+
+	b.logger.writeEntryf(level, fields, b.logger.resolveCaller(), targetAll, format, args...)
+}
+
+// advance schedules the next logged failure, doubling the interval up to
+// backoffMax. The caller holds state.mu.
+func (state *backoffState) advance(now time.Time) {
+	if state.interval == 0 {
+		state.interval = backoffInitial
+	} else {
+		state.interval = min(state.interval*2, backoffMax)
+	}
+
+	state.next = now.Add(state.interval)
+	state.suppressed = 0
+}
+
+// reset forgets every failure. The caller holds state.mu.
+func (state *backoffState) reset() {
+	state.next = time.Time{}
+	state.interval = 0
+	state.failures = 0
+	state.suppressed = 0
+}
@@ -0,0 +1,103 @@
+package logger_test
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	backoffKey          = "db-connect"
+	backoffMsg          = "connect failed: %s"
+	backoffCause        = "connection refused"
+	backoffFailures     = 5
+	backoffFirstFields  = "backoff_key=db-connect failures=1"
+	backoffRecoveredMsg = "recovered after 5 failures"
+	backoffCountFmt     = "expected %d entries, got %d: %q"
+	backoffMissingFmt   = "expected %q in %q"
+	backoffCallerLayout = "{caller} {msg}"
+	backoffCallerFmt    = "backoff_test.go:%d "
+)
+
+func TestBackoff_SuppressesRepeatsAndReportsRecovery(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	loggerInstance := logger.NewStreamLogger(&buf)
+
+	for range backoffFailures {
+		loggerInstance.Backoff(backoffKey).Errorf(backoffMsg, backoffCause)
+	}
+
+	loggerInstance.Backoff(backoffKey).Recovered()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf(backoffCountFmt, 2, len(lines), lines)
+	}
+
+	if !strings.Contains(lines[0], backoffFirstFields) {
+		t.Errorf(backoffMissingFmt, backoffFirstFields, lines[0])
+	}
+
+	if !strings.Contains(lines[1], backoffRecoveredMsg) {
+		t.Errorf(backoffMissingFmt, backoffRecoveredMsg, lines[1])
+	}
+}
+
+func TestBackoff_RecoveredResetsState(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	loggerInstance := logger.NewStreamLogger(&buf)
+	backoff := loggerInstance.Backoff(backoffKey)
+
+	// Without failures Recovered logs nothing.
+	backoff.Recovered()
+
+	backoff.Warnf(backoffMsg, backoffCause)
+	backoff.Recovered()
+	backoff.Warnf(backoffMsg, backoffCause)
+
+	const want = 3
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != want {
+		t.Fatalf(backoffCountFmt, want, len(lines), lines)
+	}
+
+	if !strings.Contains(lines[2], backoffFirstFields) {
+		t.Errorf(backoffMissingFmt, backoffFirstFields, lines[2])
+	}
+}
+
+func TestBackoff_RecoveredReportsCaller(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	loggerInstance := logger.NewStreamLogger(&buf, logger.WithLayout(logger.MustParseLayout(backoffCallerLayout)))
+	backoff := loggerInstance.Backoff(backoffKey)
+
+	_, _, line, _ := runtime.Caller(0)
+	backoff.Errorf(backoffMsg, backoffCause)
+	backoff.Recovered()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf(backoffCountFmt, 2, len(lines), lines)
+	}
+
+	for offset, text := range lines {
+		want := fmt.Sprintf(backoffCallerFmt, line+1+offset)
+		if !strings.HasPrefix(text, want) {
+			t.Errorf(backoffMissingFmt, want, text)
+		}
+	}
+}
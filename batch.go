@@ -0,0 +1,189 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	errBackPressureMsg       = "sink batch full"
+	errFmtFlushBatch         = "flush batch: %w"
+	dropReasonBackPressure   = "sink back-pressure"
+	defaultBatchFlushTimeout = time.Second
+)
+
+// ErrBackPressure is returned by BatchSink writes while the batch is full
+// and waiting to be delivered. Routes count such entries as dropped rather
+// than failed.
+var ErrBackPressure = errors.New(errBackPressureMsg)
+
+// BatchSink is a Sink decorator that collects lines and delivers them to the
+// inner sink in one Write, for remote destinations that charge per request.
+// A batch is delivered once it holds maxEntries lines or maxBytes bytes, and
+// at least every flushInterval, by a background goroutine. Writes fill the
+// next batch during a delivery; once that one is full as well, writes fail
+// with ErrBackPressure instead of blocking the logger. A failed delivery
+// discards the batch and its error is returned by the next Write, Flush or
+// Close. Close delivers the pending batch and closes the inner sink.
+type BatchSink struct {
+	inner      Sink
+	err        error
+	wake       chan struct{}
+	done       chan struct{}
+	pending    []byte
+	spare      []byte
+	entries    int
+	maxEntries int
+	maxBytes   int
+	wg         sync.WaitGroup
+	mu         sync.Mutex
+	deliver    sync.Mutex
+	closed     bool
+}
+
+// NewBatchSink wraps inner in a batching decorator and starts its flusher.
+// Non-positive limits are treated as one entry, one byte and one second.
+func NewBatchSink(inner Sink, maxEntries, maxBytes int, flushInterval time.Duration) *BatchSink {
+	batch := &BatchSink{
+		inner:      inner,
+		err:        nil,
+		wake:       make(chan struct{}, 1),
+		done:       make(chan struct{}),
+		pending:    nil,
+		spare:      nil,
+		entries:    0,
+		maxEntries: max(maxEntries, 1),
+		maxBytes:   max(maxBytes, 1),
+		wg:         sync.WaitGroup{},
+		mu:         sync.Mutex{},
+		deliver:    sync.Mutex{},
+		closed:     false,
+	}
+
+	if flushInterval <= 0 {
+		flushInterval = defaultBatchFlushTimeout
+	}
+
+	batch.wg.Go(func() { batch.run(flushInterval) })
+
+	return batch
+}
+
+// Write adds data to the current batch.
+func (batch *BatchSink) Write(data []byte) (int, error) {
+	batch.mu.Lock()
+	defer batch.mu.Unlock()
+
+	if batch.closed {
+		return 0, ErrLoggerClosed
+	}
+
+	if batch.full(len(data)) {
+		return 0, ErrBackPressure
+	}
+
+	err := batch.err
+	batch.err = nil
+	batch.pending = append(batch.pending, data...)
+	batch.entries++
+
+	if batch.full(0) {
+		select {
+		case batch.wake <- struct{}{}:
+		default:
+		}
+	}
+
+	return len(data), err
+}
+
+// Flush delivers the pending batch and returns the first delivery error since
+// the previous call.
+func (batch *BatchSink) Flush() error {
+	batch.flush()
+
+	batch.mu.Lock()
+	defer batch.mu.Unlock()
+
+	err := batch.err
+	batch.err = nil
+
+	return err
+}
+
+// Close stops the flusher, delivers the pending batch and closes the inner
+// sink.
+func (batch *BatchSink) Close() error {
+	batch.mu.Lock()
+	if batch.closed {
+		batch.mu.Unlock()
+
+		return nil
+	}
+
+	batch.closed = true
+	batch.mu.Unlock()
+
+	close(batch.done)
+	batch.wg.Wait()
+
+	return errors.Join(batch.Flush(), batch.inner.Close())
+}
+
+// full reports whether a line of size bytes no longer fits the batch; a size
+// of zero asks whether the batch has reached its limits. The caller holds
+// batch.mu.
+func (batch *BatchSink) full(size int) bool {
+	if batch.entries >= batch.maxEntries {
+		return true
+	}
+
+	return batch.entries > 0 && len(batch.pending)+size > batch.maxBytes
+}
+
+func (batch *BatchSink) run(flushInterval time.Duration) {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-batch.done:
+			return
+		case <-batch.wake:
+		case <-ticker.C:
+		}
+
+		batch.flush()
+	}
+}
+
+// flush takes the pending batch and writes it to the inner sink. Writes
+// meanwhile fill the next batch, whose buffer is recycled from the previous
+// delivery.
+func (batch *BatchSink) flush() {
+	batch.deliver.Lock()
+	defer batch.deliver.Unlock()
+
+	batch.mu.Lock()
+	data := batch.pending
+	batch.pending = batch.spare[:0]
+	batch.entries = 0
+	batch.mu.Unlock()
+
+	if len(data) == 0 {
+		return
+	}
+
+	_, err := batch.inner.Write(data)
+
+	batch.mu.Lock()
+	defer batch.mu.Unlock()
+
+	batch.spare = data
+
+	if err != nil && batch.err == nil {
+		batch.err = fmt.Errorf(errFmtFlushBatch, err)
+	}
+}
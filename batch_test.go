@@ -0,0 +1,135 @@
+package logger_test
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	batchLine         = "line\n"
+	batchMaxEntries   = 3
+	batchMaxBytes     = 1 << 10
+	batchBuffered     = 8
+	batchWait         = 5 * time.Second
+	batchTwoLines     = batchLine + batchLine
+	batchThreeLines   = batchTwoLines + batchLine
+	batchGotFmt       = "batch = %q, want %q"
+	batchTimeoutMsg   = "timed out waiting for a batch"
+	batchWriteErrFmt  = "write %d: %v"
+	batchPressureFmt  = "write error = %v, want %v"
+	batchCloseErrFmt  = "close: %v"
+	batchNotClosedMsg = "inner sink not closed"
+)
+
+// recordingSink passes every delivered batch to batches and, when gate is
+// set, blocks until the gate is released.
+type recordingSink struct {
+	batches chan string
+	gate    chan struct{}
+	closed  atomic.Bool
+}
+
+func newRecordingSink(gate chan struct{}) *recordingSink {
+	return &recordingSink{batches: make(chan string, batchBuffered), gate: gate, closed: atomic.Bool{}}
+}
+
+func (sink *recordingSink) Write(data []byte) (int, error) {
+	sink.batches <- string(data)
+
+	if sink.gate != nil {
+		<-sink.gate
+	}
+
+	return len(data), nil
+}
+
+func (sink *recordingSink) Close() error {
+	sink.closed.Store(true)
+
+	return nil
+}
+
+func TestBatchSink_DeliversFullBatch(t *testing.T) {
+	t.Parallel()
+
+	inner := newRecordingSink(nil)
+	batch := logger.NewBatchSink(inner, batchMaxEntries, batchMaxBytes, time.Hour)
+
+	defer func() { _ = batch.Close() }()
+
+	writeBatchLines(t, batch, batchMaxEntries)
+	expectBatch(t, inner, batchThreeLines)
+}
+
+func TestBatchSink_BackPressure(t *testing.T) {
+	t.Parallel()
+
+	gate := make(chan struct{})
+	inner := newRecordingSink(gate)
+	batch := logger.NewBatchSink(inner, 1, batchMaxBytes, time.Hour)
+
+	writeBatchLines(t, batch, 1)
+	expectBatch(t, inner, batchLine)
+	writeBatchLines(t, batch, 1)
+
+	_, err := batch.Write([]byte(batchLine))
+	if !errors.Is(err, logger.ErrBackPressure) {
+		t.Errorf(batchPressureFmt, err, logger.ErrBackPressure)
+	}
+
+	close(gate)
+	expectBatch(t, inner, batchLine)
+
+	err = batch.Close()
+	if err != nil {
+		t.Errorf(batchCloseErrFmt, err)
+	}
+}
+
+func TestBatchSink_CloseFlushes(t *testing.T) {
+	t.Parallel()
+
+	inner := newRecordingSink(nil)
+	batch := logger.NewBatchSink(inner, batchMaxEntries, batchMaxBytes, time.Hour)
+
+	writeBatchLines(t, batch, 2)
+
+	err := batch.Close()
+	if err != nil {
+		t.Errorf(batchCloseErrFmt, err)
+	}
+
+	expectBatch(t, inner, batchTwoLines)
+
+	if !inner.closed.Load() {
+		t.Error(batchNotClosedMsg)
+	}
+}
+
+func writeBatchLines(t *testing.T, batch *logger.BatchSink, count int) {
+	t.Helper()
+
+	for index := range count {
+		_, err := batch.Write([]byte(batchLine))
+		if err != nil {
+			t.Fatalf(batchWriteErrFmt, index, err)
+		}
+	}
+}
+
+func expectBatch(t *testing.T, inner *recordingSink, want string) {
+	t.Helper()
+
+	select {
+	case got := <-inner.batches:
+		if got != want {
+			t.Errorf(batchGotFmt, got, want)
+		}
+	case <-time.After(batchWait):
+		t.Fatal(batchTimeoutMsg)
+	}
+}
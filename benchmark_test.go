@@ -0,0 +1,180 @@
+package logger_test
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"testing"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	benchMsg    = "processed %d items"
+	benchItems  = 42
+	benchLayout = "{time} {level}: {msg}"
+	benchPadded = "{time} {level:<8} {msg} {fields}"
+	// benchGoroutines is the number of logging goroutines per GOMAXPROCS in
+	// the writer benchmarks, modelling services with 100+ loggers.
+	benchGoroutines = 32
+	benchAsyncQueue = 4096
+	benchFanOut     = 4
+	benchFile       = "bench.log"
+	benchClients    = 1024
+	benchClientFmt  = "10.%d.%d.%d"
+)
+
+func BenchmarkInfof_Stream(b *testing.B) {
+	loggerInstance := logger.NewStreamLogger(io.Discard)
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		loggerInstance.Infof(benchMsg, benchItems)
+	}
+}
+
+// BenchmarkDebugf_Disabled measures a call below the minimum level, which
+// costs one atomic load and no formatting.
+func BenchmarkDebugf_Disabled(b *testing.B) {
+	loggerInstance := logger.NewStreamLogger(io.Discard)
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			loggerInstance.Debugf(benchMsg, benchItems)
+		}
+	})
+}
+
+func BenchmarkInfof_RouteLayout(b *testing.B) {
+	// The route's own layout renders the entry a second time.
+	loggerInstance := logger.NewStreamLogger(io.Discard, logger.WithRoute(logger.Route{
+		Writer: io.Discard,
+		Layout: logger.MustParseLayout(benchLayout),
+	}))
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		loggerInstance.Infof(benchMsg, benchItems)
+	}
+}
+
+func BenchmarkInfof_FanOut(b *testing.B) {
+	// Routes sharing a layout share one rendering.
+	layout := logger.MustParseLayout(benchLayout)
+	opts := make([]logger.Option, 0, benchFanOut)
+
+	for range benchFanOut {
+		opts = append(opts, logger.WithRoute(logger.Route{Writer: io.Discard, Layout: layout}))
+	}
+
+	loggerInstance := logger.NewStreamLogger(io.Discard, opts...)
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		loggerInstance.Infof(benchMsg, benchItems)
+	}
+}
+
+func BenchmarkInfof_PaddedDecorated(b *testing.B) {
+	loggerInstance := logger.NewStreamLogger(io.Discard,
+		logger.WithLayout(logger.MustParseLayout(benchPadded)),
+		logger.WithDecoratedStdout(),
+	)
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		loggerInstance.Infof(benchMsg, benchItems)
+	}
+}
+
+// BenchmarkWriter_Mutex and BenchmarkWriter_Channel compare the synchronous
+// mutex-serialized writer with the channel writer selected by WithAsync under
+// heavy concurrency. Run them with -cpu to match the target machine.
+func BenchmarkWriter_Mutex(b *testing.B) {
+	benchmarkWriter(b, logger.NewStreamLogger(io.Discard))
+}
+
+func BenchmarkWriter_Channel(b *testing.B) {
+	benchmarkWriter(b, logger.NewStreamLogger(io.Discard, logger.WithAsync(benchAsyncQueue)))
+}
+
+// BenchmarkWriter_Coalesced is BenchmarkWriter_Channel with WithCoalescing,
+// writing queued entries in batches.
+func BenchmarkWriter_Coalesced(b *testing.B) {
+	benchmarkWriter(b, logger.NewStreamLogger(io.Discard,
+		logger.WithAsync(benchAsyncQueue),
+		logger.WithCoalescing(benchAsyncQueue),
+	))
+}
+
+// BenchmarkFile_Coalesced and BenchmarkFile_Vectored compare copying the
+// coalesced lines of a log file into one buffer with handing them to one
+// vectored write, selected by WithVectoredWrites.
+func BenchmarkFile_Coalesced(b *testing.B) {
+	benchmarkWriter(b, newBenchFileLogger(b))
+}
+
+func BenchmarkFile_Vectored(b *testing.B) {
+	benchmarkWriter(b, newBenchFileLogger(b, logger.WithVectoredWrites()))
+}
+
+func newBenchFileLogger(b *testing.B, opts ...logger.Option) *logger.Logger {
+	b.Helper()
+
+	opts = append([]logger.Option{
+		logger.WithoutStdout(),
+		logger.WithAsync(benchAsyncQueue),
+		logger.WithCoalescing(benchAsyncQueue),
+	}, opts...)
+
+	loggerInstance, err := logger.New(b.TempDir(), benchFile, opts...)
+	if err != nil {
+		b.Fatalf(newLoggerError, err)
+	}
+
+	return loggerInstance
+}
+
+func benchmarkWriter(b *testing.B, loggerInstance *logger.Logger) {
+	b.Helper()
+	b.Cleanup(func() { _ = loggerInstance.Close() })
+	b.ReportAllocs()
+	b.SetParallelism(benchGoroutines)
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			loggerInstance.Infof(benchMsg, benchItems)
+		}
+	})
+
+	loggerInstance.Flush()
+}
+
+// BenchmarkClientLimiter_Overflow measures the lines of new clients while the
+// limiter tracks its maximum of busy clients, as during a connection flood.
+func BenchmarkClientLimiter_Overflow(b *testing.B) {
+	loggerInstance := logger.NewStreamLogger(io.Discard)
+	limiter := loggerInstance.LimitClients(logger.ClientRateLimit{Rate: 0.001, Burst: 1, SampleEvery: 0})
+
+	for client := range benchClients {
+		limiter.Allow(strconv.Itoa(client))
+	}
+
+	clients := make([]string, benchClients)
+	for index := range clients {
+		clients[index] = fmt.Sprintf(benchClientFmt, index>>16, index>>8&0xff, index&0xff)
+	}
+
+	b.ReportAllocs()
+
+	index := 0
+	for b.Loop() {
+		limiter.Allow(clients[index%len(clients)])
+		index++
+	}
+}
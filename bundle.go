@@ -0,0 +1,344 @@
+package logger
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultBundleGlob selects the log files of a bundle, including rotated
+	// backups, when BundleOptions.Glob is empty. Write-ahead logs are never
+	// bundled.
+	DefaultBundleGlob = "*.log*"
+
+	// Members of a bundle archive.
+	BundleLogsDir         = "logs"
+	BundleStatsFile       = "stats.json"
+	BundleEnvironmentFile = "environment.json"
+
+	bundleFilePerm      = 0o600
+	bundleEnvPrefix     = "LOGGER_"
+	bundleJSONIndent    = "  "
+	bundleMaxLineBytes  = 1 << 20
+	bundleSpoolPattern  = "logger-bundle-*"
+	errFmtBundleGlob    = "bundle %s: %w"
+	errFmtBundleFile    = "bundle %s: %w"
+	errFmtBundleArchive = "write bundle: %w"
+)
+
+// BundleOptions selects what WriteBundle collects. Files matching Glob in Dir
+// and modified within Since, or every matching file when Since is zero, are
+// added with every line scrubbed by Redactor, or by DefaultRedactor when it
+// is nil.
+type BundleOptions struct {
+	Redactor *Redactor
+	Dir      string
+	Glob     string
+	Since    time.Duration
+}
+
+// BundleFile describes a log file in a bundle. Levels counts its lines by
+// level as recognized by DefaultIngestPattern; lines without a level count
+// as INFO. Truncated counts the lines cut to 1 MiB in the bundle.
+type BundleFile struct {
+	Modified  time.Time      `json:"modified"`
+	Levels    map[string]int `json:"levels"`
+	Name      string         `json:"name"`
+	Bytes     int64          `json:"bytes"`
+	Lines     int            `json:"lines"`
+	Truncated int            `json:"truncated,omitempty"`
+}
+
+// bundleEnvironment is the environment.json member of a bundle.
+type bundleEnvironment struct {
+	Created       time.Time `json:"created"`
+	Hostname      string    `json:"hostname"`
+	OS            string    `json:"os"`
+	Arch          string    `json:"arch"`
+	GoVersion     string    `json:"go_version"`
+	Dir           string    `json:"dir"`
+	Since         string    `json:"since,omitempty"`
+	Environment   []string  `json:"environment"`
+	SchemaVersion int       `json:"schema_version"`
+	CPUs          int       `json:"cpus"`
+}
+
+// WriteBundle writes a gzip-compressed tar archive for support requests to
+// w: the selected log files under logs/, their sizes, line counts and level
+// counts in stats.json, and the host, platform and LOGGER_ environment
+// variables in environment.json. Everything leaving the machine is redacted.
+// It returns the files added.
+func WriteBundle(w io.Writer, options BundleOptions) ([]BundleFile, error) {
+	redactor := options.Redactor
+	if redactor == nil {
+		redactor = DefaultRedactor()
+	}
+
+	paths, err := bundlePaths(options)
+	if err != nil {
+		return nil, err
+	}
+
+	compressed := gzip.NewWriter(w)
+	archive := tar.NewWriter(compressed)
+	files := make([]BundleFile, 0, len(paths))
+
+	for _, filePath := range paths {
+		file, err := addBundleLog(archive, filePath, redactor)
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, file)
+	}
+
+	err = addBundleJSON(archive, BundleStatsFile, files)
+	if err != nil {
+		return nil, err
+	}
+
+	err = addBundleJSON(archive, BundleEnvironmentFile, newBundleEnvironment(&options, redactor))
+	if err != nil {
+		return nil, err
+	}
+
+	err = archive.Close()
+	if err != nil {
+		return nil, fmt.Errorf(errFmtBundleArchive, err)
+	}
+
+	err = compressed.Close()
+	if err != nil {
+		return nil, fmt.Errorf(errFmtBundleArchive, err)
+	}
+
+	return files, nil
+}
+
+// bundlePaths returns the files selected by options, oldest first.
+func bundlePaths(options BundleOptions) ([]string, error) {
+	glob := options.Glob
+	if glob == "" {
+		glob = DefaultBundleGlob
+	}
+
+	matches, err := filepath.Glob(filepath.Join(options.Dir, glob))
+	if err != nil {
+		return nil, fmt.Errorf(errFmtBundleGlob, glob, err)
+	}
+
+	cutoff := time.Time{}
+	if options.Since > 0 {
+		cutoff = time.Now().Add(-options.Since)
+	}
+
+	var files []shipFile
+
+	for _, match := range matches {
+		if strings.HasSuffix(match, walSuffix) {
+			continue
+		}
+
+		info, err := os.Stat(match)
+
+		if err == nil && info.Mode().IsRegular() && !info.ModTime().Before(cutoff) {
+			files = append(files, shipFile{info: info, path: match})
+		}
+	}
+
+	slices.SortStableFunc(files, func(a, b shipFile) int {
+		return a.info.ModTime().Compare(b.info.ModTime())
+	})
+
+	paths := make([]string, 0, len(files))
+	for _, file := range files {
+		paths = append(paths, file.path)
+	}
+
+	return paths, nil
+}
+
+// addBundleLog adds the redacted lines of the file at filePath. The lines are
+// spooled to a temporary file, since the archive needs the size of a member
+// before its content, and lines over bundleMaxLineBytes are truncated.
+func addBundleLog(archive *tar.Writer, filePath string, redactor *Redactor) (BundleFile, error) {
+	// #nosec G304 -- the bundled files are selected by the operator.
+	file, err := os.Open(filePath)
+	if err != nil {
+		return BundleFile{}, fmt.Errorf(errFmtBundleFile, filePath, err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return BundleFile{}, fmt.Errorf(errFmtBundleFile, filePath, err)
+	}
+
+	spool, err := os.CreateTemp("", bundleSpoolPattern)
+	if err != nil {
+		return BundleFile{}, fmt.Errorf(errFmtBundleFile, filePath, err)
+	}
+
+	defer os.Remove(spool.Name())
+	defer spool.Close()
+
+	described := BundleFile{
+		Modified:  info.ModTime(),
+		Levels:    make(map[string]int),
+		Name:      path.Join(BundleLogsDir, filepath.Base(filePath)),
+		Bytes:     info.Size(),
+		Lines:     0,
+		Truncated: 0,
+	}
+
+	size, err := redactBundleLog(file, spool, redactor, &described)
+	if err != nil {
+		return BundleFile{}, fmt.Errorf(errFmtBundleFile, filePath, err)
+	}
+
+	_, err = spool.Seek(0, io.SeekStart)
+	if err != nil {
+		return BundleFile{}, fmt.Errorf(errFmtBundleFile, filePath, err)
+	}
+
+	return described, addBundleMember(archive, described.Name, info.ModTime(), size, spool)
+}
+
+// redactBundleLog writes the redacted lines of file to spool, counting them
+// in described, and returns the bytes written.
+func redactBundleLog(file io.Reader, spool io.Writer, redactor *Redactor, described *BundleFile) (int64, error) {
+	parser, _ := NewLineParser("", "")
+	reader := bufio.NewReader(file)
+	writer := bufio.NewWriter(spool)
+	size := int64(0)
+
+	for {
+		line, truncated, err := readBundleLine(reader)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return 0, err
+		}
+
+		described.Lines++
+		described.Levels[parser.Parse(line, described.Modified).Level.String()]++
+
+		if truncated {
+			described.Truncated++
+		}
+
+		redacted := redactor.Redact(line)
+		size += int64(len(redacted)) + 1
+
+		_, _ = writer.WriteString(redacted)
+		_ = writer.WriteByte(shipLineEnd)
+	}
+
+	return size, writer.Flush()
+}
+
+// readBundleLine returns the next line of reader without its line end,
+// truncated to bundleMaxLineBytes, and whether it was truncated. The rest of
+// a truncated line is skipped.
+func readBundleLine(reader *bufio.Reader) (string, bool, error) {
+	var line []byte
+
+	truncated := false
+
+	for {
+		chunk, more, err := reader.ReadLine()
+		if err != nil {
+			return "", false, err
+		}
+
+		room := bundleMaxLineBytes - len(line)
+		if len(chunk) > room {
+			chunk, truncated = chunk[:room], true
+		}
+
+		line = append(line, chunk...)
+
+		if !more {
+			return string(line), truncated, nil
+		}
+	}
+}
+
+func addBundleJSON(archive *tar.Writer, name string, value any) error {
+	data, err := json.MarshalIndent(value, "", bundleJSONIndent)
+	if err != nil {
+		return fmt.Errorf(errFmtBundleArchive, err)
+	}
+
+	data = append(data, shipLineEnd)
+
+	return addBundleMember(archive, name, time.Now(), int64(len(data)), bytes.NewReader(data))
+}
+
+// addBundleMember adds the size bytes of content as the member name.
+func addBundleMember(archive *tar.Writer, name string, modified time.Time, size int64, content io.Reader) error {
+	err := archive.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     bundleFilePerm,
+		Size:     size,
+		ModTime:  modified,
+	})
+	if err != nil {
+		return fmt.Errorf(errFmtBundleArchive, err)
+	}
+
+	_, err = io.Copy(archive, content)
+	if err != nil {
+		return fmt.Errorf(errFmtBundleArchive, err)
+	}
+
+	return nil
+}
+
+func newBundleEnvironment(options *BundleOptions, redactor *Redactor) bundleEnvironment {
+	hostname, _ := os.Hostname()
+
+	environment := []string{}
+
+	for _, variable := range os.Environ() {
+		if strings.HasPrefix(variable, bundleEnvPrefix) {
+			environment = append(environment, redactor.Redact(variable))
+		}
+	}
+
+	slices.Sort(environment)
+
+	since := ""
+	if options.Since > 0 {
+		since = options.Since.String()
+	}
+
+	return bundleEnvironment{
+		Created:       time.Now(),
+		Hostname:      hostname,
+		OS:            runtime.GOOS,
+		Arch:          runtime.GOARCH,
+		GoVersion:     runtime.Version(),
+		Dir:           options.Dir,
+		Since:         since,
+		Environment:   environment,
+		SchemaVersion: SchemaVersion,
+		CPUs:          runtime.NumCPU(),
+	}
+}
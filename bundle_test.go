@@ -0,0 +1,170 @@
+package logger_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	bundleAppFile = "app.log"
+	bundleOldFile = "old.log.1"
+	bundleAppLog  = "2025/01/02 15:04:05 [ERROR] login failed for alice@example.com password=hunter2\n" +
+		"2025/01/02 15:04:06 [INFO] Authorization: Bearer abc.def.ghi\n" +
+		"2025/01/02 15:04:07 [INFO] done\n"
+	bundleAppWant = "2025/01/02 15:04:05 [ERROR] login failed for [REDACTED] password=[REDACTED]\n" +
+		"2025/01/02 15:04:06 [INFO] Authorization: [REDACTED] [REDACTED]\n" +
+		"2025/01/02 15:04:07 [INFO] done\n"
+	bundleOldLog      = "old entry\n"
+	bundleFilePerm    = 0o600
+	bundleOldAge      = 48 * time.Hour
+	bundleSince       = 24 * time.Hour
+	bundleSetupFmt    = "setup: %v"
+	bundleWriteFmt    = "WriteBundle: %v"
+	bundleReadFmt     = "read bundle: %v"
+	bundleMembersFmt  = "bundle members = %v; want %v"
+	bundleContentFmt  = "%s:\n%s\nwant:\n%s"
+	bundleStatsFmt    = "stats = %+v; want 3 lines, 1 ERROR and 2 INFO"
+	bundleRedactFmt   = "Redact(%q) = %q; want %q"
+	bundleSecretInput = `token: "s3cr3t" api_key=xyz&user=bob`
+	bundleSecretWant  = `token: [REDACTED] api_key=[REDACTED]&user=bob`
+	bundleWALFile     = "app.log.wal"
+	bundleMaxLine     = 1 << 20
+	bundleLongFmt     = "member = %d bytes in %d lines, stats = %+v; want a line of %d bytes and 1 truncated"
+)
+
+func TestWriteBundle(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeBundleFile(t, filepath.Join(dir, bundleAppFile), bundleAppLog, time.Now())
+	writeBundleFile(t, filepath.Join(dir, bundleOldFile), bundleOldLog, time.Now().Add(-bundleOldAge))
+
+	var archive bytes.Buffer
+
+	files, err := logger.WriteBundle(&archive, logger.BundleOptions{
+		Redactor: nil,
+		Dir:      dir,
+		Glob:     "",
+		Since:    bundleSince,
+	})
+	if err != nil {
+		t.Fatalf(bundleWriteFmt, err)
+	}
+
+	if len(files) != 1 || files[0].Lines != 3 || files[0].Levels["ERROR"] != 1 || files[0].Levels["INFO"] != 2 {
+		t.Errorf(bundleStatsFmt, files)
+	}
+
+	members := readBundle(t, &archive)
+	appMember := logger.BundleLogsDir + "/" + bundleAppFile
+
+	want := []string{appMember, logger.BundleStatsFile, logger.BundleEnvironmentFile}
+	if len(members) != len(want) {
+		t.Fatalf(bundleMembersFmt, members, want)
+	}
+
+	if members[appMember] != bundleAppWant {
+		t.Errorf(bundleContentFmt, appMember, members[appMember], bundleAppWant)
+	}
+
+	var stats []logger.BundleFile
+
+	err = json.Unmarshal([]byte(members[logger.BundleStatsFile]), &stats)
+	if err != nil || len(stats) != 1 || stats[0].Name != appMember {
+		t.Errorf(bundleContentFmt, logger.BundleStatsFile, members[logger.BundleStatsFile], appMember)
+	}
+}
+
+func TestWriteBundle_TruncatesLongLinesAndSkipsWAL(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	long := strings.Repeat("x", bundleMaxLine+bundleMaxLine/2)
+	writeBundleFile(t, filepath.Join(dir, bundleAppFile), long+"\n"+bundleOldLog, time.Now())
+	writeBundleFile(t, filepath.Join(dir, bundleWALFile), bundleOldLog, time.Now())
+
+	var archive bytes.Buffer
+
+	files, err := logger.WriteBundle(&archive, logger.BundleOptions{Redactor: nil, Dir: dir, Glob: "", Since: 0})
+	if err != nil {
+		t.Fatalf(bundleWriteFmt, err)
+	}
+
+	members := readBundle(t, &archive)
+	if len(members) != 3 {
+		t.Fatalf(bundleMembersFmt, len(members), 3)
+	}
+
+	content := members[logger.BundleLogsDir+"/"+bundleAppFile]
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+
+	if len(files) != 1 || files[0].Lines != 2 || files[0].Truncated != 1 ||
+		len(lines) != 2 || len(lines[0]) != bundleMaxLine || lines[1]+"\n" != bundleOldLog {
+		t.Errorf(bundleLongFmt, len(content), len(lines), files, bundleMaxLine)
+	}
+}
+
+func TestDefaultRedactor(t *testing.T) {
+	t.Parallel()
+
+	got := logger.DefaultRedactor().Redact(bundleSecretInput)
+	if got != bundleSecretWant {
+		t.Errorf(bundleRedactFmt, bundleSecretInput, got, bundleSecretWant)
+	}
+}
+
+func writeBundleFile(t *testing.T, path, content string, modified time.Time) {
+	t.Helper()
+
+	err := os.WriteFile(path, []byte(content), bundleFilePerm)
+	if err != nil {
+		t.Fatalf(bundleSetupFmt, err)
+	}
+
+	err = os.Chtimes(path, modified, modified)
+	if err != nil {
+		t.Fatalf(bundleSetupFmt, err)
+	}
+}
+
+// readBundle returns the members of a bundle by name.
+func readBundle(t *testing.T, archive io.Reader) map[string]string {
+	t.Helper()
+
+	uncompressed, err := gzip.NewReader(archive)
+	if err != nil {
+		t.Fatalf(bundleReadFmt, err)
+	}
+
+	reader := tar.NewReader(uncompressed)
+	members := make(map[string]string)
+
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return members
+		}
+
+		if err != nil {
+			t.Fatalf(bundleReadFmt, err)
+		}
+
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			t.Fatalf(bundleReadFmt, err)
+		}
+
+		members[header.Name] = string(data)
+	}
+}
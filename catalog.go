@@ -0,0 +1,132 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+)
+
+const (
+	// MessageIDField is the field key under which message IDs are emitted.
+	MessageIDField = "msg_id"
+
+	// messageIDInvalidChars lists characters that are not allowed in message IDs
+	// because they would break key=value parsing of text output.
+	messageIDInvalidChars = " \t\r\n=\""
+
+	errMessageIDEmptyMsg      = "message ID cannot be empty"
+	errMessageIDInvalidMsg    = "message ID contains invalid characters"
+	errMessageIDRegisteredMsg = "message ID already registered"
+	errFmtMessageID           = "%w: %q"
+)
+
+// Predefined errors for message catalog registration.
+var (
+	ErrMessageIDEmpty      = errors.New(errMessageIDEmptyMsg)
+	ErrMessageIDInvalid    = errors.New(errMessageIDInvalidMsg)
+	ErrMessageIDRegistered = errors.New(errMessageIDRegisteredMsg)
+)
+
+// messageCatalog holds the process-wide registry of stable message IDs.
+var messageCatalog = struct {
+	entries map[string]string
+	mu      sync.RWMutex
+}{
+	entries: make(map[string]string),
+	mu:      sync.RWMutex{},
+}
+
+// RegisterMessageID adds a stable message ID and its description to the
+// process-wide catalog. Operations teams can export the catalog with
+// MessageCatalog to build runbooks keyed on IDs rather than free text.
+func RegisterMessageID(id, description string) error {
+	err := validateMessageID(id)
+	if err != nil {
+		return err
+	}
+
+	messageCatalog.mu.Lock()
+	defer messageCatalog.mu.Unlock()
+
+	if _, exists := messageCatalog.entries[id]; exists {
+		return fmt.Errorf(errFmtMessageID, ErrMessageIDRegistered, id)
+	}
+
+	messageCatalog.entries[id] = description
+
+	return nil
+}
+
+// MessageCatalog returns a copy of all registered message IDs and their
+// descriptions.
+func MessageCatalog() map[string]string {
+	messageCatalog.mu.RLock()
+	defer messageCatalog.mu.RUnlock()
+
+	return maps.Clone(messageCatalog.entries)
+}
+
+// LookupMessageID returns the description registered for id, if any.
+func LookupMessageID(id string) (string, bool) {
+	messageCatalog.mu.RLock()
+	defer messageCatalog.mu.RUnlock()
+
+	description, exists := messageCatalog.entries[id]
+
+	return description, exists
+}
+
+func validateMessageID(id string) error {
+	if id == "" {
+		return ErrMessageIDEmpty
+	}
+
+	if strings.ContainsAny(id, messageIDInvalidChars) {
+		return fmt.Errorf(errFmtMessageID, ErrMessageIDInvalid, id)
+	}
+
+	return nil
+}
+
+// InfoID logs an informational message tagged with a stable message ID.
+func (l *Logger) InfoID(id, format string, args ...any) {
+	l.writef(LevelInfo, messageIDFields(id), format, args...)
+}
+
+// WarnID logs a warning message tagged with a stable message ID.
+func (l *Logger) WarnID(id, format string, args ...any) {
+	l.writef(LevelWarn, messageIDFields(id), format, args...)
+}
+
+// ErrorID logs an error message tagged with a stable message ID.
+func (l *Logger) ErrorID(id, format string, args ...any) {
+	l.writef(LevelError, messageIDFields(id), format, args...)
+}
+
+// SuccessID logs a success message tagged with a stable message ID.
+func (l *Logger) SuccessID(id, format string, args ...any) {
+	l.writef(LevelSuccess, messageIDFields(id), format, args...)
+}
+
+// FatalID logs a fatal message tagged with a stable message ID. Like Fatalf it
+// does NOT exit.
+func (l *Logger) FatalID(id, format string, args ...any) {
+	l.writef(LevelFatal, messageIDFields(id), format, args...)
+}
+
+// PanicID logs a panic-level message tagged with a stable message ID. Like
+// Panicf it does NOT panic.
+func (l *Logger) PanicID(id, format string, args ...any) {
+	l.writef(LevelPanic, messageIDFields(id), format, args...)
+}
+
+// SystemID logs a system-level event tagged with a stable message ID.
+func (l *Logger) SystemID(id, format string, args ...any) {
+	l.writef(LevelSystem, messageIDFields(id), format, args...)
+}
+
+func messageIDFields(id string) []Field {
+	return []Field{F(MessageIDField, id)}
+}
@@ -0,0 +1,87 @@
+package logger_test
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	catalogLogFile          = "catalog.log"
+	catalogID               = "DB001"
+	catalogDuplicateID      = "DB002"
+	catalogLookupID         = "DB003"
+	catalogInvalidID        = "DB 004"
+	catalogDescription      = "connection pool exhausted"
+	catalogMessage          = "pool size %d reached"
+	catalogExpectedLine     = "[ERROR] pool size 10 reached msg_id=DB001"
+	registerMessageIDErrFmt = "RegisterMessageID: %v"
+	lookupMessageIDErrFmt   = "LookupMessageID(%q) = %q, %v"
+	catalogMissingIDErrFmt  = "catalog missing %q: %v"
+)
+
+func TestLogger_ErrorIDEmitsMessageIDField(t *testing.T) {
+	t.Parallel()
+
+	loggerInstance, logPath := setupTestLogger(t, catalogLogFile)
+	loggerInstance.ErrorID(catalogID, catalogMessage, 10)
+	// #nosec G304
+	content, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf(readLogFileErr, err)
+	}
+
+	if !strings.Contains(string(content), catalogExpectedLine) {
+		t.Errorf(logFileMissingFmt, catalogExpectedLine, string(content))
+	}
+}
+
+func TestRegisterMessageID_RejectsDuplicates(t *testing.T) {
+	t.Parallel()
+
+	err := logger.RegisterMessageID(catalogDuplicateID, catalogDescription)
+	if err != nil {
+		t.Fatalf(registerMessageIDErrFmt, err)
+	}
+
+	err = logger.RegisterMessageID(catalogDuplicateID, catalogDescription)
+	if !errors.Is(err, logger.ErrMessageIDRegistered) {
+		t.Errorf(expectedErrFmt, logger.ErrMessageIDRegistered, err)
+	}
+}
+
+func TestRegisterMessageID_RejectsInvalidIDs(t *testing.T) {
+	t.Parallel()
+
+	err := logger.RegisterMessageID("", catalogDescription)
+	if !errors.Is(err, logger.ErrMessageIDEmpty) {
+		t.Errorf(expectedErrFmt, logger.ErrMessageIDEmpty, err)
+	}
+
+	err = logger.RegisterMessageID(catalogInvalidID, catalogDescription)
+	if !errors.Is(err, logger.ErrMessageIDInvalid) {
+		t.Errorf(expectedErrFmt, logger.ErrMessageIDInvalid, err)
+	}
+}
+
+func TestMessageCatalog_ContainsRegisteredIDs(t *testing.T) {
+	t.Parallel()
+
+	err := logger.RegisterMessageID(catalogLookupID, catalogDescription)
+	if err != nil {
+		t.Fatalf(registerMessageIDErrFmt, err)
+	}
+
+	description, found := logger.LookupMessageID(catalogLookupID)
+	if !found || description != catalogDescription {
+		t.Errorf(lookupMessageIDErrFmt, catalogLookupID, description, found)
+	}
+
+	catalog := logger.MessageCatalog()
+	if catalog[catalogLookupID] != catalogDescription {
+		t.Errorf(catalogMissingIDErrFmt, catalogLookupID, catalog)
+	}
+}
@@ -0,0 +1,156 @@
+package logger
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	errCircuitOpenMsg     = "circuit breaker open"
+	dropReasonCircuitOpen = "open circuit breaker"
+	circuitClosedName     = "closed"
+	circuitOpenName       = "open"
+	circuitHalfOpenName   = "half-open"
+)
+
+// ErrCircuitOpen is returned by CircuitBreaker writes rejected while the
+// circuit is open. Routes count such entries as dropped rather than failed.
+var ErrCircuitOpen = errors.New(errCircuitOpenMsg)
+
+// CircuitState is the state of a CircuitBreaker.
+type CircuitState int
+
+// Circuit breaker states.
+const (
+	// CircuitClosed passes every write to the inner sink.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects writes without calling the inner sink.
+	CircuitOpen
+	// CircuitHalfOpen passes a single probe write to the inner sink.
+	CircuitHalfOpen
+)
+
+// String returns the state name used in metrics.
+func (state CircuitState) String() string {
+	switch state {
+	case CircuitOpen:
+		return circuitOpenName
+	case CircuitHalfOpen:
+		return circuitHalfOpenName
+	default:
+		return circuitClosedName
+	}
+}
+
+// CircuitMetrics describes a CircuitBreaker: its state, the failures counted
+// towards opening it, and totals since it was created.
+type CircuitMetrics struct {
+	State               CircuitState
+	ConsecutiveFailures int
+	Opens               uint64
+	Probes              uint64
+	Rejected            uint64
+}
+
+// CircuitBreaker is a Sink decorator that stops calling a failing inner sink,
+// so that a collector outage costs an error return instead of a timeout per
+// entry. After maxFailures consecutive failed writes the circuit opens and
+// writes fail with ErrCircuitOpen. Once openTimeout has elapsed, the next
+// write is a probe passed to the inner sink: its success closes the circuit
+// and its failure opens it for another openTimeout.
+type CircuitBreaker struct {
+	inner       Sink
+	openedAt    time.Time
+	metrics     CircuitMetrics
+	openTimeout time.Duration
+	maxFailures int
+	mu          sync.Mutex
+}
+
+// NewCircuitBreaker wraps inner in a circuit breaker. A maxFailures below one
+// is treated as one.
+func NewCircuitBreaker(inner Sink, maxFailures int, openTimeout time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		inner:       inner,
+		openedAt:    time.Time{},
+		metrics:     CircuitMetrics{State: CircuitClosed, ConsecutiveFailures: 0, Opens: 0, Probes: 0, Rejected: 0},
+		openTimeout: openTimeout,
+		maxFailures: max(maxFailures, 1),
+		mu:          sync.Mutex{},
+	}
+}
+
+// Write passes data to the inner sink unless the circuit is open.
+func (breaker *CircuitBreaker) Write(data []byte) (int, error) {
+	if !breaker.allow() {
+		return 0, ErrCircuitOpen
+	}
+
+	n, err := breaker.inner.Write(data)
+	breaker.record(err)
+
+	return n, err
+}
+
+// Close closes the inner sink.
+func (breaker *CircuitBreaker) Close() error {
+	return breaker.inner.Close()
+}
+
+// Metrics returns the current state and counters.
+func (breaker *CircuitBreaker) Metrics() CircuitMetrics {
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+
+	return breaker.metrics
+}
+
+// allow reports whether a write may reach the inner sink, moving an open
+// circuit to half-open once its timeout has elapsed. Only one probe is in
+// flight at a time.
+func (breaker *CircuitBreaker) allow() bool {
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+
+	switch breaker.metrics.State {
+	case CircuitClosed:
+		return true
+	case CircuitOpen:
+		if time.Since(breaker.openedAt) >= breaker.openTimeout {
+			breaker.metrics.State = CircuitHalfOpen
+			breaker.metrics.Probes++
+
+			return true
+		}
+	case CircuitHalfOpen:
+	}
+
+	breaker.metrics.Rejected++
+
+	return false
+}
+
+// record updates the circuit with the outcome of a write.
+func (breaker *CircuitBreaker) record(err error) {
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+
+	if err == nil {
+		breaker.metrics.State = CircuitClosed
+		breaker.metrics.ConsecutiveFailures = 0
+
+		return
+	}
+
+	breaker.metrics.ConsecutiveFailures++
+	if breaker.metrics.State == CircuitOpen {
+		return
+	}
+
+	if breaker.metrics.State == CircuitHalfOpen || breaker.metrics.ConsecutiveFailures >= breaker.maxFailures {
+		breaker.metrics.State = CircuitOpen
+		breaker.metrics.Opens++
+		breaker.openedAt = time.Now()
+	}
+}
@@ -0,0 +1,116 @@
+package logger_test
+
+import (
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	circuitMaxFailures = 2
+	circuitLine        = "line\n"
+	circuitEntries     = 5
+	circuitStateFmt    = "state = %v, want %v"
+	circuitWritesFmt   = "inner writes = %d, want %d"
+	circuitErrFmt      = "write error = %v, want %v"
+	circuitMetricsFmt  = "metrics = %+v"
+	circuitClosedFmt   = "inner sink closed = %v, want true"
+)
+
+// stubSink is a Sink whose writes fail while fail is set.
+type stubSink struct {
+	writes int
+	fail   bool
+	closed bool
+}
+
+func (sink *stubSink) Write(data []byte) (int, error) {
+	sink.writes++
+	if sink.fail {
+		return 0, errTestWriteFailed
+	}
+
+	return len(data), nil
+}
+
+func (sink *stubSink) Close() error {
+	sink.closed = true
+
+	return nil
+}
+
+func TestCircuitBreaker_OpensAfterConsecutiveFailures(t *testing.T) {
+	t.Parallel()
+
+	inner := &stubSink{writes: 0, fail: true, closed: false}
+	breaker := logger.NewCircuitBreaker(inner, circuitMaxFailures, time.Hour)
+
+	for range circuitMaxFailures {
+		_, _ = breaker.Write([]byte(circuitLine))
+	}
+
+	_, err := breaker.Write([]byte(circuitLine))
+	if !errors.Is(err, logger.ErrCircuitOpen) {
+		t.Errorf(circuitErrFmt, err, logger.ErrCircuitOpen)
+	}
+
+	if inner.writes != circuitMaxFailures {
+		t.Errorf(circuitWritesFmt, inner.writes, circuitMaxFailures)
+	}
+
+	metrics := breaker.Metrics()
+	if metrics.State != logger.CircuitOpen || metrics.Opens != 1 || metrics.Rejected != 1 {
+		t.Errorf(circuitMetricsFmt, metrics)
+	}
+}
+
+func TestCircuitBreaker_HalfOpenProbe(t *testing.T) {
+	t.Parallel()
+
+	inner := &stubSink{writes: 0, fail: true, closed: false}
+	breaker := logger.NewCircuitBreaker(inner, 1, 0)
+
+	_, _ = breaker.Write([]byte(circuitLine))
+	_, _ = breaker.Write([]byte(circuitLine))
+
+	if state := breaker.Metrics().State; state != logger.CircuitOpen {
+		t.Fatalf(circuitStateFmt, state, logger.CircuitOpen)
+	}
+
+	inner.fail = false
+
+	_, err := breaker.Write([]byte(circuitLine))
+	if err != nil {
+		t.Errorf(circuitErrFmt, err, nil)
+	}
+
+	metrics := breaker.Metrics()
+	if metrics.State != logger.CircuitClosed || metrics.Probes != 2 {
+		t.Errorf(circuitMetricsFmt, metrics)
+	}
+}
+
+func TestCircuitBreaker_RouteDropsWhileOpen(t *testing.T) {
+	t.Parallel()
+
+	inner := &stubSink{writes: 0, fail: true, closed: false}
+	loggerInstance := logger.NewStreamLogger(io.Discard,
+		logger.WithRoute(logger.Route{Sink: logger.NewCircuitBreaker(inner, circuitMaxFailures, time.Hour)}))
+
+	for range circuitEntries {
+		loggerInstance.Infof(circuitLine)
+	}
+
+	if inner.writes != circuitMaxFailures {
+		t.Errorf(circuitWritesFmt, inner.writes, circuitMaxFailures)
+	}
+
+	_ = loggerInstance.Close()
+
+	if !inner.closed {
+		t.Errorf(circuitClosedFmt, inner.closed)
+	}
+}
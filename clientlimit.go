@@ -0,0 +1,281 @@
+package logger
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// ClientOther collects the lines of the clients seen while a
+	// ClientLimiter tracks maxLimitedClients busy clients, bounding its memory
+	// when clients are keyed by connection or come from many addresses, and
+	// the counts of the clients it forgot.
+	ClientOther = "other"
+
+	// maxLimitedClients is the number of clients with their own bucket.
+	maxLimitedClients = 1024
+	// clientSweepInterval bounds how often a full ClientLimiter looks for idle
+	// clients, so that a stream of new clients costs one sweep per interval
+	// rather than one per line.
+	clientSweepInterval = time.Second
+	dropReasonClientFmt = "rate limit of client %s"
+	metricClientHelp    = "# HELP logger_client_lines_total Lines received per network client and outcome.\n"
+	metricClientType    = "# TYPE logger_client_lines_total counter\n"
+	metricClientFmt     = "logger_client_lines_total{client=%q,outcome=%q} %d\n"
+	clientOutcomeOK     = "accepted"
+	clientOutcomeReject = "rejected"
+	clientOutcomeSample = "sampled"
+)
+
+// ClientRateLimit is a token bucket limiting the lines of one network client
+// to Rate lines per second on average, in bursts of up to Burst lines. Lines
+// over the limit are rejected; when SampleEvery is above 1, every
+// SampleEvery-th of them is kept instead, so that a flooding client is still
+// visible in the log. A non-positive Rate disables the limit.
+type ClientRateLimit struct {
+	Rate        float64
+	Burst       int
+	SampleEvery int
+}
+
+// ClientStats counts the lines of one client: accepted within its limit,
+// rejected over it and sampled over it.
+type ClientStats struct {
+	Client   string
+	Accepted uint64
+	Rejected uint64
+	Sampled  uint64
+}
+
+// ClientLimiter applies a ClientRateLimit to every client separately, so that
+// one misbehaving producer cannot flood a logger shared by many. It is
+// returned by LimitClients and safe for concurrent use.
+type ClientLimiter struct {
+	swept   time.Time
+	core    *loggerCore
+	buckets map[string]*clientBucket
+	limit   ClientRateLimit
+	mu      sync.Mutex
+}
+
+// clientBucket is the token bucket and counters of one client. It is guarded
+// by ClientLimiter.mu.
+type clientBucket struct {
+	refilled time.Time
+	tokens   float64
+	excess   uint64
+	stats    ClientStats
+}
+
+// LimitClients returns a limiter of the lines of network clients, identified
+// by a key such as their address. The counts of its clients are reported in
+// Stats().Clients and by the metrics handler, and rejected lines in drop
+// summaries.
+func (l *Logger) LimitClients(limit ClientRateLimit) *ClientLimiter {
+	limiter := &ClientLimiter{
+		swept:   time.Time{},
+		core:    l.core,
+		buckets: make(map[string]*clientBucket),
+		limit:   limit,
+		mu:      sync.Mutex{},
+	}
+
+	l.core.mu.Lock()
+	l.core.clientLimiters = append(l.core.clientLimiters, limiter)
+	l.core.mu.Unlock()
+
+	return limiter
+}
+
+// Allow counts a line received from client and reports whether it should be
+// logged.
+func (limiter *ClientLimiter) Allow(client string) bool {
+	now := limiter.core.clock()
+
+	limiter.mu.Lock()
+	bucket := limiter.bucket(client, now)
+	allowed := limiter.take(bucket, now)
+	name := bucket.stats.Client
+	limiter.mu.Unlock()
+
+	if !allowed {
+		limiter.core.noteDrop(fmt.Sprintf(dropReasonClientFmt, name))
+	}
+
+	return allowed
+}
+
+// Forget drops the bucket of client, for clients that will not be seen again
+// such as a closed connection, so that it does not take the place of a new
+// client. Its counts are added to those of ClientOther.
+func (limiter *ClientLimiter) Forget(client string) {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	bucket, ok := limiter.buckets[client]
+	if ok && client != ClientOther {
+		limiter.retire(client, bucket, limiter.core.clock())
+	}
+}
+
+// bucket returns the bucket of client, full when it is new. Once
+// maxLimitedClients clients are tracked, the idle clients whose buckets
+// refilled are retired to make room, at most once per clientSweepInterval,
+// and new clients share the bucket of ClientOther meanwhile. The caller holds
+// mu.
+func (limiter *ClientLimiter) bucket(client string, now time.Time) *clientBucket {
+	bucket, ok := limiter.buckets[client]
+	if ok {
+		return bucket
+	}
+
+	if len(limiter.buckets) >= maxLimitedClients && now.Sub(limiter.swept) >= clientSweepInterval {
+		limiter.swept = now
+		limiter.retireIdle(now)
+	}
+
+	if len(limiter.buckets) >= maxLimitedClients {
+		client = ClientOther
+
+		bucket, ok = limiter.buckets[client]
+		if ok {
+			return bucket
+		}
+	}
+
+	bucket = limiter.newBucket(client, now)
+	limiter.buckets[client] = bucket
+
+	return bucket
+}
+
+func (limiter *ClientLimiter) newBucket(client string, now time.Time) *clientBucket {
+	return &clientBucket{
+		refilled: now,
+		tokens:   float64(limiter.burst()),
+		excess:   0,
+		stats:    ClientStats{Client: client, Accepted: 0, Rejected: 0, Sampled: 0},
+	}
+}
+
+// retireIdle retires the clients whose buckets have refilled since their
+// last line. The caller holds mu.
+func (limiter *ClientLimiter) retireIdle(now time.Time) {
+	for client, bucket := range limiter.buckets {
+		if client != ClientOther && limiter.refilled(bucket, now) {
+			limiter.retire(client, bucket, now)
+		}
+	}
+}
+
+// refilled reports whether bucket would be full at now.
+func (limiter *ClientLimiter) refilled(bucket *clientBucket, now time.Time) bool {
+	if limiter.limit.Rate <= 0 {
+		return true
+	}
+
+	return bucket.tokens+now.Sub(bucket.refilled).Seconds()*limiter.limit.Rate >= float64(limiter.burst())
+}
+
+// retire drops the bucket of client and adds its counts to ClientOther. The
+// caller holds mu.
+func (limiter *ClientLimiter) retire(client string, bucket *clientBucket, now time.Time) {
+	delete(limiter.buckets, client)
+
+	other, ok := limiter.buckets[ClientOther]
+	if !ok {
+		other = limiter.newBucket(ClientOther, now)
+		limiter.buckets[ClientOther] = other
+	}
+
+	other.stats.Accepted += bucket.stats.Accepted
+	other.stats.Rejected += bucket.stats.Rejected
+	other.stats.Sampled += bucket.stats.Sampled
+}
+
+// take refills bucket for the time elapsed and spends a token for one line.
+// The caller holds mu.
+func (limiter *ClientLimiter) take(bucket *clientBucket, now time.Time) bool {
+	if limiter.limit.Rate <= 0 {
+		bucket.stats.Accepted++
+
+		return true
+	}
+
+	elapsed := now.Sub(bucket.refilled).Seconds()
+	bucket.refilled = now
+	bucket.tokens = min(bucket.tokens+elapsed*limiter.limit.Rate, float64(limiter.burst()))
+
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		bucket.stats.Accepted++
+
+		return true
+	}
+
+	bucket.excess++
+
+	if limiter.limit.SampleEvery > 1 && bucket.excess%uint64(limiter.limit.SampleEvery) == 0 {
+		bucket.stats.Sampled++
+
+		return true
+	}
+
+	bucket.stats.Rejected++
+
+	return false
+}
+
+func (limiter *ClientLimiter) burst() int {
+	return max(limiter.limit.Burst, 1)
+}
+
+// Stats returns the counts of every client, by client.
+func (limiter *ClientLimiter) Stats() []ClientStats {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	stats := make([]ClientStats, 0, len(limiter.buckets))
+	for _, bucket := range limiter.buckets {
+		stats = append(stats, bucket.stats)
+	}
+
+	slices.SortFunc(stats, func(a, b ClientStats) int {
+		return cmp.Compare(a.Client, b.Client)
+	})
+
+	return stats
+}
+
+// clientStats returns the counts of the clients of every limiter.
+func (c *loggerCore) clientStats() []ClientStats {
+	c.mu.Lock()
+	limiters := c.clientLimiters
+	c.mu.Unlock()
+
+	var stats []ClientStats
+	for _, limiter := range limiters {
+		stats = append(stats, limiter.Stats()...)
+	}
+
+	return stats
+}
+
+func writeClientMetrics(builder *strings.Builder, clients []ClientStats) {
+	if len(clients) == 0 {
+		return
+	}
+
+	builder.WriteString(metricClientHelp)
+	builder.WriteString(metricClientType)
+
+	for _, client := range clients {
+		fmt.Fprintf(builder, metricClientFmt, client.Client, clientOutcomeOK, client.Accepted)
+		fmt.Fprintf(builder, metricClientFmt, client.Client, clientOutcomeReject, client.Rejected)
+		fmt.Fprintf(builder, metricClientFmt, client.Client, clientOutcomeSample, client.Sampled)
+	}
+}
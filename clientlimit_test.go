@@ -0,0 +1,197 @@
+package logger_test
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	clientFlooding   = "10.0.0.1"
+	clientQuiet      = "10.0.0.2"
+	clientLines      = 8
+	clientAllowedFmt = "Allow(%q) admitted %d of %d lines; want %d"
+	clientStatsFmt   = "Clients = %+v; want %+v"
+	clientSummary    = "dropped 4 entries in last 0s due to rate limit of client 10.0.0.1"
+	clientMetric     = `logger_client_lines_total{client="10.0.0.1",outcome="rejected"} 4`
+	clientOutputFmt  = "output lacks %q:\n%s"
+	clientMetricFmt  = "metrics lack %q:\n%s"
+	clientMsg        = "after the flood"
+	clientCapacity   = 1024
+	clientNewcomer   = "10.0.1.1"
+	clientCountFmt   = "tracked %d clients; want %d"
+	clientLateFmt    = "10.0.2.%d"
+)
+
+func TestLogger_LimitClients(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	current := time.Date(2025, time.March, 1, 8, 0, 0, 0, time.UTC)
+	loggerInstance := logger.NewStreamLogger(&buf,
+		logger.WithClock(func() time.Time { return current }),
+		logger.WithDropSummaryInterval(time.Nanosecond),
+	)
+	limiter := loggerInstance.LimitClients(logger.ClientRateLimit{Rate: 1, Burst: 2, SampleEvery: 3})
+
+	// Two lines fit the burst; of the six excess lines every third is sampled.
+	admitted := 0
+
+	for range clientLines {
+		if limiter.Allow(clientFlooding) {
+			admitted++
+		}
+	}
+
+	if admitted != 4 {
+		t.Errorf(clientAllowedFmt, clientFlooding, admitted, clientLines, 4)
+	}
+
+	if !limiter.Allow(clientQuiet) {
+		t.Errorf(clientAllowedFmt, clientQuiet, 0, 1, 1)
+	}
+
+	current = current.Add(time.Second)
+
+	if !limiter.Allow(clientFlooding) {
+		t.Errorf(clientAllowedFmt, clientFlooding, 0, 1, 1)
+	}
+
+	loggerInstance.Infof(clientMsg)
+
+	stats := loggerInstance.Stats()
+	closeTestLogger(t, loggerInstance)
+
+	want := []logger.ClientStats{
+		{Client: clientFlooding, Accepted: 3, Rejected: 4, Sampled: 2},
+		{Client: clientQuiet, Accepted: 1, Rejected: 0, Sampled: 0},
+	}
+
+	if len(stats.Clients) != len(want) || stats.Clients[0] != want[0] || stats.Clients[1] != want[1] {
+		t.Errorf(clientStatsFmt, stats.Clients, want)
+	}
+
+	output := buf.String()
+	if !strings.Contains(output, clientSummary) {
+		t.Errorf(clientOutputFmt, clientSummary, output)
+	}
+
+	var metrics strings.Builder
+
+	err := stats.WritePrometheus(&metrics)
+	if err != nil || !strings.Contains(metrics.String(), clientMetric) {
+		t.Errorf(clientMetricFmt, clientMetric, metrics.String())
+	}
+}
+
+func TestClientLimiter_Unlimited(t *testing.T) {
+	t.Parallel()
+
+	loggerInstance := logger.NewStreamLogger(&bytes.Buffer{})
+	limiter := loggerInstance.LimitClients(logger.ClientRateLimit{Rate: 0, Burst: 0, SampleEvery: 0})
+
+	for range clientLines {
+		if !limiter.Allow(clientFlooding) {
+			t.Fatalf(clientAllowedFmt, clientFlooding, 0, 1, 1)
+		}
+	}
+
+	closeTestLogger(t, loggerInstance)
+}
+
+func TestClientLimiter_RetiresIdleClients(t *testing.T) {
+	t.Parallel()
+
+	current := time.Date(2025, time.March, 1, 8, 0, 0, 0, time.UTC)
+	loggerInstance := logger.NewStreamLogger(&bytes.Buffer{}, logger.WithClock(func() time.Time { return current }))
+	limiter := loggerInstance.LimitClients(logger.ClientRateLimit{Rate: 1, Burst: 1, SampleEvery: 0})
+
+	for client := range clientCapacity {
+		limiter.Allow(strconv.Itoa(client))
+	}
+
+	// Every bucket is empty, so the newcomer shares the bucket of "other".
+	limiter.Allow(clientNewcomer)
+
+	if stats := limiter.Stats(); stats[len(stats)-1].Client != logger.ClientOther {
+		t.Errorf(clientStatsFmt, stats[len(stats)-1], logger.ClientOther)
+	}
+
+	// Once the buckets refilled, the idle clients make room for the newcomer.
+	current = current.Add(time.Second)
+	limiter.Allow(clientNewcomer)
+
+	stats := limiter.Stats()
+	if len(stats) != 2 || stats[0].Client != clientNewcomer || stats[1].Accepted != clientCapacity+1 {
+		t.Errorf(clientStatsFmt, stats, clientNewcomer)
+	}
+
+	closeTestLogger(t, loggerInstance)
+}
+
+func TestClientLimiter_SweepsOncePerInterval(t *testing.T) {
+	t.Parallel()
+
+	current := time.Date(2025, time.March, 1, 8, 0, 0, 0, time.UTC)
+	loggerInstance := logger.NewStreamLogger(&bytes.Buffer{}, logger.WithClock(func() time.Time { return current }))
+	limiter := loggerInstance.LimitClients(logger.ClientRateLimit{Rate: 4, Burst: 1, SampleEvery: 0})
+
+	for client := range clientCapacity {
+		limiter.Allow(strconv.Itoa(client))
+	}
+
+	// The first newcomer sweeps and finds no idle client.
+	limiter.Allow(fmt.Sprintf(clientLateFmt, 0))
+
+	// The buckets refill within a quarter second, but the next sweep waits
+	// for the interval, so these newcomers share the bucket of "other".
+	current = current.Add(time.Second / 2)
+	for client := 1; client < clientLines; client++ {
+		limiter.Allow(fmt.Sprintf(clientLateFmt, client))
+	}
+
+	if stats := limiter.Stats(); len(stats) != clientCapacity+1 {
+		t.Errorf(clientCountFmt, len(stats), clientCapacity+1)
+	}
+
+	current = current.Add(time.Second / 2)
+	limiter.Allow(clientNewcomer)
+
+	if stats := limiter.Stats(); len(stats) != 2 || stats[0].Client != clientNewcomer {
+		t.Errorf(clientStatsFmt, stats, clientNewcomer)
+	}
+
+	closeTestLogger(t, loggerInstance)
+}
+
+func TestClientLimiter_Forget(t *testing.T) {
+	t.Parallel()
+
+	loggerInstance := logger.NewStreamLogger(&bytes.Buffer{})
+	limiter := loggerInstance.LimitClients(logger.ClientRateLimit{Rate: 1, Burst: 2, SampleEvery: 0})
+
+	limiter.Allow(clientFlooding)
+	limiter.Allow(clientQuiet)
+	limiter.Forget(clientFlooding)
+
+	stats := limiter.Stats()
+	if len(stats) != 2 {
+		t.Fatalf(clientCountFmt, len(stats), 2)
+	}
+
+	want := []logger.ClientStats{
+		{Client: clientQuiet, Accepted: 1, Rejected: 0, Sampled: 0},
+		{Client: logger.ClientOther, Accepted: 1, Rejected: 0, Sampled: 0},
+	}
+	if stats[0] != want[0] || stats[1] != want[1] {
+		t.Errorf(clientStatsFmt, stats, want)
+	}
+
+	closeTestLogger(t, loggerInstance)
+}
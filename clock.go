@@ -0,0 +1,15 @@
+package logger
+
+import "time"
+
+// WithClock stamps entries with the time returned by now instead of
+// time.Now, and measures {elapsed} from its value when the logger is
+// created. A fixed clock makes the output reproducible, as used by
+// loggertest.Golden. A nil now keeps time.Now.
+func WithClock(now func() time.Time) Option {
+	return func(config *options) {
+		if now != nil {
+			config.clock = now
+		}
+	}
+}
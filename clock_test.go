@@ -0,0 +1,42 @@
+package logger_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	clockMsg       = "tick"
+	clockElapsed   = 90 * time.Second
+	clockLineFmt   = "expected %q, got %q"
+	clockExpected1 = "2024/03/04 05:06:07 +00:00:00.000 [INFO] tick"
+	clockExpected2 = "2024/03/04 05:07:37 +00:01:30.000 [INFO] tick"
+)
+
+func TestWithClock_StampsEntries(t *testing.T) {
+	t.Parallel()
+
+	current := time.Date(2024, time.March, 4, 5, 6, 7, 0, time.UTC)
+
+	var buf bytes.Buffer
+
+	loggerInstance := logger.NewStreamLogger(&buf,
+		logger.WithLayout(logger.MustParseLayout(logger.ElapsedLayout)),
+		logger.WithClock(func() time.Time { return current }),
+	)
+	loggerInstance.Infof(clockMsg)
+
+	current = current.Add(clockElapsed)
+	loggerInstance.Infof(clockMsg)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	for index, expected := range []string{clockExpected1, clockExpected2} {
+		if index >= len(lines) || strings.TrimSpace(lines[index]) != expected {
+			t.Errorf(clockLineFmt, expected, lines)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	forwardLogFile   = "app.log"
+	forwardOutFile   = "collector.log"
+	forwardPublicMsg = "order placed"
+	forwardSecretMsg = "signing key rotated"
+
+	forwardMissingFmt = "forwarded lines lack %q:\n%s"
+	forwardLeakFmt    = "forwarded lines leak %q:\n%s"
+)
+
+func TestRunForward_KeepsSecretEntries(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	out := filepath.Join(t.TempDir(), forwardOutFile)
+
+	loggerInstance, err := logger.New(dir, forwardLogFile, logger.WithoutStdout())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loggerInstance.Infof(forwardPublicMsg)
+	loggerInstance.WithSensitivity(logger.SensitivitySecret).Infof(forwardSecretMsg)
+	loggerInstance.Infof(forwardPublicMsg)
+
+	err = loggerInstance.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = runForward([]string{"-dir", dir, "-state", t.TempDir(), "-to", out, "-once"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	forwarded := string(data)
+	if strings.Count(forwarded, forwardPublicMsg) != 2 {
+		t.Errorf(forwardMissingFmt, forwardPublicMsg, forwarded)
+	}
+
+	if strings.Contains(forwarded, forwardSecretMsg) {
+		t.Errorf(forwardLeakFmt, forwardSecretMsg, forwarded)
+	}
+}
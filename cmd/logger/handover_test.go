@@ -0,0 +1,124 @@
+package main
+
+import (
+	"io"
+	"log"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	// envHandoverChild makes the test binary act as the new process of a
+	// handover instead of running the tests.
+	envHandoverChild   = "LOGGER_TEST_HANDOVER_CHILD"
+	handoverGreeting   = "served by the new process"
+	handoverTimeout    = 10 * time.Second
+	handoverSkipMsg    = "no restart signal on this platform"
+	handoverChildFmt   = "handover child: %v"
+	handoverInheritFmt = "handover child: inherited %d listeners, want 1"
+
+	greetingFmt = "greeting = %q, want %q"
+)
+
+func TestMain(m *testing.M) {
+	if os.Getenv(envHandoverChild) != "" {
+		os.Exit(runHandoverChild())
+	}
+
+	os.Exit(m.Run())
+}
+
+// runHandoverChild serves handoverGreeting on the listener the test passed,
+// as a restarted daemon serves on the sockets of the previous process.
+func runHandoverChild() int {
+	restart := newHandover()
+	if len(restart.inherited) != 1 {
+		log.Printf(handoverInheritFmt, len(restart.inherited))
+
+		return 1
+	}
+
+	var listener net.Listener
+
+	for addr := range restart.inherited {
+		var err error
+
+		listener, err = restart.listen(addr)
+		if err != nil {
+			log.Printf(handoverChildFmt, err)
+
+			return 1
+		}
+	}
+
+	restart.takeOver(logger.NewStreamLogger(io.Discard))
+
+	conn, err := listener.Accept()
+	if err != nil {
+		log.Printf(handoverChildFmt, err)
+
+		return 1
+	}
+
+	_, err = conn.Write([]byte(handoverGreeting))
+	conn.Close()
+
+	if err != nil {
+		log.Printf(handoverChildFmt, err)
+
+		return 1
+	}
+
+	return 0
+}
+
+func TestHandover_PassesListeners(t *testing.T) {
+	if len(restartSignals) == 0 {
+		t.Skip(handoverSkipMsg)
+	}
+
+	t.Setenv(envHandoverChild, "1")
+
+	restart := newHandover()
+
+	listener, err := restart.listen(testLoopback)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addr := listener.Addr().String()
+
+	err = restart.start(logger.NewStreamLogger(io.Discard))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The new process keeps the socket open once this one no longer
+	// listens, and takes over once this one exits.
+	listener.Close()
+	restart.successor.Close()
+
+	conn, err := net.DialTimeout(testNetwork, addr, handoverTimeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	err = conn.SetDeadline(time.Now().Add(handoverTimeout))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	greeting, err := io.ReadAll(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(greeting) != handoverGreeting {
+		t.Errorf(greetingFmt, greeting, handoverGreeting)
+	}
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testLimit       = 8
+	testLifetime    = 50 * time.Millisecond
+	testBlockWait   = 20 * time.Millisecond
+	testHandleLimit = 5 * time.Second
+
+	nextFmt       = "next() #%d = %q, %d, %v, want %q, %d, %v"
+	budgetUsedFmt = "used = %d, want %d"
+	budgetHeldFmt = "%s = %d, want %d"
+	lifetimeFmt   = "connection still open %v after its lifetime"
+	lifetimeMsg   = "before the deadline"
+	lifetimeLine  = "WARN:" + lifetimeMsg + "\n"
+	readErrFmt    = "read after the lifetime = %v, want %v"
+	truncatedLine = "truncated_from"
+)
+
+// readResult is one line returned by lineReader.next.
+type readResult struct {
+	line string
+	size int
+	err  error
+}
+
+func TestLineReader_Next(t *testing.T) {
+	t.Parallel()
+
+	long := strings.Repeat("a", 5000)
+
+	tests := []struct {
+		name  string
+		input string
+		want  []readResult
+	}{
+		{
+			name:  "short line",
+			input: "short\n",
+			want:  []readResult{{line: "short", size: 0, err: nil}, {line: "", size: 0, err: io.EOF}},
+		},
+		{
+			name:  "line at the limit",
+			input: "01234567\n",
+			want:  []readResult{{line: "01234567", size: 0, err: nil}, {line: "", size: 0, err: io.EOF}},
+		},
+		{
+			name:  "line at the limit with CRLF",
+			input: "01234567\r\n",
+			want:  []readResult{{line: "01234567", size: 0, err: nil}, {line: "", size: 0, err: io.EOF}},
+		},
+		{
+			name:  "truncated line",
+			input: "0123456789\nnext\n",
+			want: []readResult{
+				{line: "01234567", size: 10, err: nil},
+				{line: "next", size: 0, err: nil},
+				{line: "", size: 0, err: io.EOF},
+			},
+		},
+		{
+			name:  "truncated line with CRLF",
+			input: "0123456789\r\n",
+			want:  []readResult{{line: "01234567", size: 10, err: nil}, {line: "", size: 0, err: io.EOF}},
+		},
+		{
+			name:  "line beyond the read buffer",
+			input: long + "\nnext\n",
+			want: []readResult{
+				{line: long[:testLimit], size: len(long), err: nil},
+				{line: "next", size: 0, err: nil},
+				{line: "", size: 0, err: io.EOF},
+			},
+		},
+		{
+			name:  "unterminated last line",
+			input: "0123456789",
+			want:  []readResult{{line: "01234567", size: 10, err: io.EOF}},
+		},
+		{
+			name:  "empty line",
+			input: "\n",
+			want:  []readResult{{line: "", size: 0, err: nil}, {line: "", size: 0, err: io.EOF}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			lines := newLineReader(strings.NewReader(test.input), testLimit)
+
+			for index, want := range test.want {
+				line, size, err := lines.next()
+				if line != want.line || size != want.size || !errors.Is(err, want.err) {
+					t.Errorf(nextFmt, index, line, size, err, want.line, want.size, want.err)
+				}
+			}
+		})
+	}
+}
+
+func TestLineServer_MaxLifetime(t *testing.T) {
+	t.Parallel()
+
+	server, buf := newTestServer(t, nil)
+	server.lifetime = testLifetime
+
+	clientConn, done := startTestConn(t, server)
+
+	_, err := clientConn.Write([]byte(lifetimeLine))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The client keeps the connection open; the server must close it.
+	select {
+	case <-done:
+	case <-time.After(testHandleLimit):
+		t.Fatalf(lifetimeFmt, testHandleLimit)
+	}
+
+	_, err = clientConn.Read(make([]byte, 1))
+	if !errors.Is(err, io.EOF) {
+		t.Errorf(readErrFmt, err, io.EOF)
+	}
+
+	if !strings.Contains(buf.String(), lifetimeMsg) {
+		t.Errorf(listenOutputFmt, buf.String(), lifetimeMsg)
+	}
+}
+
+func TestByteBudget_Nil(t *testing.T) {
+	t.Parallel()
+
+	budget := newByteBudget(0)
+	if budget != nil {
+		t.Fatalf(budgetHeldFmt, "newByteBudget(0)", budget.limit, 0)
+	}
+
+	held := budget.acquire(testLimit)
+	held = budget.shrink(held, 1)
+	budget.release(held)
+
+	if held != 0 {
+		t.Errorf(budgetHeldFmt, "held", held, 0)
+	}
+}
+
+func TestByteBudget_Accounting(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		acquire  int
+		lineSize int
+		wantHeld int
+		wantKept int
+	}{
+		{name: "short line", acquire: testLimit, lineSize: 3, wantHeld: testLimit, wantKept: 3},
+		{name: "line at the budget", acquire: testLimit, lineSize: testLimit, wantHeld: testLimit, wantKept: testLimit},
+		{name: "truncated line", acquire: testLimit, lineSize: testLimit * 4, wantHeld: testLimit, wantKept: testLimit},
+		{name: "reservation beyond the budget", acquire: testLimit * 4, lineSize: 3, wantHeld: testLimit, wantKept: 3},
+		{name: "empty line", acquire: testLimit, lineSize: 0, wantHeld: testLimit, wantKept: 0},
+	}
+
+	for _, test := range tests {
+		budget := newByteBudget(testLimit)
+
+		held := budget.acquire(test.acquire)
+		if held != test.wantHeld {
+			t.Errorf(budgetHeldFmt, test.name+" acquire", held, test.wantHeld)
+		}
+
+		kept := budget.shrink(held, test.lineSize)
+		if kept != test.wantKept {
+			t.Errorf(budgetHeldFmt, test.name+" shrink", kept, test.wantKept)
+		}
+
+		assertBudgetUsed(t, budget, test.wantKept)
+		budget.release(kept)
+		assertBudgetUsed(t, budget, 0)
+	}
+}
+
+func TestByteBudget_AcquireBlocks(t *testing.T) {
+	t.Parallel()
+
+	budget := newByteBudget(testLimit)
+	held := budget.acquire(testLimit - 2)
+
+	acquired := make(chan int)
+
+	go func() {
+		acquired <- budget.acquire(testLimit / 2)
+	}()
+
+	select {
+	case size := <-acquired:
+		t.Fatalf(budgetHeldFmt, "acquire beyond the budget returned", size, 0)
+	case <-time.After(testBlockWait):
+	}
+
+	// Shrinking to what a short line takes frees enough for the waiter.
+	held = budget.shrink(held, 1)
+
+	size := <-acquired
+	if size != testLimit/2 {
+		t.Errorf(budgetHeldFmt, "acquire", size, testLimit/2)
+	}
+
+	assertBudgetUsed(t, budget, held+size)
+	budget.release(held)
+	budget.release(size)
+	assertBudgetUsed(t, budget, 0)
+}
+
+func TestLineServer_InFlightAfterTruncation(t *testing.T) {
+	t.Parallel()
+
+	server, buf := newTestServer(t, nil)
+	server.maxLine = testLimit
+	server.inFlight = newByteBudget(testLimit)
+
+	serveTestConn(t, server, "WARN:0123456789\nshort\n"+strings.Repeat("b", 5000)+"\n")
+
+	assertBudgetUsed(t, server.inFlight, 0)
+
+	if !strings.Contains(buf.String(), truncatedLine) {
+		t.Errorf(listenOutputFmt, buf.String(), truncatedLine)
+	}
+}
+
+// assertBudgetUsed fails t unless budget holds want bytes.
+func assertBudgetUsed(t *testing.T, budget *byteBudget, want int) {
+	t.Helper()
+
+	budget.cond.L.Lock()
+	used := budget.used
+	budget.cond.L.Unlock()
+
+	if used != want {
+		t.Errorf(budgetUsedFmt, used, want)
+	}
+}
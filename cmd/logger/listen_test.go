@@ -0,0 +1,307 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	testToken      = "tok1"
+	testTag        = "app1"
+	testSharedTok  = "shared"
+	testMaxLine    = 1024
+	testTokenFile  = "tokens"
+	testFileMode   = 0o600
+	testClientRate = 1000
+	testProducer   = logger.ProducerField + "="
+	testNetwork    = "tcp"
+	testLoopback   = "127.0.0.1:0"
+
+	listenOutputFmt = "output = %q, want it to contain %q"
+	listenLeakFmt   = "output = %q, must not contain %q"
+	lookupFmt       = "lookup(%q) = %q, %t, want %q, %t"
+	loadTokensFmt   = "loadListenTokens() = %v, %v, want %v"
+	loadTokensErr   = "loadListenTokens() error = %v, want %v"
+)
+
+// newTestServer returns a lineServer logging to a buffer, as serveLines sets
+// it up, without a listener.
+func newTestServer(t *testing.T, tokens listenTokens) (*lineServer, *bytes.Buffer) {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+	loggerInstance := logger.NewStreamLogger(buf)
+
+	return &lineServer{
+		logger: loggerInstance,
+		limiter: loggerInstance.LimitClients(logger.ClientRateLimit{
+			Rate:        testClientRate,
+			Burst:       testClientRate,
+			SampleEvery: 0,
+		}),
+		failures: loggerInstance.EveryN(authWarnEvery),
+		listener: nil,
+		tokens:   tokens,
+		conns:    make(map[net.Conn]struct{}),
+		inFlight: nil,
+		wg:       sync.WaitGroup{},
+		mu:       sync.Mutex{},
+		lifetime: 0,
+		maxLine:  testMaxLine,
+		byConn:   false,
+	}, buf
+}
+
+// startTestConn connects a client to server over loopback and handles the
+// connection in the background; done is closed when handle returns.
+func startTestConn(t *testing.T, server *lineServer) (net.Conn, <-chan struct{}) {
+	t.Helper()
+
+	listener, err := net.Listen(testNetwork, testLoopback)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	clientConn, err := net.Dial(testNetwork, listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { clientConn.Close() })
+
+	serverConn, err := listener.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+
+	server.wg.Add(1)
+
+	go func() {
+		server.handle(serverConn)
+		close(done)
+	}()
+
+	return clientConn, done
+}
+
+// serveTestConn sends input to server, closes the client end and waits for
+// handle to return.
+func serveTestConn(t *testing.T, server *lineServer, input string) {
+	t.Helper()
+
+	clientConn, done := startTestConn(t, server)
+
+	_, err := clientConn.Write([]byte(input))
+	if err != nil {
+		t.Error(err)
+	}
+
+	clientConn.Close()
+	<-done
+}
+
+func TestLineServer_Authenticate(t *testing.T) {
+	t.Parallel()
+
+	tokens := listenTokens{testToken: testTag, testSharedTok: ""}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "tagged token",
+			input:   "AUTH tok1\nWARN:hello\n",
+			want:    []string{"hello", testProducer + testTag},
+			notWant: []string{"failed authentication"},
+		},
+		{
+			name:    "relabel attempt",
+			input:   "AUTH tok1\n[app2] WARN:hello\n[x] [app2] WARN:again\n",
+			want:    []string{"hello", testProducer + testTag},
+			notWant: []string{testProducer + "app2"},
+		},
+		{
+			name:    "shared token keeps the line's tag",
+			input:   "AUTH shared\n[app3] WARN:hello\n",
+			want:    []string{"hello", testProducer + "app3"},
+			notWant: nil,
+		},
+		{
+			name:    "bad token",
+			input:   "AUTH nope\nWARN:hello\n",
+			want:    []string{"failed authentication"},
+			notWant: []string{"hello"},
+		},
+		{
+			name:    "missing AUTH line",
+			input:   "WARN:hello\n",
+			want:    []string{"failed authentication"},
+			notWant: []string{"hello"},
+		},
+		{
+			name:    "hang up before AUTH",
+			input:   "",
+			want:    []string{"failed authentication"},
+			notWant: nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			server, buf := newTestServer(t, tokens)
+			serveTestConn(t, server, test.input)
+
+			output := buf.String()
+			for _, want := range test.want {
+				if !strings.Contains(output, want) {
+					t.Errorf(listenOutputFmt, output, want)
+				}
+			}
+
+			for _, notWant := range test.notWant {
+				if strings.Contains(output, notWant) {
+					t.Errorf(listenLeakFmt, output, notWant)
+				}
+			}
+		})
+	}
+}
+
+func TestListenTokens_Lookup(t *testing.T) {
+	t.Parallel()
+
+	tokens := listenTokens{testToken: testTag, testSharedTok: ""}
+
+	tests := []struct {
+		token   string
+		wantTag string
+		wantOK  bool
+	}{
+		{token: testToken, wantTag: testTag, wantOK: true},
+		{token: testSharedTok, wantTag: "", wantOK: true},
+		{token: "tok", wantTag: "", wantOK: false},
+		{token: "tok12", wantTag: "", wantOK: false},
+		{token: "", wantTag: "", wantOK: false},
+	}
+
+	for _, test := range tests {
+		tag, ok := tokens.lookup(test.token)
+		if tag != test.wantTag || ok != test.wantOK {
+			t.Errorf(lookupFmt, test.token, tag, ok, test.wantTag, test.wantOK)
+		}
+	}
+}
+
+func TestLoadListenTokens(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		shared string
+		file   string
+		want   listenTokens
+		err    error
+	}{
+		{
+			name:   "no tokens",
+			shared: "",
+			file:   "",
+			want:   nil,
+			err:    nil,
+		},
+		{
+			name:   "shared token only",
+			shared: testSharedTok,
+			file:   "",
+			want:   listenTokens{testSharedTok: ""},
+			err:    nil,
+		},
+		{
+			name:   "comments and blank lines",
+			shared: testSharedTok,
+			file:   "# tokens of the app hosts\n\napp1=tok1\n  # indented comment\n\t\napp2=tok2\n",
+			want:   listenTokens{testSharedTok: "", testToken: testTag, "tok2": "app2"},
+			err:    nil,
+		},
+		{
+			name:   "line without separator",
+			shared: "",
+			file:   "app1=tok1\ntok2\n",
+			want:   nil,
+			err:    ErrInvalidToken,
+		},
+		{
+			name:   "missing tag",
+			shared: "",
+			file:   "=tok1\n",
+			want:   nil,
+			err:    ErrInvalidToken,
+		},
+		{
+			name:   "missing token",
+			shared: "",
+			file:   "app1=\n",
+			want:   nil,
+			err:    ErrInvalidToken,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := &config{listenToken: test.shared}
+			if test.file != "" {
+				cfg.tokenFile = filepath.Join(t.TempDir(), testTokenFile)
+
+				err := os.WriteFile(cfg.tokenFile, []byte(test.file), testFileMode)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			tokens, err := loadListenTokens(cfg)
+			if !errors.Is(err, test.err) {
+				t.Fatalf(loadTokensErr, err, test.err)
+			}
+
+			if len(tokens) != len(test.want) || (tokens == nil) != (test.want == nil) {
+				t.Fatalf(loadTokensFmt, tokens, err, test.want)
+			}
+
+			for token, tag := range test.want {
+				got, ok := tokens[token]
+				if !ok || got != tag {
+					t.Errorf(loadTokensFmt, tokens, err, test.want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadListenTokens_MissingFile(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config{tokenFile: filepath.Join(t.TempDir(), testTokenFile)}
+
+	_, err := loadListenTokens(cfg)
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf(loadTokensErr, err, os.ErrNotExist)
+	}
+}
@@ -3,12 +3,25 @@ package main
 
 import (
 	"bufio"
+	"bytes"
+	"context"
+	"crypto/subtle"
 	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log"
+	"net"
+	"net/http"
 	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/book-expert/logger"
@@ -24,31 +37,276 @@ const (
 	flagNameMessage      = "message"
 	flagNameHelp         = "help"
 	flagNameDaemon       = "daemon"
+	flagNameLevels       = "levels"
+	flagNameConfig       = "config"
+	flagNameMetrics      = "metrics"
+	flagNameRecent       = "recent"
+	flagNameWatchGlob    = "watch-glob"
+	flagNameWatchRegex   = "watch-regex"
+	flagNameWatchTime    = "watch-time-layout"
+	flagNameWatchState   = "watch-state"
+	flagNameWatchGrok    = "watch-grok"
+	flagNameGrokPatterns = "grok-patterns"
+	flagNameMultiline    = "watch-multiline"
+	flagNameMultiNegate  = "watch-multiline-negate"
+	flagNameMultiTimeout = "watch-multiline-timeout"
+	flagNameFormat       = "format"
+	flagNameDiskLimit    = "disk-limit"
+	flagNameDocker       = "docker"
+	flagNameDockerRoot   = "docker-root"
+	flagNameDockerNames  = "docker-containers"
+	flagNameDockerState  = "docker-state"
+	flagNameKube         = "kube"
+	flagNameKubeSelector = "kube-selector"
+	flagNameKubeNS       = "kube-namespace"
+	flagNameKubeServer   = "kube-server"
+	flagNameExpectTag    = "expect-tag"
+	flagNameExpectHook   = "expect-webhook"
+	flagNameListen       = "listen"
+	flagNameClientRate   = "client-rate"
+	flagNameClientBurst  = "client-burst"
+	flagNameClientSample = "client-sample"
+	flagNameClientBy     = "client-limit-by"
+	flagNameListenToken  = "listen-token"
+	flagNameTokenFile    = "listen-token-file"
+	flagNameReloadToken  = "reload-token-file"
+	flagNameMaxLine      = "max-line"
+	flagNameMaxLifetime  = "client-max-lifetime"
+	flagNameMaxInFlight  = "max-inflight"
+	flagNameBackfillAge  = "backfill-threshold"
+	flagNameBackfillFile = "backfill-file"
+	flagNameAggregate    = "aggregate"
+	flagNameAggregateWin = "aggregate-window"
+	flagNameRateFactor   = "rate-anomalies"
 	usageDir             = "Log directory"
 	usageFile            = "Log filename (required)"
 	usageLevel           = "Log level (info, warn, error, success, fatal, panic, system)"
 	usageMessage         = "Log message (required)"
 	usageHelp            = "Show help"
 	usageDaemon          = "Run as daemon service (accept log messages on stdin)"
+	usageLevels          = "Custom levels as NAME=SEVERITY pairs, comma separated"
+	usageConfig          = "JSON configuration file declaring the log file and sinks"
+	usageMetrics         = "Address to serve Prometheus metrics on in daemon mode"
+	usageRecent          = "Keep this much recent history in memory, served by -metrics at /entries"
+	usageWatchGlob       = "Tail the files matching this pattern in daemon mode instead of reading stdin"
+	usageWatchRegex      = "Regular expression with time, level and msg groups parsing watched lines"
+	usageWatchTime       = "Time layout of the time group of -watch-regex"
+	usageWatchState      = "File holding the watch cursors, so a restart resumes where it stopped"
+	usageWatchGrok       = "File of GLOB=PATTERN lines tailing the files of GLOB and parsing them with the grok PATTERN"
+	usageGrokPatterns    = "File of NAME REGEX grok pattern definitions for -watch-grok"
+	usageMultiline       = "Regular expression of the lines continuing a watched entry, or default for Java stack traces"
+	usageMultiNegate     = "Lines not matching -watch-multiline continue the entry"
+	usageMultiTimeout    = "Log a stitched entry once no line arrived for this long"
+	usageFormat          = "Encoding of stdout and the log file: text, json, cef, leef or syslog"
+	usageDiskLimit       = "Bound the writes to the log and route files to this many MB/s"
+	bytesPerMB           = 1 << 20
+	multilineDefault     = "default"
+	errorFmtMultiline    = "-watch-multiline: %w"
+	usageDocker          = "Collect the json-file logs of Docker containers in daemon mode"
+	usageDockerRoot      = "Directory of the Docker containers"
+	usageDockerNames     = "Container names or ID prefixes to collect, comma separated (default: all)"
+	usageDockerState     = "Directory holding one cursor file per container"
+	usageKube            = "Stream the logs of Kubernetes pods in daemon mode"
+	usageKubeSelector    = "Label selector of the streamed pods, e.g. app=web (default: all)"
+	usageKubeNS          = "Namespace of the streamed pods (default: the pod's own, or all with -kube-server)"
+	usageKubeServer      = "Kubernetes API address, e.g. http://127.0.0.1:8001 of kubectl proxy (default: in-cluster)"
+	usageExpectTag       = "Warn when a tag logs nothing for its interval, as TAG=DURATION pairs, comma separated"
+	usageExpectHook      = "Sink address, e.g. https://hooks.example.com/x, also sent the -expect-tag warnings"
+	usageListen          = "Address to accept log lines on over TCP in daemon mode instead of reading stdin"
+	usageClientRate      = "Lines per second each -listen client may send on average (default: unlimited)"
+	usageClientBurst     = "Lines a -listen client may send at once above -client-rate"
+	usageClientSample    = "Keep every Nth line over -client-rate instead of rejecting them all"
+	usageClientBy        = "Key of the -client-rate limit: ip or connection"
+	usageListenToken     = "Token -listen clients must send first, as AUTH TOKEN"
+	usageTokenFile       = "File with one TAG=TOKEN line per -listen client; its lines are logged under TAG"
+	usageReloadToken     = "File with the token POST /reload requires as Authorization: Bearer TOKEN (default: loopback clients only)"
+	usageMaxLine         = "Longest input line in bytes; longer lines are truncated"
+	usageMaxLifetime     = "Close -listen connections after this long, e.g. 1h (default: never)"
+	usageMaxInFlight     = "Bytes of -listen lines being read or logged, reserving -max-line per line; clients wait beyond it (default: unlimited)"
+	usageBackfillAge     = "Tag watched entries older than the newest written by more than this, e.g. 1h"
+	usageBackfillFile    = "Write the entries of -backfill-threshold to this file in -dir instead"
+	usageAggregate       = "Sink address or file to write per-window rollups of INFO and lower entries to instead of them"
+	usageAggregateWin    = "Rollup window of -aggregate"
+	usageRateFactor      = "Warn when the per-minute rate of a level and tag deviates from its baseline by this factor"
+	errorFmtAggregate    = "open -aggregate: %w"
+	defaultMaxLine       = 64 << 10
+	stdinSource          = "stdin"
+	truncatedField       = "truncated_from"
+	truncateWarnEvery    = 100
+	daemonTruncatedFmt   = "line from %s truncated from %d to %d bytes"
+	defaultClientBurst   = 100
+	clientByIP           = "ip"
+	clientByConnection   = "connection"
 	logLevelINFO         = "INFO"
 	errorFormat          = "error: %v\n"
 	errorClosingLogger   = "error closing logger: %v"
 	errorCreatingLogger  = "error creating logger: %w"
 	errorFmtUnknownLevel = "%w: '%s'"
-	daemonLogFilenameFmt = "daemon-%s.log"
-	daemonTimestampFmt   = "20060102-150405"
-	daemonStartedMsg     = "Logger daemon started, reading from stdin..."
-	daemonStartedInfoFmt = "Logger daemon started: %s/%s\n"
-	daemonUsageMsg       = "Send log messages in format: LEVEL:MESSAGE"
-	daemonExampleMsg     = "Example: INFO:Application started"
-	daemonStopMsg        = "Press Ctrl+C to stop"
-	daemonStoppedMsg     = "Logger daemon stopped"
-	daemonStdinErrorFmt  = "error reading from stdin: %v"
-	logLineSplitCount    = 2
+	// Custom level definitions.
+	levelListSeparator       = ","
+	levelDefinitionSeparator = "="
+	errorFmtLevelDefinition  = "%w: '%s'"
+	errorFmtRegisterLevel    = "register level: %w"
+	daemonLogFilenameFmt     = "daemon-%s.log"
+	daemonTimestampFmt       = "20060102-150405"
+	daemonStartedMsg         = "Logger daemon started, reading from stdin..."
+	daemonStartedInfoFmt     = "Logger daemon started: %s/%s\n"
+	daemonUsageMsg           = "Send log messages in format: [TAG] LEVEL:MESSAGE (TAG optional)"
+	daemonExampleMsg         = "Example: [billing] INFO:Application started"
+	daemonStopMsg            = "Press Ctrl+C to stop"
+	daemonStoppedMsg         = "Logger daemon stopped"
+	daemonStdinErrorFmt      = "error reading from stdin: %v"
+	daemonMetricsFmt         = "Serving metrics on http://%s%s\n"
+	daemonMetricsErrFmt      = "metrics server stopped: %v"
+	daemonWatchingFmt        = "Logger daemon watching %s"
+	daemonWatchInfoFmt       = "Logger daemon watching %s\n"
+	daemonWatchErrorFmt      = "watch: %v"
+	daemonWatchEvery         = time.Second
+	metricsPath              = "/metrics"
+	entriesPath              = "/entries"
+	statsPath                = "/stats"
+	reloadPath               = "/reload"
+	headerAllow              = "Allow"
+	headerAuthorization      = "Authorization"
+	headerAuthenticate       = "WWW-Authenticate"
+	bearerScheme             = "Bearer"
+	bearerPrefix             = bearerScheme + " "
+	daemonReloadErrFmt       = "reload %s: %v"
+	daemonReloadDeniedFmt    = "reload request from %s denied"
+	// Restarts with socket handover.
+	envListenAddrs         = "LOGGER_LISTEN_ADDRS"
+	envReadyFD             = "LOGGER_READY_FD"
+	envPreviousFD          = "LOGGER_PREVIOUS_FD"
+	envAssignFmt           = "%s=%s"
+	listenAddrSeparator    = ","
+	extraFilesFD           = 3 // the descriptor of exec.Cmd.ExtraFiles[0]
+	handoverReadyTimeout   = 30 * time.Second
+	handoverDrainTimeout   = 2 * time.Second
+	metricsShutdownTimeout = 5 * time.Second
+	daemonHandoverFmt      = "Logger daemon handing over to process %d"
+	daemonTakeoverMsg      = "Logger daemon took over from the previous process"
+	daemonRestartErrFmt    = "restart: %v"
+	errorFmtHandoverFile   = "pass listener %s: %w"
+	errorFmtHandoverStart  = "start new process: %w"
+	errorFmtHandoverReady  = "%w: %w"
+	logTagOpen             = "["
+	logTagClose            = "] "
+	metricsReadTimeout     = 10 * time.Second
+	errorFmtMetrics        = "listen for metrics: %w"
+	errorFmtExpectTag      = "%w: '%s'"
+	errorFmtExpectHook     = "open -expect-webhook: %w"
+	daemonExpectHookFmt    = "expect webhook: %v"
+	daemonListeningFmt     = "Logger daemon listening on %s"
+	daemonListenInfoFmt    = "Logger daemon listening on %s\n"
+	daemonListenErrFmt     = "listen: %v"
+	daemonClientErrFmt     = "client %s: %v"
+	errorFmtListen         = "listen for lines: %w"
+	errorFmtClientBy       = "%w: '%s'"
+	acceptRetryDelay       = 100 * time.Millisecond
+	authPrefix             = "AUTH "
+	authTimeout            = 10 * time.Second
+	authWarnEvery          = 100
+	daemonAuthFailedFmt    = "client %s failed authentication"
+	errorFmtTokenLine      = "%w: line %d"
+	errorFmtGrokLine       = "-watch-grok line %d: %w"
+	errorFmtReadGrok       = "read %s: %w"
+	grokStateFmt           = "%s.%d"
+	logLineSplitCount      = 2
+	// Audit verification.
+	verifyCommand        = "verify"
+	rewrapCommand        = "rewrap"
+	unwrapCommand        = "unwrap"
+	redeliverCommand     = "redeliver"
+	forwardCommand       = "forward"
+	bundleCommand        = "bundle"
+	doctorCommand        = "doctor"
+	flagNameSince        = "since"
+	flagNameRedact       = "redact"
+	flagNameKey          = "key"
+	flagNameKeyFile      = "key-file"
+	flagNameKeyring      = "keyring"
+	flagNameOut          = "out"
+	flagNameNewKeyID     = "new-key-id"
+	flagNameNewKeyFile   = "new-key-file"
+	flagNameDLQ          = "dlq"
+	flagNameTo           = "to"
+	flagNameState        = "state"
+	flagNameGlob         = "glob"
+	flagNameInterval     = "interval"
+	flagNameOnce         = "once"
+	usageVerifyFile      = "Audit log file to verify (required)"
+	usageRewrapFile      = "Audit log file to reseal (required)"
+	usageUnwrapFile      = "Ring file to unwrap (required)"
+	usageUnwrapOut       = "Path of the chronological copy; must not exist (default: stdout)"
+	errorFmtOpenRing     = "open ring file: %w"
+	errorFmtUnwrap       = "%s: %w"
+	usageDLQ             = "Dead letter file to replay (required)"
+	usageTo              = "Sink address, e.g. tcp://host:port or https://host/path (required)"
+	redeliverOKFmt       = "%s: redelivered %d entries to %s\n"
+	errorFmtRedeliver    = "%s: %w (after %d redelivered entries)"
+	errorFmtOpenSink     = "open sink: %w"
+	usageForwardDir      = "Directory of the log files to forward (required)"
+	usageForwardState    = "Directory holding the forwarding cursors (required)"
+	usageForwardTo       = "Sink address, e.g. loki://host:3100 or https://host/path (required)"
+	usageForwardGlob     = "Pattern of the forwarded files within -dir, including rotated backups"
+	usageForwardInterval = "How often to look for new lines"
+	usageForwardOnce     = "Forward what is there and exit"
+	defaultForwardGlob   = "*.log*"
+	defaultForwardEvery  = time.Second
+	forwardStateFile     = "forward.json"
+	forwardStatePerm     = 0o750
+	forwardErrorFmt      = "forward: %v"
+	forwardStartedFmt    = "Forwarding %s to %s\n"
+	errorFmtForwardState = "create state directory: %w"
+	usageBundleDir       = "Directory of the log files to bundle (required)"
+	usageBundleSince     = "Only bundle files modified within this duration, e.g. 24h (default: all)"
+	usageBundleOut       = "Path of the .tar.gz bundle; must not exist (required)"
+	usageBundleGlob      = "Pattern of the bundled files within -dir, including rotated backups"
+	usageBundleConfig    = "JSON configuration file declaring redaction profiles"
+	usageRedact          = "Redaction profile: default, none or one declared in -config"
+	bundleOKFmt          = "%s: bundled %d log files\n"
+	usageDoctorDir       = "Log directory to check (required unless -config names one)"
+	usageDoctorFile      = "Log file receiving the self-test probe"
+	usageDoctorConfig    = "JSON configuration file whose sinks are self-tested too"
+	defaultDoctorFile    = "doctor.log"
+	doctorAdviceOpen     = "check -dir, -file and the -config file"
+	doctorOKFmt          = "%s: no problems found, %d warnings\n"
+	errorFmtDoctor       = "%s: %w: %d"
+	usageKey             = "HMAC key used in audit mode"
+	usageKeyFile         = "File containing the HMAC key used in audit mode"
+	usageKeyring         = "File with one ID=SECRET line per audit key"
+	usageOut             = "Path of the resealed copy; must not exist (required)"
+	usageNewKeyID        = "ID recorded with the new key"
+	usageNewKeyFile      = "File containing the new HMAC key (required)"
+	verifyOKFmt          = "%s: verified %d entries\n"
+	rewrapOKFmt          = "%s: resealed %d entries into %s\n"
+	keyringLineSeparator = "\n"
+	keyringComment       = "#"
+	auditOutPerm         = 0o600
+	errorFmtVerify       = "%s: %w (after %d verified entries)"
+	errorFmtOpenAuditLog = "open audit log: %w"
+	errorFmtReadKeyFile  = "read key file: %w"
+	errorFmtKeyringLine  = "%w: '%s'"
+	errorFmtCreateOutput = "create output: %w"
 	// Error messages.
 	errFileRequiredMsg    = "-file is required"
 	errMessageRequiredMsg = "-message is required"
 	errUnknownLogLevelMsg = "unknown log level"
+	errInvalidLevelDefMsg = "invalid level definition, expected NAME=SEVERITY"
+	errKeyRequiredMsg     = "-key, -key-file or -keyring is required"
+	errRewrapArgsMsg      = "-file, -out and -new-key-file are required"
+	errInvalidKeyringMsg  = "invalid keyring line, expected ID=SECRET"
+	errRedeliverArgsMsg   = "-dlq and -to are required"
+	errForwardArgsMsg     = "-dir, -state and -to are required"
+	errBundleArgsMsg      = "-dir and -out are required"
+	errDoctorArgsMsg      = "-dir or -config is required"
+	errDoctorFailedMsg    = "problems found"
+	errInvalidExpectMsg   = "invalid -expect-tag entry, expected TAG=DURATION"
+	errHandoverReadyMsg   = "new process did not become ready"
+	errInvalidClientByMsg = "invalid -client-limit-by, expected ip or connection"
+	errInvalidTokenMsg    = "invalid -listen-token-file line, expected TAG=TOKEN"
+	errEmptyReloadMsg     = "-reload-token-file is empty"
+	errInvalidGrokMsg     = "invalid line, expected GLOB=PATTERN"
 
 	helpText = `Logger - Standalone logging service
 
@@ -61,8 +319,189 @@ Options:
                    (default: info)
   -message TEXT    Log message (required for single message mode)
   -daemon          Run as daemon service, reading log messages from stdin
+  -levels LIST     Custom levels as NAME=SEVERITY pairs, e.g. AUDIT=10,BILLING=3
+  -config PATH     JSON configuration file with dir, file, layout and sinks;
+                   -dir and -file override the file's values. In daemon mode,
+                   SIGHUP or POST ADDR/reload on the -metrics address reload
+                   its sinks, filters and min_level without a restart
+  -reload-token-file PATH
+                   Require POST ADDR/reload to send the token of PATH as
+                   Authorization: Bearer TOKEN; without it, only loopback
+                   clients may reload
+  -format NAME     Write stdout and the log file as text (default), json
+                   (one object per line with timestamp, level, message and
+                   fields, for collectors such as Fluentd or Loki), cef, leef
+                   or syslog
+  -metrics ADDR    Serve Prometheus metrics at ADDR/metrics in daemon mode,
+                   including entry counts per message fingerprint and the
+                   lines, bytes and last-seen time per [TAG] of stdin lines;
+                   ADDR/stats serves the same counters as JSON
+  -recent DURATION Keep the entries of the last DURATION (e.g. 15m) in memory
+                   and serve them as JSON at ADDR/entries; filter with
+                   ?level=warn&since=5m&q=TEXT&limit=N
+  -expect-tag LIST In daemon mode, warn when a [TAG] of stdin lines logs
+                   nothing for its interval, e.g. app1=5m,app2=1h; each quiet
+                   period is warned about once
+  -expect-webhook ADDR
+                   Also send the -expect-tag warnings to the sink at ADDR,
+                   e.g. https://hooks.example.com/logger
+  -listen ADDR     In daemon mode, accept [TAG] LEVEL:MESSAGE lines over TCP
+                   connections to ADDR instead of reading stdin
+  -client-rate N   Let each -listen client send N lines per second on
+                   average; excess lines are dropped and counted per client in
+                   the metrics and in drop summaries (default: unlimited)
+  -client-burst N  Let a client send up to N lines at once (default: 100)
+  -client-sample N Keep every Nth excess line instead of dropping them all
+  -client-limit-by KEY
+                   Limit clients by ip (default), or by connection
+  -listen-token TOKEN
+                   Require -listen clients to send AUTH TOKEN as their first
+                   line; other connections are closed
+  -listen-token-file PATH
+                   Also accept the tokens of PATH, one TAG=TOKEN line each;
+                   the lines of a client are logged under its TAG, whatever
+                   tag they carry
+  -max-line BYTES  Truncate stdin and -listen lines longer than BYTES
+                   (default: 65536); a truncated entry carries its length in
+                   truncated_from, and a WARN entry names its source
+  -client-max-lifetime DURATION
+                   Close -listen connections after DURATION, e.g. 1h, so that
+                   clients reconnect and are limited afresh
+  -max-inflight BYTES
+                   Bound the bytes of -listen lines being read or logged; a
+                   line reserves -max-line bytes once its client sends it and
+                   keeps its length until written; beyond it clients wait,
+                   slowed down by TCP flow control
+  -watch-glob PATTERN
+                   In daemon mode, tail the files matching PATTERN, e.g.
+                   '/var/log/foo/*.log', instead of reading stdin, and re-emit
+                   their lines through the daemon's log file and format
+  -watch-regex RE  Parse watched lines with RE; its named groups time, level
+                   and msg pick the timestamp, level and message (default:
+                   optional timestamp, then LEVEL:, [LEVEL] or LEVEL)
+  -watch-time-layout LAYOUT
+                   Go time layout of the time group (default: RFC 3339 and
+                   common syslog and log package layouts)
+  -watch-state PATH
+                   Persist the watch cursors in PATH so that a restart neither
+                   repeats nor skips lines; without it, files are read from the
+                   start
+  -watch-grok PATH In daemon mode, also tail the files of every GLOB=PATTERN
+                   line of PATH and parse their lines with the grok PATTERN,
+                   e.g. %{TIMESTAMP_ISO8601:time} %{LOGLEVEL:level}
+                   %{GREEDYDATA:msg}; other captures become fields. With
+                   -watch-state, the cursors of the n-th pattern line are
+                   kept in its PATH.n
+  -grok-patterns PATH
+                   Add the NAME REGEX grok pattern definitions of PATH
+  -watch-multiline RE
+                   Join watched lines matching RE to the entry before them,
+                   so that a stack trace becomes one entry; default matches
+                   the continuation lines of Java stack traces
+  -watch-multiline-negate
+                   Join the lines that do not match -watch-multiline instead,
+                   e.g. with RE '^\d{4}-' for Python tracebacks
+  -watch-multiline-timeout DURATION
+                   Log a joined entry once no line arrived for DURATION
+                   (default: 5s)
+  -backfill-threshold DURATION
+                   Tag watched, Docker and Kubernetes entries older than the
+                   newest entry written by more than DURATION, e.g. 1h, with
+                   out_of_order=true
+  -backfill-file NAME
+                   Write those entries to NAME in -dir instead of the log file
+  -aggregate ADDR  Instead of writing INFO and lower entries, count them per
+                   level, message fingerprint and [TAG] and write one JSON
+                   rollup line per group and -aggregate-window (default: 1m)
+                   to ADDR, a file path or sink address such as
+                   tcp://host:port
+  -disk-limit MBPS In daemon mode, write at most MBPS megabytes per second to
+                   the log and route files together, so that
+                   latency-sensitive workloads on the same disk are not
+                   starved
+  -rate-anomalies FACTOR
+                   Learn the baseline entries per minute of every level and
+                   [TAG] and write a WARN entry with rate_anomaly=rose or
+                   rate_anomaly=fell when a rate deviates from it by FACTOR
+  -docker          In daemon mode, collect the logs Docker's json-file driver
+                   writes under -docker-root (default:
+                   /var/lib/docker/containers), tagging entries with
+                   container_name, container_id and stream; lines are parsed
+                   like watched files
+  -docker-containers LIST
+                   Collect only these container names or ID prefixes, comma
+                   separated
+  -docker-state DIR
+                   Keep one cursor file per container in DIR
+  -kube            In daemon mode, stream the logs of running Kubernetes pods,
+                   tagging entries with pod, namespace and container; lines
+                   are parsed like watched files
+  -kube-selector SELECTOR
+                   Stream only pods matching the label SELECTOR, e.g. app=web
+  -kube-namespace NAME
+                   Stream pods of namespace NAME (default: the daemon pod's
+                   namespace, or every namespace with -kube-server)
+  -kube-server URL Use the API at URL, e.g. http://127.0.0.1:8001 of kubectl
+                   proxy, instead of the in-cluster service account
   -help            Show this help message
 
+  Send SIGUSR2 to restart a daemon without downtime, e.g. after an upgrade:
+  it starts its executable again, passes the listening sockets to the new
+  process and, once that process serves, logs the input it has read and exits.
+  The new process reads the rest of stdin; watch modes resume from their
+  state files.
+
+Audit Verification:
+  logger verify -file PATH (-key KEY | -key-file PATH | -keyring PATH)
+  Walks the HMAC chain of a log written in audit mode and reports the first
+  tampered, missing or unsealed entry with its line number. A keyring file
+  holds one ID=SECRET line per key, so logs written across key rotations
+  verify with every key they were sealed with.
+
+  logger rewrap -file PATH -keyring PATH -new-key-id ID -new-key-file PATH \
+    -out PATH
+  Verifies the log and writes a copy resealed with the new key, so that old
+  keys can be retired.
+
+Ring Files:
+  logger unwrap -file PATH [-out PATH]
+  Writes the entries of a circular ring file, written with WithRingFile, in
+  chronological order to stdout or to a new file.
+
+Dead Letters:
+  logger redeliver -dlq PATH -to ADDRESS
+  Replays the entries a RetrySink wrote to its dead letter file once the
+  collector is healthy again. ADDRESS is tcp://, udp://, unix://, http://,
+  https:// or a file path. Delivered entries are removed from the file; after
+  a failure the rest stay for the next run.
+
+Forwarding:
+  logger forward -dir PATH -state PATH -to ADDRESS [-glob PATTERN]
+    [-interval DURATION] [-once]
+  Tails the log files in -dir (default pattern *.log*) and ships new lines to
+  ADDRESS, e.g. loki://host:3100?job=app. Cursors in -state follow files
+  renamed by rotation and advance only once the sink accepts a batch, so a
+  restart neither skips nor repeats more than one batch. Lines of secret
+  entries are not shipped.
+
+Support Bundles:
+  logger bundle -dir PATH -out PATH [-since DURATION] [-glob PATTERN]
+    [-config PATH] [-redact PROFILE]
+  Writes the log files in -dir modified within -since, with per-file line and
+  level counts and host and LOGGER_ environment metadata, to a .tar.gz archive
+  for bug reports. The default redaction profile removes credentials, tokens
+  and e-mail addresses; -redact selects none or a profile declared in the
+  redaction section of the -config file.
+
+Diagnostics:
+  logger doctor -dir PATH [-file NAME] [-config PATH]
+  Runs the logger's self-test, writing a probe entry to -file (default
+  doctor.log) and pinging the sinks of -config, then checks the directory for
+  files other users may write or read, files owned by another user, gaps in
+  rotated backups and large unrotated files, modification times in the
+  future, and PID or lock files of processes that are gone. Every finding is
+  printed with advice; errors make the command fail.
+
 Single Message Mode:
   logger -file app.log -level error -message "Database connection failed"
   logger -dir /var/log -file service.log -message "Service started"
@@ -73,6 +512,15 @@ Daemon Mode:
   # Example: echo "ERROR:Database connection timeout" | \
   #   logger -daemon -dir /var/log
   # Or use with pipes: tail -f app.log | logger -daemon -dir /var/log
+  logger -daemon -dir /var/log -watch-glob '/var/log/foo/*.log' \
+    -watch-state /var/lib/logger/foo.json
+  logger -daemon -dir /var/log -docker -docker-containers web,db \
+    -docker-state /var/lib/logger/docker
+  logger -daemon -dir /var/log -kube -kube-selector app=web
+  logger -daemon -dir /var/log -listen :5140 -client-rate 200 \
+    -client-burst 1000 -metrics :9100
+  # Then send lines over TCP: echo "[app1] WARN:disk low" | nc host 5140
+  # With -listen-token-file, first send: AUTH TOKEN
 
 Log Levels:
   info     - General information
@@ -82,16 +530,36 @@ Log Levels:
   fatal    - Fatal system errors
   panic    - Panic conditions
   system   - System-level events
+  security - Security events, fsynced to the log file
+  Level names are case-insensitive. Custom levels registered with -levels are
+  accepted in both modes.
 
 Exit codes:
   0  Success
-  1  Error (invalid arguments, file creation failed, etc.)`
+  1  Error (invalid arguments, file creation failed, failed verification,
+     etc.)`
 )
 
 var (
 	ErrFileRequired    = errors.New(errFileRequiredMsg)
 	ErrMessageRequired = errors.New(errMessageRequiredMsg)
 	ErrUnknownLogLevel = errors.New(errUnknownLogLevelMsg)
+	ErrKeyRequired     = errors.New(errKeyRequiredMsg)
+	ErrRewrapArgs      = errors.New(errRewrapArgsMsg)
+	ErrInvalidKeyring  = errors.New(errInvalidKeyringMsg)
+	ErrRedeliverArgs   = errors.New(errRedeliverArgsMsg)
+	ErrForwardArgs     = errors.New(errForwardArgsMsg)
+	ErrBundleArgs      = errors.New(errBundleArgsMsg)
+	ErrDoctorArgs      = errors.New(errDoctorArgsMsg)
+	ErrDoctorFailed    = errors.New(errDoctorFailedMsg)
+	ErrInvalidExpect   = errors.New(errInvalidExpectMsg)
+	ErrHandoverReady   = errors.New(errHandoverReadyMsg)
+	ErrInvalidClientBy = errors.New(errInvalidClientByMsg)
+	ErrInvalidToken    = errors.New(errInvalidTokenMsg)
+	ErrEmptyReload     = errors.New(errEmptyReloadMsg)
+	ErrInvalidGrok     = errors.New(errInvalidGrokMsg)
+
+	ErrInvalidLevelDefinition = errors.New(errInvalidLevelDefMsg)
 )
 
 func main() {
@@ -103,6 +571,26 @@ func main() {
 }
 
 func run() error {
+	// The audit subcommands have their own flags.
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case verifyCommand:
+			return runVerify(os.Args[2:])
+		case rewrapCommand:
+			return runRewrap(os.Args[2:])
+		case unwrapCommand:
+			return runUnwrap(os.Args[2:])
+		case redeliverCommand:
+			return runRedeliver(os.Args[2:])
+		case forwardCommand:
+			return runForward(os.Args[2:])
+		case bundleCommand:
+			return runBundle(os.Args[2:])
+		case doctorCommand:
+			return runDoctor(os.Args[2:])
+		}
+	}
+
 	// parseFlags parses command-line arguments into a config struct.
 	config := parseFlags()
 	// If the help flag is set, show the help message and exit.
@@ -112,9 +600,27 @@ func run() error {
 		return nil
 	}
 
+	// Register custom levels before any message is parsed.
+	err := registerCustomLevels(config.levels)
+	if err != nil {
+		return err
+	}
+
+	// Apply the configuration file, if any, before choosing a mode.
+	err = applyConfigFile(&config)
+	if err != nil {
+		return err
+	}
+
+	// -format overrides the encoding of the configuration file.
+	err = applyFormat(&config)
+	if err != nil {
+		return err
+	}
+
 	// If the daemon flag is set, run the logger in daemon mode.
 	if config.daemon {
-		return runDaemon(config.logDir)
+		return runDaemon(&config)
 	}
 
 	// Otherwise, run the logger in single message mode.
@@ -122,12 +628,54 @@ func run() error {
 }
 
 type config struct {
-	logDir   string
-	filename string
-	level    string
-	message  string
-	help     bool
-	daemon   bool
+	logDir      string
+	filename    string
+	level       string
+	message     string
+	levels      string
+	configPath  string
+	metrics     string
+	watchGlob   string
+	watchRegex  string
+	watchTime   string
+	watchState  string
+	watchGrok   string
+	grokDefs    string
+	multiline   string
+	multiWait   time.Duration
+	multiNegate bool
+	format      string
+	diskLimit   float64
+	dockerRoot  string
+	dockerNames string
+	dockerState string
+	kubeSelect  string
+	kubeNS      string
+	kubeServer  string
+	expectTags  string
+	expectHook  string
+	listen      string
+	listenToken string
+	tokenFile   string
+	reloadToken string
+	clientBy    string
+	maxLifetime time.Duration
+	backfillAge time.Duration
+	backfill    string
+	aggregate   string
+	aggregateIn time.Duration
+	rateFactor  float64
+	maxLine     int
+	maxInFlight int
+	clientRate  float64
+	clientBurst int
+	clientEvery int
+	recent      time.Duration
+	options     []logger.Option
+	help        bool
+	daemon      bool
+	docker      bool
+	kube        bool
 }
 
 func showHelp() {
@@ -145,6 +693,47 @@ func parseFlags() config {
 	flag.StringVar(&cfg.message, flagNameMessage, "", usageMessage)
 	flag.BoolVar(&cfg.help, flagNameHelp, false, usageHelp)
 	flag.BoolVar(&cfg.daemon, flagNameDaemon, false, usageDaemon)
+	flag.StringVar(&cfg.levels, flagNameLevels, "", usageLevels)
+	flag.StringVar(&cfg.configPath, flagNameConfig, "", usageConfig)
+	flag.StringVar(&cfg.metrics, flagNameMetrics, "", usageMetrics)
+	flag.DurationVar(&cfg.recent, flagNameRecent, 0, usageRecent)
+	flag.StringVar(&cfg.watchGlob, flagNameWatchGlob, "", usageWatchGlob)
+	flag.StringVar(&cfg.watchRegex, flagNameWatchRegex, "", usageWatchRegex)
+	flag.StringVar(&cfg.watchTime, flagNameWatchTime, "", usageWatchTime)
+	flag.StringVar(&cfg.watchState, flagNameWatchState, "", usageWatchState)
+	flag.StringVar(&cfg.watchGrok, flagNameWatchGrok, "", usageWatchGrok)
+	flag.StringVar(&cfg.grokDefs, flagNameGrokPatterns, "", usageGrokPatterns)
+	flag.StringVar(&cfg.multiline, flagNameMultiline, "", usageMultiline)
+	flag.BoolVar(&cfg.multiNegate, flagNameMultiNegate, false, usageMultiNegate)
+	flag.DurationVar(&cfg.multiWait, flagNameMultiTimeout, logger.DefaultMultilineTimeout, usageMultiTimeout)
+	flag.BoolVar(&cfg.docker, flagNameDocker, false, usageDocker)
+	flag.StringVar(&cfg.dockerRoot, flagNameDockerRoot, logger.DefaultDockerRoot, usageDockerRoot)
+	flag.StringVar(&cfg.dockerNames, flagNameDockerNames, "", usageDockerNames)
+	flag.StringVar(&cfg.dockerState, flagNameDockerState, "", usageDockerState)
+	flag.BoolVar(&cfg.kube, flagNameKube, false, usageKube)
+	flag.StringVar(&cfg.kubeSelect, flagNameKubeSelector, "", usageKubeSelector)
+	flag.StringVar(&cfg.kubeNS, flagNameKubeNS, "", usageKubeNS)
+	flag.StringVar(&cfg.kubeServer, flagNameKubeServer, "", usageKubeServer)
+	flag.StringVar(&cfg.expectTags, flagNameExpectTag, "", usageExpectTag)
+	flag.StringVar(&cfg.expectHook, flagNameExpectHook, "", usageExpectHook)
+	flag.StringVar(&cfg.listen, flagNameListen, "", usageListen)
+	flag.Float64Var(&cfg.clientRate, flagNameClientRate, 0, usageClientRate)
+	flag.IntVar(&cfg.clientBurst, flagNameClientBurst, defaultClientBurst, usageClientBurst)
+	flag.IntVar(&cfg.clientEvery, flagNameClientSample, 0, usageClientSample)
+	flag.StringVar(&cfg.clientBy, flagNameClientBy, clientByIP, usageClientBy)
+	flag.StringVar(&cfg.listenToken, flagNameListenToken, "", usageListenToken)
+	flag.StringVar(&cfg.tokenFile, flagNameTokenFile, "", usageTokenFile)
+	flag.StringVar(&cfg.reloadToken, flagNameReloadToken, "", usageReloadToken)
+	flag.IntVar(&cfg.maxLine, flagNameMaxLine, defaultMaxLine, usageMaxLine)
+	flag.DurationVar(&cfg.maxLifetime, flagNameMaxLifetime, 0, usageMaxLifetime)
+	flag.IntVar(&cfg.maxInFlight, flagNameMaxInFlight, 0, usageMaxInFlight)
+	flag.DurationVar(&cfg.backfillAge, flagNameBackfillAge, 0, usageBackfillAge)
+	flag.StringVar(&cfg.backfill, flagNameBackfillFile, "", usageBackfillFile)
+	flag.StringVar(&cfg.aggregate, flagNameAggregate, "", usageAggregate)
+	flag.DurationVar(&cfg.aggregateIn, flagNameAggregateWin, logger.DefaultAggregationWindow, usageAggregateWin)
+	flag.Float64Var(&cfg.rateFactor, flagNameRateFactor, 0, usageRateFactor)
+	flag.StringVar(&cfg.format, flagNameFormat, "", usageFormat)
+	flag.Float64Var(&cfg.diskLimit, flagNameDiskLimit, 0, usageDiskLimit)
 	flag.Parse()
 
 	return cfg
@@ -161,7 +750,7 @@ func runSingleMessage(cfg *config) error {
 		return err
 	}
 
-	loggerInstance, err := createLogger(cfg.logDir, cfg.filename)
+	loggerInstance, err := createLogger(cfg.logDir, cfg.filename, cfg.options)
 	if err != nil {
 		return err
 	}
@@ -170,10 +759,58 @@ func runSingleMessage(cfg *config) error {
 	return logMessage(loggerInstance, cfg.level, cfg.message)
 }
 
-func createLogger(logDir, filename string) (*logger.Logger, error) {
+func applyConfigFile(cfg *config) error {
+	// applyConfigFile loads the -config file. Its directory and filename are used
+	// unless -dir or -file are given explicitly, and its sinks become options.
+	if cfg.configPath == "" {
+		return nil
+	}
+
+	fileConfig, err := logger.LoadConfig(cfg.configPath)
+	if err != nil {
+		return err
+	}
+
+	explicit := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+
+	if !explicit[flagNameDir] {
+		cfg.logDir = fileConfig.Dir
+	}
+
+	if !explicit[flagNameFile] {
+		cfg.filename = fileConfig.File
+	}
+
+	cfg.options, err = fileConfig.Options()
+
+	return err
+}
+
+func applyFormat(cfg *config) error {
+	// applyFormat selects the encoding named by -format, such as json for
+	// collectors that parse JSON lines.
+	if cfg.format == "" {
+		return nil
+	}
+
+	encoding, err := logger.ParseEncoding(cfg.format)
+	if err != nil {
+		return err
+	}
+
+	cfg.options = append(cfg.options, logger.WithEncoding(encoding))
+
+	return nil
+}
+
+func createLogger(
+	logDir, filename string,
+	options []logger.Option,
+) (*logger.Logger, error) {
 	// createLogger creates a new logger instance. This function is responsible for
 	// creating a new logger with the specified log directory and filename.
-	loggerInstance, err := logger.New(logDir, filename)
+	loggerInstance, err := logger.New(logDir, filename, options...)
 	if err != nil {
 		return nil, fmt.Errorf(errorCreatingLogger, err)
 	}
@@ -204,94 +841,1977 @@ func validateArgs(filename, message string) error {
 	return nil
 }
 
-func getLogLevelHandlers() map[string]func(*logger.Logger, string) {
-	return map[string]func(*logger.Logger, string){
-		logLevelINFO: func(l *logger.Logger, msg string) { l.Infof(msg) },
-		"WARN":       func(l *logger.Logger, msg string) { l.Warnf(msg) },
-		"ERROR":      func(l *logger.Logger, msg string) { l.Errorf(msg) },
-		"SUCCESS":    func(l *logger.Logger, msg string) { l.Successf(msg) },
-		"FATAL":      func(l *logger.Logger, msg string) { l.Fatalf(msg) },
-		"PANIC":      func(l *logger.Logger, msg string) { l.Panicf(msg) },
-		"SYSTEM":     func(l *logger.Logger, msg string) { l.Systemf(msg) },
+func logMessage(loggerInstance *logger.Logger, level, message string) error {
+	parsedLevel, err := logger.ParseLevel(level)
+	if err != nil {
+		return fmt.Errorf(errorFmtUnknownLevel, ErrUnknownLogLevel, level)
 	}
-}
 
-func getLevelHandlers() map[string]func(*logger.Logger, string) {
-	// getLevelHandlers returns a map of log level handlers. This function is
-	// responsible for mapping log level strings to their corresponding logger
-	// functions.
-	return getLogLevelHandlers()
-}
+	loggerInstance.Logf(parsedLevel, message)
 
-func logMessage(loggerInstance *logger.Logger, level, message string) error {
-	handlers := getLevelHandlers()
+	return nil
+}
 
-	handler, exists := handlers[level]
-	if !exists {
-		return fmt.Errorf(errorFmtUnknownLevel, ErrUnknownLogLevel, level)
+func registerCustomLevels(spec string) error {
+	// registerCustomLevels registers the NAME=SEVERITY pairs given with -levels
+	// so that they can be used like the built-in levels.
+	if spec == "" {
+		return nil
 	}
 
-	handler(loggerInstance, message)
+	for definition := range strings.SplitSeq(spec, levelListSeparator) {
+		name, severityText, found := strings.Cut(definition, levelDefinitionSeparator)
+		if !found {
+			return fmt.Errorf(errorFmtLevelDefinition, ErrInvalidLevelDefinition, definition)
+		}
+
+		severity, err := strconv.Atoi(strings.TrimSpace(severityText))
+		if err != nil {
+			return fmt.Errorf(errorFmtLevelDefinition, ErrInvalidLevelDefinition, definition)
+		}
+
+		_, err = logger.RegisterLevel(name, severity)
+		if err != nil {
+			return fmt.Errorf(errorFmtRegisterLevel, err)
+		}
+	}
 
 	return nil
 }
 
-func runDaemon(logDir string) error {
+func runDaemon(cfg *config) error {
 	filename := generateDaemonFilename()
+	options := append(cfg.options,
+		logger.WithRecentWindow(cfg.recent),
+		logger.WithBackfill(logger.Backfill{Filename: cfg.backfill, Threshold: cfg.backfillAge}),
+	)
+
+	if cfg.aggregate != "" {
+		sink, err := logger.OpenSink(cfg.aggregate)
+		if err != nil {
+			return fmt.Errorf(errorFmtAggregate, err)
+		}
+
+		options = append(options, logger.WithAggregation(logger.Aggregation{
+			Sink:     sink,
+			Filename: "",
+			TagField: "",
+			Window:   cfg.aggregateIn,
+			MaxLevel: logger.LevelInfo,
+		}))
+	}
+
+	if cfg.diskLimit > 0 {
+		limiter := logger.NewDiskLimiter(int64(cfg.diskLimit * bytesPerMB))
+		options = append(options, logger.WithDiskLimit(limiter))
+	}
+
+	if cfg.rateFactor > 0 {
+		options = append(options, logger.WithRateAnomalies(logger.RateAnomalies{
+			Interval: 0,
+			Factor:   cfg.rateFactor,
+			Alpha:    0,
+			Minimum:  0,
+			Warmup:   0,
+		}))
+	}
 
-	loggerInstance, err := createLogger(logDir, filename)
+	loggerInstance, err := createLogger(cfg.logDir, filename, options)
 	if err != nil {
 		return err
 	}
 	defer closeLogger(loggerInstance)
 
-	startDaemon(loggerInstance, logDir, filename)
-	processDaemonInput(loggerInstance)
+	restart := newHandover()
+
+	server, err := serveMetrics(loggerInstance, restart, cfg)
+	if err != nil {
+		return err
+	}
+	defer shutdownMetrics(loggerInstance, server)
+
+	stopReload := reloadOnHangup(loggerInstance, cfg.configPath)
+	defer stopReload()
+
+	stopHeartbeats, err := watchHeartbeats(loggerInstance, cfg)
+	if err != nil {
+		return err
+	}
+	defer stopHeartbeats()
+
+	lines, err := serveLines(loggerInstance, restart, cfg)
+	if err != nil {
+		return err
+	}
+	defer lines.shutdown()
+
+	restart.takeOver(loggerInstance)
+	handedOver := restart.watch(loggerInstance)
+
+	switch {
+	case cfg.watchGlob != "" || cfg.watchGrok != "" || cfg.docker || cfg.kube:
+		err = watch(loggerInstance, cfg, handedOver)
+		if err != nil {
+			return err
+		}
+	case lines != nil:
+		awaitStop(handedOver)
+	default:
+		startDaemon(loggerInstance, cfg.logDir, filename)
+		processDaemonInput(loggerInstance, cfg.maxLine, handedOver)
+	}
+
 	loggerInstance.Systemf(daemonStoppedMsg)
 
 	return nil
 }
 
+func watchHeartbeats(loggerInstance *logger.Logger, cfg *config) (func(), error) {
+	// watchHeartbeats warns when a tag of -expect-tag logs nothing for its
+	// interval, and sends the warning to -expect-webhook when set.
+	heartbeats, err := parseExpectTags(cfg.expectTags)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.expectHook == "" {
+		return loggerInstance.WatchProducers(heartbeats, nil), nil
+	}
+
+	hook, err := logger.OpenSink(cfg.expectHook)
+	if err != nil {
+		return nil, fmt.Errorf(errorFmtExpectHook, err)
+	}
+
+	stop := loggerInstance.WatchProducers(heartbeats, func(summary string) {
+		_, err := io.WriteString(hook, summary+"\n")
+		if err != nil {
+			loggerInstance.Errorf(daemonExpectHookFmt, err)
+		}
+	})
+
+	return func() {
+		stop()
+		hook.Close()
+	}, nil
+}
+
+func parseExpectTags(spec string) ([]logger.ProducerHeartbeat, error) {
+	// parseExpectTags parses the TAG=DURATION pairs given with -expect-tag.
+	if spec == "" {
+		return nil, nil
+	}
+
+	var heartbeats []logger.ProducerHeartbeat
+
+	for definition := range strings.SplitSeq(spec, levelListSeparator) {
+		tag, intervalText, found := strings.Cut(definition, levelDefinitionSeparator)
+		if !found || tag == "" {
+			return nil, fmt.Errorf(errorFmtExpectTag, ErrInvalidExpect, definition)
+		}
+
+		interval, err := time.ParseDuration(intervalText)
+		if err != nil || interval <= 0 {
+			return nil, fmt.Errorf(errorFmtExpectTag, ErrInvalidExpect, definition)
+		}
+
+		heartbeats = append(heartbeats, logger.ProducerHeartbeat{Name: tag, Interval: interval})
+	}
+
+	return heartbeats, nil
+}
+
+// watchSource logs the lines written since its last call.
+type watchSource func() (int64, error)
+
+func watch(loggerInstance *logger.Logger, cfg *config, handedOver <-chan struct{}) error {
+	// watch re-emits the new lines of the files matching -watch-glob, of the
+	// Docker containers and of the Kubernetes pods through the logger every second until
+	// interrupted or handed over to a new process.
+	sources, closers, err := watchSources(loggerInstance, cfg)
+	for _, closer := range closers {
+		defer closer.Close()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ticker := time.NewTicker(daemonWatchEvery)
+	defer ticker.Stop()
+
+	for {
+		for _, source := range sources {
+			_, err := source()
+			if err != nil {
+				loggerInstance.Errorf(daemonWatchErrorFmt, err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-handedOver:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func watchSources(loggerInstance *logger.Logger, cfg *config) ([]watchSource, []io.Closer, error) {
+	parser, err := logger.NewLineParser(cfg.watchRegex, cfg.watchTime)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	multiline, err := watchMultiline(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		sources []watchSource
+		closers []io.Closer
+	)
+
+	if cfg.watchGlob != "" {
+		sink := ingestSink(loggerInstance, parser, multiline)
+		closers = append(closers, sink)
+
+		shipper, err := logger.OpenShipper(cfg.watchState, sink, 0)
+		if err != nil {
+			return nil, closers, err
+		}
+
+		sources = append(sources, func() (int64, error) { return shipper.ShipGlob(cfg.watchGlob) })
+		announceWatch(loggerInstance, cfg.watchGlob)
+	}
+
+	grokked, grokClosers, err := grokSources(loggerInstance, cfg, multiline)
+	closers = append(closers, grokClosers...)
+
+	if err != nil {
+		return nil, closers, err
+	}
+
+	sources = append(sources, grokked...)
+
+	if cfg.docker {
+		var selected []string
+		if cfg.dockerNames != "" {
+			selected = strings.Split(cfg.dockerNames, levelListSeparator)
+		}
+
+		if cfg.dockerState != "" {
+			err = os.MkdirAll(cfg.dockerState, forwardStatePerm)
+			if err != nil {
+				return nil, closers, fmt.Errorf(errorFmtForwardState, err)
+			}
+		}
+
+		collector := loggerInstance.DockerCollector(cfg.dockerRoot, cfg.dockerState, selected, parser)
+		sources = append(sources, collector.Collect)
+		announceWatch(loggerInstance, cfg.dockerRoot)
+	}
+
+	if !cfg.kube {
+		return sources, closers, nil
+	}
+
+	kubeConfig, err := kubeConfig(cfg)
+	if err != nil {
+		return nil, closers, err
+	}
+
+	collector := loggerInstance.KubeCollector(kubeConfig, parser)
+	announceWatch(loggerInstance, kubeConfig.Server)
+
+	return append(sources, collector.Collect), append(closers, collector), nil
+}
+
+func watchMultiline(cfg *config) (*logger.Multiline, error) {
+	// watchMultiline returns the stitching rule of -watch-multiline, or nil.
+	if cfg.multiline == "" {
+		return nil, nil
+	}
+
+	pattern := cfg.multiline
+	if pattern == multilineDefault {
+		pattern = logger.DefaultContinuationPattern
+	}
+
+	continuation, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf(errorFmtMultiline, err)
+	}
+
+	return &logger.Multiline{
+		Continuation: continuation,
+		Timeout:      cfg.multiWait,
+		MaxLines:     0,
+		Negate:       cfg.multiNegate,
+	}, nil
+}
+
+func ingestSink(loggerInstance *logger.Logger, parser *logger.LineParser, multiline *logger.Multiline) *logger.IngestSink {
+	// ingestSink returns the sink re-emitting watched lines, stitching them
+	// with multiline when set.
+	sink := loggerInstance.IngestSink(parser)
+	if multiline == nil {
+		return sink
+	}
+
+	return sink.Stitch(*multiline)
+}
+
+func grokSources(
+	loggerInstance *logger.Logger, cfg *config, multiline *logger.Multiline,
+) ([]watchSource, []io.Closer, error) {
+	// grokSources tails the files of every GLOB=PATTERN line of -watch-grok,
+	// parsing them with the grok PATTERN and the definitions of
+	// -grok-patterns. Blank lines and lines starting with # are ignored.
+	if cfg.watchGrok == "" {
+		return nil, nil, nil
+	}
+
+	definitions, err := loadGrokPatterns(cfg.grokDefs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := readGrokFile(cfg.watchGrok)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		sources []watchSource
+		closers []io.Closer
+	)
+
+	for index, line := range strings.Split(string(data), keyringLineSeparator) {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, keyringComment) {
+			continue
+		}
+
+		glob, pattern, found := strings.Cut(line, levelDefinitionSeparator)
+		if !found || glob == "" || pattern == "" {
+			return nil, closers, fmt.Errorf(errorFmtGrokLine, index+1, ErrInvalidGrok)
+		}
+
+		parser, err := logger.NewGrokParser(pattern, cfg.watchTime, definitions)
+		if err != nil {
+			return nil, closers, fmt.Errorf(errorFmtGrokLine, index+1, err)
+		}
+
+		sink := ingestSink(loggerInstance, parser, multiline)
+		closers = append(closers, sink)
+
+		source, err := grokSource(loggerInstance, cfg, glob, sink, len(sources)+1)
+		if err != nil {
+			return nil, closers, err
+		}
+
+		sources = append(sources, source)
+	}
+
+	return sources, closers, nil
+}
+
+func grokSource(
+	loggerInstance *logger.Logger, cfg *config, glob string, sink *logger.IngestSink, rule int,
+) (watchSource, error) {
+	// grokSource tails the files of glob, keeping the cursors of the rule-th
+	// -watch-grok line next to those of -watch-state.
+	state := ""
+	if cfg.watchState != "" {
+		state = fmt.Sprintf(grokStateFmt, cfg.watchState, rule)
+	}
+
+	shipper, err := logger.OpenShipper(state, sink, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	announceWatch(loggerInstance, glob)
+
+	return func() (int64, error) { return shipper.ShipGlob(glob) }, nil
+}
+
+func loadGrokPatterns(path string) (map[string]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := readGrokFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return logger.ParseGrokPatterns(bytes.NewReader(data))
+}
+
+func readGrokFile(path string) ([]byte, error) {
+	// #nosec G304 -- the path is chosen by the operator.
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf(errorFmtReadGrok, path, err)
+	}
+
+	return data, nil
+}
+
+func kubeConfig(cfg *config) (logger.KubeConfig, error) {
+	// kubeConfig uses -kube-server when set and the pod's service account
+	// otherwise.
+	if cfg.kubeServer != "" {
+		return logger.KubeConfig{
+			Client:        nil,
+			Server:        cfg.kubeServer,
+			Token:         "",
+			Namespace:     cfg.kubeNS,
+			LabelSelector: cfg.kubeSelect,
+		}, nil
+	}
+
+	kubeConfig, err := logger.InClusterKubeConfig(cfg.kubeSelect)
+	if err != nil {
+		return kubeConfig, err
+	}
+
+	if cfg.kubeNS != "" {
+		kubeConfig.Namespace = cfg.kubeNS
+	}
+
+	return kubeConfig, nil
+}
+
+func announceWatch(loggerInstance *logger.Logger, target string) {
+	loggerInstance.Systemf(daemonWatchingFmt, target)
+	log.Printf(daemonWatchInfoFmt, target)
+}
+
 func generateDaemonFilename() string {
 	return fmt.Sprintf(daemonLogFilenameFmt, time.Now().Format(daemonTimestampFmt))
 }
-func startDaemon(loggerInstance *logger.Logger, logDir, filename string) {
-	loggerInstance.Systemf(daemonStartedMsg)
-	log.Printf(daemonStartedInfoFmt, logDir, filename)
-	log.Println(daemonUsageMsg)
-	log.Println(daemonExampleMsg)
-	log.Println(daemonStopMsg)
-}
-func processDaemonInput(loggerInstance *logger.Logger) {
-	scanner := bufio.NewScanner(os.Stdin)
-	for scanner.Scan() {
-		processLogLine(loggerInstance, scanner.Text())
+func serveMetrics(loggerInstance *logger.Logger, restart *handover, cfg *config) (*http.Server, error) {
+	// serveMetrics serves the logger's metrics and recent entries on -metrics
+	// in the background, and reloads -config on POST /reload when set. The
+	// listener is opened first so that a bad address fails the daemon start.
+	if cfg.metrics == "" {
+		return nil, nil
 	}
 
-	err := scanner.Err()
+	reloadToken, err := loadReloadToken(cfg.reloadToken)
 	if err != nil {
-		loggerInstance.Errorf(daemonStdinErrorFmt, err)
+		return nil, err
 	}
+
+	listener, err := restart.listen(cfg.metrics)
+	if err != nil {
+		return nil, fmt.Errorf(errorFmtMetrics, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(metricsPath, loggerInstance.MetricsHandler())
+	mux.Handle(entriesPath, loggerInstance.RecentHandler())
+	mux.Handle(statsPath, loggerInstance.StatsHandler())
+
+	if cfg.configPath != "" {
+		mux.Handle(reloadPath, reloadHandler(loggerInstance, cfg.configPath, reloadToken))
+	}
+
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: metricsReadTimeout}
+
+	go func() {
+		err := server.Serve(listener)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			loggerInstance.Errorf(daemonMetricsErrFmt, err)
+		}
+	}()
+
+	log.Printf(daemonMetricsFmt, listener.Addr(), metricsPath)
+
+	return server, nil
 }
-func processLogLine(loggerInstance *logger.Logger, line string) {
-	if line == "" {
+
+func shutdownMetrics(loggerInstance *logger.Logger, server *http.Server) {
+	// shutdownMetrics stops accepting connections and lets the requests in
+	// progress finish. After a handover the new process keeps accepting on the
+	// shared socket.
+	if server == nil {
 		return
 	}
 
-	level, message := parseLogLine(line)
+	ctx, cancel := context.WithTimeout(context.Background(), metricsShutdownTimeout)
+	defer cancel()
 
-	err := logMessage(loggerInstance, level, message)
+	err := server.Shutdown(ctx)
 	if err != nil {
-		loggerInstance.Errorf("error logging message from daemon: %v", err)
+		loggerInstance.Errorf(daemonMetricsErrFmt, err)
 	}
 }
 
-func parseLogLine(line string) (string, string) {
-	parts := strings.SplitN(line, ":", logLineSplitCount)
-	if len(parts) != logLineSplitCount {
-		return logLevelINFO, line // Default to INFO if format is incorrect
+func reloadOnHangup(loggerInstance *logger.Logger, configPath string) func() {
+	// reloadOnHangup reloads configPath whenever the daemon receives SIGHUP,
+	// until the returned function is called.
+	if configPath == "" {
+		return func() {}
 	}
 
-	return strings.ToUpper(parts[0]), parts[1]
+	hangups := make(chan os.Signal, 1)
+	signal.Notify(hangups, syscall.SIGHUP)
+
+	go func() {
+		for range hangups {
+			err := reloadConfigFile(loggerInstance, configPath)
+			if err != nil {
+				loggerInstance.Errorf(daemonReloadErrFmt, configPath, err)
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(hangups)
+		close(hangups)
+	}
 }
 
+func loadReloadToken(path string) (string, error) {
+	// loadReloadToken reads the token of -reload-token-file. Without the flag
+	// there is no token, and only loopback clients may reload.
+	if path == "" {
+		return "", nil
+	}
+
+	data, err := readKeyFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	if len(data) == 0 {
+		return "", ErrEmptyReload
+	}
+
+	return string(data), nil
+}
+
+func reloadHandler(loggerInstance *logger.Logger, configPath, token string) http.Handler {
+	// reloadHandler reloads configPath on POST and reports a failed reload as
+	// 422 with the validation error. A request must carry token as a bearer
+	// token, or come from a loopback address when there is none, so that
+	// whoever reaches the metrics address cannot swap the sinks.
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set(headerAllow, http.MethodPost)
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+
+			return
+		}
+
+		status := authorizeReload(r, token)
+		if status != http.StatusOK {
+			if status == http.StatusUnauthorized {
+				w.Header().Set(headerAuthenticate, bearerScheme)
+			}
+
+			loggerInstance.Warnf(daemonReloadDeniedFmt, r.RemoteAddr)
+			http.Error(w, http.StatusText(status), status)
+
+			return
+		}
+
+		err := reloadConfigFile(loggerInstance, configPath)
+		if err != nil {
+			loggerInstance.Errorf(daemonReloadErrFmt, configPath, err)
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func authorizeReload(r *http.Request, token string) int {
+	// authorizeReload returns the status denying r, or http.StatusOK. The
+	// token is compared in constant time.
+	if token == "" {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil || !net.ParseIP(host).IsLoopback() {
+			return http.StatusForbidden
+		}
+
+		return http.StatusOK
+	}
+
+	sent, found := strings.CutPrefix(r.Header.Get(headerAuthorization), bearerPrefix)
+	if !found || subtle.ConstantTimeCompare([]byte(sent), []byte(token)) != 1 {
+		return http.StatusUnauthorized
+	}
+
+	return http.StatusOK
+}
+
+func reloadConfigFile(loggerInstance *logger.Logger, configPath string) error {
+	// reloadConfigFile applies the sinks and minimum level of the edited
+	// configuration file; the other settings keep their startup values.
+	fileConfig, err := logger.LoadConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	return loggerInstance.Reload(fileConfig)
+}
+
+// lineServer logs the lines clients send to the -listen address like stdin
+// lines, one goroutine per connection, and limits each client to
+// -client-rate lines per second. With tokens, a client must authenticate
+// first.
+type lineServer struct {
+	logger   *logger.Logger
+	limiter  *logger.ClientLimiter
+	failures *logger.Throttled
+	listener net.Listener
+	tokens   listenTokens
+	conns    map[net.Conn]struct{}
+	inFlight *byteBudget
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+	lifetime time.Duration
+	maxLine  int
+	byConn   bool
+}
+
+// listenTokens maps the tokens of -listen clients to the tag their lines are
+// logged under; the shared -listen-token has no tag. Nil disables
+// authentication.
+type listenTokens map[string]string
+
+func serveLines(loggerInstance *logger.Logger, restart *handover, cfg *config) (*lineServer, error) {
+	// serveLines accepts line connections on -listen in the background. The
+	// listener is opened first so that a bad address fails the daemon start.
+	if cfg.listen == "" {
+		return nil, nil
+	}
+
+	if cfg.clientBy != clientByIP && cfg.clientBy != clientByConnection {
+		return nil, fmt.Errorf(errorFmtClientBy, ErrInvalidClientBy, cfg.clientBy)
+	}
+
+	tokens, err := loadListenTokens(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := restart.listen(cfg.listen)
+	if err != nil {
+		return nil, fmt.Errorf(errorFmtListen, err)
+	}
+
+	server := &lineServer{
+		logger: loggerInstance,
+		limiter: loggerInstance.LimitClients(logger.ClientRateLimit{
+			Rate:        cfg.clientRate,
+			Burst:       cfg.clientBurst,
+			SampleEvery: cfg.clientEvery,
+		}),
+		failures: loggerInstance.EveryN(authWarnEvery),
+		listener: listener,
+		tokens:   tokens,
+		conns:    make(map[net.Conn]struct{}),
+		inFlight: newByteBudget(cfg.maxInFlight),
+		wg:       sync.WaitGroup{},
+		mu:       sync.Mutex{},
+		lifetime: cfg.maxLifetime,
+		maxLine:  cfg.maxLine,
+		byConn:   cfg.clientBy == clientByConnection,
+	}
+
+	// The accept loop holds the wait group, so that connections are added to
+	// it only while shutdown cannot have finished waiting.
+	server.wg.Add(1)
+
+	go server.serve()
+
+	loggerInstance.Systemf(daemonListeningFmt, listener.Addr())
+	log.Printf(daemonListenInfoFmt, listener.Addr())
+
+	return server, nil
+}
+
+func (server *lineServer) serve() {
+	defer server.wg.Done()
+
+	for {
+		conn, err := server.listener.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return
+		}
+
+		if err != nil {
+			server.logger.Errorf(daemonListenErrFmt, err)
+			time.Sleep(acceptRetryDelay)
+
+			continue
+		}
+
+		server.mu.Lock()
+		server.conns[conn] = struct{}{}
+		server.mu.Unlock()
+
+		server.wg.Add(1)
+
+		go server.handle(conn)
+	}
+}
+
+func (server *lineServer) handle(conn net.Conn) {
+	// handle logs the lines of conn that its client's limit admits until the
+	// client disconnects or the connection reaches -client-max-lifetime.
+	defer server.wg.Done()
+	defer server.forget(conn)
+
+	client := server.client(conn)
+	if server.byConn {
+		// The key holds the ephemeral port, so it is not seen again.
+		defer server.limiter.Forget(client)
+	}
+
+	lines := newLineReader(conn, server.maxLine)
+	expires := time.Time{}
+
+	if server.lifetime > 0 {
+		expires = time.Now().Add(server.lifetime)
+	}
+
+	tag, ok := server.authenticate(conn, lines, expires)
+	if !ok {
+		server.failures.Warnf(daemonAuthFailedFmt, client)
+
+		return
+	}
+
+	loggerInstance := server.logger
+	if tag != "" {
+		loggerInstance = loggerInstance.Named(tag)
+	}
+
+	err := conn.SetReadDeadline(expires)
+	if err == nil {
+		err = server.logLines(loggerInstance, lines, client, tag)
+	}
+
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) &&
+		!errors.Is(err, os.ErrDeadlineExceeded) {
+		server.logger.Errorf(daemonClientErrFmt, client, err)
+	}
+}
+
+func (server *lineServer) logLines(loggerInstance *logger.Logger, lines *lineReader, client, tag string) error {
+	for {
+		// Reserve a whole line once the client sends one, before reading it,
+		// and keep what the line takes until it is logged.
+		lines.wait()
+
+		held := server.inFlight.acquire(server.maxLine)
+		line, size, err := lines.next()
+		held = server.inFlight.shrink(held, len(line))
+
+		if (line != "" || size > 0) && server.limiter.Allow(client) {
+			bound := tag != ""
+			if bound {
+				// The token decides the tag, so that a client cannot log as
+				// another: the tag the line carries is dropped, and the rest
+				// is not parsed for one.
+				_, line = parseLogTag(line)
+			}
+
+			logReadLine(loggerInstance, client, line, size, bound)
+		}
+
+		server.inFlight.release(held)
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (server *lineServer) authenticate(conn net.Conn, lines *lineReader, expires time.Time) (string, bool) {
+	// authenticate reads the AUTH line of conn, which must arrive within
+	// authTimeout, and returns the tag of its token.
+	if server.tokens == nil {
+		return "", true
+	}
+
+	deadline := time.Now().Add(authTimeout)
+	if !expires.IsZero() && expires.Before(deadline) {
+		deadline = expires
+	}
+
+	err := conn.SetReadDeadline(deadline)
+	if err != nil {
+		return "", false
+	}
+
+	line, _, err := lines.next()
+	if err != nil {
+		return "", false
+	}
+
+	token, found := strings.CutPrefix(line, authPrefix)
+	if !found {
+		return "", false
+	}
+
+	return server.tokens.lookup(token)
+}
+
+func (tokens listenTokens) lookup(token string) (string, bool) {
+	// lookup compares token with every known token in constant time, so that
+	// response times do not reveal how much of a token a client guessed.
+	tag, ok := "", false
+
+	for known, knownTag := range tokens {
+		if subtle.ConstantTimeCompare([]byte(known), []byte(token)) == 1 {
+			tag, ok = knownTag, true
+		}
+	}
+
+	return tag, ok
+}
+
+func loadListenTokens(cfg *config) (listenTokens, error) {
+	// loadListenTokens reads -listen-token and the TAG=TOKEN lines of
+	// -listen-token-file. Blank lines and lines starting with # are ignored.
+	if cfg.listenToken == "" && cfg.tokenFile == "" {
+		return nil, nil
+	}
+
+	tokens := make(listenTokens)
+	if cfg.listenToken != "" {
+		tokens[cfg.listenToken] = ""
+	}
+
+	if cfg.tokenFile == "" {
+		return tokens, nil
+	}
+
+	data, err := readKeyFile(cfg.tokenFile)
+	if err != nil {
+		return nil, err
+	}
+
+	for index, line := range strings.Split(string(data), keyringLineSeparator) {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, keyringComment) {
+			continue
+		}
+
+		// Errors name the line rather than quote it, which holds a token.
+		tag, token, found := strings.Cut(line, levelDefinitionSeparator)
+		if !found || tag == "" || token == "" {
+			return nil, fmt.Errorf(errorFmtTokenLine, ErrInvalidToken, index+1)
+		}
+
+		tokens[token] = tag
+	}
+
+	return tokens, nil
+}
+
+func (server *lineServer) client(conn net.Conn) string {
+	// client returns the key of the limit conn counts against: its remote
+	// address, or only the IP of it.
+	addr := conn.RemoteAddr().String()
+	if server.byConn {
+		return addr
+	}
+
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+
+	return host
+}
+
+func (server *lineServer) forget(conn net.Conn) {
+	server.mu.Lock()
+	delete(server.conns, conn)
+	server.mu.Unlock()
+
+	conn.Close()
+}
+
+func (server *lineServer) shutdown() {
+	// shutdown stops accepting connections and waits at most
+	// handoverDrainTimeout for the clients to disconnect before closing their
+	// connections. After a handover the new process keeps accepting on the
+	// shared socket, and clients reconnect to it.
+	if server == nil {
+		return
+	}
+
+	server.listener.Close()
+
+	done := make(chan struct{})
+
+	go func() {
+		server.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return
+	case <-time.After(handoverDrainTimeout):
+	}
+
+	server.mu.Lock()
+	for conn := range server.conns {
+		conn.Close()
+	}
+	server.mu.Unlock()
+
+	<-done
+}
+
+func awaitStop(handedOver <-chan struct{}) {
+	// awaitStop blocks until the daemon is interrupted or handed over.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	select {
+	case <-ctx.Done():
+	case <-handedOver:
+	}
+}
+
+// handover passes the daemon's listening sockets to a new process on restart,
+// so that clients connecting meanwhile are accepted by one process or the
+// other instead of being refused. The new process gets them as inherited
+// descriptors named by the LOGGER_* environment variables.
+type handover struct {
+	inherited map[string]*os.File // sockets of the previous process, by address
+	ready     *os.File            // written once this process serves
+	previous  *os.File            // reaches EOF when the previous process exits
+	successor *os.File            // held until this process exits
+	addrs     []string
+	listeners []*net.TCPListener
+}
+
+func newHandover() *handover {
+	restart := &handover{
+		inherited: make(map[string]*os.File),
+		ready:     inheritedFile(envReadyFD),
+		previous:  inheritedFile(envPreviousFD),
+		successor: nil,
+		addrs:     nil,
+		listeners: nil,
+	}
+
+	addrs := os.Getenv(envListenAddrs)
+	if addrs != "" {
+		for index, addr := range strings.Split(addrs, listenAddrSeparator) {
+			restart.inherited[addr] = os.NewFile(uintptr(extraFilesFD+index), addr)
+		}
+	}
+
+	// The variables describe this process's descriptors only.
+	for _, name := range []string{envListenAddrs, envReadyFD, envPreviousFD} {
+		_ = os.Unsetenv(name)
+	}
+
+	return restart
+}
+
+func inheritedFile(name string) *os.File {
+	fd, err := strconv.Atoi(os.Getenv(name))
+	if err != nil || fd < extraFilesFD {
+		return nil
+	}
+
+	return os.NewFile(uintptr(fd), name) // #nosec G115 -- fd is a small non-negative integer.
+}
+
+func (restart *handover) listen(addr string) (net.Listener, error) {
+	// listen reuses the socket the previous process passed for addr, or opens
+	// one, and keeps it for the next restart.
+	listener, err := restart.open(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	tcpListener, ok := listener.(*net.TCPListener)
+	if ok {
+		restart.addrs = append(restart.addrs, addr)
+		restart.listeners = append(restart.listeners, tcpListener)
+	}
+
+	return listener, nil
+}
+
+func (restart *handover) open(addr string) (net.Listener, error) {
+	file, ok := restart.inherited[addr]
+	if !ok {
+		return net.Listen("tcp", addr)
+	}
+
+	delete(restart.inherited, addr)
+	defer file.Close()
+
+	return net.FileListener(file)
+}
+
+func (restart *handover) takeOver(loggerInstance *logger.Logger) {
+	// takeOver tells the previous process that this one serves and waits for
+	// it to exit, so that the two never read input at the same time.
+	for _, file := range restart.inherited {
+		file.Close()
+	}
+
+	if restart.ready == nil {
+		return
+	}
+
+	_, _ = restart.ready.Write([]byte{0})
+	restart.ready.Close()
+
+	if restart.previous != nil {
+		_, _ = io.Copy(io.Discard, restart.previous)
+		restart.previous.Close()
+	}
+
+	loggerInstance.Systemf(daemonTakeoverMsg)
+}
+
+func (restart *handover) watch(loggerInstance *logger.Logger) <-chan struct{} {
+	// watch starts a new process on a restart signal and returns a channel
+	// closed once that process serves. A failed restart is logged and this
+	// process keeps running.
+	handedOver := make(chan struct{})
+	if len(restartSignals) == 0 {
+		return handedOver
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, restartSignals...)
+
+	go func() {
+		for range signals {
+			err := restart.start(loggerInstance)
+			if err != nil {
+				loggerInstance.Errorf(daemonRestartErrFmt, err)
+
+				continue
+			}
+
+			signal.Stop(signals)
+			close(handedOver)
+
+			return
+		}
+	}()
+
+	return handedOver
+}
+
+func (restart *handover) start(loggerInstance *logger.Logger) error {
+	// start runs the daemon's executable again with the same arguments and
+	// waits until it serves on the passed listeners.
+	files, err := restart.listenerFiles()
+	defer closeFiles(files)
+
+	if err != nil {
+		return err
+	}
+
+	readyRead, readyWrite, err := os.Pipe()
+	if err != nil {
+		return fmt.Errorf(errorFmtHandoverStart, err)
+	}
+	defer readyRead.Close()
+
+	previousRead, previousWrite, err := os.Pipe()
+	if err != nil {
+		readyWrite.Close()
+
+		return fmt.Errorf(errorFmtHandoverStart, err)
+	}
+
+	cmd, err := restart.spawn(append(files, readyWrite, previousRead))
+	readyWrite.Close()
+	previousRead.Close()
+
+	if err != nil {
+		previousWrite.Close()
+
+		return err
+	}
+
+	loggerInstance.Systemf(daemonHandoverFmt, cmd.Process.Pid)
+
+	err = awaitReady(readyRead)
+	if err != nil {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		previousWrite.Close()
+
+		return err
+	}
+
+	restart.successor = previousWrite
+
+	return nil
+}
+
+func (restart *handover) listenerFiles() ([]*os.File, error) {
+	files := make([]*os.File, 0, len(restart.listeners))
+
+	for index, listener := range restart.listeners {
+		file, err := listener.File()
+		if err != nil {
+			return files, fmt.Errorf(errorFmtHandoverFile, restart.addrs[index], err)
+		}
+
+		files = append(files, file)
+	}
+
+	return files, nil
+}
+
+func (restart *handover) spawn(files []*os.File) (*exec.Cmd, error) {
+	// spawn starts the new process with files as its descriptors from 3 on:
+	// the listeners, then the ready and previous pipes.
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf(errorFmtHandoverStart, err)
+	}
+
+	listeners := len(restart.listeners)
+
+	// #nosec G204 -- the daemon restarts its own executable with its own arguments.
+	cmd := exec.Command(executable, os.Args[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	cmd.ExtraFiles = files
+	cmd.Env = append(os.Environ(),
+		fmt.Sprintf(envAssignFmt, envListenAddrs, strings.Join(restart.addrs, listenAddrSeparator)),
+		fmt.Sprintf(envAssignFmt, envReadyFD, strconv.Itoa(extraFilesFD+listeners)),
+		fmt.Sprintf(envAssignFmt, envPreviousFD, strconv.Itoa(extraFilesFD+listeners+1)),
+	)
+
+	err = cmd.Start()
+	if err != nil {
+		return nil, fmt.Errorf(errorFmtHandoverStart, err)
+	}
+
+	return cmd, nil
+}
+
+func awaitReady(ready *os.File) error {
+	// awaitReady waits for the new process to write to its ready pipe. It
+	// fails when the process exits first or takes longer than
+	// handoverReadyTimeout.
+	_ = ready.SetReadDeadline(time.Now().Add(handoverReadyTimeout))
+
+	_, err := ready.Read(make([]byte, 1))
+	if err != nil {
+		return fmt.Errorf(errorFmtHandoverReady, ErrHandoverReady, err)
+	}
+
+	return nil
+}
+
+func closeFiles(files []*os.File) {
+	for _, file := range files {
+		file.Close()
+	}
+}
+
+func startDaemon(loggerInstance *logger.Logger, logDir, filename string) {
+	loggerInstance.Systemf(daemonStartedMsg)
+	log.Printf(daemonStartedInfoFmt, logDir, filename)
+	log.Println(daemonUsageMsg)
+	log.Println(daemonExampleMsg)
+	log.Println(daemonStopMsg)
+}
+func processDaemonInput(loggerInstance *logger.Logger, maxLine int, handedOver <-chan struct{}) {
+	// processDaemonInput logs the lines of stdin until it ends. After a
+	// handover it logs the lines already read and leaves the rest to the new
+	// process, waiting at most handoverDrainTimeout for an idle stdin.
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		readDaemonInput(loggerInstance, maxLine, handedOver)
+	}()
+
+	select {
+	case <-done:
+	case <-handedOver:
+		select {
+		case <-done:
+		case <-time.After(handoverDrainTimeout):
+		}
+	}
+}
+
+func readDaemonInput(loggerInstance *logger.Logger, maxLine int, handedOver <-chan struct{}) {
+	lines := newLineReader(os.Stdin, maxLine)
+
+	for {
+		line, size, err := lines.next()
+		logReadLine(loggerInstance, stdinSource, line, size, false)
+
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				loggerInstance.Errorf(daemonStdinErrorFmt, err)
+			}
+
+			return
+		}
+
+		// Stop at a line boundary with nothing buffered, so that no line is
+		// split between the processes.
+		select {
+		case <-handedOver:
+			if lines.reader.Buffered() == 0 {
+				return
+			}
+		default:
+		}
+	}
+}
+
+// lineReader reads the lines of daemon input, keeping at most limit bytes of
+// each, so that a producer that never ends its line cannot exhaust memory.
+type lineReader struct {
+	reader *bufio.Reader
+	limit  int
+}
+
+func newLineReader(input io.Reader, limit int) *lineReader {
+	return &lineReader{reader: bufio.NewReader(input), limit: max(limit, 1)}
+}
+
+func (lines *lineReader) wait() {
+	// wait returns once input is available or the input failed, without
+	// reading it; the error is left to next.
+	_, _ = lines.reader.Peek(1)
+}
+
+func (lines *lineReader) next() (string, int, error) {
+	// next returns the next line without its line ending and, when it was
+	// truncated, its length. The rest of a truncated line is discarded.
+	var line []byte
+
+	raw := []byte{}
+	size := 0
+
+	for {
+		chunk, err := lines.reader.ReadSlice('\n')
+		size += len(chunk)
+
+		room := lines.limit - len(line)
+		if room > 0 {
+			line = append(line, chunk[:min(room, len(chunk))]...)
+		}
+
+		// Keep the last two bytes read to tell the length of the line ending.
+		raw = append(raw, chunk[max(len(chunk)-2, 0):]...)
+		raw = raw[max(len(raw)-2, 0):]
+
+		if errors.Is(err, bufio.ErrBufferFull) {
+			continue
+		}
+
+		ending := len(raw) - len(bytes.TrimSuffix(bytes.TrimSuffix(raw, []byte("\n")), []byte("\r")))
+		size -= ending
+
+		if size <= lines.limit {
+			size = 0
+		}
+
+		return strings.TrimSuffix(strings.TrimSuffix(string(line), "\n"), "\r"), size, err
+	}
+}
+
+// byteBudget bounds the bytes of the lines being read from -listen clients
+// or logged. A nil budget is unlimited.
+type byteBudget struct {
+	cond  *sync.Cond
+	used  int
+	limit int
+}
+
+func newByteBudget(limit int) *byteBudget {
+	if limit <= 0 {
+		return nil
+	}
+
+	return &byteBudget{cond: sync.NewCond(&sync.Mutex{}), used: 0, limit: limit}
+}
+
+func (budget *byteBudget) acquire(size int) int {
+	// acquire waits until size bytes fit the budget, so that a client whose
+	// lines cannot be logged fast enough is slowed down by TCP flow control,
+	// and returns the bytes to release. Lines larger than the budget take it
+	// whole.
+	if budget == nil {
+		return 0
+	}
+
+	size = min(size, budget.limit)
+
+	budget.cond.L.Lock()
+	for budget.used+size > budget.limit {
+		budget.cond.Wait()
+	}
+
+	budget.used += size
+	budget.cond.L.Unlock()
+
+	return size
+}
+
+func (budget *byteBudget) shrink(held, size int) int {
+	// shrink keeps size of the held bytes, releasing the rest, and returns
+	// the bytes still held.
+	kept := min(held, size)
+	budget.release(held - kept)
+
+	return kept
+}
+
+func (budget *byteBudget) release(size int) {
+	if budget == nil {
+		return
+	}
+
+	budget.cond.L.Lock()
+	budget.used -= size
+	budget.cond.L.Unlock()
+	budget.cond.Broadcast()
+}
+
+func logReadLine(loggerInstance *logger.Logger, source, line string, size int, bound bool) {
+	// logReadLine logs a line of daemon input. A truncated line carries its
+	// length in truncatedField, and a throttled WARN entry names its source.
+	// The lines of a client bound to a tag by its token are not parsed for a
+	// tag.
+	if size > 0 {
+		loggerInstance.EveryN(truncateWarnEvery).Warnf(daemonTruncatedFmt, source, size, len(line))
+		loggerInstance = loggerInstance.With(logger.F(truncatedField, size))
+	}
+
+	if bound {
+		logLevelLine(loggerInstance, line)
+
+		return
+	}
+
+	processLogLine(loggerInstance, line)
+}
+
+func processLogLine(loggerInstance *logger.Logger, line string) {
+	if line == "" {
+		return
+	}
+
+	tag, line := parseLogTag(line)
+	if tag != "" {
+		// Tagged lines are counted per tag in the producer statistics.
+		loggerInstance = loggerInstance.Named(tag)
+	}
+
+	logLevelLine(loggerInstance, line)
+}
+
+func logLevelLine(loggerInstance *logger.Logger, line string) {
+	// logLevelLine logs a LEVEL:MESSAGE line without a tag.
+	level, message := parseLogLine(line)
+
+	err := logMessage(loggerInstance, level, message)
+	if err != nil {
+		loggerInstance.Errorf("error logging message from daemon: %v", err)
+	}
+}
+
+func parseLogTag(line string) (string, string) {
+	// parseLogTag splits the optional "[TAG] " prefix off a daemon line.
+	rest, found := strings.CutPrefix(line, logTagOpen)
+	if !found {
+		return "", line
+	}
+
+	tag, message, found := strings.Cut(rest, logTagClose)
+	if !found || tag == "" {
+		return "", line
+	}
+
+	return tag, message
+}
+
+func parseLogLine(line string) (string, string) {
+	parts := strings.SplitN(line, ":", logLineSplitCount)
+	if len(parts) != logLineSplitCount {
+		return logLevelINFO, line // Default to INFO if format is incorrect
+	}
+
+	return strings.ToUpper(parts[0]), parts[1]
+}
+
+func runVerify(args []string) error {
+	// runVerify checks the audit chain of a log file and reports the first
+	// broken link. Any failure makes the command exit non-zero.
+	flags := flag.NewFlagSet(verifyCommand, flag.ContinueOnError)
+	path := flags.String(flagNameFile, "", usageVerifyFile)
+	keys := defineKeyFlags(flags)
+
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if *path == "" {
+		return ErrFileRequired
+	}
+
+	keyring, err := keys.load()
+	if err != nil {
+		return err
+	}
+
+	file, err := openAuditLog(*path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	verified, err := logger.VerifyAuditLogKeyring(file, keyring)
+	if err != nil {
+		return fmt.Errorf(errorFmtVerify, *path, err, verified)
+	}
+
+	log.Printf(verifyOKFmt, *path, verified)
+
+	return nil
+}
+
+func runRewrap(args []string) error {
+	// runRewrap verifies an audit log with its current keys and writes a copy
+	// resealed with a new key, so that old keys can be retired.
+	flags := flag.NewFlagSet(rewrapCommand, flag.ContinueOnError)
+	path := flags.String(flagNameFile, "", usageRewrapFile)
+	outPath := flags.String(flagNameOut, "", usageOut)
+	newKeyID := flags.String(flagNameNewKeyID, "", usageNewKeyID)
+	newKeyFile := flags.String(flagNameNewKeyFile, "", usageNewKeyFile)
+	keys := defineKeyFlags(flags)
+
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if *path == "" || *outPath == "" || *newKeyFile == "" {
+		return ErrRewrapArgs
+	}
+
+	keyring, err := keys.load()
+	if err != nil {
+		return err
+	}
+
+	newKey, err := readKeyFile(*newKeyFile)
+	if err != nil {
+		return err
+	}
+
+	return rewrapFile(*path, *outPath, keyring, *newKeyID, newKey)
+}
+
+func runUnwrap(args []string) error {
+	// runUnwrap writes the entries of a ring file in chronological order.
+	flags := flag.NewFlagSet(unwrapCommand, flag.ContinueOnError)
+	path := flags.String(flagNameFile, "", usageUnwrapFile)
+	outPath := flags.String(flagNameOut, "", usageUnwrapOut)
+
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if *path == "" {
+		return ErrFileRequired
+	}
+
+	// #nosec G304 -- the ring file path is chosen by the operator.
+	file, err := os.Open(*path)
+	if err != nil {
+		return fmt.Errorf(errorFmtOpenRing, err)
+	}
+	defer file.Close()
+
+	if *outPath == "" {
+		return unwrapRing(*path, file, os.Stdout)
+	}
+
+	// #nosec G304 -- the output path is chosen by the operator.
+	out, err := os.OpenFile(*outPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, auditOutPerm)
+	if err != nil {
+		return fmt.Errorf(errorFmtCreateOutput, err)
+	}
+
+	err = errors.Join(unwrapRing(*path, file, out), out.Close())
+	if err != nil {
+		_ = os.Remove(*outPath)
+	}
+
+	return err
+}
+
+func runRedeliver(args []string) error {
+	// runRedeliver replays a dead letter file to a sink.
+	flags := flag.NewFlagSet(redeliverCommand, flag.ContinueOnError)
+	path := flags.String(flagNameDLQ, "", usageDLQ)
+	address := flags.String(flagNameTo, "", usageTo)
+
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if *path == "" || *address == "" {
+		return ErrRedeliverArgs
+	}
+
+	sink, err := logger.OpenSink(*address)
+	if err != nil {
+		return fmt.Errorf(errorFmtOpenSink, err)
+	}
+
+	count, err := logger.RedeliverDeadLetters(*path, sink)
+	err = errors.Join(err, sink.Close())
+	if err != nil {
+		return fmt.Errorf(errorFmtRedeliver, *path, err, count)
+	}
+
+	log.Printf(redeliverOKFmt, *path, count, *address)
+
+	return nil
+}
+
+// forwardFlags holds the options of the forward command.
+type forwardFlags struct {
+	dir      string
+	state    string
+	address  string
+	glob     string
+	interval time.Duration
+	once     bool
+}
+
+func runForward(args []string) error {
+	// runForward ships new lines of the log files in a directory to a sink
+	// until interrupted.
+	var cfg forwardFlags
+
+	flags := flag.NewFlagSet(forwardCommand, flag.ContinueOnError)
+	flags.StringVar(&cfg.dir, flagNameDir, "", usageForwardDir)
+	flags.StringVar(&cfg.state, flagNameState, "", usageForwardState)
+	flags.StringVar(&cfg.address, flagNameTo, "", usageForwardTo)
+	flags.StringVar(&cfg.glob, flagNameGlob, defaultForwardGlob, usageForwardGlob)
+	flags.DurationVar(&cfg.interval, flagNameInterval, defaultForwardEvery, usageForwardInterval)
+	flags.BoolVar(&cfg.once, flagNameOnce, false, usageForwardOnce)
+
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if cfg.dir == "" || cfg.state == "" || cfg.address == "" {
+		return ErrForwardArgs
+	}
+
+	err = logger.ValidatePath(cfg.dir)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(cfg.state, forwardStatePerm)
+	if err != nil {
+		return fmt.Errorf(errorFmtForwardState, err)
+	}
+
+	sink, err := logger.OpenSink(cfg.address)
+	if err != nil {
+		return fmt.Errorf(errorFmtOpenSink, err)
+	}
+	defer sink.Close()
+
+	// Like a sink route, the forwarded lines leave the machine, so secret
+	// entries stay in the files.
+	remote := logger.NewSensitivitySink(sink, logger.SensitivityInternal)
+
+	shipper, err := logger.OpenShipper(filepath.Join(cfg.state, forwardStateFile), remote, 0)
+	if err != nil {
+		return err
+	}
+
+	return forward(shipper, &cfg)
+}
+
+func forward(shipper *logger.Shipper, cfg *forwardFlags) error {
+	// forward ships the matching files every interval. Failed batches are
+	// retried on the next round from their acknowledged cursor.
+	pattern := filepath.Join(cfg.dir, cfg.glob)
+	if cfg.once {
+		_, err := shipper.ShipGlob(pattern)
+
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	log.Printf(forwardStartedFmt, pattern, cfg.address)
+
+	ticker := time.NewTicker(cfg.interval)
+	defer ticker.Stop()
+
+	for {
+		_, err := shipper.ShipGlob(pattern)
+		if err != nil {
+			log.Printf(forwardErrorFmt, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// bundleFlags holds the options of the bundle command.
+type bundleFlags struct {
+	dir     string
+	out     string
+	glob    string
+	config  string
+	profile string
+	since   time.Duration
+}
+
+func runBundle(args []string) error {
+	// runBundle writes a redacted support bundle of the log files in a
+	// directory.
+	var cfg bundleFlags
+
+	flags := flag.NewFlagSet(bundleCommand, flag.ContinueOnError)
+	flags.StringVar(&cfg.dir, flagNameDir, "", usageBundleDir)
+	flags.StringVar(&cfg.out, flagNameOut, "", usageBundleOut)
+	flags.StringVar(&cfg.glob, flagNameGlob, logger.DefaultBundleGlob, usageBundleGlob)
+	flags.DurationVar(&cfg.since, flagNameSince, 0, usageBundleSince)
+	flags.StringVar(&cfg.config, flagNameConfig, "", usageBundleConfig)
+	flags.StringVar(&cfg.profile, flagNameRedact, logger.RedactProfileDefault, usageRedact)
+
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if cfg.dir == "" || cfg.out == "" {
+		return ErrBundleArgs
+	}
+
+	err = logger.ValidatePath(cfg.dir)
+	if err != nil {
+		return err
+	}
+
+	redactor, err := loadRedactor(cfg.config, cfg.profile)
+	if err != nil {
+		return err
+	}
+
+	// #nosec G304 -- the output path is chosen by the operator.
+	out, err := os.OpenFile(cfg.out, os.O_CREATE|os.O_EXCL|os.O_WRONLY, auditOutPerm)
+	if err != nil {
+		return fmt.Errorf(errorFmtCreateOutput, err)
+	}
+
+	files, err := logger.WriteBundle(out, logger.BundleOptions{
+		Redactor: redactor,
+		Dir:      cfg.dir,
+		Glob:     cfg.glob,
+		Since:    cfg.since,
+	})
+
+	err = errors.Join(err, out.Close())
+	if err != nil {
+		_ = os.Remove(cfg.out)
+
+		return err
+	}
+
+	log.Printf(bundleOKFmt, cfg.out, len(files))
+
+	return nil
+}
+
+// doctorFlags holds the options of the doctor command.
+type doctorFlags struct {
+	dir    string
+	file   string
+	config string
+}
+
+func runDoctor(args []string) error {
+	// runDoctor self-tests a logger on the directory, checks the directory
+	// and prints every finding.
+	var cfg doctorFlags
+
+	flags := flag.NewFlagSet(doctorCommand, flag.ContinueOnError)
+	flags.StringVar(&cfg.dir, flagNameDir, "", usageDoctorDir)
+	flags.StringVar(&cfg.file, flagNameFile, defaultDoctorFile, usageDoctorFile)
+	flags.StringVar(&cfg.config, flagNameConfig, "", usageDoctorConfig)
+
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	loggerInstance, err := doctorLogger(&cfg)
+	if errors.Is(err, ErrDoctorArgs) {
+		return err
+	}
+
+	if loggerInstance != nil {
+		defer closeLogger(loggerInstance)
+	}
+
+	findings := logger.Diagnose(logger.DoctorOptions{
+		Logger:       loggerInstance,
+		Dir:          cfg.dir,
+		MaxFileBytes: 0,
+		ClockSkew:    0,
+		StaleLockAge: 0,
+	})
+	if err != nil {
+		findings = append([]logger.Finding{{
+			Check:   logger.DoctorCheckSelfTest,
+			Path:    cfg.dir,
+			Problem: err.Error(),
+			Advice:  doctorAdviceOpen,
+			Level:   logger.LevelError,
+		}}, findings...)
+	}
+
+	return reportFindings(cfg.dir, findings)
+}
+
+func doctorLogger(cfg *doctorFlags) (*logger.Logger, error) {
+	// doctorLogger opens the logger to self-test: the one of the -config file,
+	// in -dir when given, or a plain logger writing to -file in -dir.
+	if cfg.config == "" {
+		if cfg.dir == "" {
+			return nil, ErrDoctorArgs
+		}
+
+		return createLogger(cfg.dir, cfg.file, []logger.Option{logger.WithoutStdout()})
+	}
+
+	fileConfig, err := logger.LoadConfig(cfg.config)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.dir == "" {
+		cfg.dir = fileConfig.Dir
+	}
+
+	fileConfig.Dir = cfg.dir
+
+	return logger.NewFromConfig(fileConfig, logger.WithoutStdout())
+}
+
+func reportFindings(dir string, findings []logger.Finding) error {
+	// reportFindings prints the findings and fails when any is an error.
+	problems, warnings := 0, 0
+
+	for _, finding := range findings {
+		log.Println(finding)
+
+		if finding.Level >= logger.LevelError {
+			problems++
+		} else {
+			warnings++
+		}
+	}
+
+	if problems > 0 {
+		return fmt.Errorf(errorFmtDoctor, dir, ErrDoctorFailed, problems)
+	}
+
+	log.Printf(doctorOKFmt, dir, warnings)
+
+	return nil
+}
+
+func loadRedactor(configPath, profile string) (*logger.Redactor, error) {
+	// loadRedactor returns the redaction profile, looking it up in the
+	// configuration file when one is given.
+	var fileConfig *logger.Config
+
+	if configPath != "" {
+		var err error
+
+		fileConfig, err = logger.LoadConfig(configPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return fileConfig.Redactor(profile)
+}
+
+func unwrapRing(path string, ring io.Reader, out io.Writer) error {
+	err := logger.UnwrapRingFile(ring, out)
+	if err != nil {
+		return fmt.Errorf(errorFmtUnwrap, path, err)
+	}
+
+	return nil
+}
+
+func rewrapFile(
+	path, outPath string,
+	keyring logger.AuditKeyring,
+	newKeyID string,
+	newKey []byte,
+) error {
+	// rewrapFile writes the resealed log to outPath, removing it again if the
+	// source does not verify.
+	file, err := openAuditLog(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// #nosec G304 -- the output path is chosen by the operator.
+	out, err := os.OpenFile(outPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, auditOutPerm)
+	if err != nil {
+		return fmt.Errorf(errorFmtCreateOutput, err)
+	}
+
+	count, err := logger.RewrapAuditLog(file, out, keyring, newKeyID, newKey)
+	err = errors.Join(err, out.Close())
+	if err != nil {
+		_ = os.Remove(outPath)
+
+		return fmt.Errorf(errorFmtVerify, path, err, count)
+	}
+
+	log.Printf(rewrapOKFmt, path, count, outPath)
+
+	return nil
+}
+
+// keyFlags holds the flags naming the keys that sealed an audit log.
+type keyFlags struct {
+	key     *string
+	keyFile *string
+	keyring *string
+}
+
+func defineKeyFlags(flags *flag.FlagSet) keyFlags {
+	return keyFlags{
+		key:     flags.String(flagNameKey, "", usageKey),
+		keyFile: flags.String(flagNameKeyFile, "", usageKeyFile),
+		keyring: flags.String(flagNameKeyring, "", usageKeyring),
+	}
+}
+
+func (keys keyFlags) load() (logger.AuditKeyring, error) {
+	// load builds the keyring from -keyring, with -key or -key-file as the key
+	// without an ID.
+	keyring := make(logger.AuditKeyring)
+
+	if *keys.keyring != "" {
+		err := readKeyring(*keys.keyring, keyring)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case *keys.keyFile != "":
+		key, err := readKeyFile(*keys.keyFile)
+		if err != nil {
+			return nil, err
+		}
+
+		keyring[""] = key
+	case *keys.key != "":
+		keyring[""] = []byte(*keys.key)
+	}
+
+	if len(keyring) == 0 {
+		return nil, ErrKeyRequired
+	}
+
+	return keyring, nil
+}
+
+func readKeyFile(path string) ([]byte, error) {
+	// #nosec G304 -- the key file path is chosen by the operator.
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf(errorFmtReadKeyFile, err)
+	}
+
+	return bytes.TrimSpace(data), nil
+}
+
+func readKeyring(path string, keyring logger.AuditKeyring) error {
+	// readKeyring adds the ID=SECRET lines of path to keyring. Blank lines and
+	// lines starting with # are ignored.
+	data, err := readKeyFile(path)
+	if err != nil {
+		return err
+	}
+
+	for line := range strings.SplitSeq(string(data), keyringLineSeparator) {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, keyringComment) {
+			continue
+		}
+
+		keyID, secret, found := strings.Cut(line, levelDefinitionSeparator)
+		if !found {
+			return fmt.Errorf(errorFmtKeyringLine, ErrInvalidKeyring, line)
+		}
+
+		keyring[keyID] = []byte(secret)
+	}
+
+	return nil
+}
+
+func openAuditLog(path string) (*os.File, error) {
+	// #nosec G304 -- the audit log path is chosen by the operator.
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf(errorFmtOpenAuditLog, err)
+	}
+
+	return file, nil
+}
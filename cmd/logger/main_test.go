@@ -0,0 +1,32 @@
+package main
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/book-expert/logger"
+)
+
+const parseLogLineErrFmt = "parseLogLine(%q) = %q, %q"
+
+func FuzzParseLogLine(f *testing.F) {
+	f.Add("INFO:Application started")
+	f.Add("no separator")
+	f.Add(":%s%!d(MISSING)")
+	f.Add("warn:a:b:c")
+
+	f.Fuzz(func(t *testing.T, line string) {
+		level, message := parseLogLine(line)
+
+		if !strings.Contains(line, ":") {
+			if level != logLevelINFO || message != line {
+				t.Errorf(parseLogLineErrFmt, line, level, message)
+			}
+		} else if !strings.HasSuffix(line, message) {
+			t.Errorf(parseLogLineErrFmt, line, level, message)
+		}
+
+		processLogLine(logger.NewStreamLogger(io.Discard), line)
+	})
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	reloadToken      = "s3cret"
+	reloadConfigFmt  = `{"dir": %q, "file": "daemon.log"}`
+	reloadConfigName = "logger.json"
+	reloadTokenFile  = "reload.token"
+	loopbackClient   = "127.0.0.1:40000"
+	loopbackClient6  = "[::1]:40000"
+	remoteClient     = "192.0.2.1:40000"
+
+	reloadStatusFmt = "%s %s from %s = %d, want %d: %s"
+	challengeFmt    = "%s = %q, want %q"
+	reloadTokenFmt  = "loadReloadToken() = %q, %v, want %q, %v"
+)
+
+func TestReloadHandler_Authorization(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, reloadConfigName)
+
+	err := os.WriteFile(configPath, []byte(fmt.Sprintf(reloadConfigFmt, dir)), testFileMode)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		token      string
+		remote     string
+		header     string
+		wantStatus int
+	}{
+		{
+			name:       "loopback without token",
+			method:     http.MethodPost,
+			token:      "",
+			remote:     loopbackClient,
+			header:     "",
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "IPv6 loopback without token",
+			method:     http.MethodPost,
+			token:      "",
+			remote:     loopbackClient6,
+			header:     "",
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "remote without token",
+			method:     http.MethodPost,
+			token:      "",
+			remote:     remoteClient,
+			header:     bearerPrefix + reloadToken,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "missing bearer token",
+			method:     http.MethodPost,
+			token:      reloadToken,
+			remote:     loopbackClient,
+			header:     "",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "wrong bearer token",
+			method:     http.MethodPost,
+			token:      reloadToken,
+			remote:     remoteClient,
+			header:     bearerPrefix + "guess",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "token without scheme",
+			method:     http.MethodPost,
+			token:      reloadToken,
+			remote:     remoteClient,
+			header:     reloadToken,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "remote with bearer token",
+			method:     http.MethodPost,
+			token:      reloadToken,
+			remote:     remoteClient,
+			header:     bearerPrefix + reloadToken,
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "GET",
+			method:     http.MethodGet,
+			token:      reloadToken,
+			remote:     remoteClient,
+			header:     bearerPrefix + reloadToken,
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := reloadHandler(logger.NewStreamLogger(io.Discard), configPath, test.token)
+
+			request := httptest.NewRequest(test.method, reloadPath, nil)
+			request.RemoteAddr = test.remote
+
+			if test.header != "" {
+				request.Header.Set(headerAuthorization, test.header)
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			if recorder.Code != test.wantStatus {
+				t.Fatalf(reloadStatusFmt, test.method, reloadPath, test.remote, recorder.Code, test.wantStatus, recorder.Body)
+			}
+
+			challenge := recorder.Header().Get(headerAuthenticate)
+			wantChallenge := ""
+
+			if test.wantStatus == http.StatusUnauthorized {
+				wantChallenge = bearerScheme
+			}
+
+			if challenge != wantChallenge {
+				t.Errorf(challengeFmt, headerAuthenticate, challenge, wantChallenge)
+			}
+		})
+	}
+}
+
+func TestLoadReloadToken(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		err     error
+	}{
+		{name: "no file", content: "", want: "", err: nil},
+		{name: "token", content: reloadToken + "\n", want: reloadToken, err: nil},
+		{name: "empty file", content: "\n", want: "", err: ErrEmptyReload},
+	}
+
+	for _, test := range tests {
+		path := ""
+		if test.content != "" {
+			path = filepath.Join(dir, test.name)
+
+			err := os.WriteFile(path, []byte(test.content), testFileMode)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		token, err := loadReloadToken(path)
+		if token != test.want || !errors.Is(err, test.err) {
+			t.Errorf(reloadTokenFmt, token, err, test.want, test.err)
+		}
+	}
+
+	_, err := loadReloadToken(filepath.Join(dir, reloadTokenFile))
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf(reloadTokenFmt, "", err, "", os.ErrNotExist)
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !(linux || darwin || freebsd)
+
+package main
+
+import "os"
+
+// restartSignals is empty: restarts with socket handover need descriptor
+// inheritance, which this platform lacks.
+var restartSignals []os.Signal
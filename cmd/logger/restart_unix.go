@@ -0,0 +1,11 @@
+//go:build linux || darwin || freebsd
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// restartSignals make the daemon hand its listeners over to a new process.
+var restartSignals = []os.Signal{syscall.SIGUSR2}
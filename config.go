@@ -0,0 +1,445 @@
+package logger
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"time"
+)
+
+// Sink types accepted in configuration files.
+const (
+	SinkTypeFile   = "file"
+	SinkTypeStdout = "stdout"
+	SinkTypeStderr = "stderr"
+
+	// EncoderText renders lines with the sink's layout (or the logger's).
+	EncoderText = "text"
+	// EncoderJSON renders JSONEntry lines; see SchemaVersion.
+	EncoderJSON = "json"
+	// EncoderCEF and EncoderLEEF render lines for ArcSight and QRadar.
+	EncoderCEF  = "cef"
+	EncoderLEEF = "leef"
+	// EncoderSyslog renders RFC 5424 messages with fields as STRUCTURED-DATA.
+	EncoderSyslog = "syslog"
+
+	errConfigInvalidMsg  = "invalid logger configuration"
+	errFmtReadConfig     = "read config: %w"
+	errFmtParseConfig    = "parse config %s: %w"
+	errFmtConfigMsg      = "%w: %s"
+	errFmtConfigKeyErr   = "%w: %s: %w"
+	errFmtConfigSink     = "%w: sink %q: %s"
+	errFmtConfigSinkErr  = "%w: sink %q: %s: %w"
+	configSinkIndexFmt   = "#%d"
+	configMsgDirRequired = "dir is required"
+	configMsgDir         = "dir"
+	configMsgFileInvalid = "file"
+	configMsgLayout      = "layout"
+	configMsgNameMissing = "name is required"
+	configMsgNameDup     = "duplicate sink name"
+	configMsgTypeFmt     = "unknown type %q (want file, stdout or stderr)"
+	configMsgTargetFile  = "target must be a filename for file sinks"
+	configMsgTargetOther = "target is only valid for file sinks"
+	configMsgEncoderFmt  = "unknown encoder %q (want text, json, cef, leef or syslog)"
+	configMsgMinLevel    = "min_level"
+	configMsgFilterMsg   = "filters.message"
+	configMsgFilterField = "filters.value requires filters.field"
+	configMsgRedaction   = "redaction %q rule %d"
+	configMsgSensitivity = "unknown max_sensitivity %q (want public, internal or secret)"
+	configMsgTimezone    = "timezone"
+	configMsgStdoutZone  = "stdout_timezone"
+	configMsgTimeFormat  = "unknown time_format %q (want default, nano, rfc3339nano, epoch_seconds, " +
+		"epoch_millis or epoch_nanos)"
+)
+
+// ErrConfigInvalid wraps every validation error reported by LoadConfig.
+var ErrConfigInvalid = errors.New(errConfigInvalidMsg)
+
+// Config is the JSON configuration file schema. It names the log directory and
+// file and declares additional named sinks, each with its own type, target,
+// encoder, minimum level and filters:
+//
+//	{
+//	  "dir": "/var/log/app",
+//	  "file": "app.log",
+//	  "min_level": "warn",
+//	  "layout": "{time} {level:<7} {msg} {fields}",
+//	  "encoder": "text",
+//	  "time_format": "epoch_millis",
+//	  "timezone": "UTC",
+//	  "stdout_timezone": "Local",
+//	  "sinks": [
+//	    {"name": "payments", "type": "file", "target": "payment.log",
+//	     "min_level": "info", "filters": {"field": "component", "value": "payment"}},
+//	    {"name": "alerts", "type": "stderr", "encoder": "json", "min_level": "error",
+//	     "timezone": "Europe/Berlin"}
+//	  ],
+//	  "redaction": {
+//	    "support": [{"pattern": "customer=\\w+", "replacement": "customer=[REDACTED]"}]
+//	  }
+//	}
+//
+// MinLevel sets WithMinLevel. TimeFormat names a TimeFormat as accepted by
+// ParseTimeFormat. Timezones are
+// IANA names, "UTC" or "Local". Timezone applies to the log
+// file, stdout and sinks without their own, and StdoutTimezone overrides it
+// for stdout. Redaction declares named redaction profiles, applied by
+// Redactor to data leaving the machine such as support bundles.
+type Config struct {
+	Redaction      map[string][]RedactRuleConfig `json:"redaction,omitempty"`
+	Dir            string                        `json:"dir"`
+	File           string                        `json:"file"`
+	MinLevel       string                        `json:"min_level,omitempty"`
+	Layout         string                        `json:"layout,omitempty"`
+	Encoder        string                        `json:"encoder,omitempty"`
+	TimeFormat     string                        `json:"time_format,omitempty"`
+	Timezone       string                        `json:"timezone,omitempty"`
+	StdoutTimezone string                        `json:"stdout_timezone,omitempty"`
+	Sinks          []SinkConfig                  `json:"sinks,omitempty"`
+}
+
+// SinkConfig declares one named sink. Sinks receive copies of the entries
+// that pass their filters, in addition to the logger's regular outputs.
+// MaxSensitivity, public, internal or secret, sets Route.MaxSensitivity, and
+// Timezone sets Route.Timezone.
+type SinkConfig struct {
+	Filters        FilterConfig `json:"filters"`
+	Name           string       `json:"name"`
+	Type           string       `json:"type"`
+	Target         string       `json:"target,omitempty"`
+	Encoder        string       `json:"encoder,omitempty"`
+	Layout         string       `json:"layout,omitempty"`
+	MinLevel       string       `json:"min_level,omitempty"`
+	MaxSensitivity string       `json:"max_sensitivity,omitempty"`
+	Timezone       string       `json:"timezone,omitempty"`
+}
+
+// FilterConfig restricts a sink to entries carrying Field with Value (any value
+// when Value is empty or "*") and whose message matches the Message regular
+// expression.
+type FilterConfig struct {
+	Field   string `json:"field,omitempty"`
+	Value   string `json:"value,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// LoadConfig reads and validates a JSON configuration file. Unknown keys are
+// rejected so that typos surface at load time instead of being ignored.
+func LoadConfig(path string) (*Config, error) {
+	// #nosec G304 -- the configuration path is chosen by the operator.
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf(errFmtReadConfig, err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var config Config
+
+	err = decoder.Decode(&config)
+	if err != nil {
+		return nil, fmt.Errorf(errFmtParseConfig, path, err)
+	}
+
+	_, err = config.Options()
+	if err != nil {
+		return nil, err
+	}
+
+	for name := range config.Redaction {
+		_, err = config.Redactor(name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &config, nil
+}
+
+// NewFromConfig creates a Logger from a validated configuration. Extra options
+// are applied after those derived from the configuration.
+func NewFromConfig(config *Config, opts ...Option) (*Logger, error) {
+	configOpts, err := config.Options()
+	if err != nil {
+		return nil, err
+	}
+
+	return New(config.Dir, config.File, append(configOpts, opts...)...)
+}
+
+// Options validates the configuration and converts it into logger options.
+// Every error wraps ErrConfigInvalid and names the offending sink and key.
+func (config *Config) Options() ([]Option, error) {
+	if config.Dir == "" {
+		return nil, fmt.Errorf(errFmtConfigMsg, ErrConfigInvalid, configMsgDirRequired)
+	}
+
+	err := ValidatePath(config.Dir)
+	if err != nil {
+		return nil, fmt.Errorf(errFmtConfigKeyErr, ErrConfigInvalid, configMsgDir, err)
+	}
+
+	err = ValidateFilename(config.File)
+	if err != nil {
+		return nil, fmt.Errorf(errFmtConfigKeyErr, ErrConfigInvalid, configMsgFileInvalid, err)
+	}
+
+	var opts []Option
+
+	if config.Layout != "" {
+		layout, err := ParseLayout(config.Layout)
+		if err != nil {
+			return nil, fmt.Errorf(errFmtConfigKeyErr, ErrConfigInvalid, configMsgLayout, err)
+		}
+
+		opts = append(opts, WithLayout(layout))
+	}
+
+	encoding, ok := parseEncoder(config.Encoder)
+	if !ok {
+		return nil, fmt.Errorf(errFmtConfigMsg, ErrConfigInvalid,
+			fmt.Sprintf(configMsgEncoderFmt, config.Encoder))
+	}
+
+	opts = append(opts, WithEncoding(encoding))
+
+	timeFormat, ok := ParseTimeFormat(cmp.Or(config.TimeFormat, timeFormatDefaultName))
+	if !ok {
+		return nil, fmt.Errorf(errFmtConfigMsg, ErrConfigInvalid,
+			fmt.Sprintf(configMsgTimeFormat, config.TimeFormat))
+	}
+
+	opts = append(opts, WithTimeFormat(timeFormat))
+
+	timezoneOpts, err := config.timezoneOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts, timezoneOpts...)
+
+	minLevel, err := config.minLevel()
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts, WithMinLevel(minLevel))
+
+	routes, err := config.routes()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, route := range routes {
+		opts = append(opts, WithRoute(route))
+	}
+
+	return opts, nil
+}
+
+// minLevel returns the parsed MinLevel, LevelInfo when empty.
+func (config *Config) minLevel() (Level, error) {
+	if config.MinLevel == "" {
+		return LevelInfo, nil
+	}
+
+	level, err := ParseLevel(config.MinLevel)
+	if err != nil {
+		return LevelInfo, fmt.Errorf(errFmtConfigKeyErr, ErrConfigInvalid, configMsgMinLevel, err)
+	}
+
+	return level, nil
+}
+
+// routes validates the sinks and converts them into routes.
+func (config *Config) routes() ([]Route, error) {
+	routes := make([]Route, 0, len(config.Sinks))
+	seen := make(map[string]bool, len(config.Sinks))
+
+	for index, sink := range config.Sinks {
+		route, err := sink.route(index)
+		if err != nil {
+			return nil, err
+		}
+
+		if seen[sink.Name] {
+			return nil, fmt.Errorf(errFmtConfigSink, ErrConfigInvalid, sink.Name, configMsgNameDup)
+		}
+
+		seen[sink.Name] = true
+		routes = append(routes, route)
+	}
+
+	return routes, nil
+}
+
+func (config *Config) timezoneOptions() ([]Option, error) {
+	timezone, err := loadTimezone(config.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf(errFmtConfigKeyErr, ErrConfigInvalid, configMsgTimezone, err)
+	}
+
+	stdoutTimezone, err := loadTimezone(config.StdoutTimezone)
+	if err != nil {
+		return nil, fmt.Errorf(errFmtConfigKeyErr, ErrConfigInvalid, configMsgStdoutZone, err)
+	}
+
+	return []Option{WithTimezone(timezone), WithStdoutTimezone(stdoutTimezone)}, nil
+}
+
+// loadTimezone returns the location named name, or nil when name is empty.
+func loadTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		return nil, nil
+	}
+
+	return time.LoadLocation(name)
+}
+
+// route validates the sink and converts it into a Route.
+func (sink *SinkConfig) route(index int) (Route, error) {
+	name := sink.Name
+	if name == "" {
+		return Route{}, fmt.Errorf(errFmtConfigSink, ErrConfigInvalid,
+			fmt.Sprintf(configSinkIndexFmt, index+1), configMsgNameMissing)
+	}
+
+	route, err := sink.destination()
+	if err != nil {
+		return Route{}, err
+	}
+
+	encoding, ok := parseEncoder(sink.Encoder)
+	if !ok {
+		return Route{}, fmt.Errorf(errFmtConfigSink, ErrConfigInvalid, name,
+			fmt.Sprintf(configMsgEncoderFmt, sink.Encoder))
+	}
+
+	route.Name = name
+	route.Encoding = encoding
+
+	err = sink.applyFormatting(&route)
+	if err != nil {
+		return Route{}, err
+	}
+
+	err = sink.applyFilters(&route)
+	if err != nil {
+		return Route{}, err
+	}
+
+	return route, nil
+}
+
+func (sink *SinkConfig) destination() (Route, error) {
+	switch sink.Type {
+	case SinkTypeFile:
+		err := ValidateFilename(sink.Target)
+		if err != nil {
+			return Route{}, fmt.Errorf(errFmtConfigSinkErr, ErrConfigInvalid, sink.Name,
+				configMsgTargetFile, err)
+		}
+
+		return Route{Filename: sink.Target}, nil
+	case SinkTypeStdout, SinkTypeStderr:
+		if sink.Target != "" {
+			return Route{}, fmt.Errorf(errFmtConfigSink, ErrConfigInvalid, sink.Name,
+				configMsgTargetOther)
+		}
+
+		if sink.Type == SinkTypeStdout {
+			return Route{Writer: os.Stdout}, nil
+		}
+
+		return Route{Writer: os.Stderr}, nil
+	default:
+		return Route{}, fmt.Errorf(errFmtConfigSink, ErrConfigInvalid, sink.Name,
+			fmt.Sprintf(configMsgTypeFmt, sink.Type))
+	}
+}
+
+func (sink *SinkConfig) applyFormatting(route *Route) error {
+	if sink.Layout != "" {
+		layout, err := ParseLayout(sink.Layout)
+		if err != nil {
+			return fmt.Errorf(errFmtConfigSinkErr, ErrConfigInvalid, sink.Name, configMsgLayout, err)
+		}
+
+		route.Layout = layout
+	}
+
+	if sink.MinLevel != "" {
+		level, err := ParseLevel(sink.MinLevel)
+		if err != nil {
+			return fmt.Errorf(errFmtConfigSinkErr, ErrConfigInvalid, sink.Name, configMsgMinLevel, err)
+		}
+
+		route.MinLevel = level
+	}
+
+	if sink.MaxSensitivity != "" {
+		sensitivity, ok := ParseSensitivity(sink.MaxSensitivity)
+		if !ok {
+			return fmt.Errorf(errFmtConfigSink, ErrConfigInvalid, sink.Name,
+				fmt.Sprintf(configMsgSensitivity, sink.MaxSensitivity))
+		}
+
+		route.MaxSensitivity = sensitivity
+	}
+
+	timezone, err := loadTimezone(sink.Timezone)
+	if err != nil {
+		return fmt.Errorf(errFmtConfigSinkErr, ErrConfigInvalid, sink.Name, configMsgTimezone, err)
+	}
+
+	route.Timezone = timezone
+
+	return nil
+}
+
+func (sink *SinkConfig) applyFilters(route *Route) error {
+	if sink.Filters.Value != "" && sink.Filters.Field == "" {
+		return fmt.Errorf(errFmtConfigSink, ErrConfigInvalid, sink.Name, configMsgFilterField)
+	}
+
+	route.Field = sink.Filters.Field
+
+	route.Value = sink.Filters.Value
+	if route.Field != "" && route.Value == "" {
+		route.Value = RouteAnyValue
+	}
+
+	if sink.Filters.Message != "" {
+		pattern, err := regexp.Compile(sink.Filters.Message)
+		if err != nil {
+			return fmt.Errorf(errFmtConfigSinkErr, ErrConfigInvalid, sink.Name, configMsgFilterMsg, err)
+		}
+
+		route.Message = pattern
+	}
+
+	return nil
+}
+
+// parseEncoder maps an encoder name to its Encoding; the empty name is text.
+func parseEncoder(name string) (Encoding, bool) {
+	switch name {
+	case "", EncoderText:
+		return EncodingText, true
+	case EncoderJSON:
+		return EncodingJSON, true
+	case EncoderCEF:
+		return EncodingCEF, true
+	case EncoderLEEF:
+		return EncodingLEEF, true
+	case EncoderSyslog:
+		return EncodingSyslog, true
+	default:
+		return EncodingText, false
+	}
+}
@@ -0,0 +1,120 @@
+package logger_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	configFileName   = "logger.json"
+	configLogFile    = "app.log"
+	configAlertsFile = "alerts.log"
+	configValidJSON  = `{
+  "dir": %q,
+  "file": "app.log",
+  "layout": "[{level}] {msg} {fields}",
+  "sinks": [
+    {"name": "alerts", "type": "file", "target": "alerts.log", "min_level": "error",
+     "layout": "ALERT {msg}"},
+    {"name": "payments", "type": "file", "target": "payment.log",
+     "filters": {"field": "component", "value": "payment"}}
+  ]
+}`
+	configAlertLine     = "ALERT disk failed\n"
+	configAlertMsg      = "disk failed"
+	configWarnMsg       = "disk slow"
+	writeConfigErrFmt   = "write config: %v"
+	loadConfigErrFmt    = "LoadConfig: %v"
+	configErrorFmt      = "%s: expected error containing %q, got: %v"
+	configNewLoggerErrF = "NewFromConfig: %v"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), configFileName)
+
+	err := os.WriteFile(path, []byte(content), 0o600)
+	if err != nil {
+		t.Fatalf(writeConfigErrFmt, err)
+	}
+
+	return path
+}
+
+func TestLoadConfig_SinksWithLevelsAndLayouts(t *testing.T) {
+	t.Parallel()
+
+	logDir := t.TempDir()
+
+	config, err := logger.LoadConfig(writeConfigFile(t, fmt.Sprintf(configValidJSON, logDir)))
+	if err != nil {
+		t.Fatalf(loadConfigErrFmt, err)
+	}
+
+	loggerInstance, err := logger.NewFromConfig(config)
+	if err != nil {
+		t.Fatalf(configNewLoggerErrF, err)
+	}
+
+	loggerInstance.Warnf(configWarnMsg)
+	loggerInstance.Errorf(configAlertMsg)
+	closeTestLogger(t, loggerInstance)
+
+	// #nosec G304
+	content, err := os.ReadFile(filepath.Join(logDir, configAlertsFile))
+	if err != nil {
+		t.Fatalf(readLogFileErr, err)
+	}
+
+	if string(content) != configAlertLine {
+		t.Errorf(layoutOutputErrFmt, configAlertLine, string(content))
+	}
+
+	verifyLogFileContains(t, filepath.Join(logDir, configLogFile),
+		"[WARN] disk slow", "[ERROR] disk failed")
+}
+
+func TestLoadConfig_ValidationErrors(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		`{"file": "a.log"}`: "dir is required",
+		`{"dir": "/tmp", "file": "a.log", "sinks": [{"type": "file"}]}`:                                                 `sink "#1": name is required`,
+		`{"dir": "/tmp", "file": "a.log", "sinks": [{"name": "k", "type": "kafka"}]}`:                                   `sink "k": unknown type "kafka"`,
+		`{"dir": "/tmp", "file": "a.log", "sinks": [{"name": "f", "type": "file"}]}`:                                    `sink "f": target must be a filename`,
+		`{"dir": "/tmp", "file": "a.log", "sinks": [{"name": "s", "type": "stdout", "min_level": "loud"}]}`:             `sink "s": min_level: unknown level`,
+		`{"dir": "/tmp", "file": "a.log", "sinks": [{"name": "s", "type": "stdout", "encoder": "xml"}]}`:                `sink "s": unknown encoder "xml"`,
+		`{"dir": "/tmp", "file": "a.log", "sinks": [{"name": "s", "type": "stdout", "filters": {"message": "("}}]}`:     `sink "s": filters.message`,
+		`{"dir": "/tmp", "file": "a.log", "sinks": [{"name": "s", "type": "stdout"}, {"name": "s", "type": "stderr"}]}`: `sink "s": duplicate sink name`,
+		`{"dir": "/tmp", "file": "a.log", "encoder": "xml"}`:                                                            `unknown encoder "xml"`,
+		`{"dir": "/tmp", "file": "a.log", "time_format": "iso"}`:                                                        `unknown time_format "iso"`,
+		`{"dir": "/tmp", "file": "a.log", "timezone": "Mars/Base"}`:                                                     `timezone: unknown time zone`,
+		`{"dir": "/tmp", "file": "a.log", "sinks": [{"name": "s", "type": "stdout", "timezone": "Mars/Base"}]}`:         `sink "s": timezone`,
+		`{"dir": "/tmp", "file": "a.log", "sinkz": []}`:                                                                 `unknown field "sinkz"`,
+	}
+
+	for content, want := range cases {
+		_, err := logger.LoadConfig(writeConfigFile(t, content))
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf(configErrorFmt, content, want, err)
+		}
+	}
+}
+
+func TestConfigOptions_WrapsErrConfigInvalid(t *testing.T) {
+	t.Parallel()
+
+	config := logger.Config{Dir: "/tmp", File: "a.log", Layout: "{nope}", Encoder: "", Sinks: nil}
+
+	_, err := config.Options()
+	if !errors.Is(err, logger.ErrConfigInvalid) {
+		t.Errorf(expectedErrFmt, logger.ErrConfigInvalid, err)
+	}
+}
@@ -0,0 +1,168 @@
+package logger
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	// ConfigTag is the struct tag read by LogConfigStruct.
+	ConfigTag = "log"
+	// ConfigTagSecret masks a field; ConfigTagSkip omits it.
+	ConfigTagSecret = "secret"
+	ConfigTagSkip   = "-"
+
+	configDumpMsg      = "effective configuration"
+	configDumpField    = "config"
+	configDumpJSONTag  = "json"
+	configDumpKeySep   = "."
+	configDumpTagSep   = ","
+	configDumpFieldCap = 16
+)
+
+var (
+	stringerType      = reflect.TypeFor[fmt.Stringer]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// LogConfigStruct records the effective configuration v, a struct or a
+// pointer to one, as a SYSTEM entry with a field per struct field, so that
+// every start of a service documents its settings:
+//
+//	type Config struct {
+//		Model    string `json:"model"`
+//		APIKey   string `json:"api_key" log:"secret"`
+//		Internal cache  `log:"-"`
+//		OCR      struct {
+//			Workers int `json:"workers"`
+//		} `json:"ocr"`
+//	}
+//	// [SYSTEM] effective configuration model=tesseract api_key=[REDACTED] ocr.workers=4
+//
+// Fields are named by their json tag, or else by their Go name. Nested
+// structs, and slices and arrays of structs, are flattened into dotted keys
+// such as ocr.workers or sinks.0.name; types implementing fmt.Stringer or
+// encoding.TextMarshaler, such as time.Time, are logged as values. Fields
+// tagged log:"secret" are logged as RedactedText, fields tagged log:"-" and
+// unexported fields are omitted. Map values are logged as they are and are
+// not searched for secrets. Any other v is logged in a single config field.
+func (l *Logger) LogConfigStruct(v any) {
+	l.writef(LevelSystem, configFields(reflect.ValueOf(v)), configDumpMsg)
+}
+
+func configFields(value reflect.Value) []Field {
+	value = indirect(value)
+	if value.Kind() != reflect.Struct || isConfigLeaf(value.Type()) {
+		return []Field{F(configDumpField, valueOf(value))}
+	}
+
+	fields := make([]Field, 0, configDumpFieldCap)
+
+	return appendStructFields(fields, "", value)
+}
+
+// appendStructFields appends the fields of the struct value, their keys
+// starting with prefix.
+func appendStructFields(fields []Field, prefix string, value reflect.Value) []Field {
+	structType := value.Type()
+
+	for index := range structType.NumField() {
+		structField := structType.Field(index)
+		if !structField.IsExported() {
+			continue
+		}
+
+		options := strings.Split(structField.Tag.Get(ConfigTag), configDumpTagSep)
+		if slices.Contains(options, ConfigTagSkip) {
+			continue
+		}
+
+		key := prefix + configKey(structField)
+		if slices.Contains(options, ConfigTagSecret) {
+			fields = append(fields, F(key, RedactedText))
+
+			continue
+		}
+
+		fields = appendValueFields(fields, key, value.Field(index))
+	}
+
+	return fields
+}
+
+// appendValueFields appends value under key, flattening structs and slices
+// and arrays of structs.
+func appendValueFields(fields []Field, key string, value reflect.Value) []Field {
+	value = indirect(value)
+
+	switch {
+	case value.Kind() == reflect.Struct && !isConfigLeaf(value.Type()):
+		return appendStructFields(fields, key+configDumpKeySep, value)
+	case (value.Kind() == reflect.Slice || value.Kind() == reflect.Array) && hasStructElems(value.Type()):
+		for index := range value.Len() {
+			fields = appendValueFields(fields, key+configDumpKeySep+strconv.Itoa(index), value.Index(index))
+		}
+
+		return fields
+	default:
+		return append(fields, F(key, valueOf(value)))
+	}
+}
+
+// configKey returns the json name of field, or its Go name.
+func configKey(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get(configDumpJSONTag), configDumpTagSep)
+	if name == "" || name == ConfigTagSkip {
+		return field.Name
+	}
+
+	return name
+}
+
+// indirect follows pointers and interfaces down to a value, stopping at nil.
+func indirect(value reflect.Value) reflect.Value {
+	for (value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface) && !value.IsNil() {
+		value = value.Elem()
+	}
+
+	return value
+}
+
+// isConfigLeaf reports whether values of valueType render themselves and are
+// logged whole.
+func isConfigLeaf(valueType reflect.Type) bool {
+	return valueType.Implements(stringerType) || valueType.Implements(textMarshalerType) ||
+		reflect.PointerTo(valueType).Implements(stringerType) ||
+		reflect.PointerTo(valueType).Implements(textMarshalerType)
+}
+
+func hasStructElems(valueType reflect.Type) bool {
+	elem := valueType.Elem()
+	for elem.Kind() == reflect.Pointer {
+		elem = elem.Elem()
+	}
+
+	return elem.Kind() == reflect.Struct && !isConfigLeaf(elem)
+}
+
+// valueOf returns the value held by value, or nil for invalid and nil
+// values. A struct whose String method has a pointer receiver is rendered
+// by it.
+func valueOf(value reflect.Value) any {
+	if !value.IsValid() || ((value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface) && value.IsNil()) {
+		return nil
+	}
+
+	if value.Kind() == reflect.Struct && value.CanAddr() && !value.Type().Implements(stringerType) {
+		stringer, ok := value.Addr().Interface().(fmt.Stringer)
+		if ok {
+			return stringer.String()
+		}
+	}
+
+	return value.Interface()
+}
@@ -0,0 +1,119 @@
+package logger_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/book-expert/logger"
+	"github.com/book-expert/logger/loggertest"
+)
+
+const (
+	configDumpModel    = "tesseract"
+	configDumpKey      = "sk-live-123"
+	configDumpWorkers  = 4
+	configDumpSink     = "alerts"
+	configDumpTimeout  = 30 * time.Second
+	configDumpScalar   = 42
+	configDumpFields   = 7
+	configDumpCountFmt = "entries = %d; want 1"
+	configDumpLevelFmt = "level = %v; want %v"
+	configDumpFieldFmt = "field %s = %v; want %v"
+	configDumpExtraFmt = "fields = %v; want no %s"
+	configDumpLenFmt   = "fields = %v; want %d"
+)
+
+type configDumpSinkConfig struct {
+	Name  string `json:"name"`
+	Token string `json:"token" log:"secret"`
+}
+
+type configDumpOCR struct {
+	Workers int `json:"workers"`
+}
+
+type configDumpConfig struct {
+	Started  time.Time      `json:"started"`
+	OCR      *configDumpOCR `json:"ocr"`
+	internal string
+	Model    string                 `json:"model"`
+	APIKey   string                 `json:"api_key" log:"secret"`
+	Cache    string                 `log:"-"`
+	Sinks    []configDumpSinkConfig `json:"sinks"`
+	Timeout  time.Duration
+}
+
+func TestLogger_LogConfigStruct(t *testing.T) {
+	t.Parallel()
+
+	loggerInstance, observer := loggertest.NewObservedLogger(t)
+	loggerInstance.LogConfigStruct(&configDumpConfig{
+		Started:  time.Time{},
+		OCR:      &configDumpOCR{Workers: configDumpWorkers},
+		internal: configDumpKey,
+		Model:    configDumpModel,
+		APIKey:   configDumpKey,
+		Cache:    configDumpKey,
+		Sinks:    []configDumpSinkConfig{{Name: configDumpSink, Token: configDumpKey}},
+		Timeout:  configDumpTimeout,
+	})
+
+	fields := observedConfigFields(t, observer)
+
+	for key, want := range map[string]any{
+		"started":       time.Time{},
+		"ocr.workers":   configDumpWorkers,
+		"model":         configDumpModel,
+		"api_key":       logger.RedactedText,
+		"sinks.0.name":  configDumpSink,
+		"sinks.0.token": logger.RedactedText,
+		"Timeout":       configDumpTimeout,
+	} {
+		if fields[key] != want {
+			t.Errorf(configDumpFieldFmt, key, fields[key], want)
+		}
+	}
+
+	for _, key := range []string{"internal", "Cache"} {
+		_, found := fields[key]
+		if found {
+			t.Errorf(configDumpExtraFmt, fields, key)
+		}
+	}
+
+	if len(fields) != configDumpFields {
+		t.Errorf(configDumpLenFmt, fields, configDumpFields)
+	}
+}
+
+func TestLogger_LogConfigStructNonStruct(t *testing.T) {
+	t.Parallel()
+
+	loggerInstance, observer := loggertest.NewObservedLogger(t)
+	loggerInstance.LogConfigStruct(configDumpScalar)
+
+	fields := observedConfigFields(t, observer)
+	if fields["config"] != configDumpScalar {
+		t.Errorf(configDumpFieldFmt, "config", fields["config"], configDumpScalar)
+	}
+}
+
+func observedConfigFields(t *testing.T, observer *loggertest.Observer) map[string]any {
+	t.Helper()
+
+	entries := observer.Entries()
+	if len(entries) != 1 {
+		t.Fatalf(configDumpCountFmt, len(entries))
+	}
+
+	if entries[0].Level != logger.LevelSystem {
+		t.Errorf(configDumpLevelFmt, entries[0].Level, logger.LevelSystem)
+	}
+
+	fields := make(map[string]any, len(entries[0].Fields))
+	for _, field := range entries[0].Fields {
+		fields[field.Key] = field.Value
+	}
+
+	return fields
+}
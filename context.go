@@ -0,0 +1,84 @@
+package logger
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// ContextDeadlineField carries the time left until the context deadline,
+	// negative once it has passed.
+	ContextDeadlineField = "ctx_deadline_in"
+	// ContextErrField carries the context error once it is cancelled.
+	ContextErrField = "ctx_err"
+
+	contextDeadlineRounding = time.Millisecond
+)
+
+// InfofCtx is like Infof but aware of ctx. See LogfCtx.
+func (l *Logger) InfofCtx(ctx context.Context, format string, args ...any) {
+	l.writeCtxf(ctx, LevelInfo, format, args...)
+}
+
+// WarnfCtx is like Warnf but aware of ctx. See LogfCtx.
+func (l *Logger) WarnfCtx(ctx context.Context, format string, args ...any) {
+	l.writeCtxf(ctx, LevelWarn, format, args...)
+}
+
+// ErrorfCtx is like Errorf but aware of ctx. See LogfCtx.
+func (l *Logger) ErrorfCtx(ctx context.Context, format string, args ...any) {
+	l.writeCtxf(ctx, LevelError, format, args...)
+}
+
+// SuccessfCtx is like Successf but aware of ctx. See LogfCtx.
+func (l *Logger) SuccessfCtx(ctx context.Context, format string, args ...any) {
+	l.writeCtxf(ctx, LevelSuccess, format, args...)
+}
+
+// FatalfCtx is like Fatalf but aware of ctx. See LogfCtx.
+func (l *Logger) FatalfCtx(ctx context.Context, format string, args ...any) {
+	l.writeCtxf(ctx, LevelFatal, format, args...)
+}
+
+// PanicfCtx is like Panicf but aware of ctx. See LogfCtx.
+func (l *Logger) PanicfCtx(ctx context.Context, format string, args ...any) {
+	l.writeCtxf(ctx, LevelPanic, format, args...)
+}
+
+// SystemfCtx is like Systemf but aware of ctx. See LogfCtx.
+func (l *Logger) SystemfCtx(ctx context.Context, format string, args ...any) {
+	l.writeCtxf(ctx, LevelSystem, format, args...)
+}
+
+// LogfCtx logs a message at the given level on behalf of the request that
+// ctx belongs to. The entry carries ContextDeadlineField when ctx has a
+// deadline and ContextErrField once ctx is done. A done context means the
+// request is over, so the entry is only written to stdout and the log file;
+// routes, whose writers may be slow network or webhook sinks, are skipped so
+// that request-scoped logging does not outlive its request.
+func (l *Logger) LogfCtx(ctx context.Context, level Level, format string, args ...any) {
+	l.writeCtxf(ctx, level, format, args...)
+}
+
+func (l *Logger) writeCtxf(ctx context.Context, level Level, format string, args ...any) {
+	fields, target := contextFields(ctx)
+	l.writeEntryf(level, fields, l.resolveCaller(), target, format, args...)
+}
+
+// contextFields returns the fields describing ctx and the outputs an entry
+// logged under it may use.
+func contextFields(ctx context.Context) ([]Field, writeTarget) {
+	var fields []Field
+
+	deadline, hasDeadline := ctx.Deadline()
+	if hasDeadline {
+		fields = append(fields, F(ContextDeadlineField, time.Until(deadline).Round(contextDeadlineRounding)))
+	}
+
+	err := ctx.Err()
+	if err == nil {
+		return fields, targetAll
+	}
+
+	return append(fields, F(ContextErrField, err)), targetLocal
+}
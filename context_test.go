@@ -0,0 +1,64 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	contextMsg          = "request handled"
+	contextCanceledText = `ctx_err="context canceled"`
+	contextDeadlineText = "ctx_deadline_in="
+	contextCallerFile   = "context_test.go:"
+	contextTimeout      = time.Minute
+	contextRouteErrFmt  = "expected no routed entry for a canceled context, got %q"
+)
+
+func TestContext_CanceledSkipsRoutes(t *testing.T) {
+	t.Parallel()
+
+	var buf, routed bytes.Buffer
+
+	loggerInstance := logger.NewStreamLogger(&buf, logger.WithRoute(logger.Route{Writer: &routed}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	loggerInstance.ErrorfCtx(ctx, contextMsg)
+
+	if !strings.Contains(buf.String(), contextCanceledText) {
+		t.Errorf(layoutOutputErrFmt, contextCanceledText, buf.String())
+	}
+
+	if routed.Len() != 0 {
+		t.Errorf(contextRouteErrFmt, routed.String())
+	}
+}
+
+func TestContext_DeadlineFieldAndRouting(t *testing.T) {
+	t.Parallel()
+
+	var routed bytes.Buffer
+
+	loggerInstance, buf := newLayoutLogger(t, layoutCallerTemplate+" {fields}")
+	routedLogger := logger.NewStreamLogger(&bytes.Buffer{}, logger.WithRoute(logger.Route{Writer: &routed}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), contextTimeout)
+	defer cancel()
+
+	loggerInstance.InfofCtx(ctx, contextMsg)
+	routedLogger.InfofCtx(ctx, contextMsg)
+
+	if !strings.HasPrefix(buf.String(), contextCallerFile) || !strings.Contains(buf.String(), contextDeadlineText) {
+		t.Errorf(layoutOutputErrFmt, contextCallerFile+" ... "+contextDeadlineText, buf.String())
+	}
+
+	if !strings.Contains(routed.String(), contextMsg) {
+		t.Errorf(layoutOutputErrFmt, contextMsg, routed.String())
+	}
+}
@@ -0,0 +1,211 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"slices"
+	"strings"
+)
+
+const (
+	// CrashesDir is the subdirectory of the log directory holding crash
+	// reports.
+	CrashesDir = "crashes"
+	// CrashReportField is the field naming the crash report of a PANIC or
+	// FATAL entry, relative to the log directory.
+	CrashReportField = "crash_report"
+
+	// crashRecentLimit bounds the recent entries copied into a report.
+	crashRecentLimit = 1000
+	// crashStackSize is the initial buffer for the goroutine stacks, doubled
+	// until they fit.
+	crashStackSize = 64 << 10
+
+	crashDirPerm        = 0o750
+	crashFilePerm       = 0o600
+	crashLineEnd        = "\n"
+	crashNameFmt        = "crash-%s-%016x.txt"
+	crashTimeLayout     = "20060102T150405Z"
+	crashSectionFmt     = "=== %s ===\n"
+	crashSectionEntry   = "entry"
+	crashSectionRecent  = "recent entries"
+	crashSectionBuild   = "build info"
+	crashSectionEnv     = "environment"
+	crashSectionStacks  = "goroutines"
+	crashNoRecent       = "none: enable WithRecentWindow or WithRingFile to include them\n"
+	crashNoBuildInfo    = "unavailable: binary built without module support\n"
+	errFmtCrashReport   = "write crash report: %w"
+	errFmtCrashSections = "render crash report: %w"
+)
+
+// WithCrashReports writes a standalone report for every PANIC and FATAL
+// entry to crashes/crash-<time>-<random>.txt under the log directory, for
+// post-mortem analysis: the entry, the recent entries of WithRecentWindow or
+// WithRingFile, the build info, the environment as collected by WriteBundle
+// and the stack traces of all goroutines. The entry carries the report's
+// path in the crash_report field. Loggers without a log directory write no
+// reports; a report that cannot be written is reported by Err.
+func WithCrashReports() Option {
+	return func(config *options) {
+		config.crashReports = true
+	}
+}
+
+// crashReport is a crash report named while mu is held and written once it
+// is released.
+type crashReport struct {
+	entry *Entry
+	path  string
+}
+
+// reserveCrashReport names the crash report of logEntry, when it needs one,
+// and adds its path to the entry's fields. No file is created, so that mu is
+// not held for the disk. The caller holds mu.
+func (c *loggerCore) reserveCrashReport(logEntry *Entry) *crashReport {
+	if !c.crashReports || c.logDir == "" || (logEntry.Level != LevelPanic && logEntry.Level != LevelFatal) {
+		return nil
+	}
+
+	// #nosec G404 -- the suffix only keeps report names apart.
+	name := fmt.Sprintf(crashNameFmt, logEntry.Time.UTC().Format(crashTimeLayout), rand.Uint64())
+	logEntry.Fields = append(slices.Clip(logEntry.Fields), F(CrashReportField, CrashesDir+"/"+name))
+
+	return &crashReport{entry: logEntry, path: filepath.Join(c.logDir, CrashesDir, name)}
+}
+
+// writeCrashReport renders report, whose entry has been logged, and writes
+// it. A nil report is ignored. The caller does not hold mu.
+func (c *loggerCore) writeCrashReport(report *crashReport) {
+	if report == nil {
+		return
+	}
+
+	var text strings.Builder
+
+	err := c.renderCrashReport(&text, report.entry)
+	if err == nil {
+		err = writeCrashFile(report.path, text.String())
+	}
+
+	if err != nil {
+		c.mu.Lock()
+		c.lastErr = fmt.Errorf(errFmtCrashReport, err)
+		c.mu.Unlock()
+	}
+}
+
+// writeCrashFile creates the report file at path, which must not exist, and
+// writes text to it.
+func writeCrashFile(path, text string) error {
+	err := os.MkdirAll(filepath.Dir(path), crashDirPerm)
+	if err != nil {
+		return err
+	}
+
+	// #nosec G304 -- the path is built from the log directory.
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, crashFilePerm)
+	if err != nil {
+		return err
+	}
+
+	_, err = file.WriteString(text)
+
+	return errors.Join(err, file.Close())
+}
+
+func (c *loggerCore) renderCrashReport(report *strings.Builder, logEntry *Entry) error {
+	fmt.Fprintf(report, crashSectionFmt, crashSectionEntry)
+	report.WriteString(encodeEntry(inTimezone(logEntry, c.timezone), c.encoding, c.layout))
+	report.WriteString(crashLineEnd)
+
+	fmt.Fprintf(report, crashSectionFmt, crashSectionRecent)
+
+	err := c.renderRecent(report)
+	if err != nil {
+		return fmt.Errorf(errFmtCrashSections, err)
+	}
+
+	fmt.Fprintf(report, crashSectionFmt, crashSectionBuild)
+	renderBuildInfo(report)
+
+	fmt.Fprintf(report, crashSectionFmt, crashSectionEnv)
+
+	environment, err := json.MarshalIndent(newBundleEnvironment(&BundleOptions{
+		Redactor: nil,
+		Dir:      c.logDir,
+		Glob:     "",
+		Since:    0,
+	}, DefaultRedactor()), "", bundleJSONIndent)
+	if err != nil {
+		return fmt.Errorf(errFmtCrashSections, err)
+	}
+
+	report.Write(environment)
+	report.WriteString(crashLineEnd)
+
+	fmt.Fprintf(report, crashSectionFmt, crashSectionStacks)
+	report.Write(goroutineStacks())
+
+	return nil
+}
+
+// renderRecent writes the last crashRecentLimit entries of the recent
+// window, or else the contents of the ring file. The caller does not hold mu.
+func (c *loggerCore) renderRecent(report *strings.Builder) error {
+	if c.recent != nil {
+		c.recent.mu.RLock()
+		defer c.recent.mu.RUnlock()
+
+		entries := c.recent.entries[max(len(c.recent.entries)-crashRecentLimit, 0):]
+		for _, recentEntry := range entries {
+			report.WriteString(encodeEntry(inTimezone(recentEntry, c.timezone), c.encoding, c.layout))
+			report.WriteString(crashLineEnd)
+		}
+
+		return nil
+	}
+
+	if c.ring != nil {
+		c.mu.Lock()
+		data := bytes.Clone(c.ring.data)
+		c.mu.Unlock()
+
+		return UnwrapRingFile(bytes.NewReader(data), report)
+	}
+
+	report.WriteString(crashNoRecent)
+
+	return nil
+}
+
+func renderBuildInfo(report *strings.Builder) {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		report.WriteString(crashNoBuildInfo)
+
+		return
+	}
+
+	report.WriteString(info.String())
+}
+
+// goroutineStacks returns the stack traces of all goroutines.
+func goroutineStacks() []byte {
+	buf := make([]byte, crashStackSize)
+
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n]
+		}
+
+		buf = make([]byte, 2*len(buf))
+	}
+}
@@ -0,0 +1,168 @@
+package logger_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	crashFile       = "app.log"
+	crashRingFile   = "ring.buf"
+	crashRingSize   = 4096
+	crashEarlierMsg = "page 11 converted"
+	crashPanicMsg   = "converter crashed"
+	crashFatalMsg   = "cannot continue"
+	crashWindow     = time.Minute
+	crashNewErrFmt  = "New: %v"
+	crashCountFmt   = "crash reports = %v; want %d"
+	crashMissingFmt = "%s lacks %q:\n%s"
+	crashExtraFmt   = "%s holds %q:\n%s"
+	crashErrFmt     = "Err() = %v; want nil"
+	crashAsyncQueue = 16
+)
+
+func TestWithCrashReports(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		options []logger.Option
+	}{
+		{name: "recent window", options: []logger.Option{logger.WithRecentWindow(crashWindow)}},
+		{name: "ring file", options: []logger.Option{logger.WithRingFile(crashRingFile, crashRingSize)}},
+		{
+			name:    "async ring file",
+			options: []logger.Option{logger.WithRingFile(crashRingFile, crashRingSize), logger.WithAsync(crashAsyncQueue)},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+
+			options := append([]logger.Option{logger.WithoutStdout(), logger.WithCrashReports()}, testCase.options...)
+
+			loggerInstance, err := logger.New(dir, crashFile, options...)
+			if err != nil {
+				t.Fatalf(crashNewErrFmt, err)
+			}
+
+			loggerInstance.Infof(crashEarlierMsg)
+			loggerInstance.Errorf(crashEarlierMsg)
+			loggerInstance.Panicf(crashPanicMsg)
+
+			if loggerInstance.Err() != nil {
+				t.Errorf(crashErrFmt, loggerInstance.Err())
+			}
+
+			closeOutputsLogger(t, loggerInstance)
+
+			names := crashReports(t, dir)
+			if len(names) != 1 {
+				t.Fatalf(crashCountFmt, names, 1)
+			}
+
+			report := readOutputsFile(t, filepath.Join(dir, logger.CrashesDir, names[0]))
+			for _, want := range []string{
+				"=== entry ===\n", crashPanicMsg,
+				"=== recent entries ===\n", crashEarlierMsg,
+				"=== build info ===\n",
+				"=== environment ===\n", `"go_version"`,
+				"=== goroutines ===\n", "goroutine ",
+			} {
+				if !strings.Contains(report, want) {
+					t.Errorf(crashMissingFmt, names[0], want, report)
+				}
+			}
+
+			text := readOutputsFile(t, filepath.Join(dir, crashFile))
+			reference := logger.CrashReportField + "=" + logger.CrashesDir + "/" + names[0]
+
+			if !strings.Contains(text, reference) {
+				t.Errorf(crashMissingFmt, crashFile, reference, text)
+			}
+		})
+	}
+}
+
+func TestWithCrashReports_OnePerEntry(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	loggerInstance, err := logger.New(dir, crashFile, logger.WithoutStdout(), logger.WithCrashReports())
+	if err != nil {
+		t.Fatalf(crashNewErrFmt, err)
+	}
+
+	loggerInstance.Panicf(crashPanicMsg)
+	loggerInstance.Fatalf(crashFatalMsg)
+	closeOutputsLogger(t, loggerInstance)
+
+	names := crashReports(t, dir)
+	if len(names) != 2 {
+		t.Errorf(crashCountFmt, names, 2)
+	}
+}
+
+func TestWithCrashReports_WithoutLogDir(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	loggerInstance := logger.NewStreamLogger(&buf, logger.WithCrashReports())
+	loggerInstance.Panicf(crashPanicMsg)
+
+	if loggerInstance.Err() != nil {
+		t.Errorf(crashErrFmt, loggerInstance.Err())
+	}
+
+	closeTestLogger(t, loggerInstance)
+
+	if strings.Contains(buf.String(), logger.CrashReportField) {
+		t.Errorf(crashExtraFmt, "output", logger.CrashReportField, buf.String())
+	}
+}
+
+func TestWithCrashReports_NotForOtherLevels(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	loggerInstance, err := logger.New(dir, crashFile, logger.WithoutStdout(), logger.WithCrashReports())
+	if err != nil {
+		t.Fatalf(crashNewErrFmt, err)
+	}
+
+	loggerInstance.Errorf(crashEarlierMsg)
+	closeOutputsLogger(t, loggerInstance)
+
+	_, err = os.Stat(filepath.Join(dir, logger.CrashesDir))
+	if !os.IsNotExist(err) {
+		t.Errorf(crashCountFmt, err, 0)
+	}
+}
+
+func crashReports(t *testing.T, dir string) []string {
+	t.Helper()
+
+	entries, err := os.ReadDir(filepath.Join(dir, logger.CrashesDir))
+	if err != nil {
+		t.Fatalf(crashCountFmt, err, 0)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+
+	return names
+}
@@ -0,0 +1,179 @@
+package logger
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	deadLetterMaxLine       = 1 << 20
+	deadLetterTempPattern   = ".redeliver-*"
+	deadLetterLineEnd       = "\n"
+	errFmtOpenDeadLetter    = "open dead letter file: %w"
+	errFmtSyncDeadLetter    = "sync dead letter file: %w"
+	errFmtReadDeadLetter    = "read dead letter file: %w"
+	errFmtKeepDeadLetter    = "keep undelivered dead letters: %w"
+	errFmtRedeliverStopped  = "redeliver: %w"
+	errFmtTruncDeadLetter   = "truncate dead letter file: %w"
+	errFmtRemoveDeadLetters = "remove temporary dead letters: %w"
+)
+
+// DeadLetterFile is a durable RetryPolicy.DeadLetter: every write is synced
+// to disk before it returns, so entries that failed remote delivery survive
+// a crash and can be replayed with RedeliverDeadLetters.
+type DeadLetterFile struct {
+	file *os.File
+	mu   sync.Mutex
+}
+
+// OpenDeadLetterFile opens filename in logDir for appending, validating both
+// like New does.
+func OpenDeadLetterFile(logDir, filename string) (*DeadLetterFile, error) {
+	err := validateInputs(logDir, filename)
+	if err != nil {
+		return nil, fmt.Errorf(errFmtOpenDeadLetter, err)
+	}
+
+	path, err := setupAndValidatePath(logDir, filename)
+	if err != nil {
+		return nil, fmt.Errorf(errFmtOpenDeadLetter, err)
+	}
+
+	file, err := openLogFile(path)
+	if err != nil {
+		return nil, fmt.Errorf(errFmtOpenDeadLetter, err)
+	}
+
+	return &DeadLetterFile{file: file, mu: sync.Mutex{}}, nil
+}
+
+// Write appends data and syncs the file.
+func (deadLetter *DeadLetterFile) Write(data []byte) (int, error) {
+	deadLetter.mu.Lock()
+	defer deadLetter.mu.Unlock()
+
+	n, err := deadLetter.file.Write(data)
+	if err != nil {
+		return n, err
+	}
+
+	err = deadLetter.file.Sync()
+	if err != nil {
+		return n, fmt.Errorf(errFmtSyncDeadLetter, err)
+	}
+
+	return n, nil
+}
+
+// Close closes the file.
+func (deadLetter *DeadLetterFile) Close() error {
+	deadLetter.mu.Lock()
+	defer deadLetter.mu.Unlock()
+
+	return deadLetter.file.Close()
+}
+
+// RedeliverDeadLetters writes the lines of the dead-letter file at path to
+// sink, one line per write, and returns the number delivered. Delivered lines
+// are removed from the file: it is emptied when every line was delivered, and
+// after the first failed write it keeps that line and the following ones for
+// the next attempt. If the file cannot be read it is left unchanged, so lines
+// may be delivered twice. Lines appended while it runs may be lost, so run it
+// while no logger writes to the file.
+func RedeliverDeadLetters(path string, sink Sink) (int, error) {
+	// #nosec G304 -- the dead letter path is chosen by the operator.
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf(errFmtReadDeadLetter, err)
+	}
+	defer file.Close()
+
+	remaining, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+deadLetterTempPattern)
+	if err != nil {
+		return 0, fmt.Errorf(errFmtKeepDeadLetter, err)
+	}
+
+	delivered, deliverErr, err := redeliverLines(file, sink, remaining)
+
+	closeErr := remaining.Close()
+	if closeErr != nil {
+		err = errors.Join(err, fmt.Errorf(errFmtKeepDeadLetter, closeErr))
+	}
+
+	if err != nil {
+		return delivered, errors.Join(err, removeTempDeadLetters(remaining.Name()))
+	}
+
+	if deliverErr != nil {
+		err = os.Rename(remaining.Name(), path)
+		if err != nil {
+			return delivered, fmt.Errorf(errFmtKeepDeadLetter, err)
+		}
+
+		return delivered, fmt.Errorf(errFmtRedeliverStopped, deliverErr)
+	}
+
+	err = removeTempDeadLetters(remaining.Name())
+	if err != nil {
+		return delivered, err
+	}
+
+	err = os.Truncate(path, 0)
+	if err != nil {
+		return delivered, fmt.Errorf(errFmtTruncDeadLetter, err)
+	}
+
+	return delivered, nil
+}
+
+// redeliverLines writes every line of reader to sink until a write fails,
+// then copies that line and the rest to remaining. It returns the number of
+// lines delivered, the delivery error and any error reading reader or
+// writing remaining.
+func redeliverLines(reader io.Reader, sink Sink, remaining io.Writer) (int, error, error) {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(nil, deadLetterMaxLine)
+
+	delivered := 0
+
+	var failed error
+
+	for scanner.Scan() {
+		line := scanner.Text() + deadLetterLineEnd
+
+		if failed == nil {
+			_, failed = sink.Write([]byte(line))
+			if failed == nil {
+				delivered++
+
+				continue
+			}
+		}
+
+		_, err := io.WriteString(remaining, line)
+		if err != nil {
+			return delivered, failed, fmt.Errorf(errFmtKeepDeadLetter, err)
+		}
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return delivered, failed, fmt.Errorf(errFmtReadDeadLetter, err)
+	}
+
+	return delivered, failed, nil
+}
+
+func removeTempDeadLetters(tempPath string) error {
+	err := os.Remove(tempPath)
+	if err != nil {
+		return fmt.Errorf(errFmtRemoveDeadLetters, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package logger_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	deadLetterFile      = "dead.log"
+	deadLetterLines     = "one\ntwo\nthree\n"
+	deadLetterRemaining = "two\nthree\n"
+	deadLetterOpenFmt   = "open dead letter file: %v"
+	deadLetterWriteFmt  = "write: %v"
+	deadLetterReadFmt   = "read: %v"
+	deadLetterCountFmt  = "delivered %d, want %d"
+	deadLetterErrFmt    = "redeliver error = %v, want %v"
+	deadLetterFileFmt   = "dead letter file = %q, want %q"
+	deadLetterUnexpFmt  = "unexpected error: %v"
+)
+
+// limitSink accepts its first limit writes and fails the others.
+type limitSink struct {
+	stubSink
+
+	limit int
+}
+
+func (sink *limitSink) Write(data []byte) (int, error) {
+	sink.fail = sink.writes >= sink.limit
+
+	return sink.stubSink.Write(data)
+}
+
+func TestRedeliverDeadLetters(t *testing.T) {
+	t.Parallel()
+
+	logDir := t.TempDir()
+	path := filepath.Join(logDir, deadLetterFile)
+
+	deadLetter, err := logger.OpenDeadLetterFile(logDir, deadLetterFile)
+	if err != nil {
+		t.Fatalf(deadLetterOpenFmt, err)
+	}
+
+	_, err = deadLetter.Write([]byte(deadLetterLines))
+	if err != nil {
+		t.Fatalf(deadLetterWriteFmt, err)
+	}
+
+	_ = deadLetter.Close()
+
+	partial := &limitSink{stubSink: stubSink{writes: 0, fail: false, closed: false}, limit: 1}
+
+	delivered, err := logger.RedeliverDeadLetters(path, partial)
+	if delivered != 1 || !errors.Is(err, errTestWriteFailed) {
+		t.Errorf(deadLetterErrFmt, err, errTestWriteFailed)
+	}
+
+	expectDeadLetters(t, path, deadLetterRemaining)
+
+	healthy := &stubSink{writes: 0, fail: false, closed: false}
+
+	delivered, err = logger.RedeliverDeadLetters(path, healthy)
+	if err != nil {
+		t.Fatalf(deadLetterUnexpFmt, err)
+	}
+
+	if delivered != 2 {
+		t.Errorf(deadLetterCountFmt, delivered, 2)
+	}
+
+	expectDeadLetters(t, path, "")
+}
+
+func expectDeadLetters(t *testing.T, path, want string) {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf(deadLetterReadFmt, err)
+	}
+
+	if string(data) != want {
+		t.Errorf(deadLetterFileFmt, data, want)
+	}
+}
@@ -0,0 +1,92 @@
+package logger
+
+import "time"
+
+const (
+	debugEnabledFmt  = "debug logging enabled for %s"
+	debugRestoredFmt = "debug logging expired, minimum level restored to %s"
+)
+
+// Debugf logs a diagnostic message. DEBUG entries are below the default
+// minimum level of INFO and are discarded unless debug logging is enabled
+// with EnableDebugFor.
+func (l *Logger) Debugf(format string, args ...any) {
+	l.writef(LevelDebug, nil, format, args...)
+}
+
+// EnableDebugFor lowers the minimum level to DEBUG for duration and then
+// restores the previous minimum level, for live troubleshooting without a
+// restart. Both transitions are logged as SYSTEM entries. Calling it again
+// while debug logging is enabled restarts the countdown with the new
+// duration. The setting is shared by the logger and its child loggers.
+func (l *Logger) EnableDebugFor(duration time.Duration) {
+	c := l.core
+
+	c.mu.Lock()
+
+	if c.closed {
+		c.mu.Unlock()
+
+		return
+	}
+
+	if c.debugTimer == nil {
+		c.debugRestore = c.minimumLevel()
+		c.setMinLevel(LevelDebug)
+	} else {
+		c.debugTimer.Stop()
+	}
+
+	c.debugGen++
+	gen := c.debugGen
+	c.debugTimer = time.AfterFunc(duration, func() {
+		c.runTask(ProfileTaskDebugTimer, func() { c.restoreDebugLevel(gen) })
+	})
+	c.mu.Unlock()
+
+	c.writeEntryf(LevelSystem, nil, "", targetAll, debugEnabledFmt, duration)
+}
+
+// restoreDebugLevel ends the debug period started as generation gen, unless it
+// was extended or the logger closed in the meantime.
+func (c *loggerCore) restoreDebugLevel(gen uint64) {
+	c.mu.Lock()
+
+	if c.closed || gen != c.debugGen {
+		c.mu.Unlock()
+
+		return
+	}
+
+	restored := c.debugRestore
+	c.setMinLevel(restored)
+	c.debugTimer = nil
+	c.mu.Unlock()
+
+	c.writeEntryf(LevelSystem, nil, "", targetAll, debugRestoredFmt, c.label(restored))
+}
+
+// stopDebugTimer cancels a pending restore. The caller holds c.mu.
+func (c *loggerCore) stopDebugTimer() {
+	if c.debugTimer != nil {
+		c.debugTimer.Stop()
+		c.debugTimer = nil
+	}
+}
+
+// enabled reports whether entries at level pass the minimum level. It is a
+// single atomic load, so that calls at disabled levels cost next to nothing
+// and never contend for mu.
+func (c *loggerCore) enabled(level Level) bool {
+	return level >= c.minimumLevel()
+}
+
+func (c *loggerCore) minimumLevel() Level {
+	return Level(c.minLevel.Load())
+}
+
+// setMinLevel changes the minimum level. Callers changing it together with
+// debugRestore hold mu.
+func (c *loggerCore) setMinLevel(level Level) {
+	c.minLevel.Store(int32(level)) // #nosec G115 -- levels are small integers.
+}
@@ -0,0 +1,82 @@
+package logger_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	debugMsg          = "cache state %d"
+	debugHiddenText   = "cache state 1"
+	debugShownText    = "cache state 2"
+	debugAfterText    = "cache state 3"
+	debugEnabledText  = "debug logging enabled for"
+	debugRestoredText = "minimum level restored to INFO"
+	debugPeriod       = 20 * time.Millisecond
+	debugPollInterval = time.Millisecond
+	debugWaitTimeout  = 5 * time.Second
+	debugUnexpected   = "unexpected %q in %q"
+	debugMissing      = "expected %q in %q"
+)
+
+func TestEnableDebugFor(t *testing.T) {
+	t.Parallel()
+
+	var buf syncBuffer
+
+	loggerInstance := logger.NewStreamLogger(&buf)
+
+	loggerInstance.Debugf(debugMsg, 1)
+	loggerInstance.EnableDebugFor(debugPeriod)
+	loggerInstance.Debugf(debugMsg, 2)
+
+	waitForOutput(t, &buf, debugRestoredText)
+	loggerInstance.Debugf(debugMsg, 3)
+
+	output := buf.String()
+	for _, text := range []string{debugHiddenText, debugAfterText} {
+		if strings.Contains(output, text) {
+			t.Errorf(debugUnexpected, text, output)
+		}
+	}
+
+	for _, text := range []string{debugEnabledText, debugShownText, debugRestoredText} {
+		if !strings.Contains(output, text) {
+			t.Errorf(debugMissing, text, output)
+		}
+	}
+}
+
+func TestEnableDebugFor_ExtendKeepsOriginalLevel(t *testing.T) {
+	t.Parallel()
+
+	var buf syncBuffer
+
+	loggerInstance := logger.NewStreamLogger(&buf)
+
+	loggerInstance.EnableDebugFor(time.Hour)
+	loggerInstance.EnableDebugFor(debugPeriod)
+
+	waitForOutput(t, &buf, debugRestoredText)
+	loggerInstance.Debugf(debugMsg, 3)
+
+	if strings.Contains(buf.String(), debugAfterText) {
+		t.Errorf(debugUnexpected, debugAfterText, buf.String())
+	}
+}
+
+func waitForOutput(t *testing.T, buf *syncBuffer, text string) {
+	t.Helper()
+
+	deadline := time.Now().Add(debugWaitTimeout)
+	for !strings.Contains(buf.String(), text) {
+		if time.Now().After(deadline) {
+			t.Fatalf(debugMissing, text, buf.String())
+		}
+
+		time.Sleep(debugPollInterval)
+	}
+}
@@ -0,0 +1,67 @@
+package logger
+
+import "maps"
+
+const symbolSeparator = " "
+
+// defaultLevelSymbols are the prefixes used by WithDecoratedStdout.
+var defaultLevelSymbols = map[Level]string{
+	LevelInfo:    "ℹ️",
+	LevelSuccess: "✅",
+	LevelWarn:    "⚠️",
+	LevelSystem:  "⚙️",
+	LevelError:   "❌",
+	LevelPanic:   "🔥",
+	LevelFatal:   "💀",
+}
+
+// WithDecoratedStdout prefixes terminal output with a per-level symbol
+// (✅ SUCCESS, ⚠️ WARN, ❌ ERROR, ...) for at-a-glance readability during
+// interactive runs. The log file is never decorated.
+func WithDecoratedStdout() Option {
+	return func(config *options) {
+		if config.levelSymbols == nil {
+			config.levelSymbols = maps.Clone(defaultLevelSymbols)
+		}
+	}
+}
+
+// WithLevelSymbols enables decorated terminal output like WithDecoratedStdout
+// and overrides the symbol of individual levels, including registered ones.
+// An empty symbol disables decoration for that level.
+func WithLevelSymbols(symbols map[Level]string) Option {
+	return func(config *options) {
+		WithDecoratedStdout()(config)
+		maps.Copy(config.levelSymbols, symbols)
+	}
+}
+
+// decorate returns msg prefixed with the terminal symbol of level, if any.
+// JSON, CEF and LEEF lines are never decorated so that they stay
+// machine-readable.
+func (c *loggerCore) decorate(level Level, msg string) string {
+	prefix := c.levelSymbols[level]
+	if prefix == "" || c.encoding != EncodingText {
+		return msg
+	}
+
+	return prefix + msg
+}
+
+// symbolPrefixes returns the decoration prefix of every level with a symbol,
+// built once at construction.
+func symbolPrefixes(symbols map[Level]string) map[Level]string {
+	if symbols == nil {
+		return nil
+	}
+
+	prefixes := make(map[Level]string, len(symbols))
+
+	for level, symbol := range symbols {
+		if symbol != "" {
+			prefixes[level] = symbol + symbolSeparator
+		}
+	}
+
+	return prefixes
+}
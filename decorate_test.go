@@ -0,0 +1,83 @@
+package logger_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	decorateLogFile       = "decorated.log"
+	decorateTemplate      = "[{level}] {msg}"
+	decorateMsg           = "page rendered"
+	decorateExpected      = "✅ [SUCCESS] page rendered\n⚠️ [WARN] page rendered\n"
+	decorateCustomExpect  = "OK [SUCCESS] page rendered\n[INFO] page rendered\n"
+	decorateFileSymbolErr = "log file must not be decorated, got:\n%s"
+)
+
+func TestLogger_DecoratedStdout(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	loggerInstance := logger.NewStreamLogger(
+		&buf,
+		logger.WithLayout(logger.MustParseLayout(decorateTemplate)),
+		logger.WithDecoratedStdout(),
+	)
+	loggerInstance.Successf(decorateMsg)
+	loggerInstance.Warnf(decorateMsg)
+
+	if buf.String() != decorateExpected {
+		t.Errorf(layoutOutputErrFmt, decorateExpected, buf.String())
+	}
+}
+
+func TestLogger_CustomLevelSymbols(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	loggerInstance := logger.NewStreamLogger(
+		&buf,
+		logger.WithLayout(logger.MustParseLayout(decorateTemplate)),
+		logger.WithLevelSymbols(map[logger.Level]string{
+			logger.LevelSuccess: "OK",
+			logger.LevelInfo:    "",
+		}),
+	)
+	loggerInstance.Successf(decorateMsg)
+	loggerInstance.Infof(decorateMsg)
+
+	if buf.String() != decorateCustomExpect {
+		t.Errorf(layoutOutputErrFmt, decorateCustomExpect, buf.String())
+	}
+}
+
+func TestLogger_DecoratedStdoutLeavesFileUndecorated(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+
+	loggerInstance, err := logger.New(tempDir, decorateLogFile, logger.WithDecoratedStdout())
+	if err != nil {
+		t.Fatalf(newLoggerError, err)
+	}
+
+	loggerInstance.Errorf(decorateMsg)
+	closeTestLogger(t, loggerInstance)
+
+	// #nosec G304
+	content, err := os.ReadFile(filepath.Join(tempDir, decorateLogFile))
+	if err != nil {
+		t.Fatalf(readLogFileErr, err)
+	}
+
+	if strings.Contains(string(content), "❌") {
+		t.Errorf(decorateFileSymbolErr, content)
+	}
+}
@@ -0,0 +1,55 @@
+package logger
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// DeprecatedField carries the feature key of a Deprecationf entry.
+const DeprecatedField = "deprecated"
+
+// deprecations counts the uses of every deprecated feature. It is shared by
+// all loggers, so that a feature is reported once per process whichever
+// logger reports it.
+var deprecations sync.Map // string -> *atomic.Uint64
+
+// Deprecationf reports a use of the deprecated feature as a WARN entry
+// carrying the feature in DeprecatedField. Only the first use of each
+// feature in the process is logged; later uses are counted, see
+// Deprecations. Libraries built on this logger can thus flag deprecated
+// usage on hot paths without flooding the logs.
+func (l *Logger) Deprecationf(feature, format string, args ...any) {
+	if countDeprecation(feature) > 1 {
+		return
+	}
+
+	l.writef(LevelWarn, []Field{F(DeprecatedField, feature)}, format, args...)
+}
+
+// Deprecations returns the number of uses reported by Deprecationf so far,
+// by feature.
+func Deprecations() map[string]uint64 {
+	counts := make(map[string]uint64)
+
+	deprecations.Range(func(feature, counter any) bool {
+		key, _ := feature.(string)
+		count, _ := counter.(*atomic.Uint64)
+		counts[key] = count.Load()
+
+		return true
+	})
+
+	return counts
+}
+
+// countDeprecation counts a use of feature and returns the uses so far.
+func countDeprecation(feature string) uint64 {
+	counter, ok := deprecations.Load(feature)
+	if !ok {
+		counter, _ = deprecations.LoadOrStore(feature, new(atomic.Uint64))
+	}
+
+	count, _ := counter.(*atomic.Uint64)
+
+	return count.Add(1)
+}
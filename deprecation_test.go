@@ -0,0 +1,56 @@
+package logger_test
+
+import (
+	"testing"
+
+	"github.com/book-expert/logger"
+	"github.com/book-expert/logger/loggertest"
+)
+
+const (
+	deprecationFeature = "deprecation_test.legacy_ocr"
+	deprecationOther   = "deprecation_test.v1_layout"
+	deprecationFormat  = "%s is deprecated; use %s"
+	deprecationOld     = "LegacyOCR"
+	deprecationNew     = "OCR"
+	deprecationUses    = 5
+	deprecationMsg     = "LegacyOCR is deprecated; use OCR"
+	deprecationLogFmt  = "messages = %q; want one %q"
+	deprecationLvlFmt  = "entry = %+v; want WARN with %s=%s"
+	deprecationCntFmt  = "Deprecations()[%s] = %d; want %d"
+)
+
+func TestLogger_Deprecationf(t *testing.T) {
+	t.Parallel()
+
+	loggerInstance, observer := loggertest.NewObservedLogger(t)
+	otherLogger, otherObserver := loggertest.NewObservedLogger(t)
+
+	for range deprecationUses {
+		loggerInstance.Deprecationf(deprecationFeature, deprecationFormat, deprecationOld, deprecationNew)
+		otherLogger.Deprecationf(deprecationFeature, deprecationFormat, deprecationOld, deprecationNew)
+	}
+
+	otherLogger.Deprecationf(deprecationOther, deprecationFormat, deprecationOld, deprecationNew)
+
+	messages := observer.Messages()
+	if len(messages) != 1 || messages[0] != deprecationMsg {
+		t.Fatalf(deprecationLogFmt, messages, deprecationMsg)
+	}
+
+	entry := observer.Entries()[0]
+	if entry.Level != logger.LevelWarn || len(entry.Fields) != 1 || entry.Fields[0].Value != deprecationFeature {
+		t.Errorf(deprecationLvlFmt, entry, logger.DeprecatedField, deprecationFeature)
+	}
+
+	if otherObserver.Len() != 1 {
+		t.Errorf(deprecationLogFmt, otherObserver.Messages(), deprecationOther)
+	}
+
+	counts := logger.Deprecations()
+	for feature, want := range map[string]uint64{deprecationFeature: 2 * deprecationUses, deprecationOther: 1} {
+		if counts[feature] != want {
+			t.Errorf(deprecationCntFmt, feature, counts[feature], want)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package logger
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+const (
+	// diskBurstShare is the share of a second of budget a DiskLimiter lets
+	// through at once, which bounds how long a write burst holds the disk.
+	diskBurstShare = 10
+	// minDiskBurst lets typical lines through at low limits.
+	minDiskBurst = 64 << 10
+)
+
+// DiskLimiter bounds the bytes per second the loggers sharing it write to
+// their files. Writes that exceed the budget wait, and waiting writes are
+// served one file at a time in turn, so that a burst on one logger's files
+// does not starve another logger. A logger writes its log file and route
+// files one entry at a time under its lock, so its own files do not take
+// turns: they share the budget in the order entries are logged, except for
+// route files written by WithSinkWorkers workers. Create it with
+// NewDiskLimiter and pass it to loggers with WithDiskLimit.
+type DiskLimiter struct {
+	queues  map[string][]diskRequest
+	turns   []string
+	last    time.Time
+	tokens  float64
+	rate    float64
+	burst   float64
+	mu      sync.Mutex
+	running bool
+}
+
+// diskRequest is a write waiting for budget.
+type diskRequest struct {
+	ready chan struct{}
+	size  int
+}
+
+// limitedWriter writes to a file within the budget of a DiskLimiter.
+type limitedWriter struct {
+	writer  io.Writer
+	limiter *DiskLimiter
+	name    string
+}
+
+// NewDiskLimiter returns a DiskLimiter allowing bytesPerSecond, with bursts of
+// a tenth of a second's budget and at least 64 KiB. A non-positive rate
+// returns nil, which does not limit.
+func NewDiskLimiter(bytesPerSecond int64) *DiskLimiter {
+	if bytesPerSecond <= 0 {
+		return nil
+	}
+
+	burst := max(float64(bytesPerSecond)/diskBurstShare, minDiskBurst)
+
+	return &DiskLimiter{
+		queues:  make(map[string][]diskRequest),
+		turns:   nil,
+		last:    time.Now(),
+		tokens:  burst,
+		rate:    float64(bytesPerSecond),
+		burst:   burst,
+		mu:      sync.Mutex{},
+		running: false,
+	}
+}
+
+// WithDiskLimit throttles the writes of the log file and the route files to
+// the budget of limiter, so that a logging burst does not starve latency
+// sensitive workloads on the same disk. Pass the same limiter to several
+// loggers to share one budget among all their files; turns are taken between
+// loggers, not between the files of one logger. Throttled writes hold up the
+// logging call, or the async writer with WithAsync.
+func WithDiskLimit(limiter *DiskLimiter) Option {
+	return func(config *options) {
+		config.diskLimiter = limiter
+	}
+}
+
+// limit returns writer throttled by limiter as the file name, or writer
+// itself without a limiter.
+func (limiter *DiskLimiter) limit(writer io.Writer, name string) io.Writer {
+	if limiter == nil {
+		return writer
+	}
+
+	return &limitedWriter{writer: writer, limiter: limiter, name: name}
+}
+
+// Write waits for the budget of data and writes it.
+func (lw *limitedWriter) Write(data []byte) (int, error) {
+	lw.limiter.wait(lw.name, len(data))
+
+	return lw.writer.Write(data)
+}
+
+// wait returns once size bytes of the file name may be written. Writes larger
+// than the burst wait for a full bucket and leave it in debt.
+func (limiter *DiskLimiter) wait(name string, size int) {
+	limiter.mu.Lock()
+	limiter.refill()
+
+	if len(limiter.turns) == 0 && limiter.tokens >= min(float64(size), limiter.burst) {
+		limiter.tokens -= float64(size)
+		limiter.mu.Unlock()
+
+		return
+	}
+
+	request := diskRequest{ready: make(chan struct{}), size: size}
+	if len(limiter.queues[name]) == 0 {
+		limiter.turns = append(limiter.turns, name)
+	}
+
+	limiter.queues[name] = append(limiter.queues[name], request)
+
+	if !limiter.running {
+		limiter.running = true
+
+		go limiter.dispatch()
+	}
+
+	limiter.mu.Unlock()
+
+	<-request.ready
+}
+
+// dispatch grants the waiting writes as the budget refills, taking one write
+// of each waiting file in turn, and returns when none is left.
+func (limiter *DiskLimiter) dispatch() {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	for len(limiter.turns) > 0 {
+		limiter.refill()
+
+		name := limiter.turns[0]
+		request := limiter.queues[name][0]
+
+		need := min(float64(request.size), limiter.burst)
+		if limiter.tokens < need {
+			delay := time.Duration((need - limiter.tokens) / limiter.rate * float64(time.Second))
+
+			limiter.mu.Unlock()
+			time.Sleep(delay)
+			limiter.mu.Lock()
+
+			continue
+		}
+
+		limiter.tokens -= float64(request.size)
+		close(request.ready)
+
+		limiter.queues[name] = limiter.queues[name][1:]
+		limiter.turns = limiter.turns[1:]
+
+		if len(limiter.queues[name]) > 0 {
+			limiter.turns = append(limiter.turns, name)
+		} else {
+			delete(limiter.queues, name)
+		}
+	}
+
+	limiter.running = false
+}
+
+// refill adds the budget earned since the last refill. The caller holds mu.
+func (limiter *DiskLimiter) refill() {
+	now := time.Now()
+	limiter.tokens = min(limiter.burst, limiter.tokens+now.Sub(limiter.last).Seconds()*limiter.rate)
+	limiter.last = now
+}
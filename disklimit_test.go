@@ -0,0 +1,166 @@
+package logger_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	diskRate       = 1 << 20
+	diskLineBytes  = 1 << 10
+	diskLines      = 300
+	diskMinElapsed = 150 * time.Millisecond
+	diskBurstyFile = "bursty.log"
+	diskQuietFile  = "quiet.log"
+	diskRouteFile  = "route.log"
+	diskQuietLines = 5
+	diskQuietMax   = 200 * time.Millisecond
+	diskElapsedFmt = "%d bytes took %s; want at least %s"
+	diskQuietFmt   = "%d quiet lines took %s during a burst; want less than %s"
+	diskSizeFmt    = "%s holds %d bytes; want at least %d"
+	diskNilFmt     = "NewDiskLimiter(0) = %v; want nil"
+)
+
+func TestWithDiskLimit_Throttles(t *testing.T) {
+	t.Parallel()
+
+	logDir := t.TempDir()
+	loggerInstance := newDiskLogger(t, logDir, diskBurstyFile, logger.NewDiskLimiter(diskRate))
+	line := strings.Repeat("x", diskLineBytes)
+
+	start := time.Now()
+	for range diskLines {
+		loggerInstance.Infof(line)
+	}
+
+	elapsed := time.Since(start)
+	closeTestLogger(t, loggerInstance)
+
+	if elapsed < diskMinElapsed {
+		t.Errorf(diskElapsedFmt, diskLines*diskLineBytes, elapsed, diskMinElapsed)
+	}
+
+	info, err := os.Stat(filepath.Join(logDir, diskBurstyFile))
+	if err != nil {
+		t.Fatalf(readLogFileErr, err)
+	}
+
+	if info.Size() < diskLines*diskLineBytes {
+		t.Errorf(diskSizeFmt, diskBurstyFile, info.Size(), diskLines*diskLineBytes)
+	}
+}
+
+func TestWithDiskLimit_FairAcrossLoggers(t *testing.T) {
+	t.Parallel()
+
+	logDir := t.TempDir()
+	limiter := logger.NewDiskLimiter(diskRate)
+	bursty := newDiskLogger(t, logDir, diskBurstyFile, limiter)
+	quiet := newDiskLogger(t, logDir, diskQuietFile, limiter)
+	line := strings.Repeat("x", diskLineBytes)
+
+	var (
+		stop  atomic.Bool
+		bytes atomic.Int64
+	)
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		for !stop.Load() {
+			bursty.Infof(line)
+			bytes.Add(diskLineBytes)
+		}
+	}()
+
+	// Wait until the burst has used up the limiter's budget.
+	for bytes.Load() < diskLines*diskLineBytes/2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	start := time.Now()
+	for range diskQuietLines {
+		quiet.Infof(line)
+	}
+
+	elapsed := time.Since(start)
+
+	stop.Store(true)
+	<-done
+	closeTestLogger(t, bursty)
+	closeTestLogger(t, quiet)
+
+	if elapsed > diskQuietMax {
+		t.Errorf(diskQuietFmt, diskQuietLines, elapsed, diskQuietMax)
+	}
+}
+
+func TestWithDiskLimit_CoversRouteFiles(t *testing.T) {
+	t.Parallel()
+
+	logDir := t.TempDir()
+
+	// Every entry reaches both files, so the budget must cover twice the
+	// bytes logged: half the lines of TestWithDiskLimit_Throttles take as
+	// long only if the route file draws on it too.
+	loggerInstance, err := logger.New(logDir, diskBurstyFile,
+		logger.WithoutStdout(),
+		logger.WithDiskLimit(logger.NewDiskLimiter(diskRate)),
+		logger.WithRoute(logger.Route{Filename: diskRouteFile}),
+	)
+	if err != nil {
+		t.Fatalf(newLoggerError, err)
+	}
+
+	line := strings.Repeat("x", diskLineBytes)
+
+	start := time.Now()
+	for range diskLines / 2 {
+		loggerInstance.Infof(line)
+	}
+
+	elapsed := time.Since(start)
+	closeTestLogger(t, loggerInstance)
+
+	if elapsed < diskMinElapsed {
+		t.Errorf(diskElapsedFmt, diskLines*diskLineBytes, elapsed, diskMinElapsed)
+	}
+
+	for _, filename := range []string{diskBurstyFile, diskRouteFile} {
+		info, err := os.Stat(filepath.Join(logDir, filename))
+		if err != nil {
+			t.Fatalf(readLogFileErr, err)
+		}
+
+		if info.Size() < diskLines/2*diskLineBytes {
+			t.Errorf(diskSizeFmt, filename, info.Size(), diskLines/2*diskLineBytes)
+		}
+	}
+}
+
+func TestNewDiskLimiter_Unlimited(t *testing.T) {
+	t.Parallel()
+
+	if limiter := logger.NewDiskLimiter(0); limiter != nil {
+		t.Errorf(diskNilFmt, limiter)
+	}
+}
+
+func newDiskLogger(t *testing.T, logDir, filename string, limiter *logger.DiskLimiter) *logger.Logger {
+	t.Helper()
+
+	loggerInstance, err := logger.New(logDir, filename, logger.WithoutStdout(), logger.WithDiskLimit(limiter))
+	if err != nil {
+		t.Fatalf(newLoggerError, err)
+	}
+
+	return loggerInstance
+}
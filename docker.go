@@ -0,0 +1,251 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultDockerRoot is where the Docker json-file log driver keeps the
+	// directories of the containers.
+	DefaultDockerRoot = "/var/lib/docker/containers"
+
+	// Fields added to the entries of container logs.
+	ContainerIDField   = "container_id"
+	ContainerNameField = "container_name"
+	StreamField        = "stream"
+
+	dockerLogSuffix      = "-json.log"
+	dockerConfigFile     = "config.v2.json"
+	dockerStateSuffix    = ".json"
+	dockerNamePrefix     = "/"
+	dockerLogGlob        = "*"
+	dockerShortIDLen     = 12
+	errFmtDockerRoot     = "docker containers: %w"
+	errFmtDockerShipping = "container %s: %w"
+)
+
+// DockerContainer is a container whose output Docker writes with the
+// json-file log driver.
+type DockerContainer struct {
+	ID      string
+	Name    string
+	LogPath string
+}
+
+// dockerRecord is a line of a json-file log.
+type dockerRecord struct {
+	Time   time.Time `json:"time"`
+	Log    string    `json:"log"`
+	Stream string    `json:"stream"`
+}
+
+// dockerConfig holds the fields read from a container's config.v2.json.
+type dockerConfig struct {
+	Name string `json:"Name"`
+}
+
+// DockerCollector re-emits the logs of Docker containers through a logger,
+// so that small hosts get centralized logs without a separate log shipper.
+// Every entry carries the container name and short ID and the stream it was
+// written to. Each container has its own Shipper, so rotated json-file logs
+// are followed and, with a state directory, a restart resumes where it
+// stopped.
+type DockerCollector struct {
+	logger   *Logger
+	parser   *LineParser
+	shippers map[string]*Shipper
+	root     string
+	stateDir string
+	selected []string
+}
+
+// DockerCollector returns a collector for the containers under root, or
+// DefaultDockerRoot when empty. selected limits it to containers with these
+// names or ID prefixes; when empty every container is collected, including
+// containers started later. The lines the containers wrote are parsed with
+// parser. Cursors are kept in stateDir, one file per container, or in memory
+// when stateDir is empty.
+func (l *Logger) DockerCollector(root, stateDir string, selected []string, parser *LineParser) *DockerCollector {
+	if root == "" {
+		root = DefaultDockerRoot
+	}
+
+	return &DockerCollector{
+		logger:   l,
+		parser:   parser,
+		shippers: make(map[string]*Shipper),
+		root:     root,
+		stateDir: stateDir,
+		selected: selected,
+	}
+}
+
+// Collect logs the lines the selected containers wrote since the last call
+// and returns the number of bytes consumed. A failing container does not
+// stop the others; their errors are joined.
+func (collector *DockerCollector) Collect() (int64, error) {
+	containers, err := DockerContainers(collector.root, collector.selected)
+	if err != nil {
+		return 0, err
+	}
+
+	collector.forget(containers)
+
+	var (
+		shipped int64
+		errs    []error
+	)
+
+	for _, container := range containers {
+		count, err := collector.collect(container)
+		shipped += count
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf(errFmtDockerShipping, container.Name, err))
+		}
+	}
+
+	return shipped, errors.Join(errs...)
+}
+
+func (collector *DockerCollector) collect(container DockerContainer) (int64, error) {
+	shipper, ok := collector.shippers[container.ID]
+	if !ok {
+		statePath := ""
+		if collector.stateDir != "" {
+			statePath = filepath.Join(collector.stateDir, container.ID+dockerStateSuffix)
+		}
+
+		sink := &dockerSink{logger: collector.logger.With(
+			F(ContainerNameField, container.Name),
+			F(ContainerIDField, shortContainerID(container.ID)),
+		), parser: collector.parser}
+
+		var err error
+
+		shipper, err = OpenShipper(statePath, sink, 0)
+		if err != nil {
+			return 0, err
+		}
+
+		collector.shippers[container.ID] = shipper
+	}
+
+	return shipper.ShipGlob(container.LogPath + dockerLogGlob)
+}
+
+// forget drops the shippers of containers that were removed.
+func (collector *DockerCollector) forget(containers []DockerContainer) {
+	for id := range collector.shippers {
+		if !slices.ContainsFunc(containers, func(container DockerContainer) bool { return container.ID == id }) {
+			delete(collector.shippers, id)
+		}
+	}
+}
+
+// DockerContainers lists the containers under root that have a json-file
+// log, limited to those whose name or ID prefix is in selected unless
+// selected is empty.
+func DockerContainers(root string, selected []string) ([]DockerContainer, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, fmt.Errorf(errFmtDockerRoot, err)
+	}
+
+	var containers []DockerContainer
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		container := readDockerContainer(filepath.Join(root, entry.Name()), entry.Name())
+		if fileExists(container.LogPath) && container.selected(selected) {
+			containers = append(containers, container)
+		}
+	}
+
+	return containers, nil
+}
+
+func readDockerContainer(dir, id string) DockerContainer {
+	container := DockerContainer{
+		ID:      id,
+		Name:    shortContainerID(id),
+		LogPath: filepath.Join(dir, id+dockerLogSuffix),
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, dockerConfigFile))
+	if err != nil {
+		return container
+	}
+
+	var config dockerConfig
+
+	err = json.Unmarshal(data, &config)
+	if err == nil && config.Name != "" {
+		container.Name = strings.TrimPrefix(config.Name, dockerNamePrefix)
+	}
+
+	return container
+}
+
+func (container *DockerContainer) selected(selected []string) bool {
+	if len(selected) == 0 {
+		return true
+	}
+
+	return slices.ContainsFunc(selected, func(name string) bool {
+		return name == container.Name || strings.HasPrefix(container.ID, name)
+	})
+}
+
+func shortContainerID(id string) string {
+	return id[:min(len(id), dockerShortIDLen)]
+}
+
+// dockerSink logs the records of a json-file log through a logger carrying
+// the container's fields.
+type dockerSink struct {
+	logger *Logger
+	parser *LineParser
+}
+
+// Write logs every record of data. Lines that are not json-file records are
+// logged as they are.
+func (sink *dockerSink) Write(data []byte) (int, error) {
+	now := time.Now()
+
+	for line := range bytes.SplitSeq(bytes.TrimSuffix(data, []byte{ingestLineEnd}), []byte{ingestLineEnd}) {
+		var record dockerRecord
+
+		err := json.Unmarshal(line, &record)
+		if err != nil {
+			sink.logger.logParsed(sink.parser.Parse(string(line), now))
+
+			continue
+		}
+
+		if record.Time.IsZero() {
+			record.Time = now
+		}
+
+		message := strings.TrimSuffix(record.Log, string(ingestLineEnd))
+		sink.logger.With(F(StreamField, record.Stream)).logParsed(sink.parser.Parse(message, record.Time))
+	}
+
+	return len(data), nil
+}
+
+// Close does nothing; the logger is closed by its owner.
+func (*dockerSink) Close() error {
+	return nil
+}
@@ -0,0 +1,116 @@
+package logger_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	dockerWebID     = "0123456789abcdef0123456789abcdef"
+	dockerDBID      = "fedcba9876543210fedcba9876543210"
+	dockerWebConfig = `{"ID":"0123456789abcdef0123456789abcdef","Name":"/web"}`
+	dockerWebLog    = `{"log":"ERROR: upstream timeout\n","stream":"stderr","time":"2025-01-02T15:04:05Z"}` + "\n" +
+		`{"log":"listening\n","stream":"stdout","time":"2025-01-02T15:04:06Z"}` + "\n"
+	dockerDBLog   = `{"log":"ready\n","stream":"stdout","time":"2025-01-02T15:04:07Z"}` + "\n"
+	dockerMoreLog = `{"log":"WARN: slow request\n","stream":"stdout","time":"2025-01-02T15:04:08Z"}` + "\n"
+	dockerLayout  = "[{level}] {msg} {fields}"
+	dockerWebWant = "[ERROR] upstream timeout container_name=web container_id=0123456789ab stream=stderr\n" +
+		"[INFO] listening container_name=web container_id=0123456789ab stream=stdout\n"
+	dockerMoreWant   = "[WARN] slow request container_name=web container_id=0123456789ab stream=stdout\n"
+	dockerLogPerm    = 0o600
+	dockerDirPerm    = 0o750
+	dockerSetupFmt   = "setup: %v"
+	dockerCollectFmt = "Collect: %v"
+	dockerOutputFmt  = "output:\n%s\nwant:\n%s"
+	dockerListFmt    = "DockerContainers = %+v; want the web container"
+)
+
+func TestDockerCollector_CollectsSelectedContainers(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeDockerContainer(t, root, dockerWebID, dockerWebConfig, dockerWebLog)
+	writeDockerContainer(t, root, dockerDBID, "", dockerDBLog)
+
+	var buf bytes.Buffer
+
+	loggerInstance := logger.NewStreamLogger(&buf, logger.WithLayout(logger.MustParseLayout(dockerLayout)))
+	collector := loggerInstance.DockerCollector(root, t.TempDir(), []string{"web"}, newLineParser(t, "", ""))
+
+	_, err := collector.Collect()
+	if err != nil {
+		t.Fatalf(dockerCollectFmt, err)
+	}
+
+	if buf.String() != dockerWebWant {
+		t.Fatalf(dockerOutputFmt, buf.String(), dockerWebWant)
+	}
+
+	appendDockerLog(t, root, dockerWebID, dockerMoreLog)
+	buf.Reset()
+
+	_, err = collector.Collect()
+	if err != nil {
+		t.Fatalf(dockerCollectFmt, err)
+	}
+
+	if buf.String() != dockerMoreWant {
+		t.Errorf(dockerOutputFmt, buf.String(), dockerMoreWant)
+	}
+}
+
+func TestDockerContainers_SelectsByIDPrefix(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeDockerContainer(t, root, dockerWebID, dockerWebConfig, dockerWebLog)
+	writeDockerContainer(t, root, dockerDBID, "", dockerDBLog)
+
+	containers, err := logger.DockerContainers(root, []string{dockerWebID[:6]})
+	if err != nil {
+		t.Fatalf(dockerCollectFmt, err)
+	}
+
+	if len(containers) != 1 || containers[0].Name != "web" || containers[0].ID != dockerWebID {
+		t.Errorf(dockerListFmt, containers)
+	}
+}
+
+func writeDockerContainer(t *testing.T, root, id, config, log string) {
+	t.Helper()
+
+	dir := filepath.Join(root, id)
+
+	err := os.Mkdir(dir, dockerDirPerm)
+	if err != nil {
+		t.Fatalf(dockerSetupFmt, err)
+	}
+
+	if config != "" {
+		err = os.WriteFile(filepath.Join(dir, "config.v2.json"), []byte(config), dockerLogPerm)
+		if err != nil {
+			t.Fatalf(dockerSetupFmt, err)
+		}
+	}
+
+	appendDockerLog(t, root, id, log)
+}
+
+func appendDockerLog(t *testing.T, root, id, log string) {
+	t.Helper()
+
+	file, err := os.OpenFile(filepath.Join(root, id, id+"-json.log"), os.O_CREATE|os.O_APPEND|os.O_WRONLY, dockerLogPerm)
+	if err != nil {
+		t.Fatalf(dockerSetupFmt, err)
+	}
+	defer file.Close()
+
+	_, err = file.WriteString(log)
+	if err != nil {
+		t.Fatalf(dockerSetupFmt, err)
+	}
+}
@@ -0,0 +1,326 @@
+package logger
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// Checks run by Diagnose, reported in Finding.Check.
+	DoctorCheckSelfTest    = "self-test"
+	DoctorCheckPermissions = "permissions"
+	DoctorCheckRotation    = "rotation"
+	DoctorCheckClock       = "clock"
+	DoctorCheckLocks       = "locks"
+
+	// DefaultDoctorMaxFileBytes is the size above which Diagnose reports a
+	// log file that is not rotated.
+	DefaultDoctorMaxFileBytes = 1 << 30
+	// DefaultDoctorClockSkew is how far in the future a modification time
+	// may be before Diagnose reports clock skew.
+	DefaultDoctorClockSkew = time.Minute
+	// DefaultDoctorStaleLockAge is the age after which a lock file without a
+	// process ID is reported as stale.
+	DefaultDoctorStaleLockAge = 24 * time.Hour
+
+	doctorWorldWritable = 0o002
+	doctorWorldReadable = 0o004
+	doctorPIDSuffix     = ".pid"
+	doctorLockSuffix    = ".lock"
+	doctorBackupBase    = 10
+	doctorFindingFmt    = "%s %s: %s: %s -> %s"
+
+	doctorMsgNotDir        = "is not a directory"
+	doctorMsgDirWritable   = "directory is world-writable"
+	doctorMsgFileWritable  = "file is world-writable"
+	doctorMsgFileReadable  = "file is world-readable"
+	doctorMsgNotWritable   = "file is not writable by this process: %v"
+	doctorMsgOwnerFmt      = "owned by uid %d, not by the current uid %d"
+	doctorMsgSelfTest      = "%v"
+	doctorMsgBackupGap     = "backup %s is missing between %s and %s"
+	doctorMsgLargeFmt      = "%d bytes and not rotated"
+	doctorMsgFutureFmt     = "modified %s in the future"
+	doctorMsgDeadPIDFmt    = "process %d is not running"
+	doctorMsgOldLockFmt    = "lock is %s old"
+	doctorAdviceStat       = "check that the directory exists and is readable"
+	doctorAdviceNotDir     = "point -dir at the log directory"
+	doctorAdviceChmodDir   = "run chmod o-w on it so that other users cannot plant or remove log files"
+	doctorAdviceChmodFile  = "run chmod 600 on it, the logger creates files readable by their owner only"
+	doctorAdviceWritable   = "fix its ownership or mode, or run the service as the file's owner"
+	doctorAdviceChown      = "chown it to the service user so that rotation and cleanup can rename it"
+	doctorAdviceSelfTest   = "fix the failing output before starting the service"
+	doctorAdviceBackupGap  = "a rotation was interrupted, check free space and that only one process rotates the file"
+	doctorAdviceLarge      = "enable rotation, e.g. AccessLog.MaxSize, or an external logrotate"
+	doctorAdviceFuture     = "the clock may have jumped back, check NTP before trusting entry order"
+	doctorAdviceStaleLock  = "remove it once no process uses the directory"
+	doctorAdviceReadLock   = "check that the lock file is readable"
+	doctorErrUnreadableFmt = "cannot read lock file: %v"
+)
+
+// Finding is a problem reported by Diagnose. Level is LevelWarn for risks
+// and LevelError for problems that break logging.
+type Finding struct {
+	Check   string
+	Path    string
+	Problem string
+	Advice  string
+	Level   Level
+}
+
+// String renders the finding as "LEVEL check: path: problem -> advice".
+func (finding Finding) String() string {
+	return fmt.Sprintf(doctorFindingFmt, finding.Level, finding.Check, finding.Path, finding.Problem, finding.Advice)
+}
+
+// DoctorOptions selects what Diagnose checks. Logger, when set, runs its
+// SelfTest; zero limits use the Default values.
+type DoctorOptions struct {
+	Logger       *Logger
+	Dir          string
+	MaxFileBytes int64
+	ClockSkew    time.Duration
+	StaleLockAge time.Duration
+}
+
+// Diagnose checks a log directory for problems that break logging or will:
+// a failing SelfTest, files and directories other users may write or read,
+// files owned by another user, gaps in rotated backups and large files that
+// are not rotated, modification times in the future, and lock or PID files
+// left behind by processes that are gone. Every finding carries advice.
+func Diagnose(options DoctorOptions) []Finding {
+	var findings []Finding
+
+	if options.Logger != nil {
+		findings = append(findings, selfTestFindings(options.Logger, options.Dir)...)
+	}
+
+	info, err := os.Stat(options.Dir)
+	if err != nil {
+		return append(findings,
+			newFinding(DoctorCheckPermissions, options.Dir, err.Error(), doctorAdviceStat, LevelError))
+	}
+
+	if !info.IsDir() {
+		return append(findings,
+			newFinding(DoctorCheckPermissions, options.Dir, doctorMsgNotDir, doctorAdviceNotDir, LevelError))
+	}
+
+	findings = append(findings, permissionFindings(options.Dir, info, true)...)
+
+	entries, err := os.ReadDir(options.Dir)
+	if err != nil {
+		return append(findings,
+			newFinding(DoctorCheckPermissions, options.Dir, err.Error(), doctorAdviceStat, LevelError))
+	}
+
+	now := time.Now()
+
+	for _, entry := range entries {
+		findings = append(findings, fileFindings(&options, entry, now)...)
+	}
+
+	return append(findings, backupFindings(options.Dir, entries)...)
+}
+
+func newFinding(check, path, problem, advice string, level Level) Finding {
+	return Finding{Check: check, Path: path, Problem: problem, Advice: advice, Level: level}
+}
+
+// selfTestFindings reports every error of the logger's SelfTest.
+func selfTestFindings(loggerInstance *Logger, dir string) []Finding {
+	err := loggerInstance.SelfTest()
+	if err == nil {
+		return nil
+	}
+
+	errs := []error{err}
+
+	var joined interface{ Unwrap() []error }
+	if errors.As(err, &joined) {
+		errs = joined.Unwrap()
+	}
+
+	findings := make([]Finding, 0, len(errs))
+	for _, err := range errs {
+		findings = append(findings, newFinding(DoctorCheckSelfTest, dir,
+			fmt.Sprintf(doctorMsgSelfTest, err), doctorAdviceSelfTest, LevelError))
+	}
+
+	return findings
+}
+
+// fileFindings checks one regular file of the directory.
+func fileFindings(options *DoctorOptions, entry fs.DirEntry, now time.Time) []Finding {
+	if !entry.Type().IsRegular() {
+		return nil
+	}
+
+	path := filepath.Join(options.Dir, entry.Name())
+
+	info, err := entry.Info()
+	if err != nil {
+		return nil
+	}
+
+	findings := permissionFindings(path, info, false)
+
+	skew := cmp.Or(options.ClockSkew, DefaultDoctorClockSkew)
+	if ahead := info.ModTime().Sub(now); ahead > skew {
+		findings = append(findings, newFinding(DoctorCheckClock, path,
+			fmt.Sprintf(doctorMsgFutureFmt, ahead.Round(time.Second)), doctorAdviceFuture, LevelWarn))
+	}
+
+	maxBytes := options.MaxFileBytes
+	if maxBytes <= 0 {
+		maxBytes = DefaultDoctorMaxFileBytes
+	}
+
+	if _, isBackup := backupIndex(entry.Name()); !isBackup && info.Size() > maxBytes {
+		findings = append(findings, newFinding(DoctorCheckRotation, path,
+			fmt.Sprintf(doctorMsgLargeFmt, info.Size()), doctorAdviceLarge, LevelWarn))
+	}
+
+	return append(findings, lockFindings(options, path, info, now)...)
+}
+
+// permissionFindings checks the mode and owner of path, and that files are
+// writable by this process.
+func permissionFindings(path string, info fs.FileInfo, isDir bool) []Finding {
+	var findings []Finding
+
+	perm := info.Mode().Perm()
+
+	switch {
+	case isDir && perm&doctorWorldWritable != 0:
+		findings = append(findings, newFinding(DoctorCheckPermissions, path,
+			doctorMsgDirWritable, doctorAdviceChmodDir, LevelWarn))
+	case !isDir && perm&doctorWorldWritable != 0:
+		findings = append(findings, newFinding(DoctorCheckPermissions, path,
+			doctorMsgFileWritable, doctorAdviceChmodFile, LevelWarn))
+	case !isDir && perm&doctorWorldReadable != 0:
+		findings = append(findings, newFinding(DoctorCheckPermissions, path,
+			doctorMsgFileReadable, doctorAdviceChmodFile, LevelWarn))
+	}
+
+	owner, ok := fileOwner(info)
+	if current := os.Geteuid(); ok && current > 0 && owner != current {
+		findings = append(findings, newFinding(DoctorCheckPermissions, path,
+			fmt.Sprintf(doctorMsgOwnerFmt, owner, current), doctorAdviceChown, LevelWarn))
+	}
+
+	if isDir {
+		return findings
+	}
+
+	// #nosec G304 -- the file is in the directory under diagnosis.
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return append(findings, newFinding(DoctorCheckPermissions, path,
+			fmt.Sprintf(doctorMsgNotWritable, err), doctorAdviceWritable, LevelError))
+	}
+
+	_ = file.Close()
+
+	return findings
+}
+
+// lockFindings reports PID files of processes that are gone and lock files
+// older than the stale lock age.
+func lockFindings(options *DoctorOptions, path string, info fs.FileInfo, now time.Time) []Finding {
+	name := info.Name()
+	if !strings.HasSuffix(name, doctorPIDSuffix) && !strings.HasSuffix(name, doctorLockSuffix) {
+		return nil
+	}
+
+	// #nosec G304 -- the file is in the directory under diagnosis.
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return []Finding{newFinding(DoctorCheckLocks, path,
+			fmt.Sprintf(doctorErrUnreadableFmt, err), doctorAdviceReadLock, LevelWarn)}
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(content)))
+	if err == nil && pid > 0 {
+		if processAlive(pid) {
+			return nil
+		}
+
+		return []Finding{newFinding(DoctorCheckLocks, path,
+			fmt.Sprintf(doctorMsgDeadPIDFmt, pid), doctorAdviceStaleLock, LevelWarn)}
+	}
+
+	age := now.Sub(info.ModTime())
+	if age > cmp.Or(options.StaleLockAge, DefaultDoctorStaleLockAge) {
+		return []Finding{newFinding(DoctorCheckLocks, path,
+			fmt.Sprintf(doctorMsgOldLockFmt, age.Round(time.Minute)), doctorAdviceStaleLock, LevelWarn)}
+	}
+
+	return nil
+}
+
+// backupFindings reports gaps in the numbered backups name.1, name.2, ...
+// left by rotation, which mean a rotation was interrupted.
+func backupFindings(dir string, entries []fs.DirEntry) []Finding {
+	backups := make(map[string][]int)
+
+	for _, entry := range entries {
+		index, ok := backupIndex(entry.Name())
+		if ok {
+			base := strings.TrimSuffix(entry.Name(), rotateBackupSeparator+strconv.Itoa(index))
+			backups[base] = append(backups[base], index)
+		}
+	}
+
+	var findings []Finding
+
+	for _, base := range slices.Sorted(maps.Keys(backups)) {
+		indexes := backups[base]
+		slices.Sort(indexes)
+
+		previous := 0
+		for _, index := range indexes {
+			if index > previous+1 {
+				findings = append(findings, newFinding(DoctorCheckRotation, filepath.Join(dir, base),
+					fmt.Sprintf(doctorMsgBackupGap, backupName(base, previous+1),
+						backupName(base, previous), backupName(base, index)),
+					doctorAdviceBackupGap, LevelWarn))
+			}
+
+			previous = index
+		}
+	}
+
+	return findings
+}
+
+// backupIndex returns N for a rotated backup named base.N.
+func backupIndex(name string) (int, bool) {
+	separator := strings.LastIndex(name, rotateBackupSeparator)
+	if separator <= 0 {
+		return 0, false
+	}
+
+	index, err := strconv.ParseUint(name[separator+1:], doctorBackupBase, strconv.IntSize-1)
+	if err != nil || index == 0 {
+		return 0, false
+	}
+
+	return int(index), true
+}
+
+func backupName(base string, index int) string {
+	if index == 0 {
+		return base
+	}
+
+	return base + rotateBackupSeparator + strconv.Itoa(index)
+}
@@ -0,0 +1,16 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package logger
+
+import "io/fs"
+
+// fileOwner reports that file owners are not available on this platform.
+func fileOwner(fs.FileInfo) (int, bool) {
+	return 0, false
+}
+
+// processAlive assumes that every process is alive where it cannot be
+// checked, so that no lock file is reported as stale.
+func processAlive(int) bool {
+	return true
+}
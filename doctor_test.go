@@ -0,0 +1,132 @@
+package logger_test
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	doctorLogFile     = "app.log"
+	doctorFutureFile  = "future.log"
+	doctorOpenFile    = "open.log"
+	doctorStalePID    = "worker.pid"
+	doctorLivePID     = "daemon.pid"
+	doctorDeadPID     = "999999999"
+	doctorMissingDir  = "missing"
+	doctorFutureDelay = time.Hour
+	doctorWriteFmt    = "write %s: %v"
+	doctorExpectFmt   = "expected a %s finding for %s, got:\n%s"
+	doctorUnexpectFmt = "unexpected finding for %s:\n%s"
+	doctorMissingFmt  = "expected one ERROR finding for a missing directory, got:\n%s"
+)
+
+func writeDoctorFile(t *testing.T, dir, name, content string, perm os.FileMode) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+
+	err := os.WriteFile(path, []byte(content), perm)
+	if err == nil {
+		err = os.Chmod(path, perm)
+	}
+
+	if err != nil {
+		t.Fatalf(doctorWriteFmt, name, err)
+	}
+
+	return path
+}
+
+func renderFindings(findings []logger.Finding) string {
+	var builder strings.Builder
+
+	for _, finding := range findings {
+		builder.WriteString(finding.String())
+		builder.WriteByte('\n')
+	}
+
+	return builder.String()
+}
+
+func hasFinding(findings []logger.Finding, check, name string) bool {
+	for _, finding := range findings {
+		if finding.Check == check && filepath.Base(finding.Path) == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestDiagnose_ReportsProblems(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	loggerInstance, err := logger.New(dir, doctorLogFile, logger.WithoutStdout())
+	if err != nil {
+		t.Fatalf(newLoggerWithDirErrFmt, err)
+	}
+	defer closeOutputsLogger(t, loggerInstance)
+
+	writeDoctorFile(t, dir, doctorLogFile+".1", "", 0o600)
+	writeDoctorFile(t, dir, doctorLogFile+".3", "", 0o600)
+	writeDoctorFile(t, dir, doctorOpenFile, "", 0o644)
+	writeDoctorFile(t, dir, doctorStalePID, doctorDeadPID, 0o600)
+	writeDoctorFile(t, dir, doctorLivePID, strconv.Itoa(os.Getpid()), 0o600)
+
+	future := time.Now().Add(doctorFutureDelay)
+
+	err = os.Chtimes(writeDoctorFile(t, dir, doctorFutureFile, "", 0o600), future, future)
+	if err != nil {
+		t.Fatalf(doctorWriteFmt, doctorFutureFile, err)
+	}
+
+	findings := logger.Diagnose(logger.DoctorOptions{
+		Logger:       loggerInstance,
+		Dir:          dir,
+		MaxFileBytes: 0,
+		ClockSkew:    0,
+		StaleLockAge: 0,
+	})
+	rendered := renderFindings(findings)
+
+	expected := map[string]string{
+		doctorLogFile:    logger.DoctorCheckRotation,
+		doctorOpenFile:   logger.DoctorCheckPermissions,
+		doctorStalePID:   logger.DoctorCheckLocks,
+		doctorFutureFile: logger.DoctorCheckClock,
+	}
+	for name, check := range expected {
+		if !hasFinding(findings, check, name) {
+			t.Errorf(doctorExpectFmt, check, name, rendered)
+		}
+	}
+
+	if hasFinding(findings, logger.DoctorCheckLocks, doctorLivePID) ||
+		hasFinding(findings, logger.DoctorCheckSelfTest, filepath.Base(dir)) {
+		t.Errorf(doctorUnexpectFmt, doctorLivePID, rendered)
+	}
+}
+
+func TestDiagnose_MissingDirectory(t *testing.T) {
+	t.Parallel()
+
+	findings := logger.Diagnose(logger.DoctorOptions{
+		Logger:       nil,
+		Dir:          filepath.Join(t.TempDir(), doctorMissingDir),
+		MaxFileBytes: 0,
+		ClockSkew:    0,
+		StaleLockAge: 0,
+	})
+
+	if len(findings) != 1 || findings[0].Level != logger.LevelError {
+		t.Errorf(doctorMissingFmt, renderFindings(findings))
+	}
+}
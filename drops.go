@@ -0,0 +1,97 @@
+package logger
+
+import (
+	"maps"
+	"slices"
+	"time"
+)
+
+const (
+	// DefaultDropSummaryInterval is the minimum time between two summaries of
+	// discarded entries.
+	DefaultDropSummaryInterval = 10 * time.Second
+
+	dropReasonWriteError = "write errors"
+	dropSummaryFormat    = "dropped %d entries in last %s due to %s"
+	dropFieldCount       = "dropped"
+	dropFieldReason      = "reason"
+)
+
+// dropTracker counts entries discarded since the last summary, by reason.
+type dropTracker struct {
+	windowStart time.Time
+	lastSummary time.Time
+	counts      map[string]int
+	interval    time.Duration
+}
+
+// WithDropSummaryInterval sets how often the logger may emit a WARN entry
+// summarizing discarded entries, e.g. "dropped 1372 entries in last 10s due to
+// queue overflow". Summaries are written ahead of the next entry once the
+// interval has elapsed, so operators know the log is incomplete.
+func WithDropSummaryInterval(interval time.Duration) Option {
+	return func(config *options) {
+		if interval > 0 {
+			config.dropSummaryInterval = interval
+		}
+	}
+}
+
+// recordDrop counts one entry discarded for reason. The caller holds l.mu.
+func (c *loggerCore) recordDrop(reason string) {
+	if c.drops.counts == nil {
+		c.drops.counts = make(map[string]int)
+	}
+
+	if len(c.drops.counts) == 0 {
+		c.drops.windowStart = time.Now()
+	}
+
+	c.drops.counts[reason]++
+}
+
+// noteDrop counts one entry discarded for reason outside the write path.
+func (c *loggerCore) noteDrop(reason string) {
+	c.mu.Lock()
+	c.recordDrop(reason)
+	c.mu.Unlock()
+}
+
+// flushDropSummary emits one WARN entry per drop reason when drops are
+// pending and the summary interval has elapsed. Counts whose summary could not
+// be written are kept for the next attempt. The caller holds l.mu.
+func (c *loggerCore) flushDropSummary(now time.Time) {
+	if len(c.drops.counts) == 0 || now.Sub(c.drops.lastSummary) < c.drops.interval {
+		return
+	}
+
+	window := now.Sub(c.drops.windowStart).Round(time.Second)
+	pending := c.drops.counts
+	c.drops.counts = make(map[string]int)
+
+	for _, reason := range slices.Sorted(maps.Keys(pending)) {
+		count := pending[reason]
+
+		err := c.emit(&Entry{
+			Time:       now,
+			Fields:     []Field{F(dropFieldCount, count), F(dropFieldReason, reason)},
+			Label:      c.label(LevelWarn),
+			Level:      LevelWarn,
+			Message:    c.safeFormat(dropSummaryFormat, count, window, reason),
+			Caller:     "",
+			Mandatory:  true,
+			timeText:   "",
+			elapsed:    0,
+			timeFormat: TimeFormatDefault,
+			ingested:   false,
+		}, targetAll)
+		if err != nil {
+			c.lastErr = err
+			c.drops.counts[reason] += count
+		}
+	}
+
+	if len(c.drops.counts) == 0 {
+		c.drops.lastSummary = now
+	}
+}
@@ -0,0 +1,58 @@
+package logger_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	dropsTemplate      = "[{level}] {msg} {fields}"
+	dropsFailures      = 2
+	dropsMsg           = "entry"
+	dropsSummaryPrefix = "[WARN] dropped 1 entries in last "
+	dropsSummarySuffix = " due to write errors dropped=1 reason=\"write errors\""
+	dropsInterval      = time.Hour
+	dropsSummaryErrFmt = "expected one drop summary, got:\n%s"
+)
+
+// flakyWriter fails its first failures writes and records the rest. With two
+// failures the first entry is dropped and the first summary attempt fails, so
+// the summary is written ahead of the third entry.
+type flakyWriter struct {
+	output   strings.Builder
+	failures int
+}
+
+func (w *flakyWriter) Write(p []byte) (int, error) {
+	if w.failures > 0 {
+		w.failures--
+
+		return 0, errTestWriteFailed
+	}
+
+	return w.output.Write(p)
+}
+
+func TestLogger_EmitsDropSummary(t *testing.T) {
+	t.Parallel()
+
+	writer := &flakyWriter{failures: dropsFailures}
+	loggerInstance := logger.NewStreamLogger(
+		writer,
+		logger.WithLayout(logger.MustParseLayout(dropsTemplate)),
+		logger.WithDropSummaryInterval(dropsInterval),
+	)
+
+	for range dropsFailures + 2 {
+		loggerInstance.Infof(dropsMsg)
+	}
+
+	output := writer.output.String()
+	if strings.Count(output, dropsSummaryPrefix) != 1 ||
+		!strings.Contains(output, dropsSummarySuffix) {
+		t.Errorf(dropsSummaryErrFmt, output)
+	}
+}
@@ -0,0 +1,123 @@
+package logger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+const (
+	errUnknownEncodingMsg = "unknown encoding"
+	errFmtEncodingName    = "%w: %q"
+)
+
+// ErrUnknownEncoding is returned by ParseEncoding for unknown names.
+var ErrUnknownEncoding = errors.New(errUnknownEncodingMsg)
+
+// Encoding selects how entries are rendered into lines.
+type Encoding int
+
+// Supported encodings.
+const (
+	// EncodingText renders entries with the layout template.
+	EncodingText Encoding = iota
+	// EncodingJSON renders one JSON object per line following the versioned
+	// JSONEntry schema.
+	EncodingJSON
+	// EncodingCEF renders ArcSight Common Event Format lines for SIEMs.
+	EncodingCEF
+	// EncodingLEEF renders IBM QRadar LEEF 1.0 lines for SIEMs.
+	EncodingLEEF
+	// EncodingSyslog renders RFC 5424 messages with fields as
+	// STRUCTURED-DATA.
+	EncodingSyslog
+)
+
+// WithEncoding selects the encoding of stdout and the log file. Routes use
+// their own Encoding, so that a SIEM or syslog route can receive CEF, LEEF or
+// RFC 5424 lines while the log file stays readable. Terminal decoration only applies to
+// text output.
+func WithEncoding(encoding Encoding) Option {
+	return func(config *options) {
+		config.encoding = encoding
+	}
+}
+
+// ParseEncoding returns the Encoding named by EncoderText, EncoderJSON,
+// EncoderCEF, EncoderLEEF or EncoderSyslog, the names used by configuration
+// files; the empty name is text.
+func ParseEncoding(name string) (Encoding, error) {
+	encoding, ok := parseEncoder(name)
+	if !ok {
+		return EncodingText, fmt.Errorf(errFmtEncodingName, ErrUnknownEncoding, name)
+	}
+
+	return encoding, nil
+}
+
+// encodeEntry renders logEntry with encoding, using layout for text.
+func encodeEntry(logEntry *Entry, encoding Encoding, layout *Layout) string {
+	switch encoding {
+	case EncodingJSON:
+		return encodeJSON(logEntry)
+	case EncodingCEF:
+		return encodeCEF(logEntry)
+	case EncodingLEEF:
+		return encodeLEEF(logEntry)
+	case EncodingSyslog:
+		return encodeSyslog(logEntry)
+	default:
+		return layout.render(logEntry)
+	}
+}
+
+// encodeJSON renders logEntry as a JSONEntry. Field values that cannot be
+// marshaled are rendered with fmt.Sprint so that an entry is never lost.
+func encodeJSON(logEntry *Entry) string {
+	jsonEntry := newJSONEntry(logEntry, jsonFieldValue)
+
+	data, err := json.Marshal(jsonEntry)
+	if err != nil {
+		data, err = json.Marshal(newJSONEntry(logEntry, func(value any) any {
+			return fmt.Sprint(value)
+		}))
+		if err != nil {
+			return ""
+		}
+	}
+
+	return string(data)
+}
+
+func newJSONEntry(logEntry *Entry, convert func(any) any) *JSONEntry {
+	var fields map[string]any
+	if len(logEntry.Fields) > 0 {
+		fields = make(map[string]any, len(logEntry.Fields))
+		for _, field := range logEntry.Fields {
+			fields[field.Key] = convert(field.Value)
+		}
+	}
+
+	epoch, epochUnit := logEntry.timeFormat.epoch(logEntry.Time)
+
+	return &JSONEntry{
+		Timestamp:     logEntry.Time,
+		Fields:        fields,
+		Level:         logEntry.Label,
+		Message:       logEntry.Message,
+		Caller:        logEntry.Caller,
+		EpochUnit:     epochUnit,
+		SchemaVersion: SchemaVersion,
+		Epoch:         epoch,
+	}
+}
+
+// jsonFieldValue renders errors as their message; encoding/json would
+// otherwise emit most error values as an empty object.
+func jsonFieldValue(value any) any {
+	if err, ok := value.(error); ok {
+		return err.Error()
+	}
+
+	return value
+}
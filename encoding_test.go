@@ -0,0 +1,124 @@
+package logger_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	encodingMsgStarted  = "started %s"
+	encodingMsgArg      = "worker"
+	encodingMsgExpected = "started worker"
+	encodingFieldKey    = "job"
+	encodingFieldValue  = "ingest"
+	encodingErrKey      = "error"
+	encodingCallerFile  = "encoding_test.go:"
+	encodingRouteMsg    = "routed"
+	decodeJSONErrFmt    = "decode %q: %v"
+	encodingValueErrFmt = "%s: expected %v, got %v"
+	encodingTextErrFmt  = "expected a text line on stdout, got %q"
+	encodingNoTimeMsg   = "expected a timestamp"
+	encodingCallerName  = "caller"
+	encodingMessageName = "message"
+	encodingUnknownName = "xml"
+	encodingParseErrFmt = "ParseEncoding(%q) = %v, %v; want %v"
+)
+
+var errEncodingTest = errors.New("disk full")
+
+func decodeJSONEntry(t *testing.T, line string) logger.JSONEntry {
+	t.Helper()
+
+	var jsonEntry logger.JSONEntry
+
+	err := json.Unmarshal([]byte(line), &jsonEntry)
+	if err != nil {
+		t.Fatalf(decodeJSONErrFmt, line, err)
+	}
+
+	return jsonEntry
+}
+
+func TestEncoding_JSONEntry(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	loggerInstance := logger.NewStreamLogger(&buf,
+		logger.WithEncoding(logger.EncodingJSON), logger.WithDecoratedStdout())
+	loggerInstance.With(logger.F(encodingFieldKey, encodingFieldValue),
+		logger.F(encodingErrKey, errEncodingTest)).Warnf(encodingMsgStarted, encodingMsgArg)
+
+	jsonEntry := decodeJSONEntry(t, strings.TrimSpace(buf.String()))
+
+	checks := map[string][2]any{
+		"schema_version":    {logger.SchemaVersion, jsonEntry.SchemaVersion},
+		"level":             {logger.LevelWarn.String(), jsonEntry.Level},
+		encodingMessageName: {encodingMsgExpected, jsonEntry.Message},
+		encodingFieldKey:    {encodingFieldValue, jsonEntry.Fields[encodingFieldKey]},
+		encodingErrKey:      {errEncodingTest.Error(), jsonEntry.Fields[encodingErrKey]},
+	}
+	for name, check := range checks {
+		if check[0] != check[1] {
+			t.Errorf(encodingValueErrFmt, name, check[0], check[1])
+		}
+	}
+
+	if !strings.HasPrefix(jsonEntry.Caller, encodingCallerFile) {
+		t.Errorf(encodingValueErrFmt, encodingCallerName, encodingCallerFile, jsonEntry.Caller)
+	}
+
+	if jsonEntry.Timestamp.IsZero() {
+		t.Error(encodingNoTimeMsg)
+	}
+}
+
+func TestEncoding_RouteUsesOwnEncoding(t *testing.T) {
+	t.Parallel()
+
+	var buf, routed bytes.Buffer
+
+	loggerInstance := logger.NewStreamLogger(&buf, logger.WithRoute(logger.Route{
+		Writer:   &routed,
+		Encoding: logger.EncodingJSON,
+	}))
+	loggerInstance.Infof(encodingRouteMsg)
+
+	if strings.HasPrefix(buf.String(), "{") {
+		t.Errorf(encodingTextErrFmt, buf.String())
+	}
+
+	jsonEntry := decodeJSONEntry(t, strings.TrimSpace(routed.String()))
+	if jsonEntry.Message != encodingRouteMsg {
+		t.Errorf(encodingValueErrFmt, encodingMessageName, encodingRouteMsg, jsonEntry.Message)
+	}
+}
+
+func TestParseEncoding(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]logger.Encoding{
+		logger.EncoderText:   logger.EncodingText,
+		logger.EncoderJSON:   logger.EncodingJSON,
+		logger.EncoderCEF:    logger.EncodingCEF,
+		logger.EncoderLEEF:   logger.EncodingLEEF,
+		logger.EncoderSyslog: logger.EncodingSyslog,
+	}
+
+	for name, want := range tests {
+		encoding, err := logger.ParseEncoding(name)
+		if err != nil || encoding != want {
+			t.Errorf(encodingParseErrFmt, name, encoding, err, want)
+		}
+	}
+
+	_, err := logger.ParseEncoding(encodingUnknownName)
+	if !errors.Is(err, logger.ErrUnknownEncoding) {
+		t.Errorf(encodingParseErrFmt, encodingUnknownName, logger.EncodingText, err, logger.ErrUnknownEncoding)
+	}
+}
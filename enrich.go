@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"cmp"
+	"slices"
+)
+
+// dynamicField is a field whose value is computed for every entry.
+type dynamicField struct {
+	value func() any
+	key   string
+}
+
+// WithGlobalFields adds fields to every entry, such as the service name or git
+// SHA, ahead of the entry's own fields and sorted by key. Global fields are
+// added by the first stage of the entry pipeline, so WithEntryMiddleware
+// stages see them.
+func WithGlobalFields(fields map[string]any) Option {
+	return func(config *options) {
+		for key, value := range fields {
+			config.globalFields = append(config.globalFields, F(key, value))
+		}
+
+		slices.SortStableFunc(config.globalFields, func(a, b Field) int {
+			return cmp.Compare(a.Key, b.Key)
+		})
+	}
+}
+
+// WithDynamicField adds a field to every entry whose value is computed by
+// value when the entry is logged, for example the number of requests in
+// flight. Dynamic fields follow the global fields, in the order they were
+// added. value runs on the logging goroutine and must be safe for concurrent
+// use.
+func WithDynamicField(key string, value func() any) Option {
+	return func(config *options) {
+		config.dynamicFields = append(config.dynamicFields, dynamicField{value: value, key: key})
+	}
+}
+
+// entryMiddleware returns the entry pipeline: the enrichment stage, when
+// global or dynamic fields are configured, followed by the user's stages.
+func (config *options) entryMiddleware() []EntryMiddleware {
+	if len(config.globalFields) == 0 && len(config.dynamicFields) == 0 {
+		return config.middleware
+	}
+
+	global := slices.Clip(config.globalFields)
+	dynamic := config.dynamicFields
+
+	enrich := func(logEntry Entry) (Entry, bool) {
+		fields := make([]Field, 0, len(global)+len(dynamic)+len(logEntry.Fields))
+		fields = append(fields, global...)
+
+		for _, field := range dynamic {
+			fields = append(fields, F(field.key, field.value()))
+		}
+
+		logEntry.Fields = append(fields, logEntry.Fields...)
+
+		return logEntry, true
+	}
+
+	return append([]EntryMiddleware{enrich}, config.middleware...)
+}
@@ -0,0 +1,43 @@
+package logger_test
+
+import (
+	"bytes"
+	"sync/atomic"
+	"testing"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	enrichLayout   = "{msg} {fields}"
+	enrichMsg      = "served"
+	enrichService  = "service"
+	enrichSHA      = "git_sha"
+	enrichInFlight = "in_flight"
+	enrichUser     = "user"
+	enrichExpected = "served git_sha=abc123 service=books in_flight=1 user=alice\n" +
+		"served git_sha=abc123 service=books in_flight=2\n"
+	enrichOutputFmt = "expected:\n%s\ngot:\n%s"
+)
+
+func TestWithGlobalAndDynamicFields(t *testing.T) {
+	t.Parallel()
+
+	var (
+		buf      bytes.Buffer
+		requests atomic.Int64
+	)
+
+	loggerInstance := logger.NewStreamLogger(&buf,
+		logger.WithLayout(logger.MustParseLayout(enrichLayout)),
+		logger.WithGlobalFields(map[string]any{enrichService: "books", enrichSHA: "abc123"}),
+		logger.WithDynamicField(enrichInFlight, func() any { return requests.Add(1) }),
+	)
+
+	loggerInstance.With(logger.F(enrichUser, "alice")).Infof(enrichMsg)
+	loggerInstance.Infof(enrichMsg)
+
+	if buf.String() != enrichExpected {
+		t.Errorf(enrichOutputFmt, enrichExpected, buf.String())
+	}
+}
@@ -0,0 +1,82 @@
+package logger
+
+import (
+	"os"
+	"path"
+	"runtime"
+	"runtime/debug"
+	"slices"
+	"strings"
+)
+
+const (
+	// EnvironmentFieldPrefix starts the field of every environment variable
+	// logged by LogEnvironment.
+	EnvironmentFieldPrefix = "env."
+
+	environmentMsg      = "environment snapshot"
+	environmentSep      = "="
+	goVersionField      = "go_version"
+	osField             = "os"
+	archField           = "arch"
+	cpusField           = "cpus"
+	pidField            = "pid"
+	hostnameField       = "hostname"
+	versionField        = "version"
+	develBuildVersion   = "(devel)"
+	environmentFieldCap = 8
+)
+
+// LogEnvironment records the configuration of this run as a SYSTEM entry,
+// typically once at startup: the Go version, platform, CPU count, process ID,
+// hostname and main module version, and every environment variable as an
+// env.<NAME> field. Only the variables matching a pattern of allowlist, such
+// as "LOGGER_*" or "OCR_MODEL" in the syntax of path.Match, keep their value;
+// the others are logged as RedactedText, so that secrets never reach the log
+// while the set of variables stays visible.
+func (l *Logger) LogEnvironment(allowlist ...string) {
+	l.writef(LevelSystem, environmentFields(os.Environ(), allowlist), environmentMsg)
+}
+
+// environmentFields returns the runtime fields followed by the fields of
+// environ, sorted by name.
+func environmentFields(environ, allowlist []string) []Field {
+	hostname, _ := os.Hostname()
+
+	fields := make([]Field, 0, environmentFieldCap+len(environ))
+	fields = append(fields,
+		F(goVersionField, runtime.Version()),
+		F(osField, runtime.GOOS),
+		F(archField, runtime.GOARCH),
+		F(cpusField, runtime.NumCPU()),
+		F(pidField, os.Getpid()),
+		F(hostnameField, hostname),
+	)
+
+	info, ok := debug.ReadBuildInfo()
+	if ok && info.Main.Version != "" && info.Main.Version != develBuildVersion {
+		fields = append(fields, F(versionField, info.Main.Version))
+	}
+
+	variables := slices.Sorted(slices.Values(environ))
+	for _, variable := range variables {
+		name, value, _ := strings.Cut(variable, environmentSep)
+		if !allowlisted(name, allowlist) {
+			value = RedactedText
+		}
+
+		fields = append(fields, F(EnvironmentFieldPrefix+name, value))
+	}
+
+	return fields
+}
+
+// allowlisted reports whether name matches a pattern of allowlist. Malformed
+// patterns match nothing.
+func allowlisted(name string, allowlist []string) bool {
+	return slices.ContainsFunc(allowlist, func(pattern string) bool {
+		matched, err := path.Match(pattern, name)
+
+		return err == nil && matched
+	})
+}
@@ -0,0 +1,61 @@
+package logger_test
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/book-expert/logger"
+	"github.com/book-expert/logger/loggertest"
+)
+
+const (
+	envAllowedName  = "LOGGERTEST_OCR_MODEL"
+	envAllowedValue = "tesseract-5"
+	envSecretName   = "LOGGERTEST_API_TOKEN"
+	envSecretValue  = "s3cr3t"
+	envExactName    = "LOGGERTEST_EXACT"
+	envExactValue   = "exact"
+	envPattern      = "LOGGERTEST_OCR_*"
+	envBadPattern   = "["
+	envEntriesFmt   = "entries = %d; want 1"
+	envLevelFmt     = "level = %v; want %v"
+	envFieldFmt     = "field %s = %v; want %v"
+	envGoField      = "go_version"
+)
+
+// The test below sets environment variables and therefore does not run in
+// parallel.
+
+func TestLogger_LogEnvironment(t *testing.T) {
+	t.Setenv(envAllowedName, envAllowedValue)
+	t.Setenv(envSecretName, envSecretValue)
+	t.Setenv(envExactName, envExactValue)
+
+	loggerInstance, observer := loggertest.NewObservedLogger(t)
+	loggerInstance.LogEnvironment(envPattern, envExactName, envBadPattern)
+
+	entries := observer.Entries()
+	if len(entries) != 1 {
+		t.Fatalf(envEntriesFmt, len(entries))
+	}
+
+	if entries[0].Level != logger.LevelSystem {
+		t.Errorf(envLevelFmt, entries[0].Level, logger.LevelSystem)
+	}
+
+	fields := make(map[string]any, len(entries[0].Fields))
+	for _, field := range entries[0].Fields {
+		fields[field.Key] = field.Value
+	}
+
+	for key, want := range map[string]any{
+		logger.EnvironmentFieldPrefix + envAllowedName: envAllowedValue,
+		logger.EnvironmentFieldPrefix + envExactName:   envExactValue,
+		logger.EnvironmentFieldPrefix + envSecretName:  logger.RedactedText,
+		envGoField: runtime.Version(),
+	} {
+		if fields[key] != want {
+			t.Errorf(envFieldFmt, key, fields[key], want)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package logger
+
+import "time"
+
+const (
+	errorBudgetFormat       = "route %s disabled for %s after %d write errors within %s"
+	errorBudgetFieldRoute   = "route"
+	errorBudgetFieldUntil   = "disabled_until"
+	dropReasonRouteDisabled = "disabled routes"
+)
+
+// ErrorBudget disables a failing route for a cooldown instead of paying the
+// cost of a broken destination, such as a network timeout, on every entry.
+// Once Threshold writes to a route fail within Window, the route is skipped
+// for Cooldown and a SYSTEM entry reports it on stdout and the log file.
+// Entries skipped meanwhile are counted in the dropped entry summaries. After
+// the cooldown the route is tried again with a fresh budget.
+type ErrorBudget struct {
+	Window    time.Duration
+	Cooldown  time.Duration
+	Threshold int
+}
+
+// errorBudgetState tracks the recent write errors of one route.
+type errorBudgetState struct {
+	disabledUntil time.Time
+	failures      []time.Time
+}
+
+// WithErrorBudget applies budget to every route. Budgets with a non-positive
+// Threshold, Window or Cooldown are ignored. The logger's stdout and log file
+// are never disabled.
+func WithErrorBudget(budget ErrorBudget) Option {
+	return func(config *options) {
+		if budget.Threshold > 0 && budget.Window > 0 && budget.Cooldown > 0 {
+			config.errorBudget = budget
+		}
+	}
+}
+
+// routeDisabled reports whether target is cooling down after exhausting its
+// error budget. The caller holds c.mu.
+func (c *loggerCore) routeDisabled(target *routeTarget) bool {
+	if target.budget.disabledUntil.IsZero() {
+		return false
+	}
+
+	if time.Now().Before(target.budget.disabledUntil) {
+		return true
+	}
+
+	target.budget.disabledUntil = time.Time{}
+
+	return false
+}
+
+// spendErrorBudget records a failed write to target and disables the route
+// once the budget is exhausted. The caller holds c.mu.
+func (c *loggerCore) spendErrorBudget(target *routeTarget) {
+	budget := c.errorBudget
+	if budget.Threshold == 0 {
+		return
+	}
+
+	now := time.Now()
+	cutoff := now.Add(-budget.Window)
+
+	kept := target.budget.failures[:0]
+	for _, at := range target.budget.failures {
+		if at.After(cutoff) {
+			kept = append(kept, at)
+		}
+	}
+
+	target.budget.failures = append(kept, now)
+	if len(target.budget.failures) < budget.Threshold {
+		return
+	}
+
+	target.budget.failures = target.budget.failures[:0]
+	target.budget.disabledUntil = now.Add(budget.Cooldown)
+
+	err := c.emit(&Entry{
+		Time:       now,
+		Fields:     []Field{F(errorBudgetFieldRoute, target.name), F(errorBudgetFieldUntil, target.budget.disabledUntil)},
+		Label:      c.label(LevelSystem),
+		Level:      LevelSystem,
+		Message:    c.safeFormat(errorBudgetFormat, target.name, budget.Cooldown, budget.Threshold, budget.Window),
+		Caller:     "",
+		Mandatory:  true,
+		timeText:   "",
+		elapsed:    0,
+		timeFormat: TimeFormatDefault,
+		ingested:   false,
+	}, targetLocal)
+	if err != nil {
+		c.lastErr = err
+	}
+}
@@ -0,0 +1,61 @@
+package logger_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	budgetRouteName   = "collector"
+	budgetMessage     = "entry"
+	budgetDisabledMsg = "route collector disabled for 1h0m0s after 2 write errors within 1m0s"
+	budgetEntries     = 5
+	budgetThreshold   = 2
+	budgetWritesFmt   = "route writes = %d, want %d"
+	budgetNoticeFmt   = "want one %q notice; got:\n%s"
+)
+
+// countingFailWriter fails every write and counts the attempts.
+type countingFailWriter struct {
+	writes int
+}
+
+func (writer *countingFailWriter) Write([]byte) (int, error) {
+	writer.writes++
+
+	return 0, errTestWriteFailed
+}
+
+func TestWithErrorBudget_DisablesFailingRoute(t *testing.T) {
+	t.Parallel()
+
+	var (
+		stdout bytes.Buffer
+		route  countingFailWriter
+	)
+
+	loggerInstance := logger.NewStreamLogger(&stdout,
+		logger.WithRoute(logger.Route{Writer: &route, Name: budgetRouteName}),
+		logger.WithErrorBudget(logger.ErrorBudget{
+			Window:    time.Minute,
+			Cooldown:  time.Hour,
+			Threshold: budgetThreshold,
+		}),
+	)
+
+	for range budgetEntries {
+		loggerInstance.Infof(budgetMessage)
+	}
+
+	if route.writes != budgetThreshold {
+		t.Errorf(budgetWritesFmt, route.writes, budgetThreshold)
+	}
+
+	if strings.Count(stdout.String(), budgetDisabledMsg) != 1 {
+		t.Errorf(budgetNoticeFmt, budgetDisabledMsg, stdout.String())
+	}
+}
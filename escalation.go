@@ -0,0 +1,118 @@
+package logger
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	escalationSummaryFormat = "escalation: %d entries at %s or above within %s"
+	escalationFieldCount    = "escalation_count"
+	escalationFieldWindow   = "escalation_window"
+)
+
+// EscalationRule escalates bursts of entries into a single FATAL summary, for
+// example "10 or more ERROR entries within 60s". The rule counts entries at
+// Level or above; when Threshold entries fall within Window the logger writes
+// one FATAL summary entry, calls OnEscalate with the summary text and starts
+// counting afresh. OnEscalate runs outside the logger's lock, so it may log
+// or notify an external system such as a webhook.
+type EscalationRule struct {
+	OnEscalate func(summary string)
+	Window     time.Duration
+	Threshold  int
+	Level      Level
+}
+
+// escalation is the runtime state of one EscalationRule.
+type escalation struct {
+	rule  EscalationRule
+	times []time.Time
+}
+
+// WithEscalation adds an escalation rule evaluated for every entry. Rules with
+// a non-positive Threshold or Window are ignored.
+func WithEscalation(rule EscalationRule) Option {
+	return func(config *options) {
+		if rule.Threshold > 0 && rule.Window > 0 {
+			config.escalationRules = append(config.escalationRules, rule)
+		}
+	}
+}
+
+func newEscalations(rules []EscalationRule) []*escalation {
+	escalations := make([]*escalation, 0, len(rules))
+	for _, rule := range rules {
+		escalations = append(escalations, &escalation{rule: rule, times: nil})
+	}
+
+	return escalations
+}
+
+// evaluateEscalations records an entry of level at now, writes a FATAL summary
+// for every rule whose threshold is reached and returns the rules' callbacks.
+// Summaries do not count towards any rule. The caller holds l.mu.
+func (c *loggerCore) evaluateEscalations(level Level, now time.Time) []func() {
+	var hooks []func()
+
+	for _, state := range c.escalations {
+		if !state.record(level, now) {
+			continue
+		}
+
+		summary := fmt.Sprintf(escalationSummaryFormat,
+			state.rule.Threshold, c.label(state.rule.Level), state.rule.Window)
+
+		err := c.emit(&Entry{
+			Time: now,
+			Fields: []Field{
+				F(escalationFieldCount, state.rule.Threshold),
+				F(escalationFieldWindow, state.rule.Window),
+			},
+			Label:      c.label(LevelFatal),
+			Level:      LevelFatal,
+			Message:    summary,
+			Caller:     "",
+			Mandatory:  true,
+			timeText:   "",
+			elapsed:    0,
+			timeFormat: TimeFormatDefault,
+			ingested:   false,
+		}, targetAll)
+		if err != nil {
+			c.lastErr = err
+		}
+
+		if state.rule.OnEscalate != nil {
+			onEscalate := state.rule.OnEscalate
+			hooks = append(hooks, func() { onEscalate(summary) })
+		}
+	}
+
+	return hooks
+}
+
+// record adds an entry and reports whether the rule fired.
+func (state *escalation) record(level Level, now time.Time) bool {
+	if level < state.rule.Level {
+		return false
+	}
+
+	cutoff := now.Add(-state.rule.Window)
+
+	kept := state.times[:0]
+	for _, at := range state.times {
+		if at.After(cutoff) {
+			kept = append(kept, at)
+		}
+	}
+
+	state.times = append(kept, now)
+	if len(state.times) < state.rule.Threshold {
+		return false
+	}
+
+	state.times = state.times[:0]
+
+	return true
+}
@@ -0,0 +1,56 @@
+package logger_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	escalationTemplate  = "[{level}] {msg}"
+	escalationMsg       = "upload failed"
+	escalationThreshold = 3
+	escalationWindow    = time.Minute
+	escalationSummary   = "[FATAL] escalation: 3 entries at ERROR or above within 1m0s"
+	escalationCountErr  = "expected %d summaries and %d callbacks, got %d and %d:\n%s"
+)
+
+func TestLogger_EscalationRule(t *testing.T) {
+	t.Parallel()
+
+	var (
+		buf       bytes.Buffer
+		summaries []string
+	)
+
+	var loggerInstance *logger.Logger
+
+	loggerInstance = logger.NewStreamLogger(
+		&buf,
+		logger.WithLayout(logger.MustParseLayout(escalationTemplate)),
+		logger.WithEscalation(logger.EscalationRule{
+			OnEscalate: func(summary string) {
+				summaries = append(summaries, summary)
+				loggerInstance.Infof(summary)
+			},
+			Window:    escalationWindow,
+			Threshold: escalationThreshold,
+			Level:     logger.LevelError,
+		}),
+	)
+
+	for range escalationThreshold*2 + 1 {
+		loggerInstance.Errorf(escalationMsg)
+		loggerInstance.Warnf(escalationMsg)
+	}
+
+	output := buf.String()
+
+	fired := strings.Count(output, escalationSummary)
+	if fired != 2 || len(summaries) != 2 {
+		t.Errorf(escalationCountErr, 2, 2, fired, len(summaries), output)
+	}
+}
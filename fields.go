@@ -0,0 +1,56 @@
+package logger
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	fieldSeparator  = " "
+	fieldAssignment = "="
+	// fieldQuoteChars lists the characters that force a field value to be quoted
+	// so that text output remains unambiguous to parse.
+	fieldQuoteChars = " \t\n\"="
+)
+
+// Field is a key/value pair attached to a log entry. Fields are rendered after
+// the message as key=value pairs in text output.
+type Field struct {
+	Value any
+	Key   string
+}
+
+// F creates a Field with the given key and value.
+func F(key string, value any) Field {
+	return Field{Key: key, Value: value}
+}
+
+func writeFields(builder *strings.Builder, fields []Field) {
+	for _, field := range fields {
+		builder.WriteString(fieldSeparator)
+		builder.WriteString(field.Key)
+		builder.WriteString(fieldAssignment)
+		builder.WriteString(formatFieldValue(field.Value))
+	}
+}
+
+func formatFieldValue(value any) string {
+	text := fmt.Sprint(value)
+	if text == "" || strings.ContainsAny(text, fieldQuoteChars) {
+		return strconv.Quote(text)
+	}
+
+	return text
+}
+
+// With returns a child logger that adds fields to every entry. The child
+// shares its parent's outputs, options and state; fields of the parent come
+// first.
+func (l *Logger) With(fields ...Field) *Logger {
+	return &Logger{
+		core:   l.core,
+		fields: append(slices.Clip(l.fields), fields...),
+	}
+}
@@ -0,0 +1,88 @@
+package logger
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	byteUnit       = 1024
+	byteUnits      = "KMGTPE"
+	byteSuffix     = "B"
+	byteIECInfix   = "i"
+	bytePrecision  = 1
+	byteTrimSuffix = ".0"
+	byteFormat     = 'f'
+	byteBitSize    = 64
+	fieldIntBase   = 10
+)
+
+// durationValue is the value of a Dur field.
+type durationValue time.Duration
+
+// byteSize is the value of a Bytes field.
+type byteSize int64
+
+// Dur returns a field holding the duration d. Text output renders it rounded
+// to a readable precision, such as 1.234s or 2m5s; JSON output holds the
+// exact duration in nanoseconds, like a time.Duration value.
+func Dur(key string, d time.Duration) Field {
+	return F(key, durationValue(d))
+}
+
+// Bytes returns a field holding a size of n bytes. Text output renders it in
+// binary units, such as 512B or 1.5MiB, unquoted; JSON output holds the number of
+// bytes.
+func Bytes(key string, n int64) Field {
+	return F(key, byteSize(n))
+}
+
+// String rounds the duration to milliseconds from one second, to seconds
+// from one minute and to microseconds from one millisecond.
+func (d durationValue) String() string {
+	duration := time.Duration(d)
+	magnitude := duration.Abs()
+
+	switch {
+	case magnitude >= time.Minute:
+		return duration.Round(time.Second).String()
+	case magnitude >= time.Second:
+		return duration.Round(time.Millisecond).String()
+	case magnitude >= time.Millisecond:
+		return duration.Round(time.Microsecond).String()
+	default:
+		return duration.String()
+	}
+}
+
+// MarshalJSON renders the duration in nanoseconds.
+func (d durationValue) MarshalJSON() ([]byte, error) {
+	return strconv.AppendInt(nil, int64(d), fieldIntBase), nil
+}
+
+// String renders the size in the largest binary unit it reaches, with one
+// decimal.
+func (n byteSize) String() string {
+	size := int64(n)
+	if size > -byteUnit && size < byteUnit {
+		return strconv.FormatInt(size, fieldIntBase) + byteSuffix
+	}
+
+	value := float64(size)
+	unit := 0
+
+	for value/byteUnit >= byteUnit || value/byteUnit <= -byteUnit {
+		value /= byteUnit
+		unit++
+	}
+
+	text := strconv.FormatFloat(value/byteUnit, byteFormat, bytePrecision, byteBitSize)
+
+	return strings.TrimSuffix(text, byteTrimSuffix) + byteUnits[unit:unit+1] + byteIECInfix + byteSuffix
+}
+
+// MarshalJSON renders the size in bytes.
+func (n byteSize) MarshalJSON() ([]byte, error) {
+	return strconv.AppendInt(nil, int64(n), fieldIntBase), nil
+}
@@ -0,0 +1,86 @@
+package logger_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	fieldTypesKey      = "value"
+	fieldTypesMsg      = "converted"
+	fieldTypesTextFmt  = "text output %q lacks %q"
+	fieldTypesJSONFmt  = "JSON field = %v (%v); want %v"
+	fieldTypesParseFmt = "parse %q: %v"
+)
+
+func TestFieldTypes_Text(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		field logger.Field
+		want  string
+	}{
+		{field: logger.Dur(fieldTypesKey, 1234567891*time.Nanosecond), want: "1.235s"},
+		{field: logger.Dur(fieldTypesKey, 125*time.Second+400*time.Millisecond), want: "2m5s"},
+		{field: logger.Dur(fieldTypesKey, 2500*time.Microsecond+7), want: "2.5ms"},
+		{field: logger.Dur(fieldTypesKey, 750*time.Nanosecond), want: "750ns"},
+		{field: logger.Bytes(fieldTypesKey, 512), want: "512B"},
+		{field: logger.Bytes(fieldTypesKey, 1536*1024), want: "1.5MiB"},
+		{field: logger.Bytes(fieldTypesKey, 2048), want: "2KiB"},
+		{field: logger.Bytes(fieldTypesKey, -3*1024*1024*1024), want: "-3GiB"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.want, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+
+			loggerInstance := logger.NewStreamLogger(&buf)
+			loggerInstance.With(testCase.field).Infof(fieldTypesMsg)
+			closeTestLogger(t, loggerInstance)
+
+			want := fieldTypesKey + "=" + testCase.want
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf(fieldTypesTextFmt, buf.String(), want)
+			}
+		})
+	}
+}
+
+func TestFieldTypes_JSON(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		field logger.Field
+		want  float64
+	}{
+		{field: logger.Dur(fieldTypesKey, 1234567891*time.Nanosecond), want: 1234567891},
+		{field: logger.Bytes(fieldTypesKey, 1536*1024), want: 1536 * 1024},
+	}
+
+	for _, testCase := range testCases {
+		var buf bytes.Buffer
+
+		loggerInstance := logger.NewStreamLogger(&buf, logger.WithEncoding(logger.EncodingJSON))
+		loggerInstance.With(testCase.field).Infof(fieldTypesMsg)
+		closeTestLogger(t, loggerInstance)
+
+		var entry logger.JSONEntry
+
+		err := json.Unmarshal(buf.Bytes(), &entry)
+		if err != nil {
+			t.Fatalf(fieldTypesParseFmt, buf.String(), err)
+		}
+
+		value, ok := entry.Fields[fieldTypesKey].(float64)
+		if !ok || value != testCase.want {
+			t.Errorf(fieldTypesJSONFmt, entry.Fields[fieldTypesKey], ok, testCase.want)
+		}
+	}
+}
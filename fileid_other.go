@@ -0,0 +1,11 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package logger
+
+import "os"
+
+// fileKey identifies the file behind info by its path where inodes are not
+// available; renamed files are then treated as new files.
+func fileKey(path string, _ os.FileInfo) string {
+	return path
+}
@@ -0,0 +1,23 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package logger
+
+import (
+	"os"
+	"strconv"
+	"syscall"
+)
+
+const fileKeySeparator = ":"
+
+// fileKey identifies the file behind info by device and inode, so that a file
+// keeps its key when rotation renames it.
+func fileKey(path string, info os.FileInfo) string {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return path
+	}
+
+	// Dev is int32 on some platforms.
+	return strconv.FormatUint(uint64(stat.Dev), 10) + fileKeySeparator + strconv.FormatUint(stat.Ino, 10)
+}
@@ -0,0 +1,8 @@
+//go:build !(linux || darwin || freebsd)
+
+package logger
+
+// freeBytes reports that free space cannot be determined on this platform.
+func freeBytes(string) (uint64, bool) {
+	return 0, false
+}
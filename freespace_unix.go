@@ -0,0 +1,19 @@
+//go:build linux || darwin || freebsd
+
+package logger
+
+import "syscall"
+
+// freeBytes returns the space available to unprivileged users in the file
+// system holding dir.
+func freeBytes(dir string) (uint64, bool) {
+	var stat syscall.Statfs_t
+
+	err := syscall.Statfs(dir, &stat)
+	if err != nil {
+		return 0, false
+	}
+
+	// The field types differ between platforms.
+	return uint64(stat.Bavail) * uint64(stat.Bsize), true
+}
@@ -0,0 +1,230 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	metricSyncHelp         = "# HELP logger_fsync_total Syncs of the log file by the fsync scheduler.\n"
+	metricSyncType         = "# TYPE logger_fsync_total counter\n"
+	metricSyncFmt          = "logger_fsync_total %d\n"
+	metricSyncFailuresHelp = "# HELP logger_fsync_failures_total Failed syncs of the log file.\n"
+	metricSyncFailuresType = "# TYPE logger_fsync_failures_total counter\n"
+	metricSyncFailuresFmt  = "logger_fsync_failures_total %d\n"
+	metricSyncPendingHelp  = "# HELP logger_fsync_pending_bytes Bytes written to the log file since its last sync.\n"
+	metricSyncPendingType  = "# TYPE logger_fsync_pending_bytes gauge\n"
+	metricSyncPendingFmt   = "logger_fsync_pending_bytes %d\n"
+)
+
+// SyncPolicy selects when the fsync scheduler flushes the log file to stable
+// storage: every Interval while entries were written since the last sync,
+// and as soon as Bytes bytes are waiting. Either may be zero to disable its
+// trigger; the zero SyncPolicy leaves syncing to the operating system, apart
+// from SECURITY entries, which are always synced before Securityf returns.
+type SyncPolicy struct {
+	Interval time.Duration
+	Bytes    int64
+}
+
+// SyncStats describes the fsync scheduler: its policy, the syncs done and
+// failed, the bytes written since the last sync and the time of that sync.
+type SyncStats struct {
+	LastSync     time.Time
+	Policy       SyncPolicy
+	Syncs        uint64
+	Failures     uint64
+	PendingBytes int64
+}
+
+// WithSyncPolicy syncs the log file in the background as policy selects,
+// rather than for every entry or never, bounding how much a crash can lose
+// while writes stay cheap. A failed sync is reported by Err, and Close syncs
+// what is left.
+func WithSyncPolicy(policy SyncPolicy) Option {
+	return func(config *options) {
+		config.syncPolicy = policy
+	}
+}
+
+// syncScheduler syncs the log file on its own goroutine. Its counters are
+// guarded by loggerCore.mu.
+type syncScheduler struct {
+	core     *loggerCore
+	wake     chan struct{}
+	quit     chan struct{}
+	done     chan struct{}
+	lastSync time.Time
+	policy   SyncPolicy
+	stopOnce sync.Once
+	pending  int64
+	syncs    uint64
+	failures uint64
+}
+
+// startSyncScheduler starts the scheduler when policy has a trigger.
+func (c *loggerCore) startSyncScheduler(policy SyncPolicy) {
+	if policy.Interval <= 0 && policy.Bytes <= 0 {
+		return
+	}
+
+	syncer := &syncScheduler{
+		core:     c,
+		wake:     make(chan struct{}, 1),
+		quit:     make(chan struct{}),
+		done:     make(chan struct{}),
+		lastSync: time.Time{},
+		policy:   policy,
+		stopOnce: sync.Once{},
+		pending:  0,
+		syncs:    0,
+		failures: 0,
+	}
+
+	c.mu.Lock()
+	c.syncer = syncer
+	c.mu.Unlock()
+
+	c.goTask(ProfileTaskSyncScheduler, syncer.run)
+}
+
+func (syncer *syncScheduler) run() {
+	defer close(syncer.done)
+
+	var tick <-chan time.Time
+
+	if syncer.policy.Interval > 0 {
+		ticker := time.NewTicker(syncer.policy.Interval)
+		defer ticker.Stop()
+
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-syncer.quit:
+			syncer.sync()
+
+			return
+		case <-syncer.wake:
+			syncer.sync()
+		case <-tick:
+			syncer.sync()
+		}
+	}
+}
+
+// wrote records n bytes written to the log file and wakes the scheduler once
+// the policy's byte limit is reached. A nil scheduler ignores the call. The
+// caller holds loggerCore.mu.
+func (syncer *syncScheduler) wrote(n int) {
+	if syncer == nil {
+		return
+	}
+
+	syncer.pending += int64(n)
+	if syncer.policy.Bytes > 0 && syncer.pending >= syncer.policy.Bytes {
+		select {
+		case syncer.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// synced records a sync done outside the scheduler, such as that of a
+// SECURITY entry. The caller holds loggerCore.mu.
+func (syncer *syncScheduler) synced() {
+	if syncer != nil {
+		syncer.pending = 0
+	}
+}
+
+// sync writes buffered lines and syncs the log file when bytes are pending.
+// The sync itself runs without loggerCore.mu, so that logging goes on
+// meanwhile.
+func (syncer *syncScheduler) sync() {
+	c := syncer.core
+
+	c.mu.Lock()
+
+	file := c.logFile
+	if file == nil || syncer.pending == 0 {
+		c.mu.Unlock()
+
+		return
+	}
+
+	err := c.flushLines()
+	syncer.pending = 0
+	c.mu.Unlock()
+
+	if err == nil {
+		err = file.Sync()
+		if err != nil {
+			err = fmt.Errorf(errFmtWrapCause, ErrWriteLogFile, fmt.Errorf(errFmtSyncLogFile, err))
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	syncer.lastSync = time.Now()
+
+	if err != nil {
+		syncer.failures++
+		c.lastErr = err
+
+		return
+	}
+
+	syncer.syncs++
+}
+
+// stop syncs what is pending and stops the scheduler. A nil scheduler
+// returns at once.
+func (syncer *syncScheduler) stop() {
+	if syncer == nil {
+		return
+	}
+
+	syncer.stopOnce.Do(func() { close(syncer.quit) })
+	<-syncer.done
+}
+
+// syncStats returns the state of the fsync scheduler, or the zero SyncStats
+// without one.
+func (c *loggerCore) syncStats() SyncStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	syncer := c.syncer
+	if syncer == nil {
+		return SyncStats{LastSync: time.Time{}, Policy: SyncPolicy{Interval: 0, Bytes: 0}, Syncs: 0, Failures: 0, PendingBytes: 0}
+	}
+
+	return SyncStats{
+		LastSync:     syncer.lastSync,
+		Policy:       syncer.policy,
+		Syncs:        syncer.syncs,
+		Failures:     syncer.failures,
+		PendingBytes: syncer.pending,
+	}
+}
+
+func writeSyncMetrics(builder *strings.Builder, stats *SyncStats) {
+	if stats.Policy == (SyncPolicy{Interval: 0, Bytes: 0}) {
+		return
+	}
+
+	builder.WriteString(metricSyncHelp)
+	builder.WriteString(metricSyncType)
+	fmt.Fprintf(builder, metricSyncFmt, stats.Syncs)
+	builder.WriteString(metricSyncFailuresHelp)
+	builder.WriteString(metricSyncFailuresType)
+	fmt.Fprintf(builder, metricSyncFailuresFmt, stats.Failures)
+	builder.WriteString(metricSyncPendingHelp)
+	builder.WriteString(metricSyncPendingType)
+	fmt.Fprintf(builder, metricSyncPendingFmt, stats.PendingBytes)
+}
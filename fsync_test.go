@@ -0,0 +1,103 @@
+package logger_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	syncFile          = "sync.log"
+	syncMsg           = "order %d stored"
+	syncEntries       = 3
+	syncInterval      = 5 * time.Millisecond
+	syncPoll          = time.Millisecond
+	syncDeadline      = 5 * time.Second
+	syncMetric        = "logger_fsync_total 1"
+	syncStatsErrFmt   = "expected %d syncs and no pending bytes, got %+v"
+	syncPendingErrFmt = "expected pending bytes without a sync, got %+v"
+)
+
+func newSyncLogger(t *testing.T, policy logger.SyncPolicy) *logger.Logger {
+	t.Helper()
+
+	loggerInstance, err := logger.New(t.TempDir(), syncFile, logger.WithoutStdout(), logger.WithSyncPolicy(policy))
+	if err != nil {
+		t.Fatalf(newLoggerWithDirErrFmt, err)
+	}
+
+	return loggerInstance
+}
+
+// waitForSync polls until the scheduler has synced, or fails after
+// syncDeadline.
+func waitForSync(t *testing.T, loggerInstance *logger.Logger) logger.SyncStats {
+	t.Helper()
+
+	deadline := time.Now().Add(syncDeadline)
+
+	for {
+		stats := loggerInstance.Stats().Sync
+		if stats.Syncs > 0 || time.Now().After(deadline) {
+			return stats
+		}
+
+		time.Sleep(syncPoll)
+	}
+}
+
+func TestSyncPolicy_Triggers(t *testing.T) {
+	t.Parallel()
+
+	policies := map[string]logger.SyncPolicy{
+		"bytes":    {Interval: 0, Bytes: 1},
+		"interval": {Interval: syncInterval, Bytes: 0},
+	}
+
+	for name, policy := range policies {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			loggerInstance := newSyncLogger(t, policy)
+			defer closeTestLogger(t, loggerInstance)
+
+			loggerInstance.Infof(syncMsg, 1)
+
+			stats := waitForSync(t, loggerInstance)
+			if stats.Syncs == 0 || stats.Failures != 0 || stats.Policy != policy || stats.LastSync.IsZero() {
+				t.Errorf(syncStatsErrFmt, 1, stats)
+			}
+		})
+	}
+}
+
+func TestSyncPolicy_CloseSyncsPending(t *testing.T) {
+	t.Parallel()
+
+	loggerInstance := newSyncLogger(t, logger.SyncPolicy{Interval: time.Hour, Bytes: 1 << 30})
+
+	for order := range syncEntries {
+		loggerInstance.Infof(syncMsg, order)
+	}
+
+	stats := loggerInstance.Stats().Sync
+	if stats.Syncs != 0 || stats.PendingBytes == 0 {
+		t.Errorf(syncPendingErrFmt, stats)
+	}
+
+	closeTestLogger(t, loggerInstance)
+
+	closed := loggerInstance.Stats()
+	if closed.Sync.Syncs != 1 || closed.Sync.PendingBytes != 0 {
+		t.Errorf(syncStatsErrFmt, 1, closed.Sync)
+	}
+
+	var metrics strings.Builder
+
+	_ = closed.WritePrometheus(&metrics)
+	if !strings.Contains(metrics.String(), syncMetric) {
+		t.Errorf(statsMetricsErrFmt, syncMetric, metrics.String())
+	}
+}
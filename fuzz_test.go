@@ -0,0 +1,114 @@
+package logger_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	fuzzSeedFormat    = "%s=%d %v%%"
+	fuzzPanicFormat   = "value %v"
+	fuzzSeedBadVerb   = "%!z %"
+	fuzzSeedArg       = "arg"
+	fuzzSeedAuditLine = "2025/01/02 15:04:05 [INFO] x audit_seq=1 audit_key=k audit_mac=00"
+	fuzzAuditKey      = "fuzz-key"
+	fuzzLayoutErrFmt  = "layout %q parsed but failed to render: %v"
+	fuzzVerifiedFmt   = "verified %d entries of %d lines"
+)
+
+// panicStringer panics with itself, so that printing the panic value panics
+// again and escapes fmt's own recovery.
+type panicStringer struct{}
+
+func (p panicStringer) String() string {
+	panic(p)
+}
+
+func TestSafeSprintf_RecoversNestedPanics(t *testing.T) {
+	t.Parallel()
+
+	result := logger.SafeSprintf(fuzzPanicFormat, panicStringer{})
+	if !strings.Contains(result, fuzzPanicFormat) {
+		t.Errorf(layoutOutputErrFmt, fuzzPanicFormat, result)
+	}
+
+	var buf strings.Builder
+
+	loggerInstance := logger.NewStreamLogger(&buf)
+	loggerInstance.Infof(fuzzPanicFormat, panicStringer{})
+
+	if !strings.Contains(buf.String(), fuzzPanicFormat) {
+		t.Errorf(layoutOutputErrFmt, fuzzPanicFormat, buf.String())
+	}
+}
+
+func FuzzSafeSprintf(f *testing.F) {
+	f.Add(fuzzSeedFormat, fuzzSeedArg, 1)
+	f.Add(fuzzSeedBadVerb, "", 0)
+	f.Add("100%", "", -1)
+
+	f.Fuzz(func(t *testing.T, format, text string, number int) {
+		// Arguments are passed as slices because the formats are not constant.
+		logger.SafeSprintf(format, []any{}...)
+		logger.SafeSprintf(format, []any{text, number, panicStringer{}, nil}...)
+
+		loggerInstance := logger.NewStreamLogger(io.Discard)
+		loggerInstance.Infof(format, []any{text, number}...)
+	})
+}
+
+func FuzzParseLayout(f *testing.F) {
+	f.Add(logger.DefaultLayout)
+	f.Add("{level:<7}|{level:>7}|{level:^7}|{{{msg}}}")
+	f.Add("{caller:^0} {")
+
+	f.Fuzz(func(t *testing.T, template string) {
+		layout, err := logger.ParseLayout(template)
+		if err != nil {
+			return
+		}
+
+		var buf strings.Builder
+
+		loggerInstance := logger.NewStreamLogger(&buf, logger.WithLayout(layout))
+		loggerInstance.With(logger.F(fuzzSeedArg, template)).Warnf(template, []any{}...)
+
+		_, err = loggerInstance.EntrySchema()
+		if err != nil {
+			t.Errorf(fuzzLayoutErrFmt, template, err)
+		}
+	})
+}
+
+func FuzzParseLevel(f *testing.F) {
+	f.Add("info")
+	f.Add("WaRn")
+	f.Add("")
+
+	f.Fuzz(func(t *testing.T, name string) {
+		level, err := logger.ParseLevel(name)
+		if err != nil {
+			return
+		}
+
+		_ = level.String()
+	})
+}
+
+func FuzzVerifyAuditLog(f *testing.F) {
+	f.Add(fuzzSeedAuditLine)
+	f.Add("audit_seq= audit_mac=")
+	f.Add(" audit_seq=18446744073709551615 audit_mac=\n\n")
+
+	f.Fuzz(func(t *testing.T, content string) {
+		lines := strings.Count(content, "\n") + 1
+
+		verified, err := logger.VerifyAuditLog(strings.NewReader(content), []byte(fuzzAuditKey))
+		if err == nil && verified > lines {
+			t.Errorf(fuzzVerifiedFmt, verified, lines)
+		}
+	})
+}
@@ -0,0 +1,217 @@
+package logger
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const (
+	// Types of a grok reference %{NAME:field:type}.
+	grokTypeInt   = "int"
+	grokTypeFloat = "float"
+
+	grokMaxDepth      = 32
+	grokGroupFmt      = "grok%d"
+	grokComment       = "#"
+	errGrokNameMsg    = "unknown grok pattern"
+	errGrokTypeMsg    = "unknown grok type, expected int or float"
+	errGrokDepthMsg   = "grok patterns nest too deeply"
+	errGrokDefineMsg  = "invalid grok pattern definition, expected NAME REGEX"
+	errFmtGrokRef     = "grok: %w: %q"
+	errFmtGrokLine    = "grok patterns line %d: %w"
+	errFmtGrokPattern = "grok patterns: %w"
+)
+
+var (
+	// ErrGrokName is returned for references to undefined grok patterns.
+	ErrGrokName = errors.New(errGrokNameMsg)
+	// ErrGrokType is returned for grok references with an unknown type.
+	ErrGrokType = errors.New(errGrokTypeMsg)
+	// ErrGrokDepth is returned for grok patterns that refer to themselves.
+	ErrGrokDepth = errors.New(errGrokDepthMsg)
+	// ErrGrokDefinition is returned by ParseGrokPatterns for malformed lines.
+	ErrGrokDefinition = errors.New(errGrokDefineMsg)
+)
+
+// grokReference matches %{NAME}, %{NAME:field} and %{NAME:field:type}.
+var grokReference = regexp.MustCompile(`%\{(\w+)(?::([^:}]+))?(?::(\w+))?\}`)
+
+// grokPatterns are the patterns NewGrokParser knows, a subset of the common
+// grok library written for Go's regular expression syntax.
+var grokPatterns = map[string]string{
+	"USERNAME":          `[a-zA-Z0-9._-]+`,
+	"USER":              `%{USERNAME}`,
+	"INT":               `[+-]?\d+`,
+	"BASE10NUM":         `[+-]?(?:\d+(?:\.\d*)?|\.\d+)`,
+	"NUMBER":            `%{BASE10NUM}`,
+	"POSINT":            `\b[1-9]\d*\b`,
+	"NONNEGINT":         `\b\d+\b`,
+	"WORD":              `\b\w+\b`,
+	"NOTSPACE":          `\S+`,
+	"SPACE":             `\s*`,
+	"DATA":              `.*?`,
+	"GREEDYDATA":        `.*`,
+	"QUOTEDSTRING":      `"(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*'`,
+	"QS":                `%{QUOTEDSTRING}`,
+	"UUID":              `[A-Fa-f0-9]{8}-(?:[A-Fa-f0-9]{4}-){3}[A-Fa-f0-9]{12}`,
+	"IPV4":              `(?:(?:25[0-5]|2[0-4]\d|1?\d?\d)\.){3}(?:25[0-5]|2[0-4]\d|1?\d?\d)`,
+	"IPV6":              `[0-9A-Fa-f]*:[0-9A-Fa-f:.]*[0-9A-Fa-f.]`,
+	"IP":                `%{IPV6}|%{IPV4}`,
+	"HOSTNAME":          `\b[0-9A-Za-z][0-9A-Za-z-]{0,62}(?:\.[0-9A-Za-z][0-9A-Za-z-]{0,62})*\.?\b`,
+	"IPORHOST":          `%{IP}|%{HOSTNAME}`,
+	"HOSTPORT":          `%{IPORHOST}:%{POSINT}`,
+	"PATH":              `(?:/[^/\s]*)+|(?:[A-Za-z]:)?(?:\\[^\\\s]*)+`,
+	"URIPATHPARAM":      `/[^\s?#]*(?:\?[^\s#]*)?`,
+	"URI":               `[A-Za-z][A-Za-z0-9+.-]*://\S+`,
+	"LOGLEVEL":          `(?i:trace|debug|info|notice|warn(?:ing)?|err(?:or)?|crit(?:ical)?|alert|fatal|severe|emerg)`,
+	"TIMESTAMP_ISO8601": `\d{4}-\d\d-\d\d[T ]\d\d:\d\d(?::\d\d(?:[.,]\d+)?)?(?:Z|[+-]\d\d:?\d\d)?`,
+	"HTTPDATE":          `\d\d/[A-Za-z]{3}/\d{4}:\d\d:\d\d:\d\d [+-]\d{4}`,
+	"SYSLOGTIMESTAMP":   `[A-Za-z]{3} +\d{1,2} \d\d:\d\d:\d\d`,
+	"PROG":              `[\w._/%-]+`,
+}
+
+// grokLayouts parse the time field of grok patterns without a time layout:
+// those of DefaultIngestPattern and of TIMESTAMP_ISO8601 and HTTPDATE.
+var grokLayouts = append(slices.Clone(defaultIngestLayouts),
+	"2006-01-02T15:04:05", "2006-01-02T15:04:05Z0700", "02/Jan/2006:15:04:05 -0700")
+
+// grokExpander turns a grok pattern into a regular expression.
+type grokExpander struct {
+	definitions map[string]string
+	fields      map[string]ingestField
+}
+
+// NewGrokParser returns a LineParser for a grok pattern: a regular expression
+// in which %{NAME} stands for the named pattern NAME, %{NAME:field} captures
+// it into field and %{NAME:field:int} or %{NAME:field:float} converts the
+// captured text. The fields time, level and msg are read like the groups of
+// NewLineParser, every other field becomes a field of the entry, and lines
+// without a msg field keep the whole line as their message. Patterns such as
+// TIMESTAMP_ISO8601, LOGLEVEL, IPORHOST, NUMBER and GREEDYDATA are built in;
+// definitions adds or overrides patterns, for example from
+// ParseGrokPatterns. The time field is parsed with timeLayout, or with RFC
+// 3339 and the layouts of TIMESTAMP_ISO8601 and HTTPDATE when empty.
+func NewGrokParser(pattern, timeLayout string, definitions map[string]string) (*LineParser, error) {
+	expander := &grokExpander{
+		definitions: maps.Clone(grokPatterns),
+		fields:      make(map[string]ingestField),
+	}
+	maps.Copy(expander.definitions, definitions)
+
+	expanded, err := expander.expand(pattern, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	parser, err := compileLineParser(expanded, timeLayout, expander.fields)
+	if err != nil {
+		return nil, err
+	}
+
+	if timeLayout == "" {
+		parser.timeLayouts = grokLayouts
+	}
+
+	return parser, nil
+}
+
+// ParseGrokPatterns reads grok pattern definitions, one NAME REGEX per line
+// as in the pattern files of other grok implementations. Blank lines and
+// lines starting with # are skipped.
+func ParseGrokPatterns(reader io.Reader) (map[string]string, error) {
+	definitions := make(map[string]string)
+	scanner := bufio.NewScanner(reader)
+
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, grokComment) {
+			continue
+		}
+
+		name, definition, found := strings.Cut(line, " ")
+		definition = strings.TrimSpace(definition)
+
+		if !found || definition == "" {
+			return nil, fmt.Errorf(errFmtGrokLine, number, ErrGrokDefinition)
+		}
+
+		definitions[name] = definition
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf(errFmtGrokPattern, err)
+	}
+
+	return definitions, nil
+}
+
+// expand replaces the grok references of pattern with their definitions.
+func (expander *grokExpander) expand(pattern string, depth int) (string, error) {
+	if depth > grokMaxDepth {
+		return "", ErrGrokDepth
+	}
+
+	var expanded strings.Builder
+
+	last := 0
+
+	for _, match := range grokReference.FindAllStringSubmatchIndex(pattern, -1) {
+		expanded.WriteString(pattern[last:match[0]])
+		last = match[1]
+
+		text, err := expander.reference(pattern, match, depth)
+		if err != nil {
+			return "", err
+		}
+
+		expanded.WriteString(text)
+	}
+
+	expanded.WriteString(pattern[last:])
+
+	return expanded.String(), nil
+}
+
+// reference expands the grok reference at match, a capture group when it
+// names a field.
+func (expander *grokExpander) reference(pattern string, match []int, depth int) (string, error) {
+	name := pattern[match[2]:match[3]]
+
+	definition, defined := expander.definitions[name]
+	if !defined {
+		return "", fmt.Errorf(errFmtGrokRef, ErrGrokName, name)
+	}
+
+	body, err := expander.expand(definition, depth+1)
+	if err != nil {
+		return "", err
+	}
+
+	if match[4] < 0 {
+		return "(?:" + body + ")", nil
+	}
+
+	field := ingestField{name: pattern[match[4]:match[5]], kind: "", group: 0}
+
+	if match[6] >= 0 {
+		field.kind = pattern[match[6]:match[7]]
+		if field.kind != grokTypeInt && field.kind != grokTypeFloat {
+			return "", fmt.Errorf(errFmtGrokRef, ErrGrokType, field.kind)
+		}
+	}
+
+	group := field.name
+	if group != IngestGroupTime && group != IngestGroupLevel && group != IngestGroupMessage {
+		group = fmt.Sprintf(grokGroupFmt, len(expander.fields))
+		expander.fields[group] = field
+	}
+
+	return "(?P<" + group + ">" + body + ")", nil
+}
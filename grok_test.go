@@ -0,0 +1,156 @@
+package logger_test
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	grokAppPattern  = `^%{TIMESTAMP_ISO8601:time} \[%{LOGLEVEL:level}\] %{WORD:component}: %{GREEDYDATA:msg}$`
+	grokAppLine     = "2025-01-02T15:04:05Z [warning] upload: retrying in 5s"
+	grokAccessRegex = `^%{IPORHOST:client} %{USER:ident} %{USER:auth} \[%{HTTPDATE:time}\] ` +
+		`"%{WORD:method} %{URIPATHPARAM:path} HTTP/%{NUMBER:http_version}" %{INT:status:int} %{INT:bytes:int}$`
+	grokAccessLine   = `10.0.0.7 - - [02/Jan/2025:15:04:05 +0000] "GET /index.html?q=1 HTTP/1.1" 404 512`
+	grokCustomDefs   = "# application patterns\nJOBID job-[0-9]+\n\nJOB %{JOBID:job}\n"
+	grokCustomRegex  = `^%{JOB} took %{NUMBER:seconds:float}s: %{GREEDYDATA:msg}$`
+	grokCustomLine   = "job-42 took 1.5s: done"
+	grokLayout       = "[{level}] {msg} {fields}"
+	grokParserFmt    = "NewGrokParser: %v"
+	grokPatternsFmt  = "ParseGrokPatterns: %v"
+	grokParseFmt     = "Parse(%q) = %v %q at %v %v"
+	grokFieldsFmt    = "fields = %v; want %v"
+	grokErrorFmt     = "NewGrokParser(%q) error = %v; want %v"
+	grokOutputFmt    = "output = %q; want %q"
+	grokAppWant      = "[WARN] retrying in 5s component=upload\n"
+	grokUnknownRegex = `%{NOPE:x}`
+	grokTypeRegex    = `%{INT:x:bool}`
+	grokLoopRegex    = `%{LOOP}`
+	grokLoopDef      = `%{LOOP}x`
+	grokBadDefs      = "NAME\n"
+)
+
+func TestNewGrokParser_Fields(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	parser := newGrokParser(t, grokAppPattern, nil)
+
+	parsed := parser.Parse(grokAppLine, now)
+	stamped := time.Date(2025, time.January, 2, 15, 4, 5, 0, time.UTC)
+
+	if parsed.Level != logger.LevelWarn || parsed.Message != "retrying in 5s" || !parsed.Time.Equal(stamped) {
+		t.Errorf(grokParseFmt, grokAppLine, parsed.Level, parsed.Message, parsed.Time, parsed.Fields)
+	}
+
+	want := []logger.Field{logger.F("component", "upload")}
+	if !slices.Equal(parsed.Fields, want) {
+		t.Errorf(grokFieldsFmt, parsed.Fields, want)
+	}
+}
+
+func TestNewGrokParser_TypesWithoutMessage(t *testing.T) {
+	t.Parallel()
+
+	parser := newGrokParser(t, grokAccessRegex, nil)
+
+	parsed := parser.Parse(grokAccessLine, time.Now())
+	stamped := time.Date(2025, time.January, 2, 15, 4, 5, 0, time.UTC)
+
+	if parsed.Level != logger.LevelInfo || parsed.Message != grokAccessLine || !parsed.Time.Equal(stamped) {
+		t.Errorf(grokParseFmt, grokAccessLine, parsed.Level, parsed.Message, parsed.Time, parsed.Fields)
+	}
+
+	want := []logger.Field{
+		logger.F("client", "10.0.0.7"),
+		logger.F("ident", "-"),
+		logger.F("auth", "-"),
+		logger.F("method", "GET"),
+		logger.F("path", "/index.html?q=1"),
+		logger.F("http_version", "1.1"),
+		logger.F("status", int64(404)),
+		logger.F("bytes", int64(512)),
+	}
+	if !slices.Equal(parsed.Fields, want) {
+		t.Errorf(grokFieldsFmt, parsed.Fields, want)
+	}
+}
+
+func TestNewGrokParser_CustomPatterns(t *testing.T) {
+	t.Parallel()
+
+	definitions, err := logger.ParseGrokPatterns(strings.NewReader(grokCustomDefs))
+	if err != nil {
+		t.Fatalf(grokPatternsFmt, err)
+	}
+
+	parser := newGrokParser(t, grokCustomRegex, definitions)
+
+	parsed := parser.Parse(grokCustomLine, time.Now())
+	want := []logger.Field{logger.F("job", "job-42"), logger.F("seconds", 1.5)}
+
+	if parsed.Message != "done" || !slices.Equal(parsed.Fields, want) {
+		t.Errorf(grokFieldsFmt, parsed.Fields, want)
+	}
+}
+
+func TestNewGrokParser_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pattern string
+		want    error
+	}{
+		{pattern: grokUnknownRegex, want: logger.ErrGrokName},
+		{pattern: grokTypeRegex, want: logger.ErrGrokType},
+		{pattern: grokLoopRegex, want: logger.ErrGrokDepth},
+	}
+
+	for _, test := range tests {
+		_, err := logger.NewGrokParser(test.pattern, "", map[string]string{"LOOP": grokLoopDef})
+		if !errors.Is(err, test.want) {
+			t.Errorf(grokErrorFmt, test.pattern, err, test.want)
+		}
+	}
+
+	_, err := logger.ParseGrokPatterns(strings.NewReader(grokBadDefs))
+	if !errors.Is(err, logger.ErrGrokDefinition) {
+		t.Errorf(grokErrorFmt, grokBadDefs, err, logger.ErrGrokDefinition)
+	}
+}
+
+func TestIngestSink_GrokFields(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	loggerInstance := logger.NewStreamLogger(&buf, logger.WithLayout(logger.MustParseLayout(grokLayout)))
+	sink := loggerInstance.IngestSink(newGrokParser(t, grokAppPattern, nil))
+
+	_, err := sink.Write([]byte(grokAppLine + "\n"))
+	if err != nil {
+		t.Fatalf(ingestWriteFmt, err)
+	}
+
+	closeTestLogger(t, loggerInstance)
+
+	if buf.String() != grokAppWant {
+		t.Errorf(grokOutputFmt, buf.String(), grokAppWant)
+	}
+}
+
+func newGrokParser(t *testing.T, pattern string, definitions map[string]string) *logger.LineParser {
+	t.Helper()
+
+	parser, err := logger.NewGrokParser(pattern, "", definitions)
+	if err != nil {
+		t.Fatalf(grokParserFmt, err)
+	}
+
+	return parser
+}
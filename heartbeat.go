@@ -0,0 +1,125 @@
+package logger
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// IdleProducerField names the producer a heartbeat warning is about. It
+	// is not ProducerField, so that the warning does not count as the
+	// producer's own entry.
+	IdleProducerField = "idle_producer"
+
+	heartbeatIntervalField     = "expected_interval"
+	idleProducerFormat         = "producer %s idle for %s"
+	heartbeatChecksPerInterval = 4
+)
+
+// ProducerHeartbeat expects the Named producer Name to log at least once every
+// Interval.
+type ProducerHeartbeat struct {
+	Name     string
+	Interval time.Duration
+}
+
+// heartbeatMonitor is the state of WatchProducers. alerted holds, per
+// producer, the start of the quiet period already warned about.
+type heartbeatMonitor struct {
+	start      time.Time
+	logger     *Logger
+	onIdle     func(summary string)
+	alerted    map[string]time.Time
+	heartbeats []ProducerHeartbeat
+}
+
+// WatchProducers turns the producer statistics into a heartbeat monitor: it
+// writes a WARN entry when a producer of heartbeats has not logged within its
+// Interval, counted from its last entry or from the call, and calls onIdle,
+// when not nil, with the warning text. A producer is warned about once per
+// quiet period. Producers are checked four times per shortest Interval. It
+// returns a function that stops the monitor; the function is safe to call
+// more than once.
+func (l *Logger) WatchProducers(heartbeats []ProducerHeartbeat, onIdle func(summary string)) (stop func()) {
+	period := heartbeatPeriod(heartbeats)
+	if period <= 0 {
+		return func() {}
+	}
+
+	monitor := &heartbeatMonitor{
+		start:      l.core.clock(),
+		logger:     l,
+		onIdle:     onIdle,
+		alerted:    make(map[string]time.Time),
+		heartbeats: heartbeats,
+	}
+	done := make(chan struct{})
+	ticker := time.NewTicker(period)
+
+	l.core.goTask(ProfileTaskHeartbeat, func() {
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				monitor.check()
+			case <-done:
+				return
+			}
+		}
+	})
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
+
+// heartbeatPeriod returns how often heartbeats are checked, or zero when none
+// has a positive Interval.
+func heartbeatPeriod(heartbeats []ProducerHeartbeat) time.Duration {
+	shortest := time.Duration(0)
+
+	for _, heartbeat := range heartbeats {
+		if heartbeat.Interval > 0 && (shortest == 0 || heartbeat.Interval < shortest) {
+			shortest = heartbeat.Interval
+		}
+	}
+
+	return shortest / heartbeatChecksPerInterval
+}
+
+func (monitor *heartbeatMonitor) check() {
+	lastSeen := make(map[string]time.Time)
+	for _, producer := range monitor.logger.core.producerStats() {
+		lastSeen[producer.Name] = producer.LastSeen
+	}
+
+	now := monitor.logger.core.clock()
+
+	for _, heartbeat := range monitor.heartbeats {
+		since := lastSeen[heartbeat.Name]
+		if since.Before(monitor.start) {
+			since = monitor.start
+		}
+
+		idle := now.Sub(since)
+		if heartbeat.Interval <= 0 || idle < heartbeat.Interval || monitor.alerted[heartbeat.Name].Equal(since) {
+			continue
+		}
+
+		monitor.alerted[heartbeat.Name] = since
+		monitor.alert(heartbeat, idle)
+	}
+}
+
+func (monitor *heartbeatMonitor) alert(heartbeat ProducerHeartbeat, idle time.Duration) {
+	fields := []Field{F(IdleProducerField, heartbeat.Name), Dur(heartbeatIntervalField, heartbeat.Interval)}
+	monitor.logger.writeEntryf(LevelWarn, fields, "", targetAll, idleProducerFormat, heartbeat.Name, durationValue(idle))
+
+	if monitor.onIdle != nil {
+		monitor.onIdle(fmt.Sprintf(idleProducerFormat, heartbeat.Name, durationValue(idle)))
+	}
+}
@@ -0,0 +1,83 @@
+package logger_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	heartbeatProducer = "app1"
+	heartbeatInterval = 20 * time.Millisecond
+	heartbeatTimeout  = 5 * time.Second
+	heartbeatMsg      = "tick"
+	heartbeatWarning  = "[WARN] producer app1 idle for"
+	heartbeatField    = "idle_producer=app1"
+	heartbeatCountFmt = "%d warnings in output; want %d:\n%s"
+	heartbeatIdleFmt  = "no idle warning within %s"
+	heartbeatText     = "onIdle summary = %q; want prefix %q"
+)
+
+func TestLogger_WatchProducers(t *testing.T) {
+	t.Parallel()
+
+	var buf syncBuffer
+
+	loggerInstance := logger.NewStreamLogger(&buf)
+	summaries := make(chan string, 1)
+
+	stop := loggerInstance.WatchProducers(
+		[]logger.ProducerHeartbeat{{Name: heartbeatProducer, Interval: heartbeatInterval}},
+		func(summary string) { summaries <- summary },
+	)
+	defer stop()
+
+	summary := awaitIdle(t, summaries)
+	if !strings.HasPrefix(summary, "producer "+heartbeatProducer) {
+		t.Errorf(heartbeatText, summary, "producer "+heartbeatProducer)
+	}
+
+	// A producer that stays quiet is warned about once.
+	time.Sleep(4 * heartbeatInterval)
+
+	output := buf.String()
+	if strings.Count(output, heartbeatWarning) != 1 || !strings.Contains(output, heartbeatField) {
+		t.Errorf(heartbeatCountFmt, strings.Count(output, heartbeatWarning), 1, output)
+	}
+
+	loggerInstance.Named(heartbeatProducer).Infof(heartbeatMsg)
+	awaitIdle(t, summaries)
+	stop()
+	closeTestLogger(t, loggerInstance)
+
+	output = buf.String()
+	if strings.Count(output, heartbeatWarning) != 2 {
+		t.Errorf(heartbeatCountFmt, strings.Count(output, heartbeatWarning), 2, output)
+	}
+}
+
+func TestLogger_WatchProducersDisabled(t *testing.T) {
+	t.Parallel()
+
+	loggerInstance := logger.NewStreamLogger(&syncBuffer{})
+
+	stop := loggerInstance.WatchProducers([]logger.ProducerHeartbeat{{Name: heartbeatProducer, Interval: 0}}, nil)
+	stop()
+	stop()
+	closeTestLogger(t, loggerInstance)
+}
+
+func awaitIdle(t *testing.T, summaries <-chan string) string {
+	t.Helper()
+
+	select {
+	case summary := <-summaries:
+		return summary
+	case <-time.After(heartbeatTimeout):
+		t.Fatalf(heartbeatIdleFmt, heartbeatTimeout)
+
+		return ""
+	}
+}
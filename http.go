@@ -0,0 +1,122 @@
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"time"
+)
+
+const (
+	// RequestIDHeader is read for an incoming request ID and set on responses
+	// by Middleware.
+	RequestIDHeader = "X-Request-ID"
+
+	// Fields added by RequestLogger and Middleware.
+	RequestIDField   = "request_id"
+	MethodField      = "method"
+	PathField        = "path"
+	RemoteAddrField  = "remote_addr"
+	StatusField      = "status"
+	BytesField       = "bytes"
+	DurationField    = "duration"
+	requestIDBytes   = 8
+	requestDoneMsg   = "request completed"
+	maxRequestIDSize = 128
+)
+
+// requestLoggerKey is the context key of the request logger.
+type requestLoggerKey struct{}
+
+// RequestLogger returns a child logger for r carrying its request ID, method,
+// path and remote address. Inside Middleware it returns the logger the
+// middleware created, so every entry of a request shares one request ID;
+// elsewhere the ID is taken from the RequestIDHeader or generated.
+func (l *Logger) RequestLogger(r *http.Request) *Logger {
+	requestLogger, ok := r.Context().Value(requestLoggerKey{}).(*Logger)
+	if ok {
+		return requestLogger
+	}
+
+	return l.newRequestLogger(r, requestID(r))
+}
+
+func (l *Logger) newRequestLogger(r *http.Request, id string) *Logger {
+	return l.With(
+		F(RequestIDField, id),
+		F(MethodField, r.Method),
+		F(PathField, r.URL.Path),
+		F(RemoteAddrField, r.RemoteAddr),
+	)
+}
+
+// Middleware wraps next so that every request gets a request logger, available
+// to handlers through RequestLogger, and a request ID echoed in the
+// RequestIDHeader of the response. When the request completes, a line is
+// written to the access log configured with WithAccessLog or, without one, an
+// INFO entry records its status, response size and duration.
+func (l *Logger) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		id := requestID(r)
+		requestLogger := l.newRequestLogger(r, id)
+
+		w.Header().Set(RequestIDHeader, id)
+
+		recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK, bytes: 0}
+		ctx := context.WithValue(r.Context(), requestLoggerKey{}, requestLogger)
+		next.ServeHTTP(recorder, r.WithContext(ctx))
+
+		if l.core.access != nil {
+			l.core.writeAccessLog(newAccessLogEntry(r, recorder, start, id))
+
+			return
+		}
+
+		requestLogger.writeEntryf(LevelInfo, []Field{
+			F(StatusField, recorder.status),
+			F(BytesField, recorder.bytes),
+			F(DurationField, time.Since(start)),
+		}, "", targetAll, requestDoneMsg)
+	})
+}
+
+// requestID returns the request ID sent by the client, or a new random one.
+// Overlong client IDs are replaced so that they cannot bloat every entry.
+func requestID(r *http.Request) string {
+	id := r.Header.Get(RequestIDHeader)
+	if id != "" && len(id) <= maxRequestIDSize {
+		return id
+	}
+
+	buf := make([]byte, requestIDBytes)
+	_, _ = rand.Read(buf)
+
+	return hex.EncodeToString(buf)
+}
+
+// responseRecorder captures the status code and body size of a response.
+type responseRecorder struct {
+	http.ResponseWriter
+
+	status int
+	bytes  int
+}
+
+func (recorder *responseRecorder) WriteHeader(status int) {
+	recorder.status = status
+	recorder.ResponseWriter.WriteHeader(status)
+}
+
+func (recorder *responseRecorder) Write(data []byte) (int, error) {
+	n, err := recorder.ResponseWriter.Write(data)
+	recorder.bytes += n
+
+	return n, err
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (recorder *responseRecorder) Unwrap() http.ResponseWriter {
+	return recorder.ResponseWriter
+}
@@ -0,0 +1,66 @@
+package logger_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	httpPath        = "/books/42"
+	httpRequestID   = "req-123"
+	httpHandlerMsg  = "loading book"
+	httpBody        = "not here"
+	httpHeaderErr   = "expected response header %q, got %q"
+	httpRequestLine = "request_id=req-123 method=GET path=/books/42 remote_addr=192.0.2.1:1234"
+	httpDoneLine    = "[INFO] request completed request_id=req-123"
+	httpStatusText  = "status=404 bytes=9"
+)
+
+func TestRequestLogger_Fields(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	loggerInstance := logger.NewStreamLogger(&buf)
+
+	request := httptest.NewRequest(http.MethodGet, httpPath, nil)
+	request.Header.Set(logger.RequestIDHeader, httpRequestID)
+	loggerInstance.RequestLogger(request).Infof(httpHandlerMsg)
+
+	if !strings.Contains(buf.String(), httpRequestLine) {
+		t.Errorf(layoutOutputErrFmt, httpRequestLine, buf.String())
+	}
+}
+
+func TestMiddleware_SharesRequestLogger(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	loggerInstance := logger.NewStreamLogger(&buf)
+	handler := loggerInstance.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		loggerInstance.RequestLogger(r).Warnf(httpHandlerMsg)
+		http.Error(w, httpBody, http.StatusNotFound)
+	}))
+
+	request := httptest.NewRequest(http.MethodGet, httpPath, nil)
+	request.Header.Set(logger.RequestIDHeader, httpRequestID)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	if got := recorder.Header().Get(logger.RequestIDHeader); got != httpRequestID {
+		t.Errorf(httpHeaderErr, httpRequestID, got)
+	}
+
+	for _, want := range []string{httpRequestLine, httpDoneLine, httpStatusText} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf(layoutOutputErrFmt, want, buf.String())
+		}
+	}
+}
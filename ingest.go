@@ -0,0 +1,288 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultIngestPattern matches lines such as
+	//	2025-01-02T15:04:05Z ERROR connection refused
+	//	2025/01/02 15:04:05 [WARN] disk almost full
+	//	error: connection refused
+	// with an optional timestamp and a level in brackets or before a colon.
+	DefaultIngestPattern = `^(?:(?P<time>\d{4}[-/]\d\d[-/]\d\d[T ]\d\d:\d\d:\d\d\S*)\s+)?` +
+		`\[?(?P<level>[A-Za-z]+)\]?:?\s+(?P<msg>.*)$`
+
+	// Named groups read by LineParser.
+	IngestGroupTime    = "time"
+	IngestGroupLevel   = "level"
+	IngestGroupMessage = "msg"
+
+	ingestLineEnd      = '\n'
+	errIngestGroupMsg  = "ingest pattern needs a msg group"
+	errFmtIngestRegexp = "ingest pattern: %w"
+)
+
+// ErrIngestPattern is returned for ingest patterns without a msg group.
+var ErrIngestPattern = errors.New(errIngestGroupMsg)
+
+// ingestLevelAliases maps level names used by other software to levels.
+var ingestLevelAliases = map[string]Level{
+	"WARNING":  LevelWarn,
+	"ERR":      LevelError,
+	"CRIT":     LevelFatal,
+	"CRITICAL": LevelFatal,
+	"NOTICE":   LevelInfo,
+	"TRACE":    LevelDebug,
+	"SEVERE":   LevelError,
+	"ALERT":    LevelFatal,
+	"EMERG":    LevelFatal,
+}
+
+// defaultIngestLayouts parse the timestamps matched by DefaultIngestPattern.
+var defaultIngestLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006/01/02 15:04:05"}
+
+// LineParser extracts the timestamp, level and message of lines written by
+// other software with a regular expression using the named groups time,
+// level and msg. Only msg is required; other named groups that match become
+// fields. Lines that do not match, or whose level is unknown, are logged
+// whole as INFO entries; unparsable timestamps are replaced with the time of
+// ingestion.
+type LineParser struct {
+	pattern     *regexp.Regexp
+	timeLayouts []string
+	fields      []ingestField
+	timeGroup   int
+	levelGroup  int
+	msgGroup    int
+}
+
+// ingestField is a named group of a LineParser read into a field, converted
+// to kind.
+type ingestField struct {
+	name  string
+	kind  string
+	group int
+}
+
+// IngestSink logs every line written to it through a logger.
+type IngestSink struct {
+	logger   *Logger
+	parser   *LineParser
+	stitcher *stitcher // set by Stitch
+}
+
+// NewLineParser compiles pattern, or DefaultIngestPattern when empty. The
+// time group is parsed with timeLayout, or with RFC 3339 and the layouts of
+// DefaultIngestPattern when empty.
+func NewLineParser(pattern, timeLayout string) (*LineParser, error) {
+	if pattern == "" {
+		pattern = DefaultIngestPattern
+	}
+
+	parser, err := compileLineParser(pattern, timeLayout, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if parser.msgGroup < 0 {
+		return nil, fmt.Errorf(errFmtIngestRegexp, ErrIngestPattern)
+	}
+
+	return parser, nil
+}
+
+// compileLineParser compiles pattern. Named groups other than time, level
+// and msg are read into fields, named and typed by fields when listed there.
+func compileLineParser(pattern, timeLayout string, fields map[string]ingestField) (*LineParser, error) {
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf(errFmtIngestRegexp, err)
+	}
+
+	parser := &LineParser{
+		pattern:     compiled,
+		timeLayouts: defaultIngestLayouts,
+		fields:      nil,
+		timeGroup:   compiled.SubexpIndex(IngestGroupTime),
+		levelGroup:  compiled.SubexpIndex(IngestGroupLevel),
+		msgGroup:    compiled.SubexpIndex(IngestGroupMessage),
+	}
+
+	for group, name := range compiled.SubexpNames() {
+		if name == "" || name == IngestGroupTime || name == IngestGroupLevel || name == IngestGroupMessage {
+			continue
+		}
+
+		field, listed := fields[name]
+		if !listed {
+			field = ingestField{name: name, kind: "", group: 0}
+		}
+
+		field.group = group
+		parser.fields = append(parser.fields, field)
+	}
+
+	if timeLayout != "" {
+		parser.timeLayouts = []string{timeLayout}
+	}
+
+	return parser, nil
+}
+
+// Parse returns the entry described by line, stamped with now when it has no
+// timestamp of its own.
+func (parser *LineParser) Parse(line string, now time.Time) Entry {
+	parsed := Entry{
+		Time:       now,
+		Fields:     nil,
+		Label:      "",
+		Message:    line,
+		Caller:     "",
+		Level:      LevelInfo,
+		Mandatory:  false,
+		timeText:   "",
+		elapsed:    0,
+		timeFormat: TimeFormatDefault,
+		ingested:   false,
+	}
+
+	match := parser.pattern.FindStringSubmatch(line)
+	if match == nil {
+		return parsed
+	}
+
+	if parser.levelGroup >= 0 && match[parser.levelGroup] != "" {
+		level, ok := ingestLevel(match[parser.levelGroup])
+		if !ok {
+			return parsed
+		}
+
+		parsed.Level = level
+	}
+
+	if parser.msgGroup >= 0 {
+		parsed.Message = match[parser.msgGroup]
+	}
+
+	for _, field := range parser.fields {
+		if match[field.group] != "" {
+			parsed.Fields = append(parsed.Fields, F(field.name, field.value(match[field.group])))
+		}
+	}
+
+	if parser.timeGroup >= 0 && match[parser.timeGroup] != "" {
+		parsed.Time = parser.parseTime(match[parser.timeGroup], now)
+	}
+
+	return parsed
+}
+
+func (parser *LineParser) parseTime(text string, now time.Time) time.Time {
+	for _, layout := range parser.timeLayouts {
+		parsed, err := time.ParseInLocation(layout, text, time.Local)
+		if err == nil {
+			return parsed
+		}
+	}
+
+	return now
+}
+
+// value converts text to the kind of the field, keeping text that does not
+// parse.
+func (field ingestField) value(text string) any {
+	switch field.kind {
+	case grokTypeInt:
+		number, err := strconv.ParseInt(text, 10, 64)
+		if err == nil {
+			return number
+		}
+	case grokTypeFloat:
+		number, err := strconv.ParseFloat(text, 64)
+		if err == nil {
+			return number
+		}
+	}
+
+	return text
+}
+
+func ingestLevel(name string) (Level, bool) {
+	level, err := ParseLevel(name)
+	if err == nil {
+		return level, true
+	}
+
+	level, ok := ingestLevelAliases[strings.ToUpper(name)]
+
+	return level, ok
+}
+
+// IngestSink returns a Sink that parses every line written to it with parser
+// and logs it through l, with l's fields and the logger's outputs, layout and
+// level filter. It lets a Shipper re-emit the log files of other software.
+// Closing it does not close the logger.
+func (l *Logger) IngestSink(parser *LineParser) *IngestSink {
+	return &IngestSink{logger: l, parser: parser, stitcher: nil}
+}
+
+// Write logs every line of data.
+func (sink *IngestSink) Write(data []byte) (int, error) {
+	now := time.Now()
+	lines := strings.Split(string(bytes.TrimSuffix(data, []byte{ingestLineEnd})), string(ingestLineEnd))
+
+	if sink.stitcher != nil {
+		sink.stitch(lines, now)
+
+		return len(data), nil
+	}
+
+	for _, line := range lines {
+		sink.logger.logParsed(sink.parser.Parse(line, now))
+	}
+
+	return len(data), nil
+}
+
+// Close logs the entry a sink of Stitch is stitching. It does not close the
+// logger, which is closed by its owner.
+func (sink *IngestSink) Close() error {
+	if sink.stitcher == nil {
+		return nil
+	}
+
+	sink.stitcher.mu.Lock()
+	defer sink.stitcher.mu.Unlock()
+
+	if sink.stitcher.timer != nil {
+		sink.stitcher.timer.Stop()
+	}
+
+	sink.flushLocked()
+
+	return nil
+}
+
+// logParsed writes an entry parsed from another program's log, keeping its
+// time and fields and adding the logger's fields before them.
+func (l *Logger) logParsed(parsed Entry) {
+	if !l.core.enabled(parsed.Level) {
+		return
+	}
+
+	parsed.Fields = append(slices.Clip(l.fields), parsed.Fields...)
+	parsed.Label = l.core.label(parsed.Level)
+	parsed.Message = l.core.validateFormat(parsed.Message)
+	parsed.ingested = true
+
+	l.core.fingerprints.count(parsed.Message)
+	l.core.submit(&parsed, targetAll)
+}
@@ -0,0 +1,98 @@
+package logger_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	ingestBracketLine = "2025/01/02 15:04:05 [WARN] disk almost full"
+	ingestColonLine   = "error: connection refused"
+	ingestAliasLine   = "WARNING retrying"
+	ingestPlainLine   = "just text"
+	ingestCustomLine  = "<crit> 2025-01-02 boom"
+	ingestCustomRegex = `^<(?P<level>\w+)> (?P<time>\S+) (?P<msg>.*)$`
+	ingestCustomTime  = "2006-01-02"
+	ingestLayout      = "[{level}] {msg}"
+	ingestSinkLines   = ingestBracketLine + "\n" + ingestColonLine + "\n"
+	ingestSinkWant    = "[WARN] disk almost full\n[ERROR] connection refused\n"
+	ingestParserFmt   = "NewLineParser: %v"
+	ingestParseFmt    = "Parse(%q) = %v %q at %v; want %v %q at %v"
+	ingestOutputFmt   = "output:\n%s\nwant:\n%s"
+	ingestWriteFmt    = "write: %v"
+)
+
+func TestLineParser_Parse(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, time.June, 7, 8, 9, 10, 0, time.Local)
+	stamped := time.Date(2025, time.January, 2, 15, 4, 5, 0, time.Local)
+
+	tests := []struct {
+		line    string
+		message string
+		time    time.Time
+		level   logger.Level
+	}{
+		{line: ingestBracketLine, message: "disk almost full", time: stamped, level: logger.LevelWarn},
+		{line: ingestColonLine, message: "connection refused", time: now, level: logger.LevelError},
+		{line: ingestAliasLine, message: "retrying", time: now, level: logger.LevelWarn},
+		{line: ingestPlainLine, message: ingestPlainLine, time: now, level: logger.LevelInfo},
+	}
+
+	parser := newLineParser(t, "", "")
+
+	for _, test := range tests {
+		parsed := parser.Parse(test.line, now)
+		if parsed.Level != test.level || parsed.Message != test.message || !parsed.Time.Equal(test.time) {
+			t.Errorf(ingestParseFmt, test.line, parsed.Level, parsed.Message, parsed.Time,
+				test.level, test.message, test.time)
+		}
+	}
+}
+
+func TestLineParser_CustomPattern(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	want := time.Date(2025, time.January, 2, 0, 0, 0, 0, time.Local)
+	parser := newLineParser(t, ingestCustomRegex, ingestCustomTime)
+
+	parsed := parser.Parse(ingestCustomLine, now)
+	if parsed.Level != logger.LevelFatal || parsed.Message != "boom" || !parsed.Time.Equal(want) {
+		t.Errorf(ingestParseFmt, ingestCustomLine, parsed.Level, parsed.Message, parsed.Time,
+			logger.LevelFatal, "boom", want)
+	}
+}
+
+func TestIngestSink_ReemitsThroughLogger(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	loggerInstance := logger.NewStreamLogger(&buf, logger.WithLayout(logger.MustParseLayout(ingestLayout)))
+	sink := loggerInstance.IngestSink(newLineParser(t, "", ""))
+
+	_, err := sink.Write([]byte(ingestSinkLines))
+	if err != nil {
+		t.Fatalf(ingestWriteFmt, err)
+	}
+
+	if buf.String() != ingestSinkWant {
+		t.Errorf(ingestOutputFmt, buf.String(), ingestSinkWant)
+	}
+}
+
+func newLineParser(t *testing.T, pattern, timeLayout string) *logger.LineParser {
+	t.Helper()
+
+	parser, err := logger.NewLineParser(pattern, timeLayout)
+	if err != nil {
+		t.Fatalf(ingestParserFmt, err)
+	}
+
+	return parser
+}
@@ -0,0 +1,152 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+)
+
+const (
+	// JobIDField is the field added by ForJob.
+	JobIDField = "job_id"
+	// JobsDir is the subdirectory of the log directory holding job files.
+	JobsDir = "jobs"
+
+	jobFileSuffix   = ".log"
+	errNoLogDirMsg  = "job files need a log directory"
+	errFmtOpenJob   = "open job %q: %w"
+	errFmtCloseJob  = "close job %q: %w"
+	jobRouteNameFmt = JobsDir + "/%s" + jobFileSuffix
+)
+
+// ErrNoLogDir is recorded by ForJob for loggers without a log directory.
+var ErrNoLogDir = errors.New(errNoLogDirMsg)
+
+// ForJob returns a child logger for the job jobID. Its entries carry a job_id
+// field and are written both to the logger's outputs and to
+// jobs/<jobID>.log under the log directory, at every enabled level, so that
+// the complete history of one job can be attached to a support ticket. The
+// job file stays open until EndJob or Close. When it cannot be opened, for
+// example because the logger has no log directory or jobID is not a valid
+// filename, the child logs to the regular outputs only and the error is
+// reported by Err.
+func (l *Logger) ForJob(jobID string) *Logger {
+	child := l.With(F(JobIDField, jobID))
+
+	err := l.core.openJob(jobID)
+	if err != nil {
+		l.core.mu.Lock()
+		l.core.lastErr = fmt.Errorf(errFmtOpenJob, jobID, err)
+		l.core.mu.Unlock()
+	}
+
+	return child
+}
+
+// EndJob closes the file of the job jobID opened by ForJob. Later entries of
+// the job's logger go to the regular outputs only.
+func (l *Logger) EndJob(jobID string) error {
+	c := l.core
+
+	target := c.removeJobRoute(jobID)
+	if target == nil {
+		return nil
+	}
+
+	c.sinks.detach(target)
+
+	err := target.closer.Close()
+	if err != nil {
+		return fmt.Errorf(errFmtCloseJob, jobID, err)
+	}
+
+	return nil
+}
+
+// removeJobRoute removes the route of jobID and returns it, or nil when the
+// job has no open file.
+func (c *loggerCore) removeJobRoute(jobID string) *routeTarget {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	routes := make([]*routeTarget, 0, len(c.routes))
+
+	var target *routeTarget
+
+	for _, route := range c.routes {
+		if isJobRoute(route, jobID) {
+			target = route
+		} else {
+			routes = append(routes, route)
+		}
+	}
+
+	c.routes = routes
+
+	return target
+}
+
+// openJob adds a route writing the entries of jobID to its job file, unless
+// the job is open already.
+func (c *loggerCore) openJob(jobID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return ErrLoggerClosed
+	}
+
+	for _, route := range c.routes {
+		if isJobRoute(route, jobID) {
+			return nil
+		}
+	}
+
+	if c.logDir == "" {
+		return ErrNoLogDir
+	}
+
+	filename := jobID + jobFileSuffix
+
+	err := ValidateFilename(filename)
+	if err != nil {
+		return err
+	}
+
+	jobPath, err := setupAndValidatePath(filepath.Join(c.logDir, JobsDir), filename)
+	if err != nil {
+		return err
+	}
+
+	file, err := openLogFile(jobPath)
+	if err != nil {
+		return err
+	}
+
+	target := newRouteTarget(Route{
+		Writer:         nil,
+		Sink:           nil,
+		Message:        nil,
+		Layout:         nil,
+		Timezone:       nil,
+		Name:           fmt.Sprintf(jobRouteNameFmt, jobID),
+		Field:          JobIDField,
+		Value:          jobID,
+		Filename:       filename,
+		MinLevel:       LevelDebug,
+		Encoding:       c.encoding,
+		MaxSensitivity: SensitivitySecret,
+	}, file, file)
+	target.name = target.route.Name
+	c.sinks.attach(target)
+
+	// Replace the slice rather than appending in place: flushSinks iterates a
+	// copy of it without the lock.
+	c.routes = append(c.routes[:len(c.routes):len(c.routes)], target)
+
+	return nil
+}
+
+func isJobRoute(target *routeTarget, jobID string) bool {
+	return target.route.Field == JobIDField && target.route.Value == jobID && target.route.Filename != ""
+}
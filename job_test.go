@@ -0,0 +1,82 @@
+package logger_test
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	jobMainFile   = "app.log"
+	jobID         = "doc-42"
+	jobFile       = "doc-42.log"
+	jobStartMsg   = "job started"
+	jobOtherMsg   = "unrelated work"
+	jobDoneMsg    = "job done"
+	jobLateMsg    = "after the job"
+	jobMissingFmt = "%s lacks %q:\n%s"
+	jobExtraFmt   = "%s holds %q:\n%s"
+	jobErrFmt     = "Err() = %v; want %v"
+	jobEndFmt     = "EndJob: %v"
+)
+
+func TestLogger_ForJob(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	loggerInstance := createTestLogger(t, dir, jobMainFile)
+
+	jobLogger := loggerInstance.ForJob(jobID)
+	jobLogger.Infof(jobStartMsg)
+	loggerInstance.Infof(jobOtherMsg)
+	loggerInstance.ForJob(jobID).Warnf(jobDoneMsg)
+
+	err := loggerInstance.EndJob(jobID)
+	if err != nil {
+		t.Fatalf(jobEndFmt, err)
+	}
+
+	jobLogger.Infof(jobLateMsg)
+
+	closeOutputsLogger(t, loggerInstance)
+
+	jobText := readOutputsFile(t, filepath.Join(dir, logger.JobsDir, jobFile))
+	for _, want := range []string{jobStartMsg + " job_id=" + jobID, jobDoneMsg} {
+		if !strings.Contains(jobText, want) {
+			t.Errorf(jobMissingFmt, jobFile, want, jobText)
+		}
+	}
+
+	for _, unwanted := range []string{jobOtherMsg, jobLateMsg} {
+		if strings.Contains(jobText, unwanted) {
+			t.Errorf(jobExtraFmt, jobFile, unwanted, jobText)
+		}
+	}
+
+	mainText := readOutputsFile(t, filepath.Join(dir, jobMainFile))
+	for _, want := range []string{jobStartMsg, jobOtherMsg, jobDoneMsg, jobLateMsg} {
+		if !strings.Contains(mainText, want) {
+			t.Errorf(jobMissingFmt, jobMainFile, want, mainText)
+		}
+	}
+}
+
+func TestLogger_ForJobWithoutLogDir(t *testing.T) {
+	t.Parallel()
+
+	var buf strings.Builder
+
+	loggerInstance := logger.NewStreamLogger(&buf)
+	loggerInstance.ForJob(jobID).Infof(jobStartMsg)
+
+	if !errors.Is(loggerInstance.Err(), logger.ErrNoLogDir) {
+		t.Errorf(jobErrFmt, loggerInstance.Err(), logger.ErrNoLogDir)
+	}
+
+	if !strings.Contains(buf.String(), jobStartMsg) {
+		t.Errorf(jobMissingFmt, "output", jobStartMsg, buf.String())
+	}
+}
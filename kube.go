@@ -0,0 +1,387 @@
+package logger
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// Fields added to the entries of pod logs.
+	PodField       = "pod"
+	NamespaceField = "namespace"
+	ContainerField = "container"
+
+	kubeServicePortEnv   = "KUBERNETES_SERVICE_PORT"
+	kubeAccountDir       = "/var/run/secrets/kubernetes.io/serviceaccount"
+	kubeTokenFile        = "token"
+	kubeCAFile           = "ca.crt"
+	kubeNamespaceFile    = "namespace"
+	kubeHTTPSScheme      = "https://"
+	kubeAllPodsPath      = "/api/v1/pods"
+	kubePodsPathFmt      = "/api/v1/namespaces/%s/pods"
+	kubeLogPathFmt       = "/api/v1/namespaces/%s/pods/%s/log"
+	kubeLabelSelector    = "labelSelector"
+	kubeContainerParam   = "container"
+	kubeFollowParam      = "follow"
+	kubeTimestampsParam  = "timestamps"
+	kubeSinceTimeParam   = "sinceTime"
+	kubeTrue             = "true"
+	kubePhaseRunning     = "Running"
+	kubeAuthHeader       = "Authorization"
+	kubeBearerPrefix     = "Bearer "
+	kubeTimestampSep     = " "
+	kubePathSep          = "/"
+	errNotInClusterMsg   = "not running in a Kubernetes cluster"
+	errKubeCAMsg         = "no certificates in the service account CA"
+	errKubeStatusMsg     = "unexpected Kubernetes API status"
+	errFmtKubeConfig     = "kubernetes config: %w"
+	errFmtKubeRequest    = "kubernetes %s: %w"
+	errFmtKubeStatus     = "kubernetes %s: %w: %s"
+	errFmtKubeStream     = "pod %s/%s container %s: %w"
+	errFmtKubeDecodePods = "decode pod list: %w"
+)
+
+var (
+	// ErrNotInCluster is returned by InClusterKubeConfig outside a pod.
+	ErrNotInCluster = errors.New(errNotInClusterMsg)
+	// ErrKubeCA is returned when the service account CA holds no certificate.
+	ErrKubeCA = errors.New(errKubeCAMsg)
+	// ErrKubeStatus is returned when the API answers with an error status.
+	ErrKubeStatus = errors.New(errKubeStatusMsg)
+)
+
+// KubeConfig locates the Kubernetes API and selects the pods whose logs a
+// KubeCollector streams. Server is the API address, e.g. the
+// http://127.0.0.1:8001 of kubectl proxy; Token, when set, is sent as a
+// bearer token. An empty Namespace selects every namespace and an empty
+// LabelSelector, such as app=web, every pod. Client defaults to
+// http.DefaultClient.
+type KubeConfig struct {
+	Client        *http.Client
+	Server        string
+	Token         string
+	Namespace     string
+	LabelSelector string
+}
+
+// InClusterKubeConfig returns the configuration of a pod's service account,
+// limited to the pod's namespace, with labelSelector.
+func InClusterKubeConfig(labelSelector string) (KubeConfig, error) {
+	host := os.Getenv(kubernetesHostEnv)
+	if host == "" {
+		return KubeConfig{}, fmt.Errorf(errFmtKubeConfig, ErrNotInCluster)
+	}
+
+	token, err := os.ReadFile(filepath.Join(kubeAccountDir, kubeTokenFile))
+	if err != nil {
+		return KubeConfig{}, fmt.Errorf(errFmtKubeConfig, err)
+	}
+
+	namespace, err := os.ReadFile(filepath.Join(kubeAccountDir, kubeNamespaceFile))
+	if err != nil {
+		return KubeConfig{}, fmt.Errorf(errFmtKubeConfig, err)
+	}
+
+	client, err := kubeClient(filepath.Join(kubeAccountDir, kubeCAFile))
+	if err != nil {
+		return KubeConfig{}, fmt.Errorf(errFmtKubeConfig, err)
+	}
+
+	return KubeConfig{
+		Client:        client,
+		Server:        kubeHTTPSScheme + net.JoinHostPort(host, os.Getenv(kubeServicePortEnv)),
+		Token:         strings.TrimSpace(string(token)),
+		Namespace:     strings.TrimSpace(string(namespace)),
+		LabelSelector: labelSelector,
+	}, nil
+}
+
+// kubeClient returns a client trusting the CA certificates in caPath.
+func kubeClient(caPath string) (*http.Client, error) {
+	pem, err := os.ReadFile(caPath)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, ErrKubeCA
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+
+	return &http.Client{Transport: transport}, nil
+}
+
+// kubePodList is the subset of a pod list read by KubeCollector.
+type kubePodList struct {
+	Items []struct {
+		Metadata struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"metadata"`
+		Spec struct {
+			Containers []struct {
+				Name string `json:"name"`
+			} `json:"containers"`
+		} `json:"spec"`
+		Status struct {
+			Phase string `json:"phase"`
+		} `json:"status"`
+	} `json:"items"`
+}
+
+// kubeStream identifies the log stream of a container.
+type kubeStream struct {
+	namespace string
+	pod       string
+	container string
+}
+
+// KubeCollector streams the logs of the selected pods through a logger, for
+// development clusters where a full logging stack is overkill. Every entry
+// carries pod, namespace and container fields and the time Kubernetes
+// recorded for the line. Streams that end, for example when a container
+// restarts, are resumed by the next Collect after the last line seen.
+type KubeCollector struct {
+	ctx     context.Context
+	logger  *Logger
+	parser  *LineParser
+	cancel  context.CancelFunc
+	active  map[kubeStream]bool
+	last    map[kubeStream]time.Time
+	config  KubeConfig
+	errs    []error
+	wg      sync.WaitGroup
+	shipped atomic.Int64
+	mu      sync.Mutex
+}
+
+// KubeCollector returns a collector for the pods selected by config whose
+// lines are parsed with parser. Close stops its streams.
+func (l *Logger) KubeCollector(config KubeConfig, parser *LineParser) *KubeCollector {
+	if config.Client == nil {
+		config.Client = http.DefaultClient
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &KubeCollector{
+		ctx:     ctx,
+		logger:  l,
+		parser:  parser,
+		cancel:  cancel,
+		active:  make(map[kubeStream]bool),
+		last:    make(map[kubeStream]time.Time),
+		config:  config,
+		errs:    nil,
+		wg:      sync.WaitGroup{},
+		shipped: atomic.Int64{},
+		mu:      sync.Mutex{},
+	}
+}
+
+// Collect lists the running pods and starts streaming the containers that
+// are not streamed yet. It returns the number of bytes logged since the
+// last call and the errors of the pod list and of streams that failed.
+func (collector *KubeCollector) Collect() (int64, error) {
+	streams, err := collector.listStreams()
+	if err == nil {
+		collector.start(streams)
+	}
+
+	collector.mu.Lock()
+	errs := append(collector.errs, err)
+	collector.errs = nil
+	collector.mu.Unlock()
+
+	return collector.shipped.Swap(0), errors.Join(errs...)
+}
+
+// Close stops the streams and waits for them to end.
+func (collector *KubeCollector) Close() error {
+	collector.cancel()
+	collector.wg.Wait()
+
+	return nil
+}
+
+func (collector *KubeCollector) listStreams() ([]kubeStream, error) {
+	path := kubeAllPodsPath
+	if collector.config.Namespace != "" {
+		path = fmt.Sprintf(kubePodsPathFmt, url.PathEscape(collector.config.Namespace))
+	}
+
+	query := url.Values{}
+	if collector.config.LabelSelector != "" {
+		query.Set(kubeLabelSelector, collector.config.LabelSelector)
+	}
+
+	response, err := collector.get(path, query)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	var pods kubePodList
+
+	err = json.NewDecoder(response.Body).Decode(&pods)
+	if err != nil {
+		return nil, fmt.Errorf(errFmtKubeDecodePods, err)
+	}
+
+	var streams []kubeStream
+
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != kubePhaseRunning {
+			continue
+		}
+
+		for _, container := range pod.Spec.Containers {
+			streams = append(streams, kubeStream{
+				namespace: pod.Metadata.Namespace,
+				pod:       pod.Metadata.Name,
+				container: container.Name,
+			})
+		}
+	}
+
+	return streams, nil
+}
+
+func (collector *KubeCollector) start(streams []kubeStream) {
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+
+	for _, stream := range streams {
+		if collector.active[stream] || collector.ctx.Err() != nil {
+			continue
+		}
+
+		collector.active[stream] = true
+		since := collector.last[stream]
+
+		collector.wg.Go(func() {
+			err := collector.follow(stream, since)
+			collector.finish(stream, err)
+		})
+	}
+}
+
+func (collector *KubeCollector) finish(stream kubeStream, err error) {
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+
+	delete(collector.active, stream)
+
+	if err != nil && collector.ctx.Err() == nil {
+		collector.errs = append(collector.errs,
+			fmt.Errorf(errFmtKubeStream, stream.namespace, stream.pod, stream.container, err))
+	}
+}
+
+// follow logs the lines of stream written after since until the stream ends.
+func (collector *KubeCollector) follow(stream kubeStream, since time.Time) error {
+	query := url.Values{}
+	query.Set(kubeContainerParam, stream.container)
+	query.Set(kubeFollowParam, kubeTrue)
+	query.Set(kubeTimestampsParam, kubeTrue)
+
+	if !since.IsZero() {
+		query.Set(kubeSinceTimeParam, since.UTC().Format(time.RFC3339))
+	}
+
+	path := fmt.Sprintf(kubeLogPathFmt, url.PathEscape(stream.namespace), url.PathEscape(stream.pod))
+
+	response, err := collector.get(path, query)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	podLogger := collector.logger.With(
+		F(PodField, stream.pod),
+		F(NamespaceField, stream.namespace),
+		F(ContainerField, stream.container),
+	)
+
+	scanner := bufio.NewScanner(response.Body)
+	for scanner.Scan() {
+		collector.shipped.Add(int64(len(scanner.Bytes()) + 1))
+
+		stamp, line := splitKubeTimestamp(scanner.Text())
+		if !stamp.After(since) {
+			continue
+		}
+
+		since = stamp
+		collector.remember(stream, stamp)
+		podLogger.logParsed(collector.parser.Parse(line, stamp))
+	}
+
+	return scanner.Err()
+}
+
+func (collector *KubeCollector) remember(stream kubeStream, stamp time.Time) {
+	collector.mu.Lock()
+	collector.last[stream] = stamp
+	collector.mu.Unlock()
+}
+
+// splitKubeTimestamp splits the timestamp Kubernetes prefixes to lines. Lines
+// without one are stamped with the current time.
+func splitKubeTimestamp(line string) (time.Time, string) {
+	text, rest, found := strings.Cut(line, kubeTimestampSep)
+	if found {
+		stamp, err := time.Parse(time.RFC3339Nano, text)
+		if err == nil {
+			return stamp, rest
+		}
+	}
+
+	return time.Now(), line
+}
+
+func (collector *KubeCollector) get(path string, query url.Values) (*http.Response, error) {
+	target := strings.TrimSuffix(collector.config.Server, kubePathSep) + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	request, err := http.NewRequestWithContext(collector.ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, fmt.Errorf(errFmtKubeRequest, path, err)
+	}
+
+	if collector.config.Token != "" {
+		request.Header.Set(kubeAuthHeader, kubeBearerPrefix+collector.config.Token)
+	}
+
+	response, err := collector.config.Client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf(errFmtKubeRequest, path, err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		_ = response.Body.Close()
+
+		return nil, fmt.Errorf(errFmtKubeStatus, path, ErrKubeStatus, response.Status)
+	}
+
+	return response, nil
+}
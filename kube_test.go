@@ -0,0 +1,109 @@
+package logger_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	kubeNamespace = "dev"
+	kubeSelector  = "app=web"
+	kubeToken     = "secret-token"
+	kubePodsPath  = "/api/v1/namespaces/dev/pods"
+	kubeLogPath   = "/api/v1/namespaces/dev/pods/web-1/log"
+	kubePodList   = `{"items":[` +
+		`{"metadata":{"name":"web-1","namespace":"dev"},"spec":{"containers":[{"name":"app"}]},` +
+		`"status":{"phase":"Running"}},` +
+		`{"metadata":{"name":"web-0","namespace":"dev"},"spec":{"containers":[{"name":"app"}]},` +
+		`"status":{"phase":"Succeeded"}}]}`
+	kubePodLog = "2025-01-02T15:04:05.000000001Z ERROR: upstream timeout\n" +
+		"2025-01-02T15:04:06Z listening\n"
+	kubeLayout = "[{level}] {msg} {fields}"
+	kubeWant   = "[ERROR] upstream timeout pod=web-1 namespace=dev container=app\n" +
+		"[INFO] listening pod=web-1 namespace=dev container=app\n"
+	kubeWaitTimeout = 5 * time.Second
+	kubeWaitStep    = 10 * time.Millisecond
+	kubeCollectFmt  = "Collect: %v"
+	kubeOutputFmt   = "output:\n%s\nwant:\n%s"
+	kubeStatusFmt   = "Collect error = %v; want ErrKubeStatus"
+)
+
+func TestKubeCollector_StreamsSelectedPods(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+kubeToken {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		switch {
+		case r.URL.Path == kubePodsPath && r.URL.Query().Get("labelSelector") == kubeSelector:
+			_, _ = io.WriteString(w, kubePodList)
+		case r.URL.Path == kubeLogPath && r.URL.Query().Get("container") == "app":
+			_, _ = io.WriteString(w, kubePodLog)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	var buf syncBuffer
+
+	loggerInstance := logger.NewStreamLogger(&buf, logger.WithLayout(logger.MustParseLayout(kubeLayout)))
+	collector := loggerInstance.KubeCollector(logger.KubeConfig{
+		Client:        server.Client(),
+		Server:        server.URL,
+		Token:         kubeToken,
+		Namespace:     kubeNamespace,
+		LabelSelector: kubeSelector,
+	}, newLineParser(t, "", ""))
+
+	defer collector.Close()
+
+	_, err := collector.Collect()
+	if err != nil {
+		t.Fatalf(kubeCollectFmt, err)
+	}
+
+	deadline := time.Now().Add(kubeWaitTimeout)
+	for buf.String() != kubeWant && time.Now().Before(deadline) {
+		time.Sleep(kubeWaitStep)
+	}
+
+	if buf.String() != kubeWant {
+		t.Errorf(kubeOutputFmt, buf.String(), kubeWant)
+	}
+}
+
+func TestKubeCollector_ReportsAPIErrors(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	collector := logger.NewStreamLogger(&strings.Builder{}).KubeCollector(logger.KubeConfig{
+		Client:        server.Client(),
+		Server:        server.URL,
+		Token:         "",
+		Namespace:     "",
+		LabelSelector: "",
+	}, newLineParser(t, "", ""))
+
+	defer collector.Close()
+
+	_, err := collector.Collect()
+	if !errors.Is(err, logger.ErrKubeStatus) {
+		t.Errorf(kubeStatusFmt, err)
+	}
+}
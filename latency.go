@@ -0,0 +1,162 @@
+package logger
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// Sink names reported in Stats.WriteLatency for the regular outputs.
+	SinkStdout = "stdout"
+	SinkFile   = "file"
+
+	routeNameFmt         = "route-%d"
+	metricLatencyHelp    = "# HELP logger_write_latency_seconds Time from logging an entry to its write returning, per sink.\n"
+	metricLatencyType    = "# TYPE logger_write_latency_seconds histogram\n"
+	metricLatencyBucket  = "logger_write_latency_seconds_bucket{sink=%q,le=%q} %d\n"
+	metricLatencySum     = "logger_write_latency_seconds_sum{sink=%q} %s\n"
+	metricLatencyCount   = "logger_write_latency_seconds_count{sink=%q} %d\n"
+	metricInfBound       = "+Inf"
+	metricFloatFormat    = 'g'
+	metricFloatPrecision = -1
+	metricFloatBitSize   = 64
+)
+
+// latencyBounds are the upper bounds of the write latency buckets.
+var latencyBounds = [...]time.Duration{
+	100 * time.Microsecond,
+	250 * time.Microsecond,
+	500 * time.Microsecond,
+	time.Millisecond,
+	2500 * time.Microsecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+}
+
+// LatencyHistogram describes the write latencies of one sink: the time from
+// an entry being logged, before it is formatted, to the sink's write
+// returning. Buckets are cumulative, as in Prometheus; the last bucket has no
+// upper bound and counts every write.
+type LatencyHistogram struct {
+	Sink    string
+	Buckets []LatencyBucket
+	Count   uint64
+	Sum     time.Duration
+}
+
+// LatencyBucket counts the writes that took at most UpperBound. UpperBound is
+// zero for the unbounded last bucket.
+type LatencyBucket struct {
+	UpperBound time.Duration
+	Count      uint64
+}
+
+// latencyHistogram records the write latencies of one sink. It is guarded by
+// loggerCore.mu, which every write holds.
+type latencyHistogram struct {
+	counts [len(latencyBounds) + 1]uint64
+	sum    time.Duration
+}
+
+func (histogram *latencyHistogram) observe(latency time.Duration) {
+	index := len(latencyBounds)
+
+	for i, bound := range latencyBounds {
+		if latency <= bound {
+			index = i
+
+			break
+		}
+	}
+
+	histogram.counts[index]++
+	histogram.sum += latency
+}
+
+func (histogram *latencyHistogram) snapshot(sink string) LatencyHistogram {
+	buckets := make([]LatencyBucket, 0, len(histogram.counts))
+
+	var cumulative uint64
+
+	for i, count := range histogram.counts {
+		cumulative += count
+
+		var bound time.Duration
+		if i < len(latencyBounds) {
+			bound = latencyBounds[i]
+		}
+
+		buckets = append(buckets, LatencyBucket{UpperBound: bound, Count: cumulative})
+	}
+
+	return LatencyHistogram{Sink: sink, Buckets: buckets, Count: cumulative, Sum: histogram.sum}
+}
+
+// writeLatencies returns a snapshot of the histograms of every sink that
+// received an entry.
+func (c *loggerCore) writeLatencies() []LatencyHistogram {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var histograms []LatencyHistogram
+
+	add := func(sink string, histogram *latencyHistogram) {
+		snapshot := histogram.snapshot(sink)
+		if snapshot.Count > 0 {
+			histograms = append(histograms, snapshot)
+		}
+	}
+
+	add(SinkStdout, &c.stdLatency)
+	add(SinkFile, &c.fileLatency)
+
+	for _, target := range c.routes {
+		add(target.name, &target.latency)
+	}
+
+	return histograms
+}
+
+// routeName names a route in Stats: its Name, its Filename, or its position.
+func routeName(route *Route, index int) string {
+	if route.Name != "" {
+		return route.Name
+	}
+
+	if route.Filename != "" {
+		return route.Filename
+	}
+
+	return fmt.Sprintf(routeNameFmt, index+1)
+}
+
+func writeLatencyMetrics(builder *strings.Builder, histograms []LatencyHistogram) {
+	builder.WriteString(metricLatencyHelp)
+	builder.WriteString(metricLatencyType)
+
+	for _, histogram := range histograms {
+		for _, bucket := range histogram.Buckets {
+			bound := metricInfBound
+			if bucket.UpperBound > 0 {
+				bound = formatSeconds(bucket.UpperBound)
+			}
+
+			fmt.Fprintf(builder, metricLatencyBucket, histogram.Sink, bound, bucket.Count)
+		}
+
+		fmt.Fprintf(builder, metricLatencySum, histogram.Sink, formatSeconds(histogram.Sum))
+		fmt.Fprintf(builder, metricLatencyCount, histogram.Sink, histogram.Count)
+	}
+}
+
+func formatSeconds(duration time.Duration) string {
+	return strconv.FormatFloat(duration.Seconds(), metricFloatFormat, metricFloatPrecision, metricFloatBitSize)
+}
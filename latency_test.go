@@ -0,0 +1,64 @@
+package logger_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	latencyLogFile    = "latency.log"
+	latencyRouteName  = "audit-copy"
+	latencyEntries    = 3
+	latencyMsg        = "entry %d"
+	latencySinksFmt   = "expected sinks %v, got %+v"
+	latencyCountFmt   = "sink %s: expected %d writes, got %d (last bucket %d)"
+	latencyMetricsErr = "expected %q in metrics:\n%s"
+	latencyMetric     = `logger_write_latency_seconds_count{sink="audit-copy"} 3`
+	latencyInfBucket  = `logger_write_latency_seconds_bucket{sink="file",le="+Inf"} 3`
+)
+
+func TestStats_WriteLatencyPerSink(t *testing.T) {
+	t.Parallel()
+
+	var routed bytes.Buffer
+
+	loggerInstance, err := logger.New(t.TempDir(), latencyLogFile,
+		logger.WithRoute(logger.Route{Writer: &routed, Name: latencyRouteName}))
+	if err != nil {
+		t.Fatalf(newLoggerWithDirErrFmt, err)
+	}
+
+	defer closeTestLogger(t, loggerInstance)
+
+	for index := range latencyEntries {
+		loggerInstance.Infof(latencyMsg, index)
+	}
+
+	histograms := loggerInstance.Stats().WriteLatency
+	sinks := []string{logger.SinkStdout, logger.SinkFile, latencyRouteName}
+
+	if len(histograms) != len(sinks) {
+		t.Fatalf(latencySinksFmt, sinks, histograms)
+	}
+
+	for index, histogram := range histograms {
+		last := histogram.Buckets[len(histogram.Buckets)-1]
+		if histogram.Sink != sinks[index] || histogram.Count != latencyEntries || last.Count != latencyEntries {
+			t.Errorf(latencyCountFmt, histogram.Sink, latencyEntries, histogram.Count, last.Count)
+		}
+	}
+
+	recorder := httptest.NewRecorder()
+	loggerInstance.MetricsHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, statsMetricsPath, nil))
+
+	for _, metric := range []string{latencyMetric, latencyInfBucket} {
+		if !strings.Contains(recorder.Body.String(), metric) {
+			t.Errorf(latencyMetricsErr, metric, recorder.Body.String())
+		}
+	}
+}
@@ -0,0 +1,329 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultLayout is the line layout used when no layout option is given. It
+	// matches the historical "2006/01/02 15:04:05 [LEVEL] message" format.
+	DefaultLayout = "{time} [{level}] {msg} {fields}"
+	// ElapsedLayout is DefaultLayout with the time elapsed since the logger
+	// was created next to the wall-clock time.
+	ElapsedLayout = "{time} {elapsed} [{level}] {msg} {fields}"
+
+	// Layout tokens available in templates.
+	layoutTokenTime    = "time"
+	layoutTokenElapsed = "elapsed"
+	layoutTokenLevel   = "level"
+	layoutTokenCaller  = "caller"
+	layoutTokenMsg     = "msg"
+	layoutTokenFields  = "fields"
+
+	layoutOpenBrace         = '{'
+	layoutCloseBrace        = '}'
+	layoutDirectiveSep      = ":"
+	layoutAlignLeft         = '<'
+	layoutAlignRight        = '>'
+	layoutAlignCenter       = '^'
+	layoutPadding           = " "
+	layoutSpaces            = "                                "
+	layoutTrailingSpace     = " \t"
+	layoutTimeFormat        = "2006/01/02 15:04:05"
+	layoutCenterDivisor     = 2
+	layoutEscapedBraceWidth = 2
+	layoutElapsedFmt        = "%c%02d:%02d:%02d.%03d"
+	layoutElapsedAhead      = '+'
+	layoutElapsedBehind     = '-'
+
+	// Regular expressions matching rendered tokens, used by EntrySchema.
+	layoutPatternStart   = "^"
+	layoutPatternTime    = `\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2}`
+	layoutPatternElapsed = `[+-]\d{2,}:\d{2}:\d{2}\.\d{3}`
+	layoutPatternLevel   = `\S+`
+	layoutPatternCaller  = `\S*`
+	layoutPatternAny     = ".*"
+	layoutPatternSpaces  = " *"
+
+	errLayoutUnknownTokenMsg     = "unknown layout token"
+	errLayoutInvalidDirectiveMsg = "invalid layout directive"
+	errLayoutUnbalancedBraceMsg  = "unbalanced brace in layout"
+	errFmtLayoutToken            = "%w: %q"
+	errFmtLayoutPosition         = "%w at offset %d"
+)
+
+// Predefined errors for layout parsing.
+var (
+	ErrLayoutUnknownToken     = errors.New(errLayoutUnknownTokenMsg)
+	ErrLayoutInvalidDirective = errors.New(errLayoutInvalidDirectiveMsg)
+	ErrLayoutUnbalancedBrace  = errors.New(errLayoutUnbalancedBraceMsg)
+)
+
+// Layout is a parsed text line template. Templates contain literal text and
+// tokens in braces: {time}, {elapsed}, {level}, {caller}, {msg} and {fields}.
+// {elapsed} is the monotonic time since the logger was created, such as
+// +00:03:27.120, which lines up runs that started at different times. A token
+// may carry a padding directive after a colon: {level:<7} left-aligns the
+// value in a 7-character column, {level:>7} right-aligns it and {level:^7}
+// centers it. Literal braces are written as {{ and }}. Trailing whitespace is
+// trimmed from every rendered line so that an empty {fields} token leaves no
+// padding behind.
+type Layout struct {
+	segments    []layoutSegment
+	needsCaller bool
+}
+
+type layoutSegment struct {
+	literal string
+	token   string
+	width   int
+	align   byte
+}
+
+// ParseLayout parses a layout template. It returns an error describing the
+// first malformed token so site-specific formats fail fast at startup.
+func ParseLayout(template string) (*Layout, error) {
+	var (
+		segments []layoutSegment
+		literal  strings.Builder
+	)
+
+	flushLiteral := func() {
+		if literal.Len() > 0 {
+			segments = append(segments, layoutSegment{literal: literal.String()})
+			literal.Reset()
+		}
+	}
+
+	for pos := 0; pos < len(template); {
+		char := template[pos]
+		isEscaped := pos+1 < len(template) && template[pos+1] == char
+
+		switch {
+		case (char == layoutOpenBrace || char == layoutCloseBrace) && isEscaped:
+			literal.WriteByte(char)
+
+			pos += layoutEscapedBraceWidth
+		case char == layoutOpenBrace:
+			end := strings.IndexByte(template[pos:], layoutCloseBrace)
+			if end < 0 {
+				return nil, fmt.Errorf(errFmtLayoutPosition, ErrLayoutUnbalancedBrace, pos)
+			}
+
+			segment, err := parseLayoutToken(template[pos+1 : pos+end])
+			if err != nil {
+				return nil, err
+			}
+
+			flushLiteral()
+
+			segments = append(segments, segment)
+			pos += end + 1
+		case char == layoutCloseBrace:
+			return nil, fmt.Errorf(errFmtLayoutPosition, ErrLayoutUnbalancedBrace, pos)
+		default:
+			literal.WriteByte(char)
+
+			pos++
+		}
+	}
+
+	flushLiteral()
+
+	return &Layout{segments: segments, needsCaller: containsToken(segments, layoutTokenCaller)}, nil
+}
+
+// MustParseLayout is like ParseLayout but panics if the template is invalid.
+// It simplifies initialization of package-level layouts.
+func MustParseLayout(template string) *Layout {
+	layout, err := ParseLayout(template)
+	if err != nil {
+		panic(err)
+	}
+
+	return layout
+}
+
+func parseLayoutToken(spec string) (layoutSegment, error) {
+	name, directive, hasDirective := strings.Cut(spec, layoutDirectiveSep)
+
+	switch name {
+	case layoutTokenTime, layoutTokenElapsed, layoutTokenLevel, layoutTokenCaller,
+		layoutTokenMsg, layoutTokenFields:
+	default:
+		return layoutSegment{}, fmt.Errorf(errFmtLayoutToken, ErrLayoutUnknownToken, name)
+	}
+
+	segment := layoutSegment{token: name}
+	if !hasDirective {
+		return segment, nil
+	}
+
+	if directive == "" {
+		return layoutSegment{}, fmt.Errorf(errFmtLayoutToken, ErrLayoutInvalidDirective, spec)
+	}
+
+	switch directive[0] {
+	case layoutAlignLeft, layoutAlignRight, layoutAlignCenter:
+		segment.align = directive[0]
+	default:
+		return layoutSegment{}, fmt.Errorf(errFmtLayoutToken, ErrLayoutInvalidDirective, spec)
+	}
+
+	width, err := strconv.Atoi(directive[1:])
+	if err != nil || width <= 0 {
+		return layoutSegment{}, fmt.Errorf(errFmtLayoutToken, ErrLayoutInvalidDirective, spec)
+	}
+
+	segment.width = width
+
+	return segment, nil
+}
+
+func containsToken(segments []layoutSegment, token string) bool {
+	for _, segment := range segments {
+		if segment.token == token {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (layout *Layout) render(logEntry *Entry) string {
+	var builder strings.Builder
+
+	for _, segment := range layout.segments {
+		if segment.token == "" {
+			builder.WriteString(segment.literal)
+
+			continue
+		}
+
+		writePadded(&builder, layout.tokenValue(segment.token, logEntry), segment)
+	}
+
+	return strings.TrimRight(builder.String(), layoutTrailingSpace)
+}
+
+// pattern returns a regular expression matching the start of every line the
+// layout renders with timeFormat. Literal spaces are optional because tokens may render empty
+// and trailing whitespace is trimmed.
+func (layout *Layout) pattern(timeFormat TimeFormat) string {
+	var builder strings.Builder
+
+	builder.WriteString(layoutPatternStart)
+
+	for _, segment := range layout.segments {
+		if segment.token == "" {
+			for index, part := range strings.Split(segment.literal, layoutPadding) {
+				if index > 0 {
+					builder.WriteString(layoutPatternSpaces)
+				}
+
+				builder.WriteString(regexp.QuoteMeta(part))
+			}
+
+			continue
+		}
+
+		if segment.width > 0 {
+			builder.WriteString(layoutPatternSpaces)
+		}
+
+		builder.WriteString(tokenPattern(segment.token, timeFormat))
+
+		if segment.width > 0 {
+			builder.WriteString(layoutPatternSpaces)
+		}
+	}
+
+	return builder.String()
+}
+
+func tokenPattern(token string, timeFormat TimeFormat) string {
+	switch token {
+	case layoutTokenTime:
+		return timeFormat.pattern()
+	case layoutTokenElapsed:
+		return layoutPatternElapsed
+	case layoutTokenLevel:
+		return layoutPatternLevel
+	case layoutTokenCaller:
+		return layoutPatternCaller
+	default:
+		return layoutPatternAny
+	}
+}
+
+func (layout *Layout) tokenValue(token string, logEntry *Entry) string {
+	switch token {
+	case layoutTokenTime:
+		return logEntry.formattedTime()
+	case layoutTokenElapsed:
+		return formatElapsed(logEntry.elapsed)
+	case layoutTokenLevel:
+		return logEntry.Label
+	case layoutTokenCaller:
+		return logEntry.Caller
+	case layoutTokenMsg:
+		return logEntry.Message
+	default:
+		var builder strings.Builder
+		writeFields(&builder, logEntry.Fields)
+
+		return strings.TrimPrefix(builder.String(), fieldSeparator)
+	}
+}
+
+// formatElapsed renders elapsed as +HH:MM:SS.mmm. Entries ingested from
+// before the logger was created are behind it and render with a minus sign.
+func formatElapsed(elapsed time.Duration) string {
+	sign := layoutElapsedAhead
+	if elapsed < 0 {
+		sign, elapsed = layoutElapsedBehind, -elapsed
+	}
+
+	return fmt.Sprintf(layoutElapsedFmt, sign,
+		elapsed/time.Hour, elapsed%time.Hour/time.Minute,
+		elapsed%time.Minute/time.Second, elapsed%time.Second/time.Millisecond)
+}
+
+func writePadded(builder *strings.Builder, value string, segment layoutSegment) {
+	padding := segment.width - utf8.RuneCountInString(value)
+	if padding <= 0 {
+		builder.WriteString(value)
+
+		return
+	}
+
+	switch segment.align {
+	case layoutAlignRight:
+		writeSpaces(builder, padding)
+		builder.WriteString(value)
+	case layoutAlignCenter:
+		leftPadding := padding / layoutCenterDivisor
+		writeSpaces(builder, leftPadding)
+		builder.WriteString(value)
+		writeSpaces(builder, padding-leftPadding)
+	default:
+		builder.WriteString(value)
+		writeSpaces(builder, padding)
+	}
+}
+
+// writeSpaces writes count spaces from a preallocated run instead of
+// allocating padding for every entry.
+func writeSpaces(builder *strings.Builder, count int) {
+	for count > 0 {
+		chunk := min(count, len(layoutSpaces))
+		builder.WriteString(layoutSpaces[:chunk])
+		count -= chunk
+	}
+}
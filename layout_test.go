@@ -0,0 +1,105 @@
+package logger_test
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	layoutPaddedTemplate  = "{level:<7}|{level:>7}|{level:^7}| {msg} {fields}"
+	layoutPaddedExpected  = "WARN   |   WARN| WARN  | disk low\n"
+	layoutCallerTemplate  = "{caller} {msg}"
+	layoutCallerExpected  = "layout_test.go:"
+	layoutEscapedTemplate = "{{{level}}} {msg}"
+	layoutEscapedExpected = "{INFO} escaped\n"
+	layoutMsgDiskLow      = "disk low"
+	layoutMsgEscaped      = "escaped"
+	layoutMsgCaller       = "caller"
+	layoutElapsedTemplate = "{elapsed} {msg}"
+	layoutElapsedPattern  = `^\+00:00:0\d\.\d{3} elapsed\n$`
+	layoutMsgElapsed      = "elapsed"
+	parseLayoutErrFmt     = "ParseLayout(%q): %v"
+	layoutOutputErrFmt    = "expected %q, got %q"
+)
+
+func newLayoutLogger(t *testing.T, template string) (*logger.Logger, *bytes.Buffer) {
+	t.Helper()
+
+	layout, err := logger.ParseLayout(template)
+	if err != nil {
+		t.Fatalf(parseLayoutErrFmt, template, err)
+	}
+
+	var buf bytes.Buffer
+
+	return logger.NewStreamLogger(&buf, logger.WithLayout(layout)), &buf
+}
+
+func TestLayout_PaddingDirectives(t *testing.T) {
+	t.Parallel()
+
+	loggerInstance, buf := newLayoutLogger(t, layoutPaddedTemplate)
+	loggerInstance.Warnf(layoutMsgDiskLow)
+
+	if buf.String() != layoutPaddedExpected {
+		t.Errorf(layoutOutputErrFmt, layoutPaddedExpected, buf.String())
+	}
+}
+
+func TestLayout_EscapedBraces(t *testing.T) {
+	t.Parallel()
+
+	loggerInstance, buf := newLayoutLogger(t, layoutEscapedTemplate)
+	loggerInstance.Infof(layoutMsgEscaped)
+
+	if buf.String() != layoutEscapedExpected {
+		t.Errorf(layoutOutputErrFmt, layoutEscapedExpected, buf.String())
+	}
+}
+
+func TestLayout_CallerToken(t *testing.T) {
+	t.Parallel()
+
+	loggerInstance, buf := newLayoutLogger(t, layoutCallerTemplate)
+	loggerInstance.Infof(layoutMsgCaller)
+
+	if !strings.HasPrefix(buf.String(), layoutCallerExpected) {
+		t.Errorf(layoutOutputErrFmt, layoutCallerExpected, buf.String())
+	}
+}
+
+func TestLayout_ElapsedToken(t *testing.T) {
+	t.Parallel()
+
+	loggerInstance, buf := newLayoutLogger(t, layoutElapsedTemplate)
+	loggerInstance.Infof(layoutMsgElapsed)
+
+	if !regexp.MustCompile(layoutElapsedPattern).MatchString(buf.String()) {
+		t.Errorf(layoutOutputErrFmt, layoutElapsedPattern, buf.String())
+	}
+}
+
+func TestParseLayout_Errors(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]error{
+		"{unknown}":    logger.ErrLayoutUnknownToken,
+		"{level:7}":    logger.ErrLayoutInvalidDirective,
+		"{level:<}":    logger.ErrLayoutInvalidDirective,
+		"{level:<-1}":  logger.ErrLayoutInvalidDirective,
+		"{level":       logger.ErrLayoutUnbalancedBrace,
+		"level} {msg}": logger.ErrLayoutUnbalancedBrace,
+	}
+
+	for template, want := range cases {
+		_, err := logger.ParseLayout(template)
+		if !errors.Is(err, want) {
+			t.Errorf(parseLayoutErrFmt, template, err)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// lazyFile is the main log file deferred by WithLazyFile.
+type lazyFile struct {
+	logDir   string
+	filename string
+}
+
+// WithLazyFile defers creating the log directory and opening the log file
+// until the first entry is written to it, so that tools which create a
+// logger just in case leave no empty log files behind. Entries below the
+// minimum level never open the file. The write-ahead log and audit mode read
+// the log file at construction and disable lazy opening.
+func WithLazyFile() Option {
+	return func(config *options) {
+		config.lazyFile = true
+	}
+}
+
+// newLazyFile creates a logger for WithLazyFile. logDir and filename have
+// been validated.
+func newLazyFile(logDir, filename string, config *options) (*Logger, error) {
+	err := validateLogPath(logDir, filepath.Join(logDir, filename))
+	if err != nil {
+		return nil, err
+	}
+
+	loggerInstance := newLogger(os.Stdout, config)
+	loggerInstance.core.lazy = &lazyFile{logDir: logDir, filename: filename}
+
+	err = loggerInstance.core.openDirSinks(logDir, config)
+	if err != nil {
+		_ = loggerInstance.Close()
+
+		return nil, err
+	}
+
+	loggerInstance.core.startBackground(config)
+
+	return loggerInstance, nil
+}
+
+// openLazyFile opens the deferred log file. A failure is retried with the
+// next entry. The caller holds c.mu.
+func (c *loggerCore) openLazyFile() error {
+	logPath, err := setupLogDirectory(c.lazy.logDir, c.lazy.filename)
+	if err != nil {
+		return fmt.Errorf(errFmtWrapCause, ErrWriteLogFile, err)
+	}
+
+	f, err := openLogFile(logPath)
+	if err != nil {
+		return fmt.Errorf(errFmtWrapCause, ErrWriteLogFile, err)
+	}
+
+	c.logFile = f
+	c.file = newFileLineWriter(f, logPath, c.disk, c.vectored)
+	c.lazy = nil
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package logger_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	lazyLogFile    = "lazy.log"
+	lazyLogSubdir  = "lazy"
+	lazyMessage    = "first entry"
+	lazyNewFmt     = "New: %v"
+	lazyCloseFmt   = "close: %v"
+	lazyReadFmt    = "read log file: %v"
+	lazyStatFmt    = "stat %s: %v, want not exist"
+	lazyMissingFmt = "log file missing %q; got %q"
+)
+
+func TestWithLazyFile_NoEntriesNoFile(t *testing.T) {
+	t.Parallel()
+
+	logDir := filepath.Join(t.TempDir(), lazyLogSubdir)
+	loggerInstance := newLazyLogger(t, logDir)
+	loggerInstance.Debugf(lazyMessage)
+	closeLazyLogger(t, loggerInstance)
+
+	_, err := os.Stat(logDir)
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf(lazyStatFmt, logDir, err)
+	}
+}
+
+func TestWithLazyFile_OpensOnFirstEntry(t *testing.T) {
+	t.Parallel()
+
+	logDir := filepath.Join(t.TempDir(), lazyLogSubdir)
+	loggerInstance := newLazyLogger(t, logDir)
+
+	_, err := os.Stat(logDir)
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf(lazyStatFmt, logDir, err)
+	}
+
+	loggerInstance.Infof(lazyMessage)
+	closeLazyLogger(t, loggerInstance)
+
+	data, err := os.ReadFile(filepath.Join(logDir, lazyLogFile))
+	if err != nil {
+		t.Fatalf(lazyReadFmt, err)
+	}
+
+	if !strings.Contains(string(data), lazyMessage) {
+		t.Errorf(lazyMissingFmt, lazyMessage, data)
+	}
+}
+
+func newLazyLogger(t *testing.T, logDir string) *logger.Logger {
+	t.Helper()
+
+	loggerInstance, err := logger.New(logDir, lazyLogFile, logger.WithLazyFile())
+	if err != nil {
+		t.Fatalf(lazyNewFmt, err)
+	}
+
+	return loggerInstance
+}
+
+func closeLazyLogger(t *testing.T, loggerInstance *logger.Logger) {
+	t.Helper()
+
+	err := loggerInstance.Close()
+	if err != nil {
+		t.Errorf(lazyCloseFmt, err)
+	}
+}
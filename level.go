@@ -0,0 +1,226 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Level is the severity of a log entry. Higher values are more severe.
+type Level int
+
+// Built-in levels. The numeric gaps leave room for levels registered by
+// applications. SYSTEM sits between WARN and ERROR so that lifecycle events
+// survive a WARN threshold. DEBUG sits below INFO and is discarded unless
+// debug logging is enabled. SECURITY sits above ERROR; its entries are
+// delivered durably, see Securityf.
+const (
+	LevelDebug    Level = -4
+	LevelInfo     Level = 0
+	LevelSuccess  Level = 2
+	LevelWarn     Level = 4
+	LevelSystem   Level = 6
+	LevelError    Level = 8
+	LevelSecurity Level = 11
+	LevelPanic    Level = 12
+	LevelFatal    Level = 16
+)
+
+const (
+	levelUnknownPrefix = "LEVEL("
+	// levelNameInvalidChars lists characters that would make a level label
+	// ambiguous in text output or in the daemon's LEVEL:MESSAGE protocol.
+	levelNameInvalidChars = " \t\r\n[]:="
+
+	errLevelNameEmptyMsg   = "level name cannot be empty"
+	errLevelNameInvalidMsg = "level name contains invalid characters"
+	errLevelExistsMsg      = "level already registered"
+	errUnknownLevelMsg     = "unknown level"
+	errFmtLevelName        = "%w: %q"
+	errFmtLevelSeverity    = "%w: severity %d is used by %s"
+)
+
+// Predefined errors for level registration and parsing.
+var (
+	ErrLevelNameEmpty   = errors.New(errLevelNameEmptyMsg)
+	ErrLevelNameInvalid = errors.New(errLevelNameInvalidMsg)
+	ErrLevelExists      = errors.New(errLevelExistsMsg)
+	ErrUnknownLevel     = errors.New(errUnknownLevelMsg)
+)
+
+// levelRegistry holds the built-in levels and every level registered with
+// RegisterLevel, indexed both ways.
+var levelRegistry = struct {
+	names  map[Level]string
+	byName map[string]Level
+	mu     sync.RWMutex
+}{
+	names: map[Level]string{
+		LevelDebug:    logLevelDebug,
+		LevelInfo:     logLevelInfo,
+		LevelSuccess:  logLevelSuccess,
+		LevelWarn:     logLevelWarn,
+		LevelSystem:   logLevelSystem,
+		LevelError:    logLevelError,
+		LevelSecurity: logLevelSecurity,
+		LevelPanic:    logLevelPanic,
+		LevelFatal:    logLevelFatal,
+	},
+	byName: map[string]Level{
+		logLevelDebug:    LevelDebug,
+		logLevelInfo:     LevelInfo,
+		logLevelSuccess:  LevelSuccess,
+		logLevelWarn:     LevelWarn,
+		logLevelSystem:   LevelSystem,
+		logLevelError:    LevelError,
+		logLevelSecurity: LevelSecurity,
+		logLevelPanic:    LevelPanic,
+		logLevelFatal:    LevelFatal,
+	},
+	mu: sync.RWMutex{},
+}
+
+// RegisterLevel defines an application-specific level such as AUDIT, SECURITY
+// or BILLING. The severity orders the level relative to the built-in ones, so
+// registered levels participate in filtering, routing and encoding exactly
+// like the built-in levels. Names are case-insensitive and stored upper-case;
+// both the name and the severity must be unused.
+func RegisterLevel(name string, severity int) (Level, error) {
+	name = strings.ToUpper(strings.TrimSpace(name))
+
+	err := validateLevelName(name)
+	if err != nil {
+		return 0, err
+	}
+
+	level := Level(severity)
+
+	levelRegistry.mu.Lock()
+	defer levelRegistry.mu.Unlock()
+
+	if _, exists := levelRegistry.byName[name]; exists {
+		return 0, fmt.Errorf(errFmtLevelName, ErrLevelExists, name)
+	}
+
+	if existing, exists := levelRegistry.names[level]; exists {
+		return 0, fmt.Errorf(errFmtLevelSeverity, ErrLevelExists, severity, existing)
+	}
+
+	levelRegistry.names[level] = name
+	levelRegistry.byName[name] = level
+
+	return level, nil
+}
+
+// ParseLevel returns the built-in or registered level with the given name.
+// Matching is case-insensitive.
+func ParseLevel(name string) (Level, error) {
+	levelRegistry.mu.RLock()
+	defer levelRegistry.mu.RUnlock()
+
+	level, exists := levelRegistry.byName[strings.ToUpper(strings.TrimSpace(name))]
+	if !exists {
+		return 0, fmt.Errorf(errFmtLevelName, ErrUnknownLevel, name)
+	}
+
+	return level, nil
+}
+
+func validateLevelName(name string) error {
+	if name == "" {
+		return ErrLevelNameEmpty
+	}
+
+	if strings.ContainsAny(name, levelNameInvalidChars) {
+		return fmt.Errorf(errFmtLevelName, ErrLevelNameInvalid, name)
+	}
+
+	return nil
+}
+
+// String returns the default label of the level.
+func (level Level) String() string {
+	levelRegistry.mu.RLock()
+	defer levelRegistry.mu.RUnlock()
+
+	name, exists := levelRegistry.names[level]
+	if !exists {
+		return levelUnknownPrefix + strconv.Itoa(int(level)) + ")"
+	}
+
+	return name
+}
+
+// WithLevelLabels overrides the printed label of individual levels, for
+// example WARNING instead of WARN or localized names. Levels missing from the
+// map keep their default label.
+func WithLevelLabels(labels map[Level]string) Option {
+	return func(config *options) {
+		if config.levelLabels == nil {
+			config.levelLabels = make(map[Level]string, len(labels))
+		}
+
+		maps.Copy(config.levelLabels, labels)
+	}
+}
+
+// WithMinLevel discards entries below level, for example INFO in production
+// where only WARN and above are kept. The default is LevelInfo. Mandatory
+// entries are written at every minimum level.
+func WithMinLevel(level Level) Option {
+	return func(config *options) {
+		config.minLevel = level
+	}
+}
+
+// SetLevel changes the minimum level at runtime, for the logger and its child
+// loggers, and ends a debug period started by EnableDebugFor. Entries below
+// level are discarded by a single atomic load before their message is
+// formatted.
+func (l *Logger) SetLevel(level Level) {
+	c := l.core
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stopDebugTimer()
+	c.debugGen++ // a restore already waiting for mu is stale
+	c.setMinLevel(level)
+}
+
+// GetLevel returns the minimum level, LevelDebug during a debug period.
+func (l *Logger) GetLevel() Level {
+	return l.core.minimumLevel()
+}
+
+// Logf logs a message at the given level. It is primarily used with levels
+// created by RegisterLevel; the built-in levels have dedicated methods.
+func (l *Logger) Logf(level Level, format string, args ...any) {
+	l.writef(level, nil, format, args...)
+}
+
+// levelLabelTable returns the label of every level known at construction,
+// with overrides applied, so that label needs neither the registry lock nor a
+// second lookup on the hot path.
+func levelLabelTable(overrides map[Level]string) map[Level]string {
+	levelRegistry.mu.RLock()
+	labels := maps.Clone(levelRegistry.names)
+	levelRegistry.mu.RUnlock()
+
+	maps.Copy(labels, overrides)
+
+	return labels
+}
+
+// label returns the configured label for level. Levels registered after the
+// logger was created fall back to the registry.
+func (c *loggerCore) label(level Level) string {
+	if label, exists := c.levelLabels[level]; exists {
+		return label
+	}
+
+	return level.String()
+}
@@ -0,0 +1,211 @@
+package logger_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	levelLabelsTemplate = "[{level}] {msg}"
+	levelLabelsMsg      = "disk almost full"
+	levelLabelsExpected = "[WARNING] disk almost full\n[ERREUR] disk almost full\n" +
+		"[INFO] disk almost full\n"
+	levelStringErrFmt   = "Level(%d).String() = %q, want %q"
+	registerLevelErrFmt = "RegisterLevel: %v"
+	parseLevelErrFmt    = "ParseLevel: got %v, %v"
+	customLevelMsg      = "user %s exported report"
+	customLevelUser     = "alice"
+	customLevelExpected = "[AUDIT] user alice exported report\n"
+	lateLevelName       = "late"
+	lateLevelSeverity   = 13
+	lateLevelMsg        = "registered after the logger"
+	lateLevelExpected   = "[LATE] registered after the logger\n"
+	minLevelExpected    = "[WARN] disk almost full\n"
+	setLevelErrFmt      = "GetLevel() = %v, want %v"
+	setLevelFormatFmt   = "%v"
+	setLevelFormatted   = "discarded argument formatted %d times"
+)
+
+// countingStringer counts how often it is formatted.
+type countingStringer struct {
+	calls *int
+}
+
+func (stringer countingStringer) String() string {
+	*stringer.calls++
+
+	return levelLabelsMsg
+}
+
+func TestLogger_WithLevelLabels(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	loggerInstance := logger.NewStreamLogger(
+		&buf,
+		logger.WithLayout(logger.MustParseLayout(levelLabelsTemplate)),
+		logger.WithLevelLabels(map[logger.Level]string{
+			logger.LevelWarn:  "WARNING",
+			logger.LevelError: "ERREUR",
+		}),
+	)
+	loggerInstance.Warnf(levelLabelsMsg)
+	loggerInstance.Errorf(levelLabelsMsg)
+	loggerInstance.Infof(levelLabelsMsg)
+
+	if buf.String() != levelLabelsExpected {
+		t.Errorf(layoutOutputErrFmt, levelLabelsExpected, buf.String())
+	}
+}
+
+func TestRegisterLevel_AfterLoggerCreated(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	loggerInstance := logger.NewStreamLogger(
+		&buf,
+		logger.WithLayout(logger.MustParseLayout(levelLabelsTemplate)),
+	)
+
+	late, err := logger.RegisterLevel(lateLevelName, lateLevelSeverity)
+	if err != nil {
+		t.Fatalf(registerLevelErrFmt, err)
+	}
+
+	loggerInstance.Logf(late, lateLevelMsg)
+
+	if buf.String() != lateLevelExpected {
+		t.Errorf(layoutOutputErrFmt, lateLevelExpected, buf.String())
+	}
+}
+
+func TestLevel_String(t *testing.T) {
+	t.Parallel()
+
+	cases := map[logger.Level]string{
+		logger.LevelInfo:    "INFO",
+		logger.LevelSuccess: "SUCCESS",
+		logger.LevelWarn:    "WARN",
+		logger.LevelSystem:  "SYSTEM",
+		logger.LevelError:   "ERROR",
+		logger.LevelPanic:   "PANIC",
+		logger.LevelFatal:   "FATAL",
+		logger.Level(99):    "LEVEL(99)",
+	}
+
+	for level, want := range cases {
+		if got := level.String(); got != want {
+			t.Errorf(levelStringErrFmt, int(level), got, want)
+		}
+	}
+}
+
+func TestRegisterLevel_CustomLevel(t *testing.T) {
+	t.Parallel()
+
+	audit, err := logger.RegisterLevel("audit", 10)
+	if err != nil {
+		t.Fatalf(registerLevelErrFmt, err)
+	}
+
+	parsed, err := logger.ParseLevel("Audit")
+	if err != nil || parsed != audit {
+		t.Fatalf(parseLevelErrFmt, parsed, err)
+	}
+
+	var buf bytes.Buffer
+
+	loggerInstance := logger.NewStreamLogger(
+		&buf,
+		logger.WithLayout(logger.MustParseLayout(levelLabelsTemplate)),
+	)
+	loggerInstance.Logf(audit, customLevelMsg, customLevelUser)
+
+	if buf.String() != customLevelExpected {
+		t.Errorf(layoutOutputErrFmt, customLevelExpected, buf.String())
+	}
+}
+
+func TestRegisterLevel_Errors(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		want     error
+		name     string
+		severity int
+	}{
+		{want: logger.ErrLevelNameEmpty, name: " ", severity: 30},
+		{want: logger.ErrLevelNameInvalid, name: "BAD LEVEL", severity: 31},
+		{want: logger.ErrLevelExists, name: "warn", severity: 32},
+		{want: logger.ErrLevelExists, name: "SECURITY", severity: int(logger.LevelError)},
+	}
+
+	for _, testCase := range cases {
+		_, err := logger.RegisterLevel(testCase.name, testCase.severity)
+		if !errors.Is(err, testCase.want) {
+			t.Errorf(expectedErrFmt, testCase.want, err)
+		}
+	}
+}
+
+func TestParseLevel_Unknown(t *testing.T) {
+	t.Parallel()
+
+	_, err := logger.ParseLevel("verbose")
+	if !errors.Is(err, logger.ErrUnknownLevel) {
+		t.Errorf(expectedErrFmt, logger.ErrUnknownLevel, err)
+	}
+}
+
+func TestLogger_SetLevel(t *testing.T) {
+	t.Parallel()
+
+	var (
+		buf   bytes.Buffer
+		calls int
+	)
+
+	loggerInstance := logger.NewStreamLogger(&buf, logger.WithLayout(logger.MustParseLayout(levelLabelsTemplate)))
+	child := loggerInstance.With(logger.F(customLevelUser, true))
+
+	loggerInstance.SetLevel(logger.LevelWarn)
+
+	if level := child.GetLevel(); level != logger.LevelWarn {
+		t.Errorf(setLevelErrFmt, level, logger.LevelWarn)
+	}
+
+	child.Infof(setLevelFormatFmt, countingStringer{calls: &calls})
+	loggerInstance.Warnf(levelLabelsMsg)
+	closeTestLogger(t, loggerInstance)
+
+	if buf.String() != minLevelExpected {
+		t.Errorf(layoutOutputErrFmt, minLevelExpected, buf.String())
+	}
+
+	if calls != 0 {
+		t.Errorf(setLevelFormatted, calls)
+	}
+}
+
+func TestWithMinLevel(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	loggerInstance := logger.NewStreamLogger(&buf,
+		logger.WithLayout(logger.MustParseLayout(levelLabelsTemplate)),
+		logger.WithMinLevel(logger.LevelWarn),
+	)
+	loggerInstance.Infof(levelLabelsMsg)
+	loggerInstance.Warnf(levelLabelsMsg)
+	closeTestLogger(t, loggerInstance)
+
+	if buf.String() != minLevelExpected {
+		t.Errorf(layoutOutputErrFmt, minLevelExpected, buf.String())
+	}
+}
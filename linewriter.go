@@ -0,0 +1,70 @@
+package logger
+
+import "io"
+
+const lineEnd = '\n'
+
+// lineWriter writes one line per call with a single Write, appending the line
+// end when missing. It replaces log.Logger, whose prefix, flags and locking the
+// logger never used: timestamps come from the layout and writes hold
+// loggerCore.mu. While buffered is set, lines are kept in buf until flush, so
+// that a batch of entries costs one Write, or in lines for one vectored
+// write with WithVectoredWrites.
+type lineWriter struct {
+	writer   io.Writer
+	vectors  *vectorFile // set with WithVectoredWrites
+	buf      []byte
+	lines    [][]byte
+	buffered bool
+}
+
+func newLineWriter(writer io.Writer) *lineWriter {
+	return &lineWriter{writer: writer, vectors: nil, buf: nil, lines: nil, buffered: false}
+}
+
+// writeLine writes line, or buffers it until flush. The caller holds
+// loggerCore.mu, which guards buf and lines.
+func (lw *lineWriter) writeLine(line string) error {
+	if lw.buffered && lw.vectors != nil {
+		lw.bufferVector(line)
+
+		return nil
+	}
+
+	lw.buf = append(lw.buf, line...)
+	if len(line) == 0 || line[len(line)-1] != lineEnd {
+		lw.buf = append(lw.buf, lineEnd)
+	}
+
+	if lw.buffered {
+		return nil
+	}
+
+	return lw.flush()
+}
+
+// flush writes the buffered lines. The caller holds loggerCore.mu.
+func (lw *lineWriter) flush() error {
+	if len(lw.lines) > 0 {
+		return lw.flushVectors()
+	}
+
+	if len(lw.buf) == 0 {
+		return nil
+	}
+
+	_, err := lw.writer.Write(lw.buf)
+	lw.buf = lw.buf[:0]
+
+	return err
+}
+
+// formattedTime returns Time in the logger's time format, formatting it once
+// per entry however many sinks render it.
+func (logEntry *Entry) formattedTime() string {
+	if logEntry.timeText == "" {
+		logEntry.timeText = logEntry.timeFormat.format(logEntry.Time)
+	}
+
+	return logEntry.timeText
+}
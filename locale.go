@@ -0,0 +1,142 @@
+package logger
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	localeGroupSize    = 3
+	localeDecimalPoint = "."
+	localeMinus        = "-"
+	localeFloatFormat  = 'f'
+	localeFloat32Bits  = 32
+	localeFloat64Bits  = 64
+	localeIntBase      = 10
+)
+
+// Locale selects how WithStdoutLocale renders times and numbers. TimeLayout
+// is a reference layout as for time.Format, such as "02.01.2006 15:04:05";
+// empty keeps the logger's TimeFormat. ThousandsSeparator groups the digits
+// of integer parts by three; DecimalSeparator replaces the decimal point.
+type Locale struct {
+	TimeLayout         string
+	ThousandsSeparator string
+	DecimalSeparator   string
+}
+
+// Locales for common operations teams.
+var (
+	LocaleUS     = Locale{TimeLayout: "01/02/2006 3:04:05 PM", ThousandsSeparator: ",", DecimalSeparator: "."}
+	LocaleUK     = Locale{TimeLayout: "02/01/2006 15:04:05", ThousandsSeparator: ",", DecimalSeparator: "."}
+	LocaleGerman = Locale{TimeLayout: "02.01.2006 15:04:05", ThousandsSeparator: ".", DecimalSeparator: ","}
+	LocaleFrench = Locale{TimeLayout: "02/01/2006 15:04:05", ThousandsSeparator: " ", DecimalSeparator: ","}
+)
+
+// WithStdoutLocale renders the times and numeric field values of text lines
+// on stdout, the sink read by people, in locale, for example with thousand
+// separators and the local date order. The log file, routes and JSON output
+// stay canonical for machines, and messages are rendered as formatted by the
+// caller.
+func WithStdoutLocale(locale Locale) Option {
+	return func(config *options) {
+		config.stdoutLocale = &locale
+	}
+}
+
+// localize returns a copy of logEntry with its time and numeric fields
+// rendered in locale. The copy shares the entry's other values.
+func (locale *Locale) localize(logEntry *Entry) *Entry {
+	localized := *logEntry
+
+	if locale.TimeLayout != "" {
+		localized.timeText = localized.Time.Format(locale.TimeLayout)
+	}
+
+	if len(logEntry.Fields) > 0 {
+		localized.Fields = make([]Field, len(logEntry.Fields))
+		for index, field := range logEntry.Fields {
+			localized.Fields[index] = Field{Key: field.Key, Value: locale.value(field.Value)}
+		}
+	}
+
+	return &localized
+}
+
+// value renders integers and floats in locale and returns other values,
+// including numeric types with a String method, unchanged.
+func (locale *Locale) value(value any) any {
+	_, isStringer := value.(fmt.Stringer)
+	if isStringer {
+		return value
+	}
+
+	number := reflect.ValueOf(value)
+
+	switch number.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return locale.integer(strconv.FormatInt(number.Int(), localeIntBase))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return locale.integer(strconv.FormatUint(number.Uint(), localeIntBase))
+	case reflect.Float32:
+		return locale.float(strconv.FormatFloat(number.Float(), localeFloatFormat, -1, localeFloat32Bits))
+	case reflect.Float64:
+		return locale.float(strconv.FormatFloat(number.Float(), localeFloatFormat, -1, localeFloat64Bits))
+	default:
+		return value
+	}
+}
+
+// integer groups the digits of text, a canonical integer.
+func (locale *Locale) integer(text string) string {
+	digits := strings.TrimPrefix(text, localeMinus)
+	if locale.ThousandsSeparator == "" || len(digits) <= localeGroupSize {
+		return text
+	}
+
+	var builder strings.Builder
+
+	if len(digits) < len(text) {
+		builder.WriteString(localeMinus)
+	}
+
+	head := len(digits) % localeGroupSize
+	if head == 0 {
+		head = localeGroupSize
+	}
+
+	builder.WriteString(digits[:head])
+
+	for start := head; start < len(digits); start += localeGroupSize {
+		builder.WriteString(locale.ThousandsSeparator)
+		builder.WriteString(digits[start : start+localeGroupSize])
+	}
+
+	return builder.String()
+}
+
+// float groups the integer part of text, a canonical decimal number, and
+// replaces its decimal point.
+func (locale *Locale) float(text string) string {
+	integer, fraction, found := strings.Cut(text, localeDecimalPoint)
+
+	localized := locale.integer(integer)
+	if found {
+		localized += cmp.Or(locale.DecimalSeparator, localeDecimalPoint) + fraction
+	}
+
+	return localized
+}
+
+// inLocale returns logEntry localized for stdout when a locale is set and
+// the entry is rendered as text.
+func (c *loggerCore) inLocale(logEntry *Entry) *Entry {
+	if c.stdLocale == nil || c.encoding != EncodingText {
+		return logEntry
+	}
+
+	return c.stdLocale.localize(logEntry)
+}
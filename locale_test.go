@@ -0,0 +1,82 @@
+package logger_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	localeMsg       = "converted"
+	localeRoute     = "machine"
+	localeLineFmt   = "%s line = %q; want %q"
+	localeStdout    = "02.01.2025 15:04:05 [INFO] converted pages=1.234.567 ratio=-1.234,5 small=999 size=1.5MiB"
+	localeCanonical = "[INFO] converted pages=1234567 ratio=-1234.5 small=999 size=1.5MiB"
+	localeValueFmt  = "%v in %+v = %q; want %q"
+)
+
+var localeTime = time.Date(2025, time.January, 2, 15, 4, 5, 0, time.UTC)
+
+func TestWithStdoutLocale(t *testing.T) {
+	t.Parallel()
+
+	var stdout, route bytes.Buffer
+
+	loggerInstance := logger.NewStreamLogger(&stdout,
+		logger.WithClock(func() time.Time { return localeTime }),
+		logger.WithTimezone(time.UTC),
+		logger.WithStdoutLocale(logger.LocaleGerman),
+		logger.WithRoute(logger.Route{Name: localeRoute, Writer: &route}),
+	)
+	loggerInstance.With(
+		logger.F("pages", 1234567),
+		logger.F("ratio", -1234.5),
+		logger.F("small", uint16(999)),
+		logger.Bytes("size", 1536*1024),
+	).Infof(localeMsg)
+	closeTestLogger(t, loggerInstance)
+
+	stdoutLine := strings.TrimSpace(stdout.String())
+	if stdoutLine != localeStdout {
+		t.Errorf(localeLineFmt, "stdout", stdoutLine, localeStdout)
+	}
+
+	routeLine := strings.TrimSpace(route.String())
+	if !strings.HasSuffix(routeLine, localeCanonical) || strings.HasPrefix(routeLine, "02.01.2025") {
+		t.Errorf(localeLineFmt, localeRoute, routeLine, localeCanonical)
+	}
+}
+
+func TestLocale_Numbers(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		value  any
+		want   string
+		locale logger.Locale
+	}{
+		{value: 1000, want: "1,000", locale: logger.LocaleUS},
+		{value: int64(-12345678), want: "-12,345,678", locale: logger.LocaleUK},
+		{value: 0.25, want: "0,25", locale: logger.LocaleGerman},
+		{value: float32(98765.5), want: "98\u202f765,5", locale: logger.LocaleFrench},
+		{value: uint64(100), want: "100", locale: logger.LocaleFrench},
+		{value: "12345", want: "12345", locale: logger.LocaleGerman},
+		{value: 2 * time.Second, want: "2s", locale: logger.LocaleGerman},
+	}
+
+	for _, testCase := range testCases {
+		var buf bytes.Buffer
+
+		loggerInstance := logger.NewStreamLogger(&buf, logger.WithStdoutLocale(testCase.locale))
+		loggerInstance.With(logger.F("v", testCase.value)).Infof(localeMsg)
+		closeTestLogger(t, loggerInstance)
+
+		_, got, _ := strings.Cut(strings.TrimSpace(buf.String()), " v=")
+		if got != testCase.want {
+			t.Errorf(localeValueFmt, testCase.value, testCase.locale, got, testCase.want)
+		}
+	}
+}
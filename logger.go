@@ -12,14 +12,19 @@
 package logger
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
 	"io"
-	"log"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 const (
@@ -27,21 +32,26 @@ const (
 	loggerErrorFormatString = "[LOGGER ERROR] Format panic: %v, " +
 		"format=%q, args=%v\n"
 	maxLogMessageLength = 4096 // Reasonable limit for log messages
-	// logMessageExtraCap is the extra capacity for the log message builder ([level]
-	// msg).
-	logMessageExtraCap = 3
-	logLevelInfo       = "INFO"
-	logLevelWarn       = "WARN"
-	logLevelError      = "ERROR"
-	logLevelSuccess    = "SUCCESS"
-	logLevelFatal      = "FATAL"
-	logLevelPanic      = "PANIC"
-	logLevelSystem     = "SYSTEM"
-	emptyMessage       = "(empty message)"
-	truncatedSuffix    = "... [TRUNCATED]"
-	fallbackFormat     = "[%s] (logger closed) %s\n"
-	formatErrorMsg     = "(format error: %s) args=%v"
-	logBracketSpace    = "] "
+	// callerSkip is the number of stack frames between runtime.Caller in
+	// resolveCaller and the user code that invoked a logging method.
+	callerSkip       = 3
+	callerSeparator  = ":"
+	logLevelInfo     = "INFO"
+	logLevelWarn     = "WARN"
+	logLevelError    = "ERROR"
+	logLevelSuccess  = "SUCCESS"
+	logLevelFatal    = "FATAL"
+	logLevelPanic    = "PANIC"
+	logLevelSystem   = "SYSTEM"
+	logLevelSecurity = "SECURITY"
+	logLevelDebug    = "DEBUG"
+	emptyMessage     = "(empty message)"
+	truncatedSuffix  = "... [TRUNCATED]"
+	fallbackFormat   = "[%s] (logger closed) %s\n"
+	formatErrorMsg   = "(format error: %s) args=%v"
+	// formatErrorTypesMsg is used when even printing the arguments panics.
+	formatErrorTypesMsg = "(format error: %s) arg types=%v"
+	argTypeFormat       = "%T"
 
 	// Error messages for predefined errors.
 	errLogPathOutsideBoundsMsg     = "log path outside directory bounds"
@@ -49,6 +59,9 @@ const (
 	errPathContainsInvalidCharsMsg = "path contains invalid characters"
 	errFilenameCannotBeEmptyMsg    = "filename cannot be empty"
 	errFilenameContainsInvalidMsg  = "filename contains invalid characters"
+	errLoggerClosedMsg             = "logger is closed"
+	errWriteStdoutMsg              = "write stdout"
+	errWriteLogFileMsg             = "write log file"
 
 	// Error format strings.
 	errFmtInvalidLogDir   = "invalid log directory: %w"
@@ -58,6 +71,7 @@ const (
 	errFmtResolveLogPath  = "resolve log path: %w"
 	errFmtOpenLogFile     = "open log file: %w"
 	errFmtCloseLogFile    = "close log file: %w"
+	errFmtWrapCause       = "%w: %w"
 )
 
 // Predefined errors for better error handling.
@@ -67,27 +81,131 @@ var (
 	ErrPathContainsInvalidChars = errors.New(errPathContainsInvalidCharsMsg)
 	ErrFilenameCannotBeEmpty    = errors.New(errFilenameCannotBeEmptyMsg)
 	ErrFilenameContainsInvalid  = errors.New(errFilenameContainsInvalidMsg)
+	ErrLoggerClosed             = errors.New(errLoggerClosedMsg)
+	ErrWriteStdout              = errors.New(errWriteStdoutMsg)
+	ErrWriteLogFile             = errors.New(errWriteLogFileMsg)
 )
 
 // Logger provides leveled, thread-safe logging to stdout and a rotating file per run.
 // This struct is the main entry point for the logging functionality and is responsible
-// for managing the log file and writing log messages.
+// for managing the log file and writing log messages. Child loggers created with
+// With share the outputs of their parent and add their own fields.
 type Logger struct {
-	logFile *os.File
-	std     *log.Logger
-	file    *log.Logger
-	mu      sync.Mutex
+	core   *loggerCore
+	fields []Field
+}
+
+// loggerCore holds the outputs and state shared by a logger and its children.
+type loggerCore struct {
+	logFile        *os.File
+	std            *lineWriter
+	stdWriter      io.Writer
+	file           *lineWriter
+	layout         *Layout
+	timezone       *time.Location // nil keeps the entry's own
+	stdTimezone    *time.Location
+	stdLocale      *Locale          // nil renders stdout canonically
+	levelLabels    map[Level]string // every known level, see levelLabelTable
+	levelSymbols   map[Level]string // decoration prefixes, see symbolPrefixes
+	wal            *writeAheadLog
+	ring           *ringFile
+	lazy           *lazyFile
+	recent         *recentWindow
+	audit          *auditChain
+	lastErr        error
+	drops          dropTracker
+	escalations    []*escalation
+	errorBudget    ErrorBudget
+	attachments    AttachmentRetention
+	routes         []*routeTarget
+	sinks          *sinkPool // route queues with WithSinkWorkers
+	syncer         *syncScheduler
+	routeLines     []routeLine
+	middleware     []EntryMiddleware
+	writer         entryWriter
+	access         *accessLogger
+	debugTimer     *time.Timer
+	callSites      callSites
+	backoffs       sync.Map // string -> *backoffState
+	fingerprints   fingerprintStats
+	producers      producers // bytes and quotas per Named producer
+	clientLimiters []*ClientLimiter
+	backfill       backfillState
+	aggregator     *aggregator   // set with WithAggregation
+	rates          *rateDetector // set with WithRateAnomalies
+	disk           *DiskLimiter  // set with WithDiskLimit
+	stdLatency     latencyHistogram
+	fileLatency    latencyHistogram
+	started        time.Time // creation time, the origin of {elapsed}
+	logDir         string    // holds job files; empty without a log directory
+	encoding       Encoding
+	timeFormat     TimeFormat
+	clock          func() time.Time
+	mu             sync.Mutex
+	minLevel       atomic.Int32 // a Level, read without mu by enabled
+	debugRestore   Level
+	debugGen       uint64
+	closed         bool
+	needsCaller    atomic.Bool // read without mu; Reload may set it
+	pprofLabels    bool
+	spillover      bool
+	crashReports   bool
+	vectored       bool // set with WithVectoredWrites
+}
+
+// writeTarget selects the outputs an entry is written to.
+type writeTarget int
+
+const (
+	targetAll writeTarget = iota
+	targetFileOnly
+	// targetLocal skips routes, whose writers may be slow or remote.
+	targetLocal
+)
+
+// Entry is a single log record before it is rendered. Label is the display
+// name of Level and Caller is empty unless an output renders it. Mandatory
+// entries, logged with LogMandatory, are never suppressed by level filters or
+// sampling.
+type Entry struct {
+	Time      time.Time
+	Fields    []Field
+	Label     string
+	Message   string
+	Caller    string
+	Level     Level
+	Mandatory bool
+	// timeText caches Time rendered for text layouts, so that sinks with
+	// different layouts format the timestamp once.
+	timeText string
+	// elapsed is the time since the logger was created and timeFormat the
+	// logger's time format, both set when the entry is written.
+	elapsed    time.Duration
+	timeFormat TimeFormat
+	// ingested marks entries parsed from another program's log, which
+	// WithBackfill checks for being out of order.
+	ingested bool
 }
 
 // New creates a new Logger instance that writes to both stdout and a log file.
 // This function is the designated constructor for the Logger struct and ensures
 // that the logger is initialized with a valid log directory and filename.
-func New(logDir, filename string) (*Logger, error) {
+// Options customize the logger, for example its line layout.
+func New(logDir, filename string, opts ...Option) (*Logger, error) {
+	config := newOptions(opts)
+	if config.withoutFile {
+		return newWithoutFile(logDir, config)
+	}
+
 	err := validateInputs(logDir, filename)
 	if err != nil {
 		return nil, err
 	}
 
+	if config.lazyFile && config.walSize == 0 && !config.audit {
+		return newLazyFile(logDir, filename, config)
+	}
+
 	logPath, err := setupAndValidatePath(logDir, filename)
 	if err != nil {
 		return nil, err
@@ -98,7 +216,36 @@ func New(logDir, filename string) (*Logger, error) {
 		return nil, err
 	}
 
-	return createLoggerInstance(f), nil
+	loggerInstance := createLoggerInstance(f, config)
+
+	err = loggerInstance.core.openDirSinks(logDir, config)
+	if err != nil {
+		_ = loggerInstance.Close()
+
+		return nil, err
+	}
+
+	if config.walSize > 0 {
+		loggerInstance.core.wal, err = openWriteAheadLog(logPath, config.walSize, f)
+		if err != nil {
+			_ = loggerInstance.Close()
+
+			return nil, err
+		}
+	}
+
+	if config.audit {
+		loggerInstance.core.audit, err = openAuditChain(logPath, config.auditKeyID, config.auditKey)
+		if err != nil {
+			_ = loggerInstance.Close()
+
+			return nil, err
+		}
+	}
+
+	loggerInstance.core.startBackground(config)
+
+	return loggerInstance, nil
 }
 
 func setupAndValidatePath(logDir, filename string) (string, error) {
@@ -176,23 +323,82 @@ func openLogFile(logPath string) (*os.File, error) {
 	return logFile, nil
 }
 
-func createLoggerInstance(f *os.File) *Logger {
-	return &Logger{
-		mu:      sync.Mutex{},
-		logFile: f,
-		std:     log.New(os.Stdout, "", log.LstdFlags),
-		file:    log.New(f, "", log.LstdFlags),
-	}
+func createLoggerInstance(f *os.File, config *options) *Logger {
+	loggerInstance := newLogger(os.Stdout, config)
+	loggerInstance.core.logFile = f
+	loggerInstance.core.file = newFileLineWriter(f, f.Name(), config.diskLimiter, config.vectoredWrites)
+
+	return loggerInstance
+}
+
+// startBackground starts the goroutines configured in config: the sink
+// workers, the fsync scheduler, the async writer, the rollup writer and the
+// rate anomaly detector.
+func (c *loggerCore) startBackground(config *options) {
+	c.startSinkWorkers(config.sinkQueueSize)
+	c.startSyncScheduler(config.syncPolicy)
+	c.startAsync(config.asyncBuffer, config.coalesceBatch)
+	c.startAggregation()
+	c.startRateAnomalies()
 }
 
 // NewStreamLogger creates a new Logger instance that writes only to the provided io.Writer.
-func NewStreamLogger(writer io.Writer) *Logger {
-	return &Logger{
-		mu:      sync.Mutex{},
-		logFile: nil,
-		std:     log.New(writer, "", log.LstdFlags),
-		file:    nil,
+// Routes and access logs that name a Filename need a log directory; routes are
+// ignored and access logs fall back to their Writer, if any.
+func NewStreamLogger(writer io.Writer, opts ...Option) *Logger {
+	config := newOptions(opts)
+	loggerInstance := newLogger(writer, config)
+	loggerInstance.core.openWriterSinks(config)
+	loggerInstance.core.startBackground(config)
+
+	return loggerInstance
+}
+
+func newLogger(stdWriter io.Writer, config *options) *Logger {
+	core := &loggerCore{
+		mu:           sync.Mutex{},
+		logFile:      nil,
+		stdWriter:    stdWriter,
+		std:          nil,
+		file:         nil,
+		layout:       config.layout,
+		timezone:     config.timezone,
+		stdTimezone:  cmp.Or(config.stdoutTimezone, config.timezone),
+		stdLocale:    config.stdoutLocale,
+		levelLabels:  levelLabelTable(config.levelLabels),
+		levelSymbols: symbolPrefixes(config.levelSymbols),
+		drops:        dropTracker{interval: config.dropSummaryInterval},
+		escalations:  newEscalations(config.escalationRules),
+		errorBudget:  config.errorBudget,
+		producers:    producers{states: nil, quotas: config.producerQuotas},
+		backfill:     newBackfillState(config.backfill),
+		rates:        newRateDetector(config.rateAnomalies),
+		disk:         config.diskLimiter,
+		vectored:     config.vectoredWrites,
+		attachments:  config.attachmentRetention,
+		encoding:     config.encoding,
+		timeFormat:   config.timeFormat,
+		recent:       newRecentWindow(config.recentWindow),
+		middleware:   config.entryMiddleware(),
+		started:      config.clock(),
+		clock:        config.clock,
+		callSites:    callSites{counts: sync.Map{}},
+		backoffs:     sync.Map{},
+		pprofLabels:  config.profileLabels,
+		spillover:    config.spillover,
+		crashReports: config.crashReports,
 	}
+	core.writer = &mutexWriter{core: core}
+	core.setMinLevel(config.minLevel)
+	core.needsCaller.Store(config.needsCaller())
+
+	if config.withoutStdout {
+		core.stdWriter = io.Discard
+	} else {
+		core.std = newLineWriter(stdWriter)
+	}
+
+	return &Logger{core: core, fields: nil}
 }
 
 // ValidatePath ensures the path is safe and doesn't contain directory traversal.
@@ -241,84 +447,284 @@ func containsInvalidFilenameChars(filename string) bool {
 
 // Close closes the log file and releases resources. This function is responsible
 // for ensuring that the log file is properly closed and that any resources are
-// released.
+// released. Closing a child logger closes the outputs it shares with its parent.
+// In async mode, queued entries are written first.
 func (l *Logger) Close() error {
-	l.mu.Lock()
-	defer l.mu.Unlock()
+	return l.core.close()
+}
+
+func (c *loggerCore) close() error {
+	c.writer.stop()
+	c.sinks.stop()
+	c.syncer.stop()
 
-	if l.logFile != nil {
-		err := l.logFile.Close()
+	c.stopRateAnomalies()
+	aggregationErr := c.stopAggregation()
 
-		l.logFile = nil
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closed = true
+	c.stopDebugTimer()
+
+	errs := []error{aggregationErr, c.flushLines(), c.closeWriteAheadLog()}
+
+	if c.logFile != nil {
+		err := c.logFile.Close()
+
+		c.logFile = nil
 		if err != nil {
-			return fmt.Errorf(errFmtCloseLogFile, err)
+			errs = append(errs, fmt.Errorf(errFmtCloseLogFile, err))
 		}
 	}
 
-	return nil
+	errs = append(errs, c.closeRoutes(), c.access.close(), c.ring.close(), c.closeBackfillFile())
+	c.ring = nil
+
+	return errors.Join(errs...)
+}
+
+// Err returns the most recent write error recorded since the previous call to
+// Err, or nil if every entry was written successfully. Logging methods never
+// return errors, so callers with strict durability requirements use Err to
+// detect entries that did not reach their outputs.
+func (l *Logger) Err() error {
+	l.core.mu.Lock()
+	defer l.core.mu.Unlock()
+
+	err := l.core.lastErr
+	l.core.lastErr = nil
+
+	return err
 }
 
 // Infof logs an informational message. This function is used for general
 // informational messages that are not critical to the application's operation.
 func (l *Logger) Infof(format string, args ...any) {
-	l.writef(logLevelInfo, format, args...)
+	l.writef(LevelInfo, nil, format, args...)
 }
 
 // Warnf logs a warning message. This function is used for messages that indicate
 // a potential problem but do not prevent the application from continuing.
 func (l *Logger) Warnf(format string, args ...any) {
-	l.writef(logLevelWarn, format, args...)
+	l.writef(LevelWarn, nil, format, args...)
 }
 
 // Errorf logs an error message. This function is used for messages that indicate
 // a problem that prevents the application from continuing normally.
 func (l *Logger) Errorf(format string, args ...any) {
-	l.writef(logLevelError, format, args...)
+	l.writef(LevelError, nil, format, args...)
 }
 
 // Successf logs a success message. This function is used for messages that indicate
 // that an operation has completed successfully.
 func (l *Logger) Successf(format string, args ...any) {
-	l.writef(logLevelSuccess, format, args...)
+	l.writef(LevelSuccess, nil, format, args...)
 }
 
 // Fatalf logs a fatal system error and does NOT exit (unlike log.Fatal). This
 // function is used for messages that indicate a critical error that prevents the
 // application from continuing.
 func (l *Logger) Fatalf(format string, args ...any) {
-	l.writef(logLevelFatal, format, args...)
+	l.writef(LevelFatal, nil, format, args...)
 }
 
 // Panicf logs a panic-level error and does NOT panic (unlike log.Panic). This
 // function is used for messages that indicate a panic condition.
 func (l *Logger) Panicf(format string, args ...any) {
-	l.writef(logLevelPanic, format, args...)
+	l.writef(LevelPanic, nil, format, args...)
 }
 
 // Systemf logs system-level events (startup, shutdown, configuration changes).
 // This function is used for messages that indicate system-level events.
 func (l *Logger) Systemf(format string, args ...any) {
-	l.writef(logLevelSystem, format, args...)
+	l.writef(LevelSystem, nil, format, args...)
+}
+
+func (l *Logger) writef(level Level, fields []Field, format string, args ...any) {
+	if !l.core.enabled(level) {
+		return
+	}
+
+	l.writeEntryf(level, fields, l.resolveCaller(), targetAll, format, args...)
+}
+
+// LogMandatory logs message at level with fields, bypassing level filters and
+// sampling, for entries that must never be suppressed such as audit events.
+// The message is not a format string. The reported caller is the caller of the
+// function that invoked LogMandatory, so that wrappers report their callers.
+func (l *Logger) LogMandatory(level Level, message string, fields ...Field) {
+	if len(l.fields) > 0 {
+		fields = append(slices.Clip(l.fields), fields...)
+	}
+
+	l.core.fingerprints.count(message)
+	l.core.submit(&Entry{
+		Time:       l.core.clock(),
+		Fields:     fields,
+		Label:      l.core.label(level),
+		Level:      level,
+		Message:    l.core.validateFormat(message),
+		Caller:     l.resolveCaller(),
+		Mandatory:  true,
+		timeText:   "",
+		elapsed:    0,
+		timeFormat: TimeFormatDefault,
+		ingested:   false,
+	}, targetAll)
 }
 
-func (l *Logger) writef(level, format string, args ...any) {
-	l.mu.Lock()
-	defer l.mu.Unlock()
+// writeEntryf adds the logger's own fields ahead of fields and writes the entry.
+func (l *Logger) writeEntryf(
+	level Level,
+	fields []Field,
+	caller string,
+	target writeTarget,
+	format string,
+	args ...any,
+) {
+	if !l.core.enabled(level) {
+		return
+	}
 
-	format = l.validateFormat(format)
-	if l.logFile == nil {
-		l.writeToStderrFallbackf(level, format, args...)
+	if len(l.fields) > 0 {
+		fields = append(slices.Clip(l.fields), fields...)
+	}
+
+	l.core.writeEntryf(level, fields, caller, target, format, args...)
+}
 
+func (c *loggerCore) writeEntryf(
+	level Level,
+	fields []Field,
+	caller string,
+	target writeTarget,
+	format string,
+	args ...any,
+) {
+	if !c.enabled(level) {
 		return
 	}
 
-	msg := l.prepareMessage(level, format, args...)
-	if msg != "" {
-		l.outputMessage(msg)
+	c.fingerprints.count(format)
+	c.submit(&Entry{
+		Time:       c.clock(),
+		Fields:     fields,
+		Label:      c.label(level),
+		Level:      level,
+		Message:    c.safeFormat(c.validateFormat(format), args...),
+		Caller:     caller,
+		Mandatory:  false,
+		timeText:   "",
+		elapsed:    0,
+		timeFormat: TimeFormatDefault,
+		ingested:   false,
+	}, target)
+}
+
+// submit runs the entry middleware and hands logEntry to the entry writer.
+func (c *loggerCore) submit(logEntry *Entry, target writeTarget) {
+	if c.applyMiddleware(logEntry) && !c.aggregate(logEntry) {
+		c.spill(logEntry)
+		c.writer.submit(logEntry, target)
+	}
+}
+
+// submitAndWait writes logEntry and returns its write error. In async mode
+// it waits for the writer, preserving the order of the caller's entries.
+func (c *loggerCore) submitAndWait(logEntry *Entry, target writeTarget) error {
+	if !c.applyMiddleware(logEntry) || c.aggregate(logEntry) {
+		return nil
+	}
+
+	c.spill(logEntry)
+
+	return c.writer.submitAndWait(logEntry, target)
+}
+
+// write commits logEntry to the outputs. Hooks run after the lock is released
+// so that they may log themselves.
+func (c *loggerCore) write(logEntry *Entry, target writeTarget) error {
+	hooks, report, err := c.writeLocked(logEntry, target)
+	c.writeCrashReport(report)
+
+	for _, hook := range hooks {
+		hook()
+	}
+
+	return err
+}
+
+// writeLocked writes logEntry and returns the hooks to run, the crash report
+// to write and the write error, if any. SECURITY entries are also made
+// durable.
+func (c *loggerCore) writeLocked(logEntry *Entry, target writeTarget) ([]func(), *crashReport, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		c.writeToStderrFallback(logEntry.Label, logEntry.Message)
+		c.lastErr = ErrLoggerClosed
+
+		return nil, nil, ErrLoggerClosed
+	}
+
+	now := time.Now()
+	c.flushDropSummary(now)
+
+	report := c.reserveCrashReport(logEntry)
+
+	err := c.emit(logEntry, target)
+	if c.recent != nil {
+		c.recent.add(logEntry)
+	}
+
+	if err != nil {
+		c.lastErr = err
+		c.recordDrop(dropReasonWriteError)
+	} else if logEntry.Level == LevelSecurity {
+		err = c.syncDurable()
+		if err != nil {
+			c.lastErr = err
+		}
 	}
+
+	return c.evaluateEscalations(logEntry.Level, now), report, err
 }
 
-func (l *Logger) validateFormat(format string) string {
+// emit renders logEntry and writes it to the selected outputs. The caller
+// holds l.mu.
+func (c *loggerCore) emit(logEntry *Entry, target writeTarget) error {
+	outOfOrder := c.checkOrder(logEntry)
+	logEntry.elapsed = logEntry.Time.Sub(c.started)
+	logEntry.timeFormat = c.timeFormat
+
+	msg := c.prepareMessage(logEntry)
+	if msg == "" {
+		return nil
+	}
+
+	if !c.admitProducer(logEntry, len(msg)+1) {
+		return nil
+	}
+
+	c.countRate(logEntry)
+
+	if outOfOrder && c.backfill.file != nil {
+		return c.writeBackfill(msg)
+	}
+
+	if target == targetLocal {
+		return c.outputMessage(logEntry, msg, target)
+	}
+
+	return errors.Join(
+		c.outputMessage(logEntry, msg, target),
+		c.routeMessage(logEntry, msg),
+	)
+}
+
+func (c *loggerCore) validateFormat(format string) string {
 	if format == "" {
 		return emptyMessage
 	}
@@ -326,62 +732,166 @@ func (l *Logger) validateFormat(format string) string {
 	return format
 }
 
-func (l *Logger) prepareMessage(level, format string, args ...any) string {
-	formattedMsg := l.safeFormat(format, args...)
-	if len(formattedMsg) > maxLogMessageLength {
+func (c *loggerCore) prepareMessage(logEntry *Entry) string {
+	if len(logEntry.Message) > maxLogMessageLength {
 		truncatedLen := maxLogMessageLength - len(truncatedSuffix)
 
-		formattedMsg = formattedMsg[:truncatedLen] + truncatedSuffix
+		logEntry.Message = logEntry.Message[:truncatedLen] + truncatedSuffix
 	}
 
-	return l.formatLogMessage(level, formattedMsg)
+	return encodeEntry(inTimezone(logEntry, c.timezone), c.encoding, c.layout)
 }
 
-func (l *Logger) outputMessage(msg string) {
-	l.std.Println(msg)
+// resolveCaller returns the file:line of the code that invoked the logging
+// method, or an empty string when no output displays it.
+func (l *Logger) resolveCaller() string {
+	if !l.core.needsCaller.Load() {
+		return ""
+	}
 
-	if l.file != nil {
-		l.file.Println(msg)
+	return callerAt(callerSkip + 1)
+}
+
+// resolveDirectCaller is resolveCaller for logging methods that call it
+// themselves instead of through a helper such as writef.
+func (l *Logger) resolveDirectCaller() string {
+	if !l.core.needsCaller.Load() {
+		return ""
 	}
+
+	return callerAt(callerSkip)
 }
 
-func (l *Logger) writeToStderrFallbackf(level, format string, args ...any) {
+// callerAt returns the file:line of the frame skip levels up the stack from
+// callerAt, 1 being its caller, or an empty string when the stack is
+// shorter.
+func callerAt(skip int) string {
+	_, file, line, ok := runtime.Caller(skip)
+	if !ok {
+		return ""
+	}
+
+	return filepath.Base(file) + callerSeparator + strconv.Itoa(line)
+}
+
+func (c *loggerCore) outputMessage(logEntry *Entry, msg string, target writeTarget) error {
+	var errs []error
+
+	if target != targetFileOnly && c.std != nil {
+		err := c.std.writeLine(c.decorate(logEntry.Level, c.stdoutMessage(logEntry, msg)))
+		c.stdLatency.observe(time.Since(logEntry.Time))
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf(errFmtWrapCause, ErrWriteStdout, err))
+		}
+	}
+
+	if c.ring != nil {
+		c.ring.write(msg)
+	}
+
+	if c.lazy != nil {
+		err := c.openLazyFile()
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if c.file != nil {
+		err := c.writeFileEntry(msg)
+		c.fileLatency.observe(time.Since(logEntry.Time))
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf(errFmtWrapCause, ErrWriteLogFile, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// stdoutMessage returns msg, or logEntry rendered again when stdout uses its
+// own timezone or locale.
+func (c *loggerCore) stdoutMessage(logEntry *Entry, msg string) string {
+	if c.stdTimezone == c.timezone && c.stdLocale == nil {
+		return msg
+	}
+
+	return encodeEntry(c.inLocale(inTimezone(logEntry, c.stdTimezone)), c.encoding, c.layout)
+}
+
+func (c *loggerCore) writeToStderrFallback(label, message string) {
 	// Logger is closed, only write to stderr as fallback.
-	_, err := fmt.Fprintf(
-		os.Stderr,
-		fallbackFormat,
-		level,
-		l.safeFormat(format, args...),
-	)
+	_, err := fmt.Fprintf(os.Stderr, fallbackFormat, label, message)
 
 	_ = err // Error ignored - cannot log safely.
 }
 
-func (l *Logger) formatLogMessage(level, formattedMsg string) string {
-	var builder strings.Builder
-	builder.Grow(len(level) + len(formattedMsg) + logMessageExtraCap)
-	builder.WriteString("[")
-	builder.WriteString(level)
-	builder.WriteString(logBracketSpace)
-	builder.WriteString(formattedMsg)
+// safeFormat formats the message like SafeSprintf and reports recovered
+// format panics on stderr.
+func (c *loggerCore) safeFormat(format string, args ...any) string {
+	result, recovered := safeSprintf(format, args...)
+	if recovered != nil {
+		reportFormatPanic(recovered, format, args)
+	}
+
+	return result
+}
+
+// reportFormatPanic logs a recovered format panic to stderr. Printing the
+// panic value or the arguments may panic again, which is ignored.
+func reportFormatPanic(recovered any, format string, args []any) {
+	defer func() {
+		_ = recover()
+	}()
 
-	return builder.String()
+	fmt.Fprintf(os.Stderr, loggerErrorFormatString, recovered, format, args)
 }
 
-// safeFormat safely formats the message, handling format string errors.
-func (l *Logger) safeFormat(format string, args ...any) (result string) {
+// SafeSprintf formats like fmt.Sprintf but never panics. A format without
+// arguments is returned as-is so that messages like "100%" stay intact. If
+// formatting panics, for example in a Stringer or an invalid Formatter, the
+// result describes the format and arguments instead.
+func SafeSprintf(format string, args ...any) string {
+	result, _ := safeSprintf(format, args...)
+
+	return result
+}
+
+// safeSprintf formats the message and returns the recovered panic value, if
+// any.
+func safeSprintf(format string, args ...any) (result string, recovered any) {
 	defer func() {
-		if r := recover(); r != nil {
-			// Format panic recovered - log a safe message to stderr.
-			fmt.Fprintf(os.Stderr, loggerErrorFormatString, r, format, args)
+		recovered = recover()
+		if recovered != nil {
 			// Return a safe message to be logged to the file.
-			result = fmt.Sprintf(formatErrorMsg, format, args)
+			result = formatFallback(format, args)
 		}
 	}()
 	// If no args, return format string as-is to handle cases like "100%".
 	if len(args) == 0 {
-		return format
+		return format, nil
+	}
+
+	return fmt.Sprintf(format, args...), nil
+}
+
+// formatFallback describes a format that panicked. If printing the arguments
+// panics again, only their types are described.
+func formatFallback(format string, args []any) (result string) {
+	defer func() {
+		if recover() != nil {
+			result = fmt.Sprintf(formatErrorTypesMsg, format, argTypes(args))
+		}
+	}()
+
+	return fmt.Sprintf(formatErrorMsg, format, args)
+}
+
+func argTypes(args []any) []string {
+	types := make([]string, len(args))
+	for index, arg := range args {
+		types[index] = fmt.Sprintf(argTypeFormat, arg)
 	}
 
-	return fmt.Sprintf(format, args...)
+	return types
 }
@@ -2,12 +2,14 @@
 package logger_test
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/book-expert/logger"
+	"github.com/book-expert/logger/loggertest"
 )
 
 const (
@@ -33,6 +35,8 @@ const (
 	systemLogFormat            = "system event: %s"
 	systemLogArg               = "startup complete"
 	logFileMissingFmt          = "log file missing %q; got:\n%s"
+	observedCountFmt           = "expected %d entries, got %d"
+	observedEntryFmt           = "entry %d: expected [%s] %s, got [%s] %s"
 	closeIdempotentFile        = "test2.log"
 	firstCloseErrFmt           = "first close: %v"
 	secondCloseErrFmt          = "second close should not error: %v"
@@ -82,8 +86,22 @@ const (
 	logAfterCloseErrMsg        = "This should also go to stderr"
 	setupTestLoggerErrFmt      = "setupTestLogger: failed to create logger: %v"
 	setupTestLoggerCloseErrFmt = "setupTestLogger: failed to close logger: %v"
+	errLogFile                 = "err.log"
+	errMsg                     = "durable entry"
+	unexpectedErrFmt           = "unexpected error: %v"
+	expectedErrFmt             = "expected %v, got: %v"
+	expectedErrClearedFmt      = "expected error to be cleared, got: %v"
 )
 
+var errTestWriteFailed = errors.New("write failed")
+
+// failingWriter is an io.Writer that always fails.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errTestWriteFailed
+}
+
 // setupTestLogger is a helper to create and automatically clean up a logger for tests.
 func setupTestLogger(
 	t *testing.T,
@@ -118,6 +136,34 @@ func TestLogger_WritesToStdoutAndFile(t *testing.T) {
 	verifyLogFileContents(t, filepath.Dir(logPath), filepath.Base(logPath))
 }
 
+func TestLogger_AllLevelsObserved(t *testing.T) {
+	t.Parallel()
+
+	loggerInstance, observer := loggertest.NewObservedLogger(t)
+	testLoggerAllLevels(t, loggerInstance)
+
+	expected := [][2]string{
+		{"INFO", "hello world"},
+		{"WARN", "warn 42"},
+		{"ERROR", "err 1"},
+		{"SUCCESS", "ok"},
+		{"FATAL", "system failure: disk full"},
+		{"PANIC", "panic condition: nil pointer"},
+		{"SYSTEM", "system event: startup complete"},
+	}
+
+	entries := observer.Entries()
+	if len(entries) != len(expected) {
+		t.Fatalf(observedCountFmt, len(expected), len(entries))
+	}
+
+	for index, entry := range entries {
+		if entry.Label != expected[index][0] || entry.Message != expected[index][1] {
+			t.Errorf(observedEntryFmt, index, expected[index][0], expected[index][1], entry.Label, entry.Message)
+		}
+	}
+}
+
 func testLoggerAllLevels(t *testing.T, loggerInstance *logger.Logger) {
 	t.Helper()
 	loggerInstance.Infof(infoLogFormat, infoLogArg)
@@ -371,3 +417,50 @@ func TestLogger_LogAfterClose(t *testing.T) {
 	loggerInstance.Infof(logAfterCloseInfoMsg)
 	loggerInstance.Errorf(logAfterCloseErrMsg)
 }
+
+func TestLogger_ErrNilAfterSuccessfulWrite(t *testing.T) {
+	t.Parallel()
+
+	loggerInstance, _ := setupTestLogger(t, errLogFile)
+	loggerInstance.Infof(errMsg)
+
+	err := loggerInstance.Err()
+	if err != nil {
+		t.Errorf(unexpectedErrFmt, err)
+	}
+}
+
+func TestLogger_ErrReportsFailedWrite(t *testing.T) {
+	t.Parallel()
+
+	loggerInstance := logger.NewStreamLogger(failingWriter{})
+	loggerInstance.Errorf(errMsg)
+
+	err := loggerInstance.Err()
+	if !errors.Is(err, errTestWriteFailed) {
+		t.Errorf(expectedErrFmt, errTestWriteFailed, err)
+	}
+
+	err = loggerInstance.Err()
+	if err != nil {
+		t.Errorf(expectedErrClearedFmt, err)
+	}
+}
+
+func TestLogger_ErrReportsClosedLogger(t *testing.T) {
+	t.Parallel()
+
+	loggerInstance := logger.NewStreamLogger(&strings.Builder{})
+
+	err := loggerInstance.Close()
+	if err != nil {
+		t.Fatalf(closeLoggerErrFmt, err)
+	}
+
+	loggerInstance.Infof(logAfterCloseInfoMsg)
+
+	err = loggerInstance.Err()
+	if !errors.Is(err, logger.ErrLoggerClosed) {
+		t.Errorf(expectedErrFmt, logger.ErrLoggerClosed, err)
+	}
+}
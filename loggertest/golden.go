@@ -0,0 +1,97 @@
+package loggertest
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	// GoldenDir is the directory, relative to the test's package, holding
+	// the golden files of Golden.
+	GoldenDir = "testdata"
+
+	goldenSuffix     = ".golden"
+	goldenDirPerm    = 0o750
+	goldenFilePerm   = 0o600
+	goldenUpdateFlag = "update"
+	goldenUpdateDoc  = "rewrite the golden files of loggertest.Golden"
+	errFmtGoldenRead = "read golden file %s: %v (run go test -update to create it)"
+	errFmtGoldenSave = "write golden file %s: %v"
+	errFmtGoldenDiff = "output differs from %s (run go test -update to accept it):\n" +
+		"--- got\n%s--- want\n%s"
+)
+
+// FixedTime is the time of every entry logged within Golden.
+var FixedTime = time.Date(2025, time.January, 2, 15, 4, 5, 123456789, time.UTC)
+
+// update is registered with the test binary's flags, so that
+// go test -update rewrites the golden files instead of comparing them.
+var update = flag.Bool(goldenUpdateFlag, false, goldenUpdateDoc)
+
+// Golden renders the entries logged by logFn and compares them with the
+// golden file testdata/<test name>.golden. The logger writes to a buffer
+// with the options returned by configure, which may be nil, and a clock
+// fixed at FixedTime, so that the output is reproducible; entries are
+// flushed before the comparison. Run go test -update to write the current
+// output to the golden file instead.
+func Golden(t testing.TB, configure func() []logger.Option, logFn func(*logger.Logger)) {
+	t.Helper()
+
+	var opts []logger.Option
+	if configure != nil {
+		opts = configure()
+	}
+
+	var buf bytes.Buffer
+
+	loggerInstance := logger.NewStreamLogger(&buf, append(opts, logger.WithClock(fixedClock))...)
+	logFn(loggerInstance)
+	loggerInstance.Flush()
+	_ = loggerInstance.Close()
+
+	goldenPath := filepath.Join(GoldenDir, goldenName(t.Name()))
+	if *update {
+		writeGolden(t, goldenPath, buf.Bytes())
+
+		return
+	}
+
+	want, err := os.ReadFile(goldenPath)
+	if err != nil {
+		t.Fatalf(errFmtGoldenRead, goldenPath, err)
+	}
+
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf(errFmtGoldenDiff, goldenPath, buf.String(), want)
+	}
+}
+
+func fixedClock() time.Time {
+	return FixedTime
+}
+
+// goldenName turns a test name, which may contain the slashes of subtests,
+// into a filename.
+func goldenName(testName string) string {
+	return strings.NewReplacer("/", "_", " ", "_").Replace(testName) + goldenSuffix
+}
+
+func writeGolden(t testing.TB, goldenPath string, data []byte) {
+	t.Helper()
+
+	err := os.MkdirAll(filepath.Dir(goldenPath), goldenDirPerm)
+	if err == nil {
+		err = os.WriteFile(goldenPath, data, goldenFilePerm)
+	}
+
+	if err != nil {
+		t.Fatalf(errFmtGoldenSave, goldenPath, err)
+	}
+}
@@ -0,0 +1,50 @@
+package loggertest_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/book-expert/logger"
+	"github.com/book-expert/logger/loggertest"
+)
+
+const (
+	goldenMsgStarted = "job %s started"
+	goldenMsgSlow    = "job %s took %v"
+	goldenJobID      = "42"
+	goldenTook       = 1500 * time.Millisecond
+	goldenUserField  = "user"
+	goldenUser       = "ada"
+)
+
+func logGoldenJob(loggerInstance *logger.Logger) {
+	jobLogger := loggerInstance.With(logger.F(goldenUserField, goldenUser))
+	jobLogger.Infof(goldenMsgStarted, goldenJobID)
+	jobLogger.Warnf(goldenMsgSlow, goldenJobID, goldenTook)
+}
+
+func TestGolden_Text(t *testing.T) {
+	t.Parallel()
+
+	loggertest.Golden(t, nil, logGoldenJob)
+}
+
+func TestGolden_Options(t *testing.T) {
+	t.Parallel()
+
+	t.Run("elapsed", func(t *testing.T) {
+		t.Parallel()
+
+		loggertest.Golden(t, func() []logger.Option {
+			return []logger.Option{logger.WithLayout(logger.MustParseLayout(logger.ElapsedLayout))}
+		}, logGoldenJob)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		t.Parallel()
+
+		loggertest.Golden(t, func() []logger.Option {
+			return []logger.Option{logger.WithEncoding(logger.EncodingJSON)}
+		}, logGoldenJob)
+	})
+}
@@ -0,0 +1,189 @@
+// Package loggertest provides sinks that fail on demand, for testing how
+// services and the logger's own retry and fallback logic behave when logging
+// degrades.
+//
+// A FaultySink wraps any logger.Sink and a FaultyFile a file; both inject the
+// faults currently set with SetFaults or FailNext: write errors, latency and
+// short writes. They are safe for concurrent use and can be passed anywhere a
+// logger.Sink is accepted, for example as Route.Sink or inside a RetrySink.
+package loggertest
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	filePerm        = 0o600
+	shortWriteRatio = 2
+	errInjectedMsg  = "injected write failure"
+	errSyncMsg      = "injected sync failure"
+	errFmtOpenFile  = "open faulty file: %w"
+)
+
+// ErrInjected is returned by failing writes when Faults.Err is nil, and
+// ErrSync is a ready-made Faults.SyncErr.
+var (
+	ErrInjected = errors.New(errInjectedMsg)
+	ErrSync     = errors.New(errSyncMsg)
+)
+
+// Faults selects the faults injected into writes. FailEvery fails every Nth
+// write with Err, or ErrInjected when Err is nil, and ShortEvery writes only
+// half of every Nth write and returns io.ErrShortWrite; zero disables them.
+// Latency delays every write. SyncErr is returned by FaultyFile.Sync.
+type Faults struct {
+	Err        error
+	SyncErr    error
+	Latency    time.Duration
+	FailEvery  int
+	ShortEvery int
+}
+
+// FaultySink wraps a logger.Sink and injects faults into its writes.
+type FaultySink struct {
+	inner    logger.Sink
+	faults   Faults
+	failNext int
+	writes   int
+	failures int
+	mu       sync.Mutex
+}
+
+// NewFaultySink wraps inner with faults.
+func NewFaultySink(inner logger.Sink, faults Faults) *FaultySink {
+	return &FaultySink{inner: inner, faults: faults, failNext: 0, writes: 0, failures: 0, mu: sync.Mutex{}}
+}
+
+// SetFaults replaces the injected faults, e.g. to heal the sink mid-test.
+func (sink *FaultySink) SetFaults(faults Faults) {
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+
+	sink.faults = faults
+}
+
+// FailNext makes the next count writes fail regardless of the faults.
+func (sink *FaultySink) FailNext(count int) {
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+
+	sink.failNext = count
+}
+
+// Writes returns the number of writes attempted so far.
+func (sink *FaultySink) Writes() int {
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+
+	return sink.writes
+}
+
+// Failures returns the number of writes that failed or were short.
+func (sink *FaultySink) Failures() int {
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+
+	return sink.failures
+}
+
+// Write injects the current faults and otherwise writes data to the inner
+// sink.
+func (sink *FaultySink) Write(data []byte) (int, error) {
+	faults, fail, short := sink.next()
+
+	if faults.Latency > 0 {
+		time.Sleep(faults.Latency)
+	}
+
+	switch {
+	case fail:
+		if faults.Err != nil {
+			return 0, faults.Err
+		}
+
+		return 0, ErrInjected
+	case short:
+		n, err := sink.inner.Write(data[:len(data)/shortWriteRatio])
+		if err != nil {
+			return n, err
+		}
+
+		return n, io.ErrShortWrite
+	default:
+		return sink.inner.Write(data)
+	}
+}
+
+// Close closes the inner sink.
+func (sink *FaultySink) Close() error {
+	return sink.inner.Close()
+}
+
+// next counts a write and decides its faults.
+func (sink *FaultySink) next() (Faults, bool, bool) {
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+
+	sink.writes++
+
+	fail := sink.failNext > 0 || every(sink.faults.FailEvery, sink.writes)
+	short := !fail && every(sink.faults.ShortEvery, sink.writes)
+
+	if sink.failNext > 0 {
+		sink.failNext--
+	}
+
+	if fail || short {
+		sink.failures++
+	}
+
+	return sink.faults, fail, short
+}
+
+func every(period, count int) bool {
+	return period > 0 && count%period == 0
+}
+
+// FaultyFile is a file that injects faults into its writes and syncs.
+type FaultyFile struct {
+	*FaultySink
+
+	file *os.File
+}
+
+// OpenFaultyFile opens path for appending, creating it if needed, with
+// faults.
+func OpenFaultyFile(path string, faults Faults) (*FaultyFile, error) {
+	// #nosec G304 -- tests choose the path.
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, filePerm)
+	if err != nil {
+		return nil, fmt.Errorf(errFmtOpenFile, err)
+	}
+
+	return &FaultyFile{FaultySink: NewFaultySink(file, faults), file: file}, nil
+}
+
+// Name returns the path of the file.
+func (file *FaultyFile) Name() string {
+	return file.file.Name()
+}
+
+// Sync returns the injected SyncErr, or commits the file to disk.
+func (file *FaultyFile) Sync() error {
+	file.mu.Lock()
+	syncErr := file.faults.SyncErr
+	file.mu.Unlock()
+
+	if syncErr != nil {
+		return syncErr
+	}
+
+	return file.file.Sync()
+}
@@ -0,0 +1,162 @@
+package loggertest_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/book-expert/logger"
+	"github.com/book-expert/logger/loggertest"
+)
+
+const (
+	testLine       = "line one\n"
+	testFile       = "faulty.log"
+	testLatency    = 20 * time.Millisecond
+	testRetries    = 3
+	writeErrFmt    = "write %d: expected %v, got %v"
+	shortFmt       = "expected %d bytes and io.ErrShortWrite, got %d, %v"
+	latencyFmt     = "expected a write of at least %v, took %v"
+	retryErrFmt    = "RetrySink.Write: %v"
+	countFmt       = "expected %d writes and %d failures, got %d and %d"
+	openErrFmt     = "OpenFaultyFile: %v"
+	syncErrFmt     = "expected ErrSync, got %v"
+	fileContentFmt = "expected %q in the file, got %q"
+)
+
+var (
+	errDiskFull = errors.New("disk full")
+	noFaults    = loggertest.Faults{Err: nil, SyncErr: nil, Latency: 0, FailEvery: 0, ShortEvery: 0}
+)
+
+func newFaultySink(faults loggertest.Faults) (*loggertest.FaultySink, *bytes.Buffer) {
+	var buf bytes.Buffer
+
+	return loggertest.NewFaultySink(logger.WriterSink(&buf), faults), &buf
+}
+
+func TestFaultySink_FailEvery(t *testing.T) {
+	t.Parallel()
+
+	sink, _ := newFaultySink(loggertest.Faults{
+		Err:        errDiskFull,
+		SyncErr:    nil,
+		Latency:    0,
+		FailEvery:  2,
+		ShortEvery: 0,
+	})
+
+	for index, want := range []error{nil, errDiskFull, nil, errDiskFull} {
+		_, err := sink.Write([]byte(testLine))
+		if !errors.Is(err, want) {
+			t.Errorf(writeErrFmt, index+1, want, err)
+		}
+	}
+}
+
+func TestFaultySink_ShortWrites(t *testing.T) {
+	t.Parallel()
+
+	sink, buf := newFaultySink(loggertest.Faults{
+		Err:        nil,
+		SyncErr:    nil,
+		Latency:    0,
+		FailEvery:  0,
+		ShortEvery: 1,
+	})
+
+	n, err := sink.Write([]byte(testLine))
+	if n != len(testLine)/2 || !errors.Is(err, io.ErrShortWrite) || buf.Len() != n {
+		t.Errorf(shortFmt, len(testLine)/2, n, err)
+	}
+}
+
+func TestFaultySink_Latency(t *testing.T) {
+	t.Parallel()
+
+	sink, _ := newFaultySink(loggertest.Faults{
+		Err:        nil,
+		SyncErr:    nil,
+		Latency:    testLatency,
+		FailEvery:  0,
+		ShortEvery: 0,
+	})
+
+	start := time.Now()
+
+	_, _ = sink.Write([]byte(testLine))
+
+	if elapsed := time.Since(start); elapsed < testLatency {
+		t.Errorf(latencyFmt, testLatency, elapsed)
+	}
+}
+
+func TestFaultySink_RetrySinkRecovers(t *testing.T) {
+	t.Parallel()
+
+	faulty, buf := newFaultySink(noFaults)
+	faulty.FailNext(testRetries - 1)
+
+	sink := logger.NewRetrySink(faulty, logger.RetryPolicy{
+		DeadLetter:  nil,
+		Delay:       time.Millisecond,
+		MaxDelay:    time.Millisecond,
+		Jitter:      0,
+		MaxAttempts: testRetries,
+	})
+	defer sink.Close()
+
+	_, err := sink.Write([]byte(testLine))
+	if err != nil {
+		t.Fatalf(retryErrFmt, err)
+	}
+
+	if faulty.Writes() != testRetries || faulty.Failures() != testRetries-1 || buf.String() != testLine {
+		t.Errorf(countFmt, testRetries, testRetries-1, faulty.Writes(), faulty.Failures())
+	}
+}
+
+func TestFaultyFile_SyncAndHeal(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), testFile)
+
+	file, err := loggertest.OpenFaultyFile(path, loggertest.Faults{
+		Err:        nil,
+		SyncErr:    loggertest.ErrSync,
+		Latency:    0,
+		FailEvery:  1,
+		ShortEvery: 0,
+	})
+	if err != nil {
+		t.Fatalf(openErrFmt, err)
+	}
+	defer file.Close()
+
+	_, err = file.Write([]byte(testLine))
+	if !errors.Is(err, loggertest.ErrInjected) {
+		t.Errorf(writeErrFmt, 1, loggertest.ErrInjected, err)
+	}
+
+	err = file.Sync()
+	if !errors.Is(err, loggertest.ErrSync) {
+		t.Errorf(syncErrFmt, err)
+	}
+
+	file.SetFaults(noFaults)
+
+	_, err = file.Write([]byte(testLine))
+	if err != nil {
+		t.Errorf(writeErrFmt, 2, nil, err)
+	}
+
+	content, _ := os.ReadFile(file.Name())
+	if !strings.Contains(string(content), testLine) {
+		t.Errorf(fileContentFmt, testLine, content)
+	}
+}
@@ -0,0 +1,109 @@
+package loggertest
+
+import (
+	"io"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/book-expert/logger"
+)
+
+const errFmtObservedClose = "close observed logger: %v"
+
+// Observer records the entries a logger writes, with their fields, levels
+// and timestamps, so that tests can assert on them without reading log
+// files. Each Observer holds its own entries; tests using separate
+// observers can run in parallel.
+type Observer struct {
+	entries []logger.Entry
+	mu      sync.Mutex
+}
+
+// NewObserver returns an empty Observer. Pass its Option to the logger under
+// test.
+func NewObserver() *Observer {
+	return &Observer{entries: nil, mu: sync.Mutex{}}
+}
+
+// NewObservedLogger returns a logger discarding its output, configured with
+// opts, and the Observer recording its entries. The logger is closed when
+// the test ends.
+func NewObservedLogger(t testing.TB, opts ...logger.Option) (*logger.Logger, *Observer) {
+	t.Helper()
+
+	observer := NewObserver()
+	loggerInstance := logger.NewStreamLogger(io.Discard, append(opts, observer.Option())...)
+
+	t.Cleanup(func() {
+		err := loggerInstance.Close()
+		if err != nil {
+			t.Logf(errFmtObservedClose, err)
+		}
+	})
+
+	return loggerInstance, observer
+}
+
+// Option returns the logger option recording entries in the observer. It
+// adds a stage to the entry pipeline, so that entries are recorded in the
+// order they are written and as they are after the stages added before it;
+// entries dropped by an earlier stage are not recorded. With WithAsync, call
+// Flush before reading the entries.
+func (observer *Observer) Option() logger.Option {
+	return logger.WithEntryMiddleware(observer.observe)
+}
+
+// Entries returns copies of the recorded entries, oldest first.
+func (observer *Observer) Entries() []logger.Entry {
+	observer.mu.Lock()
+	defer observer.mu.Unlock()
+
+	entries := slices.Clone(observer.entries)
+	for index := range entries {
+		entries[index].Fields = slices.Clone(entries[index].Fields)
+	}
+
+	return entries
+}
+
+// Messages returns the messages of the recorded entries, oldest first.
+func (observer *Observer) Messages() []string {
+	observer.mu.Lock()
+	defer observer.mu.Unlock()
+
+	messages := make([]string, 0, len(observer.entries))
+	for _, entry := range observer.entries {
+		messages = append(messages, entry.Message)
+	}
+
+	return messages
+}
+
+// Len returns the number of recorded entries.
+func (observer *Observer) Len() int {
+	observer.mu.Lock()
+	defer observer.mu.Unlock()
+
+	return len(observer.entries)
+}
+
+// Reset discards the recorded entries.
+func (observer *Observer) Reset() {
+	observer.mu.Lock()
+	defer observer.mu.Unlock()
+
+	observer.entries = nil
+}
+
+// observe records a copy of entry and passes it on unchanged.
+func (observer *Observer) observe(entry logger.Entry) (logger.Entry, bool) {
+	recorded := entry
+	recorded.Fields = slices.Clone(entry.Fields)
+
+	observer.mu.Lock()
+	observer.entries = append(observer.entries, recorded)
+	observer.mu.Unlock()
+
+	return entry, true
+}
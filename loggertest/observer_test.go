@@ -0,0 +1,81 @@
+package loggertest_test
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/book-expert/logger"
+	"github.com/book-expert/logger/loggertest"
+)
+
+const (
+	observerParallel = 8
+	observerMsgFmt   = "step %d"
+	observerKey      = "run"
+	observerLevelFmt = "entry %d: expected level %v, got %v"
+	observerMsgsFmt  = "expected messages %q, got %q"
+	observerFieldFmt = "expected field %s=%d, got %v"
+	observerTimeFmt  = "expected the fixed time %v, got %v"
+	observerLenFmt   = "expected %d entries, got %d"
+)
+
+func TestObserver_RecordsEntriesInOrder(t *testing.T) {
+	t.Parallel()
+
+	loggerInstance, observer := loggertest.NewObservedLogger(t, logger.WithClock(func() time.Time {
+		return loggertest.FixedTime
+	}))
+	loggerInstance.Debugf(observerMsgFmt, 0)
+	loggerInstance.Infof(observerMsgFmt, 1)
+	loggerInstance.Warnf(observerMsgFmt, 2)
+
+	expected := []string{"step 1", "step 2"}
+	if messages := observer.Messages(); !slices.Equal(messages, expected) {
+		t.Fatalf(observerMsgsFmt, expected, messages)
+	}
+
+	for index, entry := range observer.Entries() {
+		want := []logger.Level{logger.LevelInfo, logger.LevelWarn}[index]
+		if entry.Level != want {
+			t.Errorf(observerLevelFmt, index, want, entry.Level)
+		}
+
+		if !entry.Time.Equal(loggertest.FixedTime) {
+			t.Errorf(observerTimeFmt, loggertest.FixedTime, entry.Time)
+		}
+	}
+
+	observer.Reset()
+
+	if observer.Len() != 0 {
+		t.Errorf(observerLenFmt, 0, observer.Len())
+	}
+}
+
+// TestObserver_ParallelIsolation runs parallel subtests, each with its own
+// observer, and checks that none sees another's entries.
+func TestObserver_ParallelIsolation(t *testing.T) {
+	t.Parallel()
+
+	for run := range observerParallel {
+		t.Run(strconv.Itoa(run), func(t *testing.T) {
+			t.Parallel()
+
+			loggerInstance, observer := loggertest.NewObservedLogger(t, logger.WithAsync(observerParallel))
+			loggerInstance.With(logger.F(observerKey, run)).Infof(observerMsgFmt, run)
+			loggerInstance.Flush()
+
+			entries := observer.Entries()
+			if len(entries) != 1 {
+				t.Fatalf(observerLenFmt, 1, len(entries))
+			}
+
+			fields := entries[0].Fields
+			if len(fields) != 1 || fields[0].Key != observerKey || fields[0].Value != run {
+				t.Errorf(observerFieldFmt, observerKey, run, fields)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	lokiPushPath      = "/loki/api/v1/push"
+	lokiContentType   = "application/json"
+	lokiDefaultLabel  = "job"
+	lokiDefaultJob    = "logger"
+	lokiLineEnd       = '\n'
+	lokiTimestampBase = 10
+	errFmtLokiEncode  = "encode loki push: %w"
+)
+
+// lokiSink pushes lines to Grafana Loki as one stream.
+type lokiSink struct {
+	httpSink
+
+	labels map[string]string
+}
+
+// lokiPush is the body of a Loki push request.
+type lokiPush struct {
+	Streams []lokiStream `json:"streams"`
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// newLokiSink creates a sink for a loki:// or loki+https:// address. Query
+// parameters become stream labels; without any, the stream is job="logger".
+func newLokiSink(address *url.URL) *lokiSink {
+	scheme := SinkSchemeHTTP
+	if address.Scheme == SinkSchemeLokiTLS {
+		scheme = SinkSchemeHTTPS
+	}
+
+	labels := map[string]string{lokiDefaultLabel: lokiDefaultJob}
+	if query := address.Query(); len(query) > 0 {
+		labels = make(map[string]string, len(query))
+		for key := range query {
+			labels[key] = query.Get(key)
+		}
+	}
+
+	push := url.URL{Scheme: scheme, Host: address.Host, Path: address.Path + lokiPushPath}
+
+	return &lokiSink{
+		httpSink: httpSink{client: &http.Client{Timeout: DefaultSinkTimeout}, url: push.String()},
+		labels:   labels,
+	}
+}
+
+// Write pushes every line of data, stamped with the current time in
+// nanoseconds and one more per line to keep their order.
+func (sink *lokiSink) Write(data []byte) (int, error) {
+	now := time.Now().UnixNano()
+	stream := lokiStream{Stream: maps.Clone(sink.labels), Values: nil}
+
+	for line := range bytes.SplitSeq(bytes.TrimSuffix(data, []byte{lokiLineEnd}), []byte{lokiLineEnd}) {
+		timestamp := strconv.FormatInt(now+int64(len(stream.Values)), lokiTimestampBase)
+		stream.Values = append(stream.Values, [2]string{timestamp, string(line)})
+	}
+
+	body, err := json.Marshal(lokiPush{Streams: []lokiStream{stream}})
+	if err != nil {
+		return 0, fmt.Errorf(errFmtLokiEncode, err)
+	}
+
+	err = sink.post(body, lokiContentType)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(data), nil
+}
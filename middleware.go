@@ -0,0 +1,42 @@
+package logger
+
+// EntryMiddleware is a stage of the entry pipeline. It receives each entry
+// after formatting and before encoding and returns the entry to pass on,
+// possibly modified, and whether to keep it. Stages can redact, filter,
+// enrich or sample entries; they run on the logging goroutine in the order
+// they were added, and may log themselves.
+type EntryMiddleware func(Entry) (Entry, bool)
+
+// WithEntryMiddleware appends stages to the entry pipeline. Mandatory entries,
+// such as those logged with LogMandatory or Securityf, cannot be dropped: a
+// stage that drops one is skipped for that entry. When a stage changes Level
+// without changing Label, the label follows the new level.
+func WithEntryMiddleware(middleware ...EntryMiddleware) Option {
+	return func(config *options) {
+		config.middleware = append(config.middleware, middleware...)
+	}
+}
+
+// applyMiddleware runs the pipeline on logEntry and reports whether to write
+// it.
+func (c *loggerCore) applyMiddleware(logEntry *Entry) bool {
+	for _, middleware := range c.middleware {
+		next, keep := middleware(*logEntry)
+		if !keep {
+			if logEntry.Mandatory {
+				continue
+			}
+
+			return false
+		}
+
+		if next.Level != logEntry.Level && next.Label == logEntry.Label {
+			next.Label = c.label(next.Level)
+		}
+
+		next.Mandatory = logEntry.Mandatory
+		*logEntry = next
+	}
+
+	return true
+}
@@ -0,0 +1,81 @@
+package logger_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	middlewareLayout   = "[{level}] {msg} {fields}"
+	middlewareSecret   = "hunter2"
+	middlewareMask     = "***"
+	middlewareLoginMsg = "login password=%s"
+	middlewareNoisyKey = "noisy"
+	middlewareNoisyMsg = "heartbeat"
+	middlewareTimeout  = "upstream timeout"
+	middlewareAuditMsg = "config changed"
+	middlewareStageKey = "stage"
+	middlewareStage    = "enriched"
+	middlewareExpected = "[INFO] login password=*** stage=enriched\n" +
+		"[ERROR] upstream timeout stage=enriched\n" +
+		"[SYSTEM] config changed stage=enriched\n"
+	middlewareOutputFmt = "expected:\n%s\ngot:\n%s"
+)
+
+func TestWithEntryMiddleware_Stages(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	redact := func(entry logger.Entry) (logger.Entry, bool) {
+		entry.Message = strings.ReplaceAll(entry.Message, middlewareSecret, middlewareMask)
+
+		return entry, true
+	}
+
+	dropNoisy := func(entry logger.Entry) (logger.Entry, bool) {
+		for _, field := range entry.Fields {
+			if field.Key == middlewareNoisyKey {
+				return entry, false
+			}
+		}
+
+		return entry, true
+	}
+
+	escalateTimeouts := func(entry logger.Entry) (logger.Entry, bool) {
+		if strings.Contains(entry.Message, middlewareTimeout) {
+			entry.Level = logger.LevelError
+		}
+
+		return entry, true
+	}
+
+	enrich := func(entry logger.Entry) (logger.Entry, bool) {
+		entry.Fields = append(entry.Fields, logger.F(middlewareStageKey, middlewareStage))
+
+		return entry, true
+	}
+
+	loggerInstance := logger.NewStreamLogger(&buf,
+		logger.WithLayout(logger.MustParseLayout(middlewareLayout)),
+		logger.WithEntryMiddleware(redact, dropNoisy, escalateTimeouts),
+		logger.WithEntryMiddleware(enrich),
+		logger.WithEntryMiddleware(func(entry logger.Entry) (logger.Entry, bool) {
+			// Mandatory entries survive a dropping stage.
+			return entry, !entry.Mandatory
+		}),
+	)
+
+	loggerInstance.Infof(middlewareLoginMsg, middlewareSecret)
+	loggerInstance.With(logger.F(middlewareNoisyKey, true)).Infof(middlewareNoisyMsg)
+	loggerInstance.Warnf(middlewareTimeout)
+	loggerInstance.LogMandatory(logger.LevelSystem, middlewareAuditMsg)
+
+	if buf.String() != middlewareExpected {
+		t.Errorf(middlewareOutputFmt, middlewareExpected, buf.String())
+	}
+}
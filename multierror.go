@@ -0,0 +1,66 @@
+package logger
+
+import "strconv"
+
+const (
+	// ErrorCountField carries the number of errors grouped by LogErrors.
+	ErrorCountField = "error_count"
+	// ErrorFieldPrefix starts the field of every error grouped by LogErrors,
+	// followed by its index: error.0, error.1 and so on.
+	ErrorFieldPrefix = "error."
+
+	logErrorsFmt = "%d errors"
+	logErrorFmt  = "1 error"
+)
+
+// LogErrors writes the errors in errs, such as the failures of a batch, as
+// one entry at level rather than one line each: the entry carries the count
+// in ErrorCountField and every error in its own ErrorFieldPrefix field.
+// Errors joined with errors.Join, or wrapping several errors otherwise, are
+// expanded into the errors they hold; nil errors are skipped and nothing is
+// logged when none remain.
+func (l *Logger) LogErrors(level Level, errs []error) {
+	if !l.core.enabled(level) {
+		return
+	}
+
+	flat := flattenErrors(nil, errs)
+	if len(flat) == 0 {
+		return
+	}
+
+	fields := make([]Field, 0, len(flat)+1)
+	fields = append(fields, F(ErrorCountField, len(flat)))
+
+	for index, err := range flat {
+		fields = append(fields, F(ErrorFieldPrefix+strconv.Itoa(index), err.Error()))
+	}
+
+	format, args := logErrorsFmt, []any{len(flat)}
+	if len(flat) == 1 {
+		format, args = logErrorFmt, nil
+	}
+
+	l.writef(level, fields, format, args...)
+}
+
+// flattenErrors appends the non-nil errors of errs to flat, expanding the
+// errors that wrap several others.
+func flattenErrors(flat, errs []error) []error {
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+
+		joined, ok := err.(interface{ Unwrap() []error })
+		if ok {
+			flat = flattenErrors(flat, joined.Unwrap())
+
+			continue
+		}
+
+		flat = append(flat, err)
+	}
+
+	return flat
+}
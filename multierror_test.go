@@ -0,0 +1,88 @@
+package logger_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/book-expert/logger"
+	"github.com/book-expert/logger/loggertest"
+)
+
+const (
+	multiErrPage1     = "page 1: OCR timeout"
+	multiErrPage2     = "page 2: blank"
+	multiErrPage3     = "page 3: corrupt image"
+	multiErrWrapFmt   = "batch: %w"
+	multiErrWrapped   = "batch: page 3: corrupt image"
+	multiErrMessage   = "3 errors"
+	multiErrSingleMsg = "1 error"
+	multiErrCountFmt  = "entries = %d; want %d"
+	multiErrEntryFmt  = "entry = %+v; want %s with fields %v"
+)
+
+func TestLogger_LogErrors(t *testing.T) {
+	t.Parallel()
+
+	loggerInstance, observer := loggertest.NewObservedLogger(t)
+	loggerInstance.LogErrors(logger.LevelWarn, []error{
+		errors.Join(errors.New(multiErrPage1), nil, errors.New(multiErrPage2)),
+		nil,
+		fmt.Errorf(multiErrWrapFmt, errors.New(multiErrPage3)),
+	})
+
+	want := []logger.Field{
+		logger.F(logger.ErrorCountField, 3),
+		logger.F(logger.ErrorFieldPrefix+"0", multiErrPage1),
+		logger.F(logger.ErrorFieldPrefix+"1", multiErrPage2),
+		logger.F(logger.ErrorFieldPrefix+"2", multiErrWrapped),
+	}
+
+	expectErrorsEntry(t, observer, logger.LevelWarn, multiErrMessage, want)
+}
+
+func TestLogger_LogErrorsSingle(t *testing.T) {
+	t.Parallel()
+
+	loggerInstance, observer := loggertest.NewObservedLogger(t)
+	loggerInstance.LogErrors(logger.LevelError, []error{nil, errors.New(multiErrPage1)})
+
+	want := []logger.Field{
+		logger.F(logger.ErrorCountField, 1),
+		logger.F(logger.ErrorFieldPrefix+"0", multiErrPage1),
+	}
+
+	expectErrorsEntry(t, observer, logger.LevelError, multiErrSingleMsg, want)
+}
+
+func TestLogger_LogErrorsNone(t *testing.T) {
+	t.Parallel()
+
+	loggerInstance, observer := loggertest.NewObservedLogger(t)
+	loggerInstance.LogErrors(logger.LevelError, nil)
+	loggerInstance.LogErrors(logger.LevelError, []error{nil, errors.Join(nil)})
+
+	if observer.Len() != 0 {
+		t.Errorf(multiErrCountFmt, observer.Len(), 0)
+	}
+}
+
+func expectErrorsEntry(
+	t *testing.T,
+	observer *loggertest.Observer,
+	level logger.Level,
+	message string,
+	want []logger.Field,
+) {
+	t.Helper()
+
+	entries := observer.Entries()
+	if len(entries) != 1 {
+		t.Fatalf(multiErrCountFmt, len(entries), 1)
+	}
+
+	entry := entries[0]
+	if entry.Level != level || entry.Message != message || fmt.Sprint(entry.Fields) != fmt.Sprint(want) {
+		t.Errorf(multiErrEntryFmt, entry, message, want)
+	}
+}
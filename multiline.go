@@ -0,0 +1,136 @@
+package logger
+
+import (
+	"cmp"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultContinuationPattern matches the lines that continue a Java stack
+	// trace: indented frames, "Caused by:", "Suppressed:" and "... n more".
+	DefaultContinuationPattern = `^(?:\s|Caused by:|Suppressed:|\.\.\. \d+ (?:more|common frames omitted))`
+
+	// Defaults of Multiline.
+	DefaultMultilineTimeout  = 5 * time.Second
+	DefaultMultilineMaxLines = 500
+
+	multilineSeparator = "\n"
+)
+
+var defaultContinuation = regexp.MustCompile(DefaultContinuationPattern)
+
+// Multiline configures IngestSink.Stitch. A line matching Continuation, or
+// not matching it when Negate is set, continues the entry of the lines before
+// it. Zero fields take the Default values.
+type Multiline struct {
+	Continuation *regexp.Regexp
+	Timeout      time.Duration
+	MaxLines     int
+	Negate       bool
+}
+
+// stitcher holds the entry an IngestSink is stitching.
+type stitcher struct {
+	timer   *time.Timer
+	stamp   time.Time
+	first   string
+	rest    []string
+	config  Multiline
+	mu      sync.Mutex
+	pending bool
+}
+
+// Stitch returns a sink like sink that joins the lines of multiline entries,
+// such as Java or Python stack traces, into one entry instead of one per
+// line. The first line is parsed as usual and the continuation lines are
+// appended to its message. An entry is logged when a line that does not
+// continue it arrives, when it reaches MaxLines, when no line arrived for
+// Timeout, or on Close. For Python tracebacks, whose last line is not
+// indented, match the first line of every entry instead, such as its
+// timestamp, and set Negate. A Shipper acknowledges the lines of an entry
+// still being stitched, so a crash can lose that entry.
+func (sink *IngestSink) Stitch(multiline Multiline) *IngestSink {
+	multiline.Continuation = cmp.Or(multiline.Continuation, defaultContinuation)
+	multiline.Timeout = cmp.Or(multiline.Timeout, DefaultMultilineTimeout)
+	multiline.MaxLines = cmp.Or(multiline.MaxLines, DefaultMultilineMaxLines)
+
+	return &IngestSink{
+		logger: sink.logger,
+		parser: sink.parser,
+		stitcher: &stitcher{
+			timer:   nil,
+			stamp:   time.Time{},
+			first:   "",
+			rest:    nil,
+			config:  multiline,
+			mu:      sync.Mutex{},
+			pending: false,
+		},
+	}
+}
+
+// stitch adds each of lines to the pending entry when it continues it, and
+// otherwise logs the pending entry and starts the next one with the line.
+// The pending entry is logged once no line arrived for the timeout.
+func (sink *IngestSink) stitch(lines []string, now time.Time) {
+	state := sink.stitcher
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	for _, line := range lines {
+		if state.pending && state.continues(line) && len(state.rest)+1 < state.config.MaxLines {
+			state.rest = append(state.rest, line)
+
+			continue
+		}
+
+		sink.flushLocked()
+		state.first, state.stamp, state.pending = line, now, true
+	}
+
+	if !state.pending {
+		return
+	}
+
+	if state.timer == nil {
+		state.timer = time.AfterFunc(state.config.Timeout, sink.flush)
+
+		return
+	}
+
+	state.timer.Reset(state.config.Timeout)
+}
+
+// continues reports whether line continues the pending entry.
+func (state *stitcher) continues(line string) bool {
+	return state.config.Continuation.MatchString(line) != state.config.Negate
+}
+
+// flush logs the pending entry.
+func (sink *IngestSink) flush() {
+	sink.stitcher.mu.Lock()
+	defer sink.stitcher.mu.Unlock()
+
+	sink.flushLocked()
+}
+
+// flushLocked logs the pending entry. The caller holds the stitcher's mu.
+func (sink *IngestSink) flushLocked() {
+	state := sink.stitcher
+	if !state.pending {
+		return
+	}
+
+	parsed := sink.parser.Parse(state.first, state.stamp)
+	if len(state.rest) > 0 {
+		parsed.Message += multilineSeparator + strings.Join(state.rest, multilineSeparator)
+	}
+
+	state.first, state.rest, state.pending = "", nil, false
+
+	sink.logger.logParsed(parsed)
+}
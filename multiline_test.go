@@ -0,0 +1,122 @@
+package logger_test
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	multilineJava = "2025-01-02T15:04:05Z ERROR request failed\n" +
+		"java.lang.IllegalStateException: closed\n" +
+		"\tat com.example.Pool.get(Pool.java:42)\n" +
+		"Caused by: java.io.IOException: reset\n" +
+		"\t... 3 more\n" +
+		"2025-01-02T15:04:06Z INFO recovered\n"
+	multilineJavaWant = "[ERROR] request failed\n" +
+		"[INFO] java.lang.IllegalStateException: closed\n" +
+		"\tat com.example.Pool.get(Pool.java:42)\n" +
+		"Caused by: java.io.IOException: reset\n" +
+		"\t... 3 more\n" +
+		"[INFO] recovered\n"
+	multilinePython = "2025-01-02 15:04:05 ERROR job failed\n" +
+		"Traceback (most recent call last):\n" +
+		"  File \"job.py\", line 3, in <module>\n" +
+		"ValueError: bad page\n"
+	multilinePythonWant = "[ERROR] job failed\n" +
+		"Traceback (most recent call last):\n" +
+		"  File \"job.py\", line 3, in <module>\n" +
+		"ValueError: bad page\n"
+	multilineStart     = `^\d{4}-\d\d-\d\d`
+	multilineLayout    = "[{level}] {msg}"
+	multilineTimeout   = 20 * time.Millisecond
+	multilineMaxLines  = 2
+	multilineOutputFmt = "output:\n%s\nwant:\n%s"
+	multilineCloseFmt  = "close: %v"
+)
+
+func TestIngestSink_StitchJava(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	loggerInstance := logger.NewStreamLogger(&buf, logger.WithLayout(logger.MustParseLayout(multilineLayout)))
+	sink := loggerInstance.IngestSink(newLineParser(t, "", "")).Stitch(logger.Multiline{
+		Continuation: nil,
+		Timeout:      time.Hour,
+		MaxLines:     0,
+		Negate:       false,
+	})
+
+	writeMultiline(t, sink, multilineJava)
+	closeIngestSink(t, sink)
+	closeTestLogger(t, loggerInstance)
+
+	// The exception line does not continue the ERROR entry and starts its own.
+	if buf.String() != multilineJavaWant {
+		t.Errorf(multilineOutputFmt, buf.String(), multilineJavaWant)
+	}
+}
+
+func TestIngestSink_StitchNegatedTimeout(t *testing.T) {
+	t.Parallel()
+
+	var buf syncBuffer
+
+	loggerInstance := logger.NewStreamLogger(&buf, logger.WithLayout(logger.MustParseLayout(multilineLayout)))
+	defer closeTestLogger(t, loggerInstance)
+
+	sink := loggerInstance.IngestSink(newLineParser(t, "", "")).Stitch(logger.Multiline{
+		Continuation: regexp.MustCompile(multilineStart),
+		Timeout:      multilineTimeout,
+		MaxLines:     0,
+		Negate:       true,
+	})
+
+	writeMultiline(t, sink, multilinePython)
+	waitForOutput(t, &buf, multilinePythonWant)
+}
+
+func TestIngestSink_StitchMaxLines(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	loggerInstance := logger.NewStreamLogger(&buf, logger.WithLayout(logger.MustParseLayout(multilineLayout)))
+	sink := loggerInstance.IngestSink(newLineParser(t, "", "")).Stitch(logger.Multiline{
+		Continuation: regexp.MustCompile(multilineStart),
+		Timeout:      time.Hour,
+		MaxLines:     multilineMaxLines,
+		Negate:       true,
+	})
+
+	writeMultiline(t, sink, multilinePython)
+	closeIngestSink(t, sink)
+	closeTestLogger(t, loggerInstance)
+
+	if strings.Count(buf.String(), "[") != multilineMaxLines {
+		t.Errorf(multilineOutputFmt, buf.String(), multilinePythonWant)
+	}
+}
+
+func writeMultiline(t *testing.T, sink *logger.IngestSink, text string) {
+	t.Helper()
+
+	_, err := sink.Write([]byte(text))
+	if err != nil {
+		t.Fatalf(ingestWriteFmt, err)
+	}
+}
+
+func closeIngestSink(t *testing.T, sink *logger.IngestSink) {
+	t.Helper()
+
+	err := sink.Close()
+	if err != nil {
+		t.Fatalf(multilineCloseFmt, err)
+	}
+}
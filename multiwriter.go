@@ -0,0 +1,43 @@
+package logger
+
+import (
+	"errors"
+	"io"
+)
+
+// multiWriter duplicates writes to several writers.
+type multiWriter struct {
+	writers []io.Writer
+}
+
+// NewWithWriters creates a Logger writing every line to each of writers, for
+// callers that manage their own files, buffers or pipes. It skips the log
+// directory and filename validation of New; use NewStreamLogger with
+// MultiWriter to pass options as well.
+func NewWithWriters(writers ...io.Writer) *Logger {
+	return NewStreamLogger(MultiWriter(writers...))
+}
+
+// MultiWriter returns a writer that duplicates each write to every writer.
+// Unlike io.MultiWriter, a failing writer does not stop the others: every
+// writer receives the data and the errors are joined.
+func MultiWriter(writers ...io.Writer) io.Writer {
+	return &multiWriter{writers: writers}
+}
+
+func (mw *multiWriter) Write(data []byte) (int, error) {
+	var errs []error
+
+	for _, writer := range mw.writers {
+		n, err := writer.Write(data)
+		if err == nil && n < len(data) {
+			err = io.ErrShortWrite
+		}
+
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return len(data), errors.Join(errs...)
+}
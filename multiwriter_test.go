@@ -0,0 +1,36 @@
+package logger_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	multiMessage    = "fan out"
+	multiMissingFmt = "writer %d missing %q; got %q"
+	multiErrFmt     = "Err() = %v, want %v"
+)
+
+func TestNewWithWriters(t *testing.T) {
+	t.Parallel()
+
+	var first, second bytes.Buffer
+
+	loggerInstance := logger.NewWithWriters(&first, failingWriter{}, &second)
+	loggerInstance.Infof(multiMessage)
+
+	for index, buf := range []*bytes.Buffer{&first, &second} {
+		if !strings.Contains(buf.String(), multiMessage) {
+			t.Errorf(multiMissingFmt, index, multiMessage, buf.String())
+		}
+	}
+
+	err := loggerInstance.Err()
+	if !errors.Is(err, errTestWriteFailed) || !errors.Is(err, logger.ErrWriteStdout) {
+		t.Errorf(multiErrFmt, err, errTestWriteFailed)
+	}
+}
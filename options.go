@@ -0,0 +1,99 @@
+package logger
+
+import (
+	"slices"
+	"time"
+)
+
+// Option configures a Logger at construction time.
+type Option func(*options)
+
+// defaultLayout is shared by all loggers; layouts are immutable once parsed.
+var defaultLayout = MustParseLayout(DefaultLayout)
+
+type options struct {
+	escalationRules     []EscalationRule
+	routes              []Route
+	middleware          []EntryMiddleware
+	globalFields        []Field
+	dynamicFields       []dynamicField
+	accessLog           *AccessLog
+	errorBudget         ErrorBudget
+	backfill            Backfill
+	attachmentRetention AttachmentRetention
+	ring                *ringOptions
+	aggregation         *Aggregation
+	rateAnomalies       *RateAnomalies
+	diskLimiter         *DiskLimiter
+	layout              *Layout
+	timezone            *time.Location
+	stdoutTimezone      *time.Location
+	stdoutLocale        *Locale
+	clock               func() time.Time
+	levelLabels         map[Level]string
+	levelSymbols        map[Level]string
+	producerQuotas      map[string]ProducerQuota
+	auditKey            []byte
+	auditKeyID          string
+	audit               bool
+	profileLabels       bool
+	withoutFile         bool
+	withoutStdout       bool
+	lazyFile            bool
+	spillover           bool
+	crashReports        bool
+	vectoredWrites      bool
+	walSize             int
+	asyncBuffer         int
+	coalesceBatch       int
+	sinkQueueSize       int
+	syncPolicy          SyncPolicy
+	encoding            Encoding
+	minLevel            Level
+	timeFormat          TimeFormat
+	dropSummaryInterval time.Duration
+	recentWindow        time.Duration
+}
+
+func newOptions(opts []Option) *options {
+	config := &options{
+		layout:              defaultLayout,
+		dropSummaryInterval: DefaultDropSummaryInterval,
+		clock:               time.Now,
+		attachmentRetention: AttachmentRetention{
+			MaxAge:   DefaultAttachmentMaxAge,
+			MaxFiles: DefaultAttachmentMaxFiles,
+		},
+	}
+
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	return config
+}
+
+// WithLayout sets the template used to render text lines. See Layout for the
+// template syntax. A nil layout keeps the default.
+func WithLayout(layout *Layout) Option {
+	return func(config *options) {
+		if layout != nil {
+			config.layout = layout
+		}
+	}
+}
+
+// needsCaller reports whether any output renders the caller, which is costly
+// to resolve and therefore skipped otherwise.
+func (config *options) needsCaller() bool {
+	if config.layout.needsCaller || config.encoding == EncodingJSON {
+		return true
+	}
+
+	return slices.ContainsFunc(config.routes, func(route Route) bool { return route.needsCaller() })
+}
+
+// needsCaller reports whether the route renders the caller.
+func (route *Route) needsCaller() bool {
+	return route.Encoding == EncodingJSON || (route.Layout != nil && route.Layout.needsCaller)
+}
@@ -0,0 +1,90 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+)
+
+// WithoutFile makes New skip the main log file, for twelve-factor apps that
+// log to stdout only. The filename is then ignored and logDir may be empty;
+// a non-empty logDir still holds file routes, the access log and the ring
+// file, which are otherwise limited to writers as with NewStreamLogger. The
+// write-ahead log and audit mode protect the main log file and are ignored.
+func WithoutFile() Option {
+	return func(config *options) {
+		config.withoutFile = true
+	}
+}
+
+// WithoutStdout stops writing entries to stdout, or to the writer of
+// NewStreamLogger, for daemons that log to their file only. Routes still
+// receive their entries.
+func WithoutStdout() Option {
+	return func(config *options) {
+		config.withoutStdout = true
+	}
+}
+
+// newWithoutFile creates a logger for WithoutFile.
+func newWithoutFile(logDir string, config *options) (*Logger, error) {
+	loggerInstance := newLogger(os.Stdout, config)
+
+	if logDir == "" {
+		loggerInstance.core.openWriterSinks(config)
+	} else {
+		err := ValidatePath(logDir)
+		if err != nil {
+			return nil, fmt.Errorf(errFmtInvalidLogDir, err)
+		}
+
+		err = loggerInstance.core.openDirSinks(logDir, config)
+		if err != nil {
+			_ = loggerInstance.Close()
+
+			return nil, err
+		}
+	}
+
+	loggerInstance.core.startBackground(config)
+
+	return loggerInstance, nil
+}
+
+// openDirSinks opens the routes, access log and ring file configured for a
+// logger with a log directory. On error, the caller closes the logger to
+// release the sinks opened so far.
+func (c *loggerCore) openDirSinks(logDir string, config *options) error {
+	var err error
+
+	c.logDir = logDir
+
+	c.routes, err = openRoutes(logDir, config.routes, config.diskLimiter)
+	if err != nil {
+		return err
+	}
+
+	c.access, err = openAccessLogger(logDir, config.accessLog)
+	if err != nil {
+		return err
+	}
+
+	c.ring, err = openRingFile(logDir, config.ring)
+	if err != nil {
+		return err
+	}
+
+	c.aggregator, err = openAggregation(logDir, config.aggregation)
+	if err != nil {
+		return err
+	}
+
+	return c.openBackfillFile(logDir)
+}
+
+// openWriterSinks opens the sinks that need no log directory: routes and
+// access logs with a Writer.
+func (c *loggerCore) openWriterSinks(config *options) {
+	c.routes = writerRoutes(config.routes)
+	c.access, _ = openAccessLogger("", config.accessLog)
+	c.aggregator, _ = openAggregation("", config.aggregation)
+}
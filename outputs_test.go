@@ -0,0 +1,121 @@
+package logger_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	outputsLogFile    = "main.log"
+	outputsRouteFile  = "route.log"
+	outputsMessage    = "outputs check"
+	outputsNewFmt     = "New: %v"
+	outputsCloseFmt   = "close: %v"
+	outputsReadFmt    = "read %s: %v"
+	outputsStatFmt    = "stat %s: %v, want not exist"
+	outputsMissingFmt = "%s missing %q; got %q"
+	outputsStdoutFmt  = "stdout should be empty, got %q"
+)
+
+func TestWithoutFile_NoDirectory(t *testing.T) {
+	t.Parallel()
+
+	var routed bytes.Buffer
+
+	loggerInstance, err := logger.New("", "", logger.WithoutFile(), logger.WithoutStdout(),
+		logger.WithRoute(logger.Route{Writer: &routed}))
+	if err != nil {
+		t.Fatalf(outputsNewFmt, err)
+	}
+
+	loggerInstance.Infof(outputsMessage)
+	closeOutputsLogger(t, loggerInstance)
+
+	if !strings.Contains(routed.String(), outputsMessage) {
+		t.Errorf(outputsMissingFmt, "route", outputsMessage, routed.String())
+	}
+}
+
+func TestWithoutFile_KeepsFileRoutes(t *testing.T) {
+	t.Parallel()
+
+	logDir := t.TempDir()
+
+	loggerInstance, err := logger.New(logDir, outputsLogFile, logger.WithoutFile(), logger.WithoutStdout(),
+		logger.WithRoute(logger.Route{Filename: outputsRouteFile}))
+	if err != nil {
+		t.Fatalf(outputsNewFmt, err)
+	}
+
+	loggerInstance.Infof(outputsMessage)
+	closeOutputsLogger(t, loggerInstance)
+
+	_, err = os.Stat(filepath.Join(logDir, outputsLogFile))
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf(outputsStatFmt, outputsLogFile, err)
+	}
+
+	content := readOutputsFile(t, filepath.Join(logDir, outputsRouteFile))
+	if !strings.Contains(content, outputsMessage) {
+		t.Errorf(outputsMissingFmt, outputsRouteFile, outputsMessage, content)
+	}
+}
+
+func TestWithoutStdout_FileOnly(t *testing.T) {
+	t.Parallel()
+
+	logDir := t.TempDir()
+
+	loggerInstance, err := logger.New(logDir, outputsLogFile, logger.WithoutStdout())
+	if err != nil {
+		t.Fatalf(outputsNewFmt, err)
+	}
+
+	loggerInstance.Infof(outputsMessage)
+	closeOutputsLogger(t, loggerInstance)
+
+	content := readOutputsFile(t, filepath.Join(logDir, outputsLogFile))
+	if !strings.Contains(content, outputsMessage) {
+		t.Errorf(outputsMissingFmt, outputsLogFile, outputsMessage, content)
+	}
+}
+
+func TestWithoutStdout_StreamLogger(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	loggerInstance := logger.NewStreamLogger(&buf, logger.WithoutStdout())
+	loggerInstance.Infof(outputsMessage)
+	closeOutputsLogger(t, loggerInstance)
+
+	if buf.Len() != 0 {
+		t.Errorf(outputsStdoutFmt, buf.String())
+	}
+}
+
+func closeOutputsLogger(t *testing.T, loggerInstance *logger.Logger) {
+	t.Helper()
+
+	err := loggerInstance.Close()
+	if err != nil {
+		t.Errorf(outputsCloseFmt, err)
+	}
+}
+
+func readOutputsFile(t *testing.T, path string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf(outputsReadFmt, path, err)
+	}
+
+	return string(data)
+}
@@ -0,0 +1,174 @@
+// Package pipeline records the progress of book-expert pipeline stages
+// through a logger.
+//
+// Every service logs the same three events with the same fields: a stage
+// starts, completes or fails for a document. Started and completed stages are
+// INFO and SYSTEM entries, failed stages ERROR entries, so dashboards and
+// alerts can follow a document across services by its document_id.
+package pipeline
+
+import (
+	"time"
+
+	"github.com/book-expert/logger"
+)
+
+// Fields carried by pipeline events.
+const (
+	EventField      = "pipeline_event"
+	DocumentIDField = "document_id"
+	StageField      = "stage"
+	DurationField   = logger.DurationField
+	PagesField      = "pages"
+	TotalPagesField = "total_pages"
+	ErrorField      = "error"
+)
+
+// Values of EventField.
+const (
+	EventStageStarted   = "stage_started"
+	EventStageCompleted = "stage_completed"
+	EventStageFailed    = "stage_failed"
+
+	startedFmt   = "pipeline: %s started for %s"
+	completedFmt = "pipeline: %s completed for %s"
+	failedFmt    = "pipeline: %s failed for %s"
+)
+
+// StageStarted reports that stage began processing a document. TotalPages is
+// omitted when zero.
+type StageStarted struct {
+	DocumentID string
+	Stage      string
+	TotalPages int
+}
+
+// StageCompleted reports that stage finished a document after Duration,
+// having processed Pages of TotalPages pages. Page counts are omitted when
+// zero.
+type StageCompleted struct {
+	DocumentID string
+	Stage      string
+	Duration   time.Duration
+	Pages      int
+	TotalPages int
+}
+
+// StageFailed reports that stage gave up on a document with Err after
+// Duration, having processed Pages of TotalPages pages.
+type StageFailed struct {
+	Err        error
+	DocumentID string
+	Stage      string
+	Duration   time.Duration
+	Pages      int
+	TotalPages int
+}
+
+// Reporter writes pipeline events to a logger.
+type Reporter struct {
+	logger *logger.Logger
+}
+
+// NewReporter returns a Reporter writing to l.
+func NewReporter(l *logger.Logger) *Reporter {
+	return &Reporter{logger: l}
+}
+
+// Started logs event as an INFO entry.
+func (reporter *Reporter) Started(event StageStarted) {
+	fields := append(baseFields(EventStageStarted, event.DocumentID, event.Stage),
+		pageFields(0, event.TotalPages)...)
+
+	reporter.logger.With(fields...).Logf(logger.LevelInfo, startedFmt, event.Stage, event.DocumentID)
+}
+
+// Completed logs event as a SYSTEM entry.
+func (reporter *Reporter) Completed(event StageCompleted) {
+	fields := append(baseFields(EventStageCompleted, event.DocumentID, event.Stage),
+		logger.F(DurationField, event.Duration))
+	fields = append(fields, pageFields(event.Pages, event.TotalPages)...)
+
+	reporter.logger.With(fields...).Logf(logger.LevelSystem, completedFmt, event.Stage, event.DocumentID)
+}
+
+// Failed logs event as an ERROR entry.
+func (reporter *Reporter) Failed(event StageFailed) {
+	fields := append(baseFields(EventStageFailed, event.DocumentID, event.Stage),
+		logger.F(DurationField, event.Duration))
+	fields = append(fields, pageFields(event.Pages, event.TotalPages)...)
+
+	if event.Err != nil {
+		fields = append(fields, logger.F(ErrorField, event.Err.Error()))
+	}
+
+	reporter.logger.With(fields...).Logf(logger.LevelError, failedFmt, event.Stage, event.DocumentID)
+}
+
+// Stage is a running stage started with Reporter.Start.
+type Stage struct {
+	start      time.Time
+	reporter   *Reporter
+	documentID string
+	name       string
+	totalPages int
+}
+
+// Start logs StageStarted and returns the stage, whose Complete and Fail
+// methods measure its duration.
+func (reporter *Reporter) Start(documentID, stage string, totalPages int) *Stage {
+	reporter.Started(StageStarted{DocumentID: documentID, Stage: stage, TotalPages: totalPages})
+
+	return &Stage{
+		start:      time.Now(),
+		reporter:   reporter,
+		documentID: documentID,
+		name:       stage,
+		totalPages: totalPages,
+	}
+}
+
+// Complete logs StageCompleted after pages were processed.
+func (stage *Stage) Complete(pages int) {
+	stage.reporter.Completed(StageCompleted{
+		DocumentID: stage.documentID,
+		Stage:      stage.name,
+		Duration:   time.Since(stage.start),
+		Pages:      pages,
+		TotalPages: stage.totalPages,
+	})
+}
+
+// Fail logs StageFailed with err after pages were processed.
+func (stage *Stage) Fail(err error, pages int) {
+	stage.reporter.Failed(StageFailed{
+		Err:        err,
+		DocumentID: stage.documentID,
+		Stage:      stage.name,
+		Duration:   time.Since(stage.start),
+		Pages:      pages,
+		TotalPages: stage.totalPages,
+	})
+}
+
+func baseFields(event, documentID, stage string) []logger.Field {
+	return []logger.Field{
+		logger.F(EventField, event),
+		logger.F(DocumentIDField, documentID),
+		logger.F(StageField, stage),
+	}
+}
+
+func pageFields(pages, totalPages int) []logger.Field {
+	var fields []logger.Field
+
+	if pages > 0 {
+		fields = append(fields, logger.F(PagesField, pages))
+	}
+
+	if totalPages > 0 {
+		fields = append(fields, logger.F(TotalPagesField, totalPages))
+	}
+
+	return fields
+}
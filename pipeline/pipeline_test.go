@@ -0,0 +1,69 @@
+package pipeline_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/book-expert/logger"
+	"github.com/book-expert/logger/pipeline"
+)
+
+const (
+	testDocument = "doc-42"
+	testStage    = "ocr"
+	testLayout   = "[{level}] {msg} {fields}"
+	startedLine  = "[INFO] pipeline: ocr started for doc-42 " +
+		"pipeline_event=stage_started document_id=doc-42 stage=ocr total_pages=12\n"
+	completedLine = "[SYSTEM] pipeline: ocr completed for doc-42 " +
+		"pipeline_event=stage_completed document_id=doc-42 stage=ocr duration=1.5s pages=12 total_pages=12\n"
+	failedLine = "[ERROR] pipeline: ocr failed for doc-42 " +
+		"pipeline_event=stage_failed document_id=doc-42 stage=ocr duration="
+	failedFields = " pages=3 total_pages=12 error=\"engine crashed\"\n"
+	outputErrFmt = "output:\n%s\nwant:\n%s"
+	prefixErrFmt = "expected output to start with %q and end with %q, got %q"
+)
+
+var errEngineCrashed = errors.New("engine crashed")
+
+func TestReporter_TypedEvents(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	reporter := pipeline.NewReporter(newLogger(&buf))
+	reporter.Started(pipeline.StageStarted{DocumentID: testDocument, Stage: testStage, TotalPages: 12})
+	reporter.Completed(pipeline.StageCompleted{
+		DocumentID: testDocument,
+		Stage:      testStage,
+		Duration:   1500 * time.Millisecond,
+		Pages:      12,
+		TotalPages: 12,
+	})
+
+	want := startedLine + completedLine
+	if buf.String() != want {
+		t.Errorf(outputErrFmt, buf.String(), want)
+	}
+}
+
+func TestStage_Fail(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	stage := pipeline.NewReporter(newLogger(&buf)).Start(testDocument, testStage, 12)
+	buf.Reset()
+	stage.Fail(errEngineCrashed, 3)
+
+	output := buf.String()
+	if !strings.HasPrefix(output, failedLine) || !strings.HasSuffix(output, failedFields) {
+		t.Errorf(prefixErrFmt, failedLine, failedFields, output)
+	}
+}
+
+func newLogger(buf *bytes.Buffer) *logger.Logger {
+	return logger.NewStreamLogger(buf, logger.WithLayout(logger.MustParseLayout(testLayout)))
+}
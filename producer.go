@@ -0,0 +1,255 @@
+package logger
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// ProducerField names the producer of an entry; see Named.
+	ProducerField = "producer"
+	// ProducerOther counts the entries of the producers seen once
+	// maxProducers producers are tracked, bounding the counters and metric
+	// series when producer names come from clients, such as daemon tags.
+	ProducerOther = "other"
+
+	// DefaultQuotaInterval is the Interval of a ProducerQuota without one.
+	DefaultQuotaInterval = time.Minute
+
+	// maxProducers is the number of producers counted on their own, besides
+	// those with their own quota.
+	maxProducers = 1024
+
+	dropReasonQuotaFmt       = "quota of producer %s"
+	metricProducerBytesHelp  = "# HELP logger_producer_bytes_total Bytes logged per producer.\n"
+	metricProducerBytesType  = "# TYPE logger_producer_bytes_total counter\n"
+	metricProducerBytesFmt   = "logger_producer_bytes_total{producer=%q} %d\n"
+	metricProducerDropHelp   = "# HELP logger_producer_dropped_total Entries dropped over a producer's quota.\n"
+	metricProducerDropType   = "# TYPE logger_producer_dropped_total counter\n"
+	metricProducerDroppedFmt = "logger_producer_dropped_total{producer=%q} %d\n"
+	metricProducerLinesHelp  = "# HELP logger_producer_entries_total Entries logged per producer.\n"
+	metricProducerLinesType  = "# TYPE logger_producer_entries_total counter\n"
+	metricProducerLinesFmt   = "logger_producer_entries_total{producer=%q} %d\n"
+	metricProducerSeenHelp   = "# HELP logger_producer_last_seen_timestamp_seconds Time of a producer's last entry.\n"
+	metricProducerSeenType   = "# TYPE logger_producer_last_seen_timestamp_seconds gauge\n"
+	metricProducerSeenFmt    = "logger_producer_last_seen_timestamp_seconds{producer=%q} %.3f\n"
+	producerSeenScale        = float64(time.Second)
+)
+
+// ProducerQuota limits a producer to Bytes bytes of rendered lines per
+// Interval, DefaultQuotaInterval when not positive.
+type ProducerQuota struct {
+	Bytes    int64
+	Interval time.Duration
+}
+
+// ProducerStats describes what one producer logged: the entries and bytes
+// written, the entries dropped over its quota and the time of its last
+// entry, written or dropped, which tells a producer that went quiet.
+type ProducerStats struct {
+	LastSeen time.Time
+	Name     string
+	Entries  uint64
+	Bytes    uint64
+	Dropped  uint64
+}
+
+// producerState counts the output of one producer. It is guarded by
+// loggerCore.mu.
+type producerState struct {
+	windowStart time.Time
+	lastSeen    time.Time
+	quota       ProducerQuota
+	windowBytes int64
+	entries     uint64
+	bytes       uint64
+	dropped     uint64
+}
+
+// producers holds the state and quotas of every producer. Entries without a
+// producer are not counted.
+type producers struct {
+	states map[string]*producerState
+	quotas map[string]ProducerQuota
+}
+
+// Named returns a child logger whose entries carry name in ProducerField.
+// The bytes logged by each producer are counted in Stats, so that the
+// subsystem filling the log can be found, and may be limited with
+// WithProducerQuota. Beyond 1024 producers, those without their own quota are
+// counted together as ProducerOther.
+func (l *Logger) Named(name string) *Logger {
+	return l.With(F(ProducerField, name))
+}
+
+// WithProducerQuota limits the producer name to quota. An empty name sets
+// the quota of every producer without its own. Entries beyond the quota are
+// dropped until the interval ends and reported in a drop summary, so that a
+// noisy subsystem cannot crowd out the others in a shared log file. Mandatory
+// entries, such as SECURITY and audit events, are never dropped.
+func WithProducerQuota(name string, quota ProducerQuota) Option {
+	return func(config *options) {
+		if config.producerQuotas == nil {
+			config.producerQuotas = make(map[string]ProducerQuota)
+		}
+
+		if quota.Interval <= 0 {
+			quota.Interval = DefaultQuotaInterval
+		}
+
+		config.producerQuotas[name] = quota
+	}
+}
+
+// admitProducer counts the line of size bytes rendered for logEntry against
+// its producer and reports whether it may be written. The caller holds mu.
+func (c *loggerCore) admitProducer(logEntry *Entry, size int) bool {
+	name, ok := producerOf(logEntry)
+	if !ok {
+		return true
+	}
+
+	state, name := c.producers.state(name)
+	state.lastSeen = logEntry.Time
+
+	if state.quota.Bytes > 0 && !logEntry.Mandatory {
+		if logEntry.Time.Sub(state.windowStart) >= state.quota.Interval {
+			state.windowStart = logEntry.Time
+			state.windowBytes = 0
+		}
+
+		if state.windowBytes+int64(size) > state.quota.Bytes {
+			state.dropped++
+			c.recordDrop(fmt.Sprintf(dropReasonQuotaFmt, name))
+
+			return false
+		}
+
+		state.windowBytes += int64(size)
+	}
+
+	state.entries++
+	state.bytes += uint64(size)
+
+	return true
+}
+
+// producerOf returns the last ProducerField of logEntry, so that a child of
+// a named logger can be renamed.
+func producerOf(logEntry *Entry) (string, bool) {
+	for index := len(logEntry.Fields) - 1; index >= 0; index-- {
+		if logEntry.Fields[index].Key == ProducerField {
+			return fmt.Sprint(logEntry.Fields[index].Value), true
+		}
+	}
+
+	return "", false
+}
+
+// state returns the state of the producer name and the name it is counted
+// under, ProducerOther when too many producers are tracked.
+func (p *producers) state(name string) (*producerState, string) {
+	state, ok := p.states[name]
+	if ok {
+		return state, name
+	}
+
+	_, configured := p.quotas[name]
+	if len(p.states) >= maxProducers && !configured {
+		name = ProducerOther
+
+		state, ok = p.states[name]
+		if ok {
+			return state, name
+		}
+	}
+
+	if p.states == nil {
+		p.states = make(map[string]*producerState)
+	}
+
+	quota, ok := p.quotas[name]
+	if !ok {
+		quota = p.quotas[""]
+	}
+
+	state = &producerState{
+		windowStart: time.Time{},
+		lastSeen:    time.Time{},
+		quota:       quota,
+		windowBytes: 0,
+		entries:     0,
+		bytes:       0,
+		dropped:     0,
+	}
+	p.states[name] = state
+
+	return state, name
+}
+
+// producerStats returns the counters of every producer, most bytes first.
+func (c *loggerCore) producerStats() []ProducerStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := make([]ProducerStats, 0, len(c.producers.states))
+	for _, name := range slices.Sorted(maps.Keys(c.producers.states)) {
+		state := c.producers.states[name]
+		stats = append(stats, ProducerStats{
+			LastSeen: state.lastSeen,
+			Name:     name,
+			Entries:  state.entries,
+			Bytes:    state.bytes,
+			Dropped:  state.dropped,
+		})
+	}
+
+	slices.SortStableFunc(stats, func(a, b ProducerStats) int {
+		return cmp.Compare(b.Bytes, a.Bytes)
+	})
+
+	return stats
+}
+
+func writeProducerMetrics(builder *strings.Builder, producers []ProducerStats) {
+	if len(producers) == 0 {
+		return
+	}
+
+	builder.WriteString(metricProducerLinesHelp)
+	builder.WriteString(metricProducerLinesType)
+
+	for _, producer := range producers {
+		fmt.Fprintf(builder, metricProducerLinesFmt, producer.Name, producer.Entries)
+	}
+
+	builder.WriteString(metricProducerBytesHelp)
+	builder.WriteString(metricProducerBytesType)
+
+	for _, producer := range producers {
+		fmt.Fprintf(builder, metricProducerBytesFmt, producer.Name, producer.Bytes)
+	}
+
+	builder.WriteString(metricProducerDropHelp)
+	builder.WriteString(metricProducerDropType)
+
+	for _, producer := range producers {
+		fmt.Fprintf(builder, metricProducerDroppedFmt, producer.Name, producer.Dropped)
+	}
+
+	writeProducerLastSeen(builder, producers)
+}
+
+func writeProducerLastSeen(builder *strings.Builder, producers []ProducerStats) {
+	builder.WriteString(metricProducerSeenHelp)
+	builder.WriteString(metricProducerSeenType)
+
+	for _, producer := range producers {
+		seen := float64(producer.LastSeen.UnixNano()) / producerSeenScale
+		fmt.Fprintf(builder, metricProducerSeenFmt, producer.Name, seen)
+	}
+}
@@ -0,0 +1,236 @@
+package logger_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	producerOCR       = "ocr"
+	producerUpload    = "upload"
+	producerMsg       = "page converted"
+	producerUploadMsg = "uploaded"
+	producerEntries   = 10
+	producerQuota     = 150
+	producerStatsFmt  = "Producers = %+v; want %+v"
+	producerLinesFmt  = "%d lines of %q in output; want %d:\n%s"
+	producerDropFmt   = "output lacks the drop summary %q:\n%s"
+	producerMetricFmt = "metrics lack %q:\n%s"
+	producerSummary   = "due to quota of producer ocr"
+	producerMetric    = `logger_producer_dropped_total{producer="ocr"}`
+	producerSeen      = `logger_producer_last_seen_timestamp_seconds{producer="ocr"} 1740816000.000`
+	producerLines     = `logger_producer_entries_total{producer="ocr"} 1`
+	producerStatsPath = "/stats"
+	producerDecodeFmt = "decode %q: %v"
+	producerSeenFmt   = "served LastSeen = %v; want %v"
+	producerCapacity  = 1024
+	producerCountFmt  = "%d producers counted; want %d"
+	producerLayout    = "{msg} {fields}"
+	producerMsgBytes  = len(producerMsg + " producer=" + producerOCR + "\n")
+)
+
+func TestLogger_NamedAccounting(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	current := time.Date(2025, time.March, 1, 8, 0, 0, 0, time.UTC)
+	loggerInstance := logger.NewStreamLogger(&buf, logger.WithClock(func() time.Time { return current }))
+	ocr := loggerInstance.Named(producerOCR)
+
+	for range producerEntries {
+		ocr.Infof(producerMsg)
+	}
+
+	current = current.Add(time.Minute)
+	ocr.Named(producerUpload).Infof(producerUploadMsg)
+	loggerInstance.Infof(producerMsg)
+
+	stats := loggerInstance.Stats().Producers
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+
+	ocrBytes := uint64(0)
+	for _, line := range lines[:producerEntries] {
+		ocrBytes += uint64(len(line) + 1)
+	}
+
+	uploadBytes := uint64(len(lines[producerEntries]) + 1)
+	want := []logger.ProducerStats{
+		{LastSeen: current.Add(-time.Minute), Name: producerOCR, Entries: producerEntries, Bytes: ocrBytes, Dropped: 0},
+		{LastSeen: current, Name: producerUpload, Entries: 1, Bytes: uploadBytes, Dropped: 0},
+	}
+
+	if len(stats) != len(want) || stats[0] != want[0] || stats[1] != want[1] {
+		t.Errorf(producerStatsFmt, stats, want)
+	}
+
+	closeTestLogger(t, loggerInstance)
+}
+
+func TestWithProducerQuota(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	current := time.Date(2025, time.March, 1, 8, 0, 0, 0, time.UTC)
+	loggerInstance := logger.NewStreamLogger(&buf,
+		logger.WithClock(func() time.Time { return current }),
+		logger.WithProducerQuota(producerOCR, logger.ProducerQuota{Bytes: producerQuota, Interval: time.Minute}),
+		logger.WithDropSummaryInterval(time.Nanosecond),
+	)
+	ocr := loggerInstance.Named(producerOCR)
+	upload := loggerInstance.Named(producerUpload)
+
+	for range producerEntries {
+		ocr.Infof(producerMsg)
+		upload.Infof(producerUploadMsg)
+	}
+
+	stats := loggerInstance.Stats()
+
+	current = current.Add(time.Minute)
+	ocr.Infof(producerMsg)
+	closeTestLogger(t, loggerInstance)
+
+	output := buf.String()
+
+	admitted := strings.Count(output, producerMsg+" producer="+producerOCR)
+	if admitted < 2 || admitted >= producerEntries {
+		t.Errorf(producerLinesFmt, admitted, producerMsg, producerEntries, output)
+	}
+
+	uploaded := strings.Count(output, producerUploadMsg)
+	if uploaded != producerEntries {
+		t.Errorf(producerLinesFmt, uploaded, producerUploadMsg, producerEntries, output)
+	}
+
+	if !strings.Contains(output, producerSummary) {
+		t.Errorf(producerDropFmt, producerSummary, output)
+	}
+
+	dropped := uint64(0)
+	for _, producer := range stats.Producers {
+		if producer.Name == producerOCR {
+			dropped = producer.Dropped
+		}
+	}
+
+	if dropped != uint64(producerEntries-admitted+1) {
+		t.Errorf(producerStatsFmt, stats.Producers, producerOCR+" with drops")
+	}
+
+	var metrics strings.Builder
+
+	err := stats.WritePrometheus(&metrics)
+	if err != nil || !strings.Contains(metrics.String(), producerMetric) {
+		t.Errorf(producerMetricFmt, producerMetric, metrics.String())
+	}
+}
+
+func TestWithProducerQuota_DefaultInterval(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	current := time.Date(2025, time.March, 1, 8, 0, 0, 0, time.UTC)
+	loggerInstance := logger.NewStreamLogger(&buf,
+		logger.WithLayout(logger.MustParseLayout(producerLayout)),
+		logger.WithClock(func() time.Time { return current }),
+		logger.WithProducerQuota(producerOCR, logger.ProducerQuota{Bytes: int64(producerMsgBytes * 2), Interval: 0}),
+	)
+	ocr := loggerInstance.Named(producerOCR)
+
+	// Without an interval the quota spans DefaultQuotaInterval, not each entry.
+	for range producerEntries {
+		ocr.Infof(producerMsg)
+	}
+
+	current = current.Add(logger.DefaultQuotaInterval)
+	ocr.Infof(producerMsg)
+	closeTestLogger(t, loggerInstance)
+
+	admitted := strings.Count(buf.String(), producerMsg)
+	if admitted != 3 {
+		t.Errorf(producerLinesFmt, admitted, producerMsg, 3, buf.String())
+	}
+}
+
+func TestLogger_NamedCapsProducers(t *testing.T) {
+	t.Parallel()
+
+	loggerInstance := logger.NewStreamLogger(io.Discard,
+		logger.WithProducerQuota(producerOCR, logger.ProducerQuota{Bytes: producerQuota, Interval: time.Minute}),
+	)
+
+	for producer := range producerCapacity + 2 {
+		loggerInstance.Named(strconv.Itoa(producer)).Infof(producerMsg)
+	}
+
+	loggerInstance.Named(producerOCR).Infof(producerMsg)
+
+	stats := loggerInstance.Stats().Producers
+	closeTestLogger(t, loggerInstance)
+
+	if len(stats) != producerCapacity+2 {
+		t.Fatalf(producerCountFmt, len(stats), producerCapacity+2)
+	}
+
+	counted := make(map[string]uint64, len(stats))
+	for _, producer := range stats {
+		counted[producer.Name] = producer.Entries
+	}
+
+	if counted[logger.ProducerOther] != 2 || counted[producerOCR] != 1 {
+		t.Errorf(producerStatsFmt, counted, logger.ProducerOther)
+	}
+}
+
+func TestLogger_StatsHandler(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	seen := time.Date(2025, time.March, 1, 8, 0, 0, 0, time.UTC)
+	loggerInstance := logger.NewStreamLogger(&buf, logger.WithClock(func() time.Time { return seen }))
+	loggerInstance.Named(producerOCR).Infof(producerMsg)
+
+	var metrics strings.Builder
+
+	stats := loggerInstance.Stats()
+
+	err := stats.WritePrometheus(&metrics)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{producerSeen, producerLines} {
+		if !strings.Contains(metrics.String(), want) {
+			t.Errorf(producerMetricFmt, want, metrics.String())
+		}
+	}
+
+	recorder := httptest.NewRecorder()
+	loggerInstance.StatsHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, producerStatsPath, nil))
+
+	var served logger.Stats
+
+	err = json.Unmarshal(recorder.Body.Bytes(), &served)
+	if err != nil {
+		t.Fatalf(producerDecodeFmt, recorder.Body.String(), err)
+	}
+
+	if len(served.Producers) != 1 || !served.Producers[0].LastSeen.Equal(seen) {
+		t.Errorf(producerSeenFmt, served.Producers, seen)
+	}
+
+	closeTestLogger(t, loggerInstance)
+}
@@ -0,0 +1,57 @@
+package logger
+
+import (
+	"context"
+	"runtime/pprof"
+)
+
+const (
+	// ProfileLabelKey is the pprof label key set on the logger's goroutines by
+	// WithProfileLabels. Its value names the task.
+	ProfileLabelKey = "logger"
+
+	// Tasks labelled by WithProfileLabels.
+	ProfileTaskAsyncWriter   = "async_writer"
+	ProfileTaskHook          = "hook"
+	ProfileTaskRuntimeStats  = "runtime_stats"
+	ProfileTaskDebugTimer    = "debug_timer"
+	ProfileTaskSinkWorker    = "sink_worker"
+	ProfileTaskSyncScheduler = "sync_scheduler"
+	ProfileTaskHeartbeat     = "heartbeat"
+	ProfileTaskAggregation   = "aggregation"
+	ProfileTaskRateAnomalies = "rate_anomalies"
+)
+
+// WithProfileLabels tags the goroutines the logger starts (the async writer
+// and its sinks, the sink workers, the fsync scheduler, hooks run in async
+// mode, the runtime stats reporter, the heartbeat monitor, the rollup writer
+// of WithAggregation, the rate anomaly detector and the debug level timer)
+// with the pprof label
+// ProfileLabelKey naming their task,
+// so that the logger's share of CPU and heap profiles is attributable. Entries
+// written synchronously use the caller's goroutine and keep its labels.
+func WithProfileLabels() Option {
+	return func(config *options) {
+		config.profileLabels = true
+	}
+}
+
+// goTask runs fn on a new goroutine, labelled with task when profile labels
+// are enabled.
+func (c *loggerCore) goTask(task string, fn func()) {
+	go c.runTask(task, fn)
+}
+
+// runTask runs fn on the current goroutine, labelled with task when profile
+// labels are enabled. It is used by goroutines the logger owns.
+func (c *loggerCore) runTask(task string, fn func()) {
+	if !c.pprofLabels {
+		fn()
+
+		return
+	}
+
+	pprof.Do(context.Background(), pprof.Labels(ProfileLabelKey, task), func(context.Context) {
+		fn()
+	})
+}
@@ -0,0 +1,44 @@
+package logger_test
+
+import (
+	"bytes"
+	"runtime/pprof"
+	"strings"
+	"testing"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	profileAsyncBuffer  = 4
+	profileMsg          = "labelled"
+	profileGoroutineFmt = 1
+	profileLabel        = `"logger":"async_writer"`
+	profileMissingFmt   = "expected label %s in goroutine profile"
+	profileWriteErrFmt  = "write goroutine profile: %v"
+)
+
+func TestWithProfileLabels_LabelsAsyncWriter(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	loggerInstance := logger.NewStreamLogger(&buf,
+		logger.WithAsync(profileAsyncBuffer), logger.WithProfileLabels())
+	defer closeTestLogger(t, loggerInstance)
+
+	// Once an entry is written the writer runs under its labels.
+	loggerInstance.Infof(profileMsg)
+	loggerInstance.Flush()
+
+	var profile bytes.Buffer
+
+	err := pprof.Lookup("goroutine").WriteTo(&profile, profileGoroutineFmt)
+	if err != nil {
+		t.Fatalf(profileWriteErrFmt, err)
+	}
+
+	if !strings.Contains(profile.String(), profileLabel) {
+		t.Errorf(profileMissingFmt, profileLabel)
+	}
+}
@@ -0,0 +1,154 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	progressPercentScale = 100
+	// progressReportStep is the percentage interval between SYSTEM entries.
+	progressReportStep  = 10
+	progressLineFormat  = "\r\033[K%s: %d/%d (%d%%)"
+	progressCountFormat = "\r\033[K%s: %d"
+	progressLineEnd     = "\n"
+	progressStepFormat  = "%s: %d%% (%d/%d)"
+	progressDoneFormat  = "%s: done (%d/%d) in %s"
+)
+
+// Progress tracks a long-running step started with Logger.Progress. On an
+// interactive terminal it renders an updating in-place line on stdout and
+// records percentage SYSTEM entries in the log file only; otherwise the
+// SYSTEM entries go to every output. A Progress is safe for concurrent use.
+type Progress struct {
+	started     time.Time
+	logger      *Logger
+	name        string
+	total       int
+	current     int
+	lastStep    int
+	mu          sync.Mutex
+	interactive bool
+	done        bool
+}
+
+// Progress starts tracking a step of total units, e.g. pages to OCR. A total
+// of zero or less means the size is unknown and only counts are reported.
+func (l *Logger) Progress(name string, total int) *Progress {
+	return &Progress{
+		started:     time.Now(),
+		logger:      l,
+		name:        name,
+		total:       total,
+		current:     0,
+		lastStep:    0,
+		mu:          sync.Mutex{},
+		interactive: isTerminal(l.core.stdWriter),
+		done:        false,
+	}
+}
+
+// Increment advances the progress by one unit.
+func (p *Progress) Increment() {
+	p.Add(1)
+}
+
+// Add advances the progress by n units. Calls after Done are ignored.
+func (p *Progress) Add(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.done {
+		return
+	}
+
+	p.current += n
+	if p.interactive {
+		p.render()
+	}
+
+	if p.total <= 0 {
+		return
+	}
+
+	step := p.percent() / progressReportStep * progressReportStep
+	if step > p.lastStep {
+		p.lastStep = step
+		p.logger.writeEntryf(LevelSystem, nil, "", p.reportTarget(),
+			progressStepFormat, p.name, step, p.current, p.total)
+	}
+}
+
+// Done finishes the progress line and records a completion SYSTEM entry with
+// the elapsed time. It is safe to call more than once.
+func (p *Progress) Done() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.done {
+		return
+	}
+
+	p.done = true
+	if p.interactive {
+		p.logger.core.writeRaw(progressLineEnd)
+	}
+
+	p.logger.writeEntryf(LevelSystem, nil, "", targetAll, progressDoneFormat,
+		p.name, p.current, p.total, time.Since(p.started).Round(time.Millisecond))
+}
+
+func (p *Progress) percent() int {
+	return min(p.current*progressPercentScale/p.total, progressPercentScale)
+}
+
+func (p *Progress) reportTarget() writeTarget {
+	if p.interactive {
+		return targetFileOnly
+	}
+
+	return targetAll
+}
+
+func (p *Progress) render() {
+	if p.total <= 0 {
+		p.logger.core.writeRaw(fmt.Sprintf(progressCountFormat, p.name, p.current))
+
+		return
+	}
+
+	p.logger.core.writeRaw(fmt.Sprintf(progressLineFormat, p.name, p.current, p.total, p.percent()))
+}
+
+// writeRaw writes text to stdout without a layout or trailing newline.
+func (c *loggerCore) writeRaw(text string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return
+	}
+
+	_, err := io.WriteString(c.stdWriter, text)
+	if err != nil {
+		c.lastErr = fmt.Errorf(errFmtWrapCause, ErrWriteStdout, err)
+	}
+}
+
+// isTerminal reports whether writer is an interactive character device.
+func isTerminal(writer io.Writer) bool {
+	file, ok := writer.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,50 @@
+package logger_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	progressTemplate      = "[{level}] {msg}"
+	progressName          = "ocr pages"
+	progressTotal         = 20
+	progressStepEntry     = "[SYSTEM] ocr pages: 50% (10/20)"
+	progressDoneEntry     = "[SYSTEM] ocr pages: done (20/20) in "
+	progressStepCount     = 10
+	progressEntryCountFmt = "expected %d step entries, got %d:\n%s"
+)
+
+func TestProgress_ReportsPercentageSteps(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	loggerInstance := logger.NewStreamLogger(
+		&buf,
+		logger.WithLayout(logger.MustParseLayout(progressTemplate)),
+	)
+
+	progress := loggerInstance.Progress(progressName, progressTotal)
+	for range progressTotal {
+		progress.Increment()
+	}
+
+	progress.Done()
+	progress.Done()
+
+	output := buf.String()
+	for _, want := range []string{progressStepEntry, progressDoneEntry} {
+		if !strings.Contains(output, want) {
+			t.Errorf(logFileMissingFmt, want, output)
+		}
+	}
+
+	steps := strings.Count(output, "%")
+	if steps != progressStepCount {
+		t.Errorf(progressEntryCountFmt, progressStepCount, steps, output)
+	}
+}
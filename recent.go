@@ -0,0 +1,209 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// MaxRecentEntries bounds the entries kept by WithRecentWindow, whatever
+	// the window, so that a burst cannot exhaust memory.
+	MaxRecentEntries = 100_000
+
+	// Query parameters of RecentHandler.
+	RecentParamLevel = "level"
+	RecentParamSince = "since"
+	RecentParamText  = "q"
+	RecentParamLimit = "limit"
+
+	jsonContentType   = "application/json"
+	recentArrayStart  = "["
+	recentArrayEnd    = "]\n"
+	recentArraySep    = ","
+	errRecentQueryMsg = "invalid recent entries query"
+	errFmtRecentParam = "%w: %s: %w"
+)
+
+// ErrRecentQuery is returned for malformed RecentHandler query parameters.
+var ErrRecentQuery = errors.New(errRecentQueryMsg)
+
+// RecentQuery selects entries from the recent window. Zero values do not
+// filter; Limit keeps the newest entries.
+type RecentQuery struct {
+	Since    time.Time
+	Text     string
+	MinLevel Level
+	Limit    int
+}
+
+// recentWindow keeps the entries of the last window in memory, oldest first.
+// It has its own lock so that queries do not block writes for long.
+type recentWindow struct {
+	entries []*Entry
+	mu      sync.RWMutex
+	window  time.Duration
+}
+
+// WithRecentWindow keeps the entries of the last window in memory, in addition
+// to every other output, for low-latency interactive queries with Recent and
+// RecentHandler, which the daemon serves next to its metrics. At most
+// MaxRecentEntries entries are kept.
+func WithRecentWindow(window time.Duration) Option {
+	return func(config *options) {
+		config.recentWindow = window
+	}
+}
+
+func newRecentWindow(window time.Duration) *recentWindow {
+	if window <= 0 {
+		return nil
+	}
+
+	return &recentWindow{entries: nil, mu: sync.RWMutex{}, window: window}
+}
+
+// add appends logEntry and drops the entries that left the window.
+func (recent *recentWindow) add(logEntry *Entry) {
+	recent.mu.Lock()
+	defer recent.mu.Unlock()
+
+	recent.entries = append(recent.entries, logEntry)
+
+	cutoff := logEntry.Time.Add(-recent.window)
+	expired := max(len(recent.entries)-MaxRecentEntries, 0)
+
+	for expired < len(recent.entries) && recent.entries[expired].Time.Before(cutoff) {
+		expired++
+	}
+
+	// Clearing lets the expired entries be collected before the backing
+	// array is reallocated.
+	clear(recent.entries[:expired])
+	recent.entries = recent.entries[expired:]
+}
+
+// Recent returns the entries of the recent window matching query, oldest
+// first. It returns nil unless WithRecentWindow is configured.
+func (l *Logger) Recent(query RecentQuery) []Entry {
+	recent := l.core.recent
+	if recent == nil {
+		return nil
+	}
+
+	cutoff := time.Now().Add(-recent.window)
+	if query.Since.After(cutoff) {
+		cutoff = query.Since
+	}
+
+	recent.mu.RLock()
+	defer recent.mu.RUnlock()
+
+	var matched []Entry
+
+	for _, logEntry := range recent.entries {
+		if logEntry.Time.Before(cutoff) || logEntry.Level < query.MinLevel {
+			continue
+		}
+
+		if query.Text != "" && !strings.Contains(logEntry.Message, query.Text) {
+			continue
+		}
+
+		matched = append(matched, *logEntry)
+	}
+
+	if query.Limit > 0 && len(matched) > query.Limit {
+		matched = matched[len(matched)-query.Limit:]
+	}
+
+	return matched
+}
+
+// RecentHandler serves the recent window as a JSON array of JSONEntry objects,
+// oldest first. The optional query parameters are level (a level name), since
+// (a duration such as 5m, or an RFC 3339 time), q (text the message must
+// contain) and limit (the number of newest entries). Secret entries, see
+// Sensitivity, are never served.
+func (l *Logger) RecentHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query, err := parseRecentQuery(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		var builder strings.Builder
+
+		builder.WriteString(recentArrayStart)
+
+		served := 0
+
+		for _, logEntry := range l.Recent(query) {
+			if entrySensitivity(&logEntry) >= SensitivitySecret {
+				continue
+			}
+
+			if served > 0 {
+				builder.WriteString(recentArraySep)
+			}
+
+			builder.WriteString(encodeJSON(&logEntry))
+			served++
+		}
+
+		builder.WriteString(recentArrayEnd)
+
+		w.Header().Set(headerContentType, jsonContentType)
+		_, _ = w.Write([]byte(builder.String()))
+	})
+}
+
+func parseRecentQuery(r *http.Request) (RecentQuery, error) {
+	values := r.URL.Query()
+	query := RecentQuery{Since: time.Time{}, Text: values.Get(RecentParamText), MinLevel: LevelDebug, Limit: 0}
+
+	var err error
+
+	if name := values.Get(RecentParamLevel); name != "" {
+		query.MinLevel, err = ParseLevel(name)
+		if err != nil {
+			return query, recentParamError(RecentParamLevel, err)
+		}
+	}
+
+	if since := values.Get(RecentParamSince); since != "" {
+		query.Since, err = parseSince(since)
+		if err != nil {
+			return query, recentParamError(RecentParamSince, err)
+		}
+	}
+
+	if limit := values.Get(RecentParamLimit); limit != "" {
+		query.Limit, err = strconv.Atoi(limit)
+		if err != nil {
+			return query, recentParamError(RecentParamLimit, err)
+		}
+	}
+
+	return query, nil
+}
+
+// parseSince accepts a duration before now or an RFC 3339 time.
+func parseSince(since string) (time.Time, error) {
+	duration, err := time.ParseDuration(since)
+	if err == nil {
+		return time.Now().Add(-duration), nil
+	}
+
+	return time.Parse(time.RFC3339, since)
+}
+
+func recentParamError(param string, err error) error {
+	return fmt.Errorf(errFmtRecentParam, ErrRecentQuery, param, err)
+}
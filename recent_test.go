@@ -0,0 +1,99 @@
+package logger_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	recentWindow      = time.Hour
+	recentInfoMsg     = "cache warmed"
+	recentWarnMsg     = "slow query %d"
+	recentQueryText   = "slow"
+	recentURL         = "/entries?level=warn&limit=1"
+	recentBadURL      = "/entries?limit=many"
+	recentCountErrFmt = "expected %d entries, got %+v"
+	recentStatusFmt   = "expected status %d, got %d"
+	recentDecodeFmt   = "decode: %v"
+	recentLastWarn    = "slow query 2"
+)
+
+func newRecentLogger(t *testing.T) *logger.Logger {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	loggerInstance := logger.NewStreamLogger(&buf, logger.WithRecentWindow(recentWindow))
+	loggerInstance.Infof(recentInfoMsg)
+
+	for index := range 3 {
+		loggerInstance.Warnf(recentWarnMsg, index)
+	}
+
+	return loggerInstance
+}
+
+func TestRecent_Query(t *testing.T) {
+	t.Parallel()
+
+	loggerInstance := newRecentLogger(t)
+
+	all := loggerInstance.Recent(logger.RecentQuery{})
+	if len(all) != 4 || all[0].Message != recentInfoMsg {
+		t.Fatalf(recentCountErrFmt, 4, all)
+	}
+
+	warnings := loggerInstance.Recent(logger.RecentQuery{Text: recentQueryText, Limit: 2})
+	if len(warnings) != 2 || warnings[1].Message != recentLastWarn {
+		t.Fatalf(recentCountErrFmt, 2, warnings)
+	}
+
+	future := loggerInstance.Recent(logger.RecentQuery{Since: time.Now().Add(time.Minute)})
+	if len(future) != 0 {
+		t.Errorf(recentCountErrFmt, 0, future)
+	}
+}
+
+func TestRecent_DisabledByDefault(t *testing.T) {
+	t.Parallel()
+
+	loggerInstance := logger.NewStreamLogger(&bytes.Buffer{})
+	loggerInstance.Infof(recentInfoMsg)
+
+	if entries := loggerInstance.Recent(logger.RecentQuery{}); entries != nil {
+		t.Errorf(recentCountErrFmt, 0, entries)
+	}
+}
+
+func TestRecentHandler(t *testing.T) {
+	t.Parallel()
+
+	handler := newRecentLogger(t).RecentHandler()
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, recentURL, nil))
+
+	var entries []logger.JSONEntry
+
+	err := json.Unmarshal(recorder.Body.Bytes(), &entries)
+	if err != nil {
+		t.Fatalf(recentDecodeFmt, err)
+	}
+
+	if len(entries) != 1 || entries[0].Message != recentLastWarn {
+		t.Fatalf(recentCountErrFmt, 1, entries)
+	}
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, recentBadURL, nil))
+
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf(recentStatusFmt, http.StatusBadRequest, recorder.Code)
+	}
+}
@@ -0,0 +1,132 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+const (
+	// RedactedText replaces the secrets removed by the default redaction
+	// rules and by configured rules without a replacement.
+	RedactedText = "[REDACTED]"
+
+	// Built-in redaction profiles, available without a configuration file.
+	// A configuration file may redefine them.
+	RedactProfileDefault = "default"
+	RedactProfileNone    = "none"
+
+	errUnknownRedactionMsg = "unknown redaction profile"
+	errFmtUnknownRedaction = "%w: %q"
+)
+
+// ErrUnknownRedaction is returned for a redaction profile that is neither
+// built in nor configured.
+var ErrUnknownRedaction = errors.New(errUnknownRedactionMsg)
+
+// RedactRuleConfig is a redaction rule in a configuration file. Pattern is a
+// regular expression; Replacement defaults to RedactedText.
+type RedactRuleConfig struct {
+	Pattern     string `json:"pattern"`
+	Replacement string `json:"replacement,omitempty"`
+}
+
+// RedactRule replaces every match of Pattern with Replacement, which may
+// refer to submatches as in regexp.Regexp.ReplaceAllString.
+type RedactRule struct {
+	Pattern     *regexp.Regexp
+	Replacement string
+}
+
+// Redactor scrubs text leaving the machine, such as support bundles, while
+// the log files on disk stay complete. Rules apply in order.
+type Redactor struct {
+	rules []RedactRule
+}
+
+// defaultRedactRules remove bearer tokens, the values of credential-like
+// keys and e-mail addresses.
+var defaultRedactRules = []RedactRule{
+	{
+		Pattern:     regexp.MustCompile(`(?i)\b(bearer|basic)\s+[A-Za-z0-9._~+/=-]+`),
+		Replacement: "${1} " + RedactedText,
+	},
+	{
+		Pattern: regexp.MustCompile(`(?i)\b([\w-]*(?:password|passwd|secret|token|api[_-]?key|access[_-]?key|` +
+			`authorization|credential)[\w-]*"?\s*[:=]\s*)("[^"]*"|[^\s,;&]+)`),
+		Replacement: "${1}" + RedactedText,
+	},
+	{
+		Pattern:     regexp.MustCompile(`[\w.+-]+@[\w-]+(?:\.[\w-]+)+`),
+		Replacement: RedactedText,
+	},
+}
+
+// NewRedactor returns a Redactor applying rules.
+func NewRedactor(rules ...RedactRule) *Redactor {
+	return &Redactor{rules: rules}
+}
+
+// DefaultRedactor returns a Redactor removing bearer and basic credentials,
+// the values of keys such as password, token or api_key, and e-mail
+// addresses.
+func DefaultRedactor() *Redactor {
+	return NewRedactor(defaultRedactRules...)
+}
+
+// Redact returns text with every rule applied. A nil Redactor returns text
+// unchanged.
+func (redactor *Redactor) Redact(text string) string {
+	if redactor == nil {
+		return text
+	}
+
+	for _, rule := range redactor.rules {
+		text = rule.Pattern.ReplaceAllString(text, rule.Replacement)
+	}
+
+	return text
+}
+
+// Redactor returns the redaction profile name: a profile declared in the
+// configuration's redaction section or a built-in one, RedactProfileDefault
+// or RedactProfileNone. It may be called on a nil Config to use the built-in
+// profiles only.
+func (config *Config) Redactor(name string) (*Redactor, error) {
+	if config != nil {
+		ruleConfigs, ok := config.Redaction[name]
+		if ok {
+			return compileRedactRules(name, ruleConfigs)
+		}
+	}
+
+	switch name {
+	case RedactProfileDefault:
+		return DefaultRedactor(), nil
+	case RedactProfileNone:
+		return NewRedactor(), nil
+	default:
+		return nil, fmt.Errorf(errFmtUnknownRedaction, ErrUnknownRedaction, name)
+	}
+}
+
+func compileRedactRules(name string, ruleConfigs []RedactRuleConfig) (*Redactor, error) {
+	rules := make([]RedactRule, 0, len(ruleConfigs))
+
+	for index, ruleConfig := range ruleConfigs {
+		pattern, err := regexp.Compile(ruleConfig.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf(errFmtConfigKeyErr, ErrConfigInvalid,
+				fmt.Sprintf(configMsgRedaction, name, index+1), err)
+		}
+
+		replacement := ruleConfig.Replacement
+		if replacement == "" {
+			replacement = RedactedText
+		}
+
+		rules = append(rules, RedactRule{Pattern: pattern, Replacement: replacement})
+	}
+
+	return NewRedactor(rules...), nil
+}
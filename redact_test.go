@@ -0,0 +1,80 @@
+package logger_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	redactConfigJSON = `{
+  "dir": %q,
+  "file": "app.log",
+  "redaction": {
+    "support": [
+      {"pattern": "customer=\\w+", "replacement": "customer=[HIDDEN]"},
+      {"pattern": "\\bISBN[0-9-]+"}
+    ]
+  }
+}`
+	redactBadJSON = `{"dir": %q, "file": "app.log", "redaction": {"broken": [{"pattern": "("}]}}`
+	redactInput   = "customer=bob bought ISBN978-3-16 token=abc"
+	redactSupport = "customer=[HIDDEN] bought [REDACTED] token=abc"
+	redactProfile = "support"
+	redactFmt     = "%s: Redact = %q; want %q"
+	redactErrFmt  = "Redactor(%q) error = %v; want %v"
+)
+
+func TestConfig_RedactionProfiles(t *testing.T) {
+	t.Parallel()
+
+	config, err := logger.LoadConfig(writeConfigFile(t, fmt.Sprintf(redactConfigJSON, t.TempDir())))
+	if err != nil {
+		t.Fatalf(loadConfigErrFmt, err)
+	}
+
+	tests := map[string]string{
+		redactProfile:               redactSupport,
+		logger.RedactProfileNone:    redactInput,
+		logger.RedactProfileDefault: "customer=bob bought ISBN978-3-16 token=[REDACTED]",
+	}
+
+	for name, want := range tests {
+		redactor, err := config.Redactor(name)
+		if err != nil {
+			t.Fatalf(redactErrFmt, name, err, nil)
+		}
+
+		got := redactor.Redact(redactInput)
+		if got != want {
+			t.Errorf(redactFmt, name, got, want)
+		}
+	}
+
+	_, err = config.Redactor("missing")
+	if !errors.Is(err, logger.ErrUnknownRedaction) {
+		t.Errorf(redactErrFmt, "missing", err, logger.ErrUnknownRedaction)
+	}
+}
+
+func TestLoadConfig_RejectsInvalidRedaction(t *testing.T) {
+	t.Parallel()
+
+	_, err := logger.LoadConfig(writeConfigFile(t, fmt.Sprintf(redactBadJSON, t.TempDir())))
+	if !errors.Is(err, logger.ErrConfigInvalid) {
+		t.Errorf(redactErrFmt, "broken", err, logger.ErrConfigInvalid)
+	}
+}
+
+func TestNilConfig_BuiltInRedaction(t *testing.T) {
+	t.Parallel()
+
+	var config *logger.Config
+
+	redactor, err := config.Redactor(logger.RedactProfileNone)
+	if err != nil || redactor.Redact(redactInput) != redactInput {
+		t.Errorf(redactErrFmt, logger.RedactProfileNone, err, nil)
+	}
+}
@@ -0,0 +1,143 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+const (
+	reloadedFmt       = "configuration reloaded: %d sinks, minimum level %s"
+	errFmtReloadClose = "reload: %w"
+)
+
+// Reload applies the sinks and the minimum level of config to the running
+// logger, for example after an operator edited the configuration file. The new
+// sinks, with their filters and routing rules, replace the routes of the
+// logger except the job files of ForJob, and files are reopened, so that a
+// sink may also change its target. Entries logged during the reload are
+// written to either the old or the new sinks, none are lost, and lines queued
+// for an old sink by WithSinkWorkers are written before it is closed. While
+// debug logging is enabled by EnableDebugFor, the new minimum level takes
+// effect when it expires. The log directory and file, layout, encoder, time
+// format and timezones are fixed when the logger is created; changes to them
+// are ignored. An invalid config leaves the logger unchanged and is returned
+// as an error wrapping ErrConfigInvalid. A SYSTEM entry records the reload.
+func (l *Logger) Reload(config *Config) error {
+	_, err := config.Options()
+	if err != nil {
+		return err
+	}
+
+	minLevel, err := config.minLevel()
+	if err != nil {
+		return err
+	}
+
+	routes, err := config.routes()
+	if err != nil {
+		return err
+	}
+
+	c := l.core
+
+	targets, err := c.openReloadedRoutes(routes)
+	if err != nil {
+		return err
+	}
+
+	replaced, err := c.swapRoutes(targets, minLevel)
+	if err != nil {
+		closeRouteTargets(targets)
+
+		return err
+	}
+
+	err = c.closeReplacedRoutes(replaced)
+
+	c.writeEntryf(LevelSystem, nil, "", targetAll, reloadedFmt, len(targets), c.label(minLevel))
+
+	return err
+}
+
+// openReloadedRoutes opens the destinations of routes like New does: under the
+// log directory, or only the stream routes without one.
+func (c *loggerCore) openReloadedRoutes(routes []Route) ([]*routeTarget, error) {
+	c.mu.Lock()
+	logDir := c.logDir
+	c.mu.Unlock()
+
+	if logDir == "" {
+		return writerRoutes(routes), nil
+	}
+
+	return openRoutes(logDir, routes, c.disk)
+}
+
+// swapRoutes installs targets and minLevel and returns the routes they
+// replace, which the caller closes.
+func (c *loggerCore) swapRoutes(targets []*routeTarget, minLevel Level) ([]*routeTarget, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil, ErrLoggerClosed
+	}
+
+	var replaced []*routeTarget
+
+	// Replace the slice rather than editing it in place: flushSinks iterates a
+	// copy of it without the lock.
+	routes := slices.Clone(targets)
+
+	for _, target := range c.routes {
+		if target.route.Field == JobIDField && target.route.Filename != "" {
+			routes = append(routes, target)
+		} else {
+			replaced = append(replaced, target)
+		}
+	}
+
+	for _, target := range targets {
+		c.sinks.attach(target)
+	}
+
+	c.routes = routes
+
+	if c.debugTimer != nil {
+		c.debugRestore = minLevel
+	} else {
+		c.setMinLevel(minLevel)
+	}
+
+	if slices.ContainsFunc(targets, func(target *routeTarget) bool { return target.route.needsCaller() }) {
+		c.needsCaller.Store(true)
+	}
+
+	return replaced, nil
+}
+
+// closeReplacedRoutes waits for the queued lines of replaced and closes them.
+func (c *loggerCore) closeReplacedRoutes(replaced []*routeTarget) error {
+	var errs []error
+
+	for _, target := range replaced {
+		c.sinks.detach(target)
+
+		if target.closer == nil {
+			continue
+		}
+
+		err := target.closer.Close()
+		if err != nil {
+			errs = append(errs, fmt.Errorf(errFmtCloseRoute, target.name, err))
+		}
+	}
+
+	err := errors.Join(errs...)
+	if err != nil {
+		return fmt.Errorf(errFmtReloadClose, err)
+	}
+
+	return nil
+}
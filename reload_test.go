@@ -0,0 +1,137 @@
+package logger_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	reloadLogFile    = "app.log"
+	reloadOldSink    = "old.log"
+	reloadNewSink    = "new.log"
+	reloadJobID      = "job-7"
+	reloadBeforeMsg  = "before reload"
+	reloadInfoMsg    = "quiet after reload"
+	reloadWarnMsg    = "loud after reload"
+	reloadSystemMsg  = "configuration reloaded: 1 sinks, minimum level WARN"
+	reloadErrFmt     = "Reload: %v"
+	reloadInvalidFmt = "Reload of an invalid config = %v; want ErrConfigInvalid"
+	reloadHoldsFmt   = "%s holds %q:\n%s"
+	reloadLacksFmt   = "%s lacks %q:\n%s"
+)
+
+func reloadConfig(dir, sinkName, target, minLevel string) *logger.Config {
+	return &logger.Config{
+		Redaction:      nil,
+		Dir:            dir,
+		File:           reloadLogFile,
+		MinLevel:       minLevel,
+		Layout:         "",
+		Encoder:        "",
+		TimeFormat:     "",
+		Timezone:       "",
+		StdoutTimezone: "",
+		Sinks: []logger.SinkConfig{{
+			Filters:        logger.FilterConfig{Field: "", Value: "", Message: ""},
+			Name:           sinkName,
+			Type:           logger.SinkTypeFile,
+			Target:         target,
+			Encoder:        "",
+			Layout:         "",
+			MinLevel:       "",
+			MaxSensitivity: "",
+			Timezone:       "",
+		}},
+	}
+}
+
+func readReloadFile(t *testing.T, dir, name string) string {
+	t.Helper()
+
+	// #nosec G304 -- test files under t.TempDir.
+	content, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatalf(readLogFileErr, err)
+	}
+
+	return string(content)
+}
+
+func TestLogger_Reload(t *testing.T) {
+	t.Parallel()
+
+	logDir := t.TempDir()
+
+	loggerInstance, err := logger.NewFromConfig(reloadConfig(logDir, "audit", reloadOldSink, ""),
+		logger.WithoutStdout())
+	if err != nil {
+		t.Fatalf(configNewLoggerErrF, err)
+	}
+
+	job := loggerInstance.ForJob(reloadJobID)
+	loggerInstance.Infof(reloadBeforeMsg)
+
+	err = loggerInstance.Reload(reloadConfig(logDir, "audit", reloadNewSink, "warn"))
+	if err != nil {
+		t.Fatalf(reloadErrFmt, err)
+	}
+
+	loggerInstance.Infof(reloadInfoMsg)
+	job.Warnf(reloadWarnMsg)
+	closeTestLogger(t, loggerInstance)
+
+	oldSink := readReloadFile(t, logDir, reloadOldSink)
+	if !strings.Contains(oldSink, reloadBeforeMsg) || strings.Contains(oldSink, reloadWarnMsg) {
+		t.Errorf(reloadHoldsFmt, reloadOldSink, reloadWarnMsg, oldSink)
+	}
+
+	newSink := readReloadFile(t, logDir, reloadNewSink)
+	if !strings.Contains(newSink, reloadWarnMsg) || strings.Contains(newSink, reloadInfoMsg) {
+		t.Errorf(reloadLacksFmt, reloadNewSink, reloadWarnMsg, newSink)
+	}
+
+	mainLog := readReloadFile(t, logDir, reloadLogFile)
+	if !strings.Contains(mainLog, reloadSystemMsg) {
+		t.Errorf(reloadLacksFmt, reloadLogFile, reloadSystemMsg, mainLog)
+	}
+
+	jobLog := readReloadFile(t, logDir, filepath.Join(logger.JobsDir, reloadJobID+".log"))
+	if !strings.Contains(jobLog, reloadWarnMsg) {
+		t.Errorf(reloadLacksFmt, reloadJobID, reloadWarnMsg, jobLog)
+	}
+}
+
+func TestLogger_ReloadInvalid(t *testing.T) {
+	t.Parallel()
+
+	logDir := t.TempDir()
+
+	loggerInstance, err := logger.NewFromConfig(reloadConfig(logDir, "audit", reloadOldSink, ""),
+		logger.WithoutStdout())
+	if err != nil {
+		t.Fatalf(configNewLoggerErrF, err)
+	}
+
+	err = loggerInstance.Reload(reloadConfig(logDir, "audit", reloadNewSink, "loudest"))
+	if !errors.Is(err, logger.ErrConfigInvalid) {
+		t.Errorf(reloadInvalidFmt, err)
+	}
+
+	loggerInstance.Infof(reloadInfoMsg)
+	closeTestLogger(t, loggerInstance)
+
+	oldSink := readReloadFile(t, logDir, reloadOldSink)
+	if !strings.Contains(oldSink, reloadInfoMsg) {
+		t.Errorf(reloadLacksFmt, reloadOldSink, reloadInfoMsg, oldSink)
+	}
+
+	_, err = os.Stat(filepath.Join(logDir, reloadNewSink))
+	if err == nil {
+		t.Errorf(reloadHoldsFmt, logDir, reloadNewSink, "")
+	}
+}
@@ -0,0 +1,185 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+// Sink address schemes accepted by OpenSink.
+const (
+	SinkSchemeTCP   = "tcp"
+	SinkSchemeUDP   = "udp"
+	SinkSchemeUnix  = "unix"
+	SinkSchemeHTTP  = "http"
+	SinkSchemeHTTPS = "https"
+	SinkSchemeFile  = "file"
+	// SinkSchemeLoki pushes to the Loki HTTP API; SinkSchemeLokiTLS does so
+	// over HTTPS. Query parameters become stream labels.
+	SinkSchemeLoki    = "loki"
+	SinkSchemeLokiTLS = "loki+https"
+
+	// DefaultSinkTimeout bounds connecting to and writing to a remote sink.
+	DefaultSinkTimeout = 10 * time.Second
+
+	sinkContentType     = "text/plain; charset=utf-8"
+	sinkFilePerm        = 0o600
+	errSinkSchemeMsg    = "unsupported sink address scheme"
+	errSinkStatusMsg    = "sink rejected lines"
+	errFmtSinkScheme    = "%w %q"
+	errFmtSinkAddress   = "parse sink address: %w"
+	errFmtSinkStatus    = "%w: %s"
+	errFmtSinkDial      = "dial sink: %w"
+	errFmtSinkWrite     = "write sink: %w"
+	errFmtSinkOpenFile  = "open sink file: %w"
+	errFmtSinkNewPost   = "create sink request: %w"
+	errFmtSinkPostLines = "post sink lines: %w"
+)
+
+// Predefined errors for remote sinks.
+var (
+	ErrSinkScheme = errors.New(errSinkSchemeMsg)
+	ErrSinkStatus = errors.New(errSinkStatusMsg)
+)
+
+// netSink writes lines to a stream or datagram connection, dialed on first
+// use and redialed after a failed write.
+type netSink struct {
+	conn    net.Conn
+	network string
+	address string
+	mu      sync.Mutex
+}
+
+// httpSink posts lines to an HTTP endpoint.
+type httpSink struct {
+	client *http.Client
+	url    string
+}
+
+// OpenSink opens the sink named by address:
+//
+//	tcp://host:port, udp://host:port  lines over TCP or UDP
+//	unix:///run/collector.sock        lines over a Unix stream socket
+//	http://host/path, https://...     each write POSTed as text/plain
+//	loki://host:3100?job=app          Loki push API; loki+https:// over TLS
+//	file:///var/log/app/remote.log    lines appended to a file; also a bare path
+//
+// Connections are opened on the first write and reopened after a failure;
+// connecting and writing are bounded by DefaultSinkTimeout.
+func OpenSink(address string) (Sink, error) {
+	parsed, err := url.Parse(address)
+	if err != nil {
+		return nil, fmt.Errorf(errFmtSinkAddress, err)
+	}
+
+	switch parsed.Scheme {
+	case SinkSchemeTCP, SinkSchemeUDP:
+		return &netSink{conn: nil, network: parsed.Scheme, address: parsed.Host, mu: sync.Mutex{}}, nil
+	case SinkSchemeUnix:
+		return &netSink{conn: nil, network: parsed.Scheme, address: parsed.Path, mu: sync.Mutex{}}, nil
+	case SinkSchemeHTTP, SinkSchemeHTTPS:
+		return &httpSink{client: &http.Client{Timeout: DefaultSinkTimeout}, url: address}, nil
+	case SinkSchemeLoki, SinkSchemeLokiTLS:
+		return newLokiSink(parsed), nil
+	case SinkSchemeFile, "":
+		return openFileSink(parsed.Path)
+	default:
+		return nil, fmt.Errorf(errFmtSinkScheme, ErrSinkScheme, parsed.Scheme)
+	}
+}
+
+func openFileSink(path string) (Sink, error) {
+	// #nosec G304 -- sink addresses are chosen by the operator.
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, sinkFilePerm)
+	if err != nil {
+		return nil, fmt.Errorf(errFmtSinkOpenFile, err)
+	}
+
+	return file, nil
+}
+
+func (sink *netSink) Write(data []byte) (int, error) {
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+
+	if sink.conn == nil {
+		conn, err := net.DialTimeout(sink.network, sink.address, DefaultSinkTimeout)
+		if err != nil {
+			return 0, fmt.Errorf(errFmtSinkDial, err)
+		}
+
+		sink.conn = conn
+	}
+
+	_ = sink.conn.SetWriteDeadline(time.Now().Add(DefaultSinkTimeout))
+
+	n, err := sink.conn.Write(data)
+	if err != nil {
+		_ = sink.conn.Close()
+		sink.conn = nil
+
+		return n, fmt.Errorf(errFmtSinkWrite, err)
+	}
+
+	return n, nil
+}
+
+func (sink *netSink) Close() error {
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+
+	if sink.conn == nil {
+		return nil
+	}
+
+	err := sink.conn.Close()
+	sink.conn = nil
+
+	return err
+}
+
+func (sink *httpSink) Write(data []byte) (int, error) {
+	err := sink.post(data, sinkContentType)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(data), nil
+}
+
+// post sends body to the sink's URL and fails unless the response is 2xx.
+func (sink *httpSink) post(body []byte, contentType string) error {
+	request, err := http.NewRequestWithContext(context.Background(), http.MethodPost, sink.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf(errFmtSinkNewPost, err)
+	}
+
+	request.Header.Set(headerContentType, contentType)
+
+	response, err := sink.client.Do(request)
+	if err != nil {
+		return fmt.Errorf(errFmtSinkPostLines, err)
+	}
+
+	_ = response.Body.Close()
+
+	if response.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf(errFmtSinkStatus, ErrSinkStatus, response.Status)
+	}
+
+	return nil
+}
+
+func (sink *httpSink) Close() error {
+	sink.client.CloseIdleConnections()
+
+	return nil
+}
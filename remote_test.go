@@ -0,0 +1,179 @@
+package logger_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	remoteLine        = "shipped entry\n"
+	remoteBadScheme   = "ftp://example.com/logs"
+	remoteLoopback    = "127.0.0.1:0"
+	remoteOpenFmt     = "OpenSink: %v"
+	remoteWriteFmt    = "write: %v"
+	remoteListenFmt   = "listen: %v"
+	remoteGotFmt      = "received %q, want %q"
+	remoteSchemeFmt   = "OpenSink error = %v, want %v"
+	remoteStatusFmt   = "write error = %v, want %v"
+	remoteReadBodyFmt = "read body: %v"
+	remoteLokiLines   = "first\nsecond\n"
+	remoteLokiQuery   = "?job=app"
+	remoteLokiPath    = "/loki/api/v1/push"
+	remoteLokiJob     = "app"
+	remoteLokiScheme  = "loki://"
+	remoteLokiPathFmt = "path = %q, want %q"
+	remoteLokiBodyFmt = "push = %+v"
+	remoteDecodeFmt   = "decode push: %v"
+)
+
+// lokiPushBody is the subset of a Loki push request checked by the tests.
+type lokiPushBody struct {
+	Streams []struct {
+		Stream map[string]string `json:"stream"`
+		Values [][2]string       `json:"values"`
+	} `json:"streams"`
+}
+
+func TestOpenSink_TCP(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", remoteLoopback)
+	if err != nil {
+		t.Fatalf(remoteListenFmt, err)
+	}
+	defer listener.Close()
+
+	received := make(chan string, 1)
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			received <- err.Error()
+
+			return
+		}
+		defer conn.Close()
+
+		line, _ := bufio.NewReader(conn).ReadString('\n')
+		received <- line
+	}()
+
+	sink := openRemoteSink(t, "tcp://"+listener.Addr().String())
+	defer sink.Close()
+
+	_, err = sink.Write([]byte(remoteLine))
+	if err != nil {
+		t.Fatalf(remoteWriteFmt, err)
+	}
+
+	got := <-received
+	if got != remoteLine {
+		t.Errorf(remoteGotFmt, got, remoteLine)
+	}
+}
+
+func TestOpenSink_HTTP(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+
+	received := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) > 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf(remoteReadBodyFmt, err)
+		}
+
+		received <- string(body)
+	}))
+	defer server.Close()
+
+	sink := openRemoteSink(t, server.URL)
+	defer sink.Close()
+
+	_, err := sink.Write([]byte(remoteLine))
+	if err != nil {
+		t.Fatalf(remoteWriteFmt, err)
+	}
+
+	got := <-received
+	if got != remoteLine {
+		t.Errorf(remoteGotFmt, got, remoteLine)
+	}
+
+	_, err = sink.Write([]byte(remoteLine))
+	if !errors.Is(err, logger.ErrSinkStatus) {
+		t.Errorf(remoteStatusFmt, err, logger.ErrSinkStatus)
+	}
+}
+
+func TestOpenSink_Loki(t *testing.T) {
+	t.Parallel()
+
+	pushes := make(chan lokiPushBody, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != remoteLokiPath {
+			t.Errorf(remoteLokiPathFmt, r.URL.Path, remoteLokiPath)
+		}
+
+		var push lokiPushBody
+
+		err := json.NewDecoder(r.Body).Decode(&push)
+		if err != nil {
+			t.Errorf(remoteDecodeFmt, err)
+		}
+
+		pushes <- push
+	}))
+	defer server.Close()
+
+	sink := openRemoteSink(t, remoteLokiScheme+strings.TrimPrefix(server.URL, "http://")+remoteLokiQuery)
+	defer sink.Close()
+
+	_, err := sink.Write([]byte(remoteLokiLines))
+	if err != nil {
+		t.Fatalf(remoteWriteFmt, err)
+	}
+
+	push := <-pushes
+	if len(push.Streams) != 1 || push.Streams[0].Stream["job"] != remoteLokiJob ||
+		len(push.Streams[0].Values) != 2 || push.Streams[0].Values[1][1] != "second" {
+		t.Errorf(remoteLokiBodyFmt, push)
+	}
+}
+
+func TestOpenSink_UnknownScheme(t *testing.T) {
+	t.Parallel()
+
+	_, err := logger.OpenSink(remoteBadScheme)
+	if !errors.Is(err, logger.ErrSinkScheme) {
+		t.Errorf(remoteSchemeFmt, err, logger.ErrSinkScheme)
+	}
+}
+
+func openRemoteSink(t *testing.T, address string) logger.Sink {
+	t.Helper()
+
+	sink, err := logger.OpenSink(address)
+	if err != nil {
+		t.Fatalf(remoteOpenFmt, err)
+	}
+
+	return sink
+}
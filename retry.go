@@ -0,0 +1,143 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultRetryAttempts, DefaultRetryDelay and DefaultRetryMaxDelay apply
+	// to unset RetryPolicy fields.
+	DefaultRetryAttempts = 3
+	DefaultRetryDelay    = 100 * time.Millisecond
+	DefaultRetryMaxDelay = 10 * time.Second
+
+	errDeadLetteredMsg   = "delivery failed, entries dead-lettered"
+	errFmtDeadLettered   = "%w after %d attempts: %w"
+	errFmtDeadLetterFile = "write dead letter: %w"
+	errFmtRetryFailed    = "delivery failed after %d attempts: %w"
+	retryBackoffFactor   = 2
+)
+
+// ErrDeadLettered is returned by RetrySink writes that failed every attempt
+// and were written to the dead-letter writer instead.
+var ErrDeadLettered = errors.New(errDeadLetteredMsg)
+
+// RetryPolicy configures a RetrySink. Delays start at Delay and double after
+// every failed attempt up to MaxDelay; Jitter, between 0 and 1, randomizes
+// each delay by up to that fraction in either direction so that many clients
+// do not retry in lockstep. Writes that fail MaxAttempts times are appended
+// to DeadLetter, when set, so that they can be replayed later.
+type RetryPolicy struct {
+	DeadLetter  io.Writer
+	Delay       time.Duration
+	MaxDelay    time.Duration
+	Jitter      float64
+	MaxAttempts int
+}
+
+// RetrySink is a Sink decorator that retries failed writes with exponential
+// backoff. Retries block the writer, so a RetrySink is usually wrapped in a
+// BatchSink, whose flusher then absorbs the delays. Close interrupts pending
+// delays; the interrupted write goes to the dead-letter writer.
+type RetrySink struct {
+	inner     Sink
+	policy    RetryPolicy
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewRetrySink wraps inner in a retry decorator following policy. Unset
+// fields take their defaults.
+func NewRetrySink(inner Sink, policy RetryPolicy) *RetrySink {
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = DefaultRetryAttempts
+	}
+
+	if policy.Delay <= 0 {
+		policy.Delay = DefaultRetryDelay
+	}
+
+	if policy.MaxDelay <= 0 {
+		policy.MaxDelay = DefaultRetryMaxDelay
+	}
+
+	policy.Jitter = min(max(policy.Jitter, 0), 1)
+
+	return &RetrySink{inner: inner, policy: policy, done: make(chan struct{}), closeOnce: sync.Once{}}
+}
+
+// Write passes data to the inner sink, retrying failed attempts. After the
+// last attempt fails, data is appended to the dead-letter writer, if any, and
+// the returned error wraps ErrDeadLettered and the last delivery error.
+func (sink *RetrySink) Write(data []byte) (int, error) {
+	delay := sink.policy.Delay
+
+	var err error
+
+	for attempt := 1; ; attempt++ {
+		_, err = sink.inner.Write(data)
+		if err == nil {
+			return len(data), nil
+		}
+
+		if attempt == sink.policy.MaxAttempts || !sink.wait(sink.jittered(delay)) {
+			return 0, sink.deadLetter(data, attempt, err)
+		}
+
+		delay = min(delay*retryBackoffFactor, sink.policy.MaxDelay)
+	}
+}
+
+// Close interrupts pending retries and closes the inner sink.
+func (sink *RetrySink) Close() error {
+	sink.closeOnce.Do(func() { close(sink.done) })
+
+	return sink.inner.Close()
+}
+
+// jittered randomizes delay by up to the policy's jitter fraction.
+func (sink *RetrySink) jittered(delay time.Duration) time.Duration {
+	if sink.policy.Jitter == 0 {
+		return delay
+	}
+
+	// #nosec G404 -- jitter needs no cryptographic randomness.
+	spread := (rand.Float64()*2 - 1) * sink.policy.Jitter
+
+	return delay + time.Duration(float64(delay)*spread)
+}
+
+// wait sleeps for delay and reports false when Close interrupted it.
+func (sink *RetrySink) wait(delay time.Duration) bool {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-sink.done:
+		return false
+	}
+}
+
+// deadLetter appends data that failed every attempt to the dead-letter
+// writer and describes the failure.
+func (sink *RetrySink) deadLetter(data []byte, attempts int, cause error) error {
+	if sink.policy.DeadLetter == nil {
+		return fmt.Errorf(errFmtRetryFailed, attempts, cause)
+	}
+
+	err := fmt.Errorf(errFmtDeadLettered, ErrDeadLettered, attempts, cause)
+
+	_, writeErr := sink.policy.DeadLetter.Write(data)
+	if writeErr != nil {
+		return errors.Join(err, fmt.Errorf(errFmtDeadLetterFile, writeErr))
+	}
+
+	return err
+}
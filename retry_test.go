@@ -0,0 +1,83 @@
+package logger_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	retryLine        = "entry\n"
+	retryAttempts    = 3
+	retryDelay       = time.Millisecond
+	retryWritesFmt   = "inner writes = %d, want %d"
+	retryErrFmt      = "write error = %v, want %v"
+	retryDeadFmt     = "dead letter = %q, want %q"
+	retryUnexpectErr = "unexpected error: %v"
+)
+
+// flakySink fails its first failures writes.
+type flakySink struct {
+	stubSink
+
+	failures int
+}
+
+func (sink *flakySink) Write(data []byte) (int, error) {
+	sink.fail = sink.writes < sink.failures
+
+	return sink.stubSink.Write(data)
+}
+
+func TestRetrySink_RecoversFromTransientErrors(t *testing.T) {
+	t.Parallel()
+
+	inner := &flakySink{stubSink: stubSink{writes: 0, fail: false, closed: false}, failures: retryAttempts - 1}
+	sink := logger.NewRetrySink(inner, logger.RetryPolicy{
+		DeadLetter:  nil,
+		Delay:       retryDelay,
+		MaxDelay:    retryDelay,
+		Jitter:      0.5,
+		MaxAttempts: retryAttempts,
+	})
+
+	_, err := sink.Write([]byte(retryLine))
+	if err != nil {
+		t.Fatalf(retryUnexpectErr, err)
+	}
+
+	if inner.writes != retryAttempts {
+		t.Errorf(retryWritesFmt, inner.writes, retryAttempts)
+	}
+}
+
+func TestRetrySink_DeadLetters(t *testing.T) {
+	t.Parallel()
+
+	var deadLetter bytes.Buffer
+
+	inner := &stubSink{writes: 0, fail: true, closed: false}
+	sink := logger.NewRetrySink(inner, logger.RetryPolicy{
+		DeadLetter:  &deadLetter,
+		Delay:       retryDelay,
+		MaxDelay:    retryDelay,
+		Jitter:      0,
+		MaxAttempts: retryAttempts,
+	})
+
+	_, err := sink.Write([]byte(retryLine))
+	if !errors.Is(err, logger.ErrDeadLettered) || !errors.Is(err, errTestWriteFailed) {
+		t.Errorf(retryErrFmt, err, logger.ErrDeadLettered)
+	}
+
+	if inner.writes != retryAttempts {
+		t.Errorf(retryWritesFmt, inner.writes, retryAttempts)
+	}
+
+	if deadLetter.String() != retryLine {
+		t.Errorf(retryDeadFmt, deadLetter.String(), retryLine)
+	}
+}
@@ -0,0 +1,194 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+const (
+	// ringMagic starts every ring file; its last byte is the format version.
+	ringMagic = "LOGRING\x01"
+	// ringHeaderSize holds the magic and the little-endian count of bytes
+	// ever written, from which the write position follows.
+	ringHeaderSize  = len(ringMagic) + 8
+	ringRecordEnd   = '\n'
+	ringMinCapacity = 1
+
+	errRingTooSmallMsg    = "ring file size too small"
+	errRingCorruptMsg     = "not a ring file or ring file corrupt"
+	errRingUnsupportedMsg = "ring file is not supported on this platform"
+	errFmtOpenRing        = "open ring file %q: %w"
+	errFmtCloseRing       = "close ring file %q: %w"
+	errFmtReadRing        = "read ring file: %w"
+	errFmtWriteRing       = "write ring file: %w"
+)
+
+// Predefined errors for ring files.
+var (
+	ErrRingTooSmall    = errors.New(errRingTooSmallMsg)
+	ErrRingCorrupt     = errors.New(errRingCorruptMsg)
+	ErrRingUnsupported = errors.New(errRingUnsupportedMsg)
+)
+
+// ringOptions configures the ring file of WithRingFile.
+type ringOptions struct {
+	filename string
+	size     int
+}
+
+// WithRingFile writes every file entry to a fixed-size memory-mapped circular
+// file in the log directory, a flight recorder that always retains the most
+// recent size bytes of entries without rotation. Each write is a copy into
+// the mapping, so it costs the same however long the log has run, and the
+// kernel persists the pages even if the process crashes. The file is reused
+// when it was created with the same size and reinitialized otherwise. Read it
+// with UnwrapRingFile or the logger unwrap command. The option only affects
+// loggers created by New.
+func WithRingFile(filename string, size int) Option {
+	return func(config *options) {
+		config.ring = &ringOptions{filename: filename, size: size}
+	}
+}
+
+// ringFile is a memory-mapped circular buffer of log lines.
+type ringFile struct {
+	file *os.File
+	data []byte
+	path string
+}
+
+func openRingFile(logDir string, ring *ringOptions) (*ringFile, error) {
+	if ring == nil {
+		return nil, nil
+	}
+
+	if ring.size < ringHeaderSize+ringMinCapacity {
+		return nil, fmt.Errorf(errFmtOpenRing, ring.filename, ErrRingTooSmall)
+	}
+
+	err := ValidateFilename(ring.filename)
+	if err != nil {
+		return nil, fmt.Errorf(errFmtOpenRing, ring.filename, err)
+	}
+
+	path, err := setupAndValidatePath(logDir, ring.filename)
+	if err != nil {
+		return nil, fmt.Errorf(errFmtOpenRing, ring.filename, err)
+	}
+
+	// #nosec G304 -- path is derived from the validated log directory.
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, walFilePerm)
+	if err != nil {
+		return nil, fmt.Errorf(errFmtOpenRing, ring.filename, err)
+	}
+
+	ringData, err := mapRingFile(file, ring.size)
+	if err != nil {
+		_ = file.Close()
+
+		return nil, fmt.Errorf(errFmtOpenRing, ring.filename, err)
+	}
+
+	return &ringFile{file: file, data: ringData, path: path}, nil
+}
+
+// mapRingFile maps file, reinitializing it unless it is a ring of size bytes.
+func mapRingFile(file *os.File, size int) ([]byte, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	reuse := info.Size() == int64(size)
+	if !reuse {
+		err = file.Truncate(int64(size))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ringData, err := mmapFile(file, size)
+	if errors.Is(err, ErrWALUnsupported) {
+		return nil, ErrRingUnsupported
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !reuse || !bytes.HasPrefix(ringData, []byte(ringMagic)) {
+		clear(ringData[:ringHeaderSize])
+		copy(ringData, ringMagic)
+	}
+
+	return ringData, nil
+}
+
+// write appends line and a line end at the write position, wrapping around
+// the end of the buffer. Lines longer than the buffer keep their tail.
+func (ring *ringFile) write(line string) {
+	capacity := uint64(len(ring.data) - ringHeaderSize)
+	written := binary.LittleEndian.Uint64(ring.data[len(ringMagic):ringHeaderSize])
+	record := line + string(ringRecordEnd)
+
+	if uint64(len(record)) > capacity {
+		written += uint64(len(record)) - capacity
+		record = record[uint64(len(record))-capacity:]
+	}
+
+	ringBuf := ring.data[ringHeaderSize:]
+	pos := written % capacity
+	copied := copy(ringBuf[pos:], record)
+	copy(ringBuf, record[copied:])
+
+	binary.LittleEndian.PutUint64(ring.data[len(ringMagic):ringHeaderSize], written+uint64(len(record)))
+}
+
+func (ring *ringFile) close() error {
+	if ring == nil {
+		return nil
+	}
+
+	err := errors.Join(munmapFile(ring.data), ring.file.Close())
+	if err != nil {
+		return fmt.Errorf(errFmtCloseRing, ring.path, err)
+	}
+
+	return nil
+}
+
+// UnwrapRingFile reads a ring file written with WithRingFile from reader and
+// writes its entries to writer in chronological order. Once the ring has
+// wrapped, the oldest entry was partly overwritten and is skipped.
+func UnwrapRingFile(reader io.Reader, writer io.Writer) error {
+	ringData, err := io.ReadAll(reader)
+	if err != nil {
+		return fmt.Errorf(errFmtReadRing, err)
+	}
+
+	if len(ringData) < ringHeaderSize+ringMinCapacity || !bytes.HasPrefix(ringData, []byte(ringMagic)) {
+		return ErrRingCorrupt
+	}
+
+	ringBuf := ringData[ringHeaderSize:]
+	capacity := uint64(len(ringBuf))
+	written := binary.LittleEndian.Uint64(ringData[len(ringMagic):ringHeaderSize])
+
+	entries := ringBuf[:min(written, capacity)]
+	if written > capacity {
+		pos := written % capacity
+		entries = append(bytes.Clone(ringBuf[pos:]), ringBuf[:pos]...)
+		entries = entries[bytes.IndexByte(entries, ringRecordEnd)+1:]
+	}
+
+	_, err = writer.Write(entries)
+	if err != nil {
+		return fmt.Errorf(errFmtWriteRing, err)
+	}
+
+	return nil
+}
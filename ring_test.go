@@ -0,0 +1,122 @@
+package logger_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	ringLogFile     = "app.log"
+	ringFile        = "flight.ring"
+	ringSize        = 512
+	ringEntries     = 100
+	ringMsg         = "entry %02d"
+	ringLayout      = "{msg}"
+	ringTooSmall    = 8
+	ringOpenErrFmt  = "open ring: %v"
+	ringUnwrapFmt   = "UnwrapRingFile: %v"
+	ringOrderErrFmt = "expected the newest entries in order ending with %q, got %q"
+	ringWantErrFmt  = "expected %v, got %v"
+	ringLinesErrFmt = "expected %d entries before wrapping, got %q"
+	ringGarbage     = "not a ring"
+)
+
+func newRingLogger(t *testing.T, dir string) *logger.Logger {
+	t.Helper()
+
+	loggerInstance, err := logger.New(dir, ringLogFile,
+		logger.WithLayout(logger.MustParseLayout(ringLayout)),
+		logger.WithRingFile(ringFile, ringSize))
+	if err != nil {
+		t.Fatalf(ringOpenErrFmt, err)
+	}
+
+	return loggerInstance
+}
+
+func unwrapRing(t *testing.T, dir string) []string {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join(dir, ringFile))
+	if err != nil {
+		t.Fatalf(ringOpenErrFmt, err)
+	}
+
+	var out bytes.Buffer
+
+	err = logger.UnwrapRingFile(bytes.NewReader(data), &out)
+	if err != nil {
+		t.Fatalf(ringUnwrapFmt, err)
+	}
+
+	return strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+}
+
+func TestRingFile_RetainsNewestEntriesInOrder(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	loggerInstance := newRingLogger(t, dir)
+
+	for index := range ringEntries {
+		loggerInstance.Infof(ringMsg, index)
+	}
+
+	closeTestLogger(t, loggerInstance)
+
+	lines := unwrapRing(t, dir)
+	last := fmt.Sprintf(ringMsg, ringEntries-1)
+
+	if lines[len(lines)-1] != last || len(lines) >= ringEntries {
+		t.Fatalf(ringOrderErrFmt, last, lines)
+	}
+
+	// The partly overwritten oldest entry is skipped.
+	if len(lines[0]) != len(last) {
+		t.Fatalf(ringOrderErrFmt, last, lines)
+	}
+
+	for index := 1; index < len(lines); index++ {
+		if lines[index] <= lines[index-1] {
+			t.Fatalf(ringOrderErrFmt, last, lines)
+		}
+	}
+}
+
+func TestRingFile_ResumesAfterReopen(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	for range 2 {
+		loggerInstance := newRingLogger(t, dir)
+		loggerInstance.Infof(ringMsg, 1)
+		closeTestLogger(t, loggerInstance)
+	}
+
+	lines := unwrapRing(t, dir)
+	if len(lines) != 2 {
+		t.Fatalf(ringLinesErrFmt, 2, lines)
+	}
+}
+
+func TestRingFile_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := logger.New(t.TempDir(), ringLogFile, logger.WithRingFile(ringFile, ringTooSmall))
+	if !errors.Is(err, logger.ErrRingTooSmall) {
+		t.Errorf(ringWantErrFmt, logger.ErrRingTooSmall, err)
+	}
+
+	err = logger.UnwrapRingFile(strings.NewReader(ringGarbage), &bytes.Buffer{})
+	if !errors.Is(err, logger.ErrRingCorrupt) {
+		t.Errorf(ringWantErrFmt, logger.ErrRingCorrupt, err)
+	}
+}
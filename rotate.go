@@ -0,0 +1,154 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+const (
+	rotateBackupSeparator = "."
+	errFmtRotate          = "rotate %s: %w"
+)
+
+// rotatingFile is an append-only file that is renamed to path.1 once it would
+// grow beyond maxSize, shifting older backups to path.2 and so on. At most
+// maxBackups backups are kept. A maxSize of zero disables rotation.
+type rotatingFile struct {
+	file       *os.File
+	path       string
+	size       int64
+	maxSize    int64
+	maxBackups int
+}
+
+func openRotatingFile(path string, maxSize int64, maxBackups int) (*rotatingFile, error) {
+	file, err := openLogFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+
+		return nil, fmt.Errorf(errFmtOpenLogFile, err)
+	}
+
+	return &rotatingFile{
+		file:       file,
+		path:       path,
+		size:       info.Size(),
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+	}, nil
+}
+
+// Write appends data, rotating first when data would exceed maxSize. A single
+// write larger than maxSize still goes to a fresh file. When rotation fails,
+// data is still appended to the file, and the error returned.
+func (rf *rotatingFile) Write(data []byte) (int, error) {
+	var rotateErr error
+
+	if rf.file == nil {
+		rotateErr = rf.reopen()
+	} else if rf.maxSize > 0 && rf.size > 0 && rf.size+int64(len(data)) > rf.maxSize {
+		rotateErr = rf.rotate()
+	}
+
+	if rf.file == nil {
+		return 0, rotateErr
+	}
+
+	n, err := rf.file.Write(data)
+	rf.size += int64(n)
+
+	return n, errors.Join(rotateErr, err)
+}
+
+// rotate renames the file to the first backup and opens a fresh one. The
+// file is closed first so that it can be renamed on every platform; when a
+// rename fails, the file is reopened and appended to until the next attempt,
+// and when it cannot be reopened the next Write tries again.
+func (rf *rotatingFile) rotate() error {
+	err := rf.file.Close()
+	rf.file = nil
+
+	if err != nil {
+		return fmt.Errorf(errFmtRotate, rf.path, err)
+	}
+
+	err = rf.shift()
+	if err != nil {
+		return errors.Join(fmt.Errorf(errFmtRotate, rf.path, err), rf.reopen())
+	}
+
+	return rf.reopen()
+}
+
+// shift renames the file and its backups one backup further, or removes the
+// file without backups.
+func (rf *rotatingFile) shift() error {
+	if rf.maxBackups <= 0 {
+		return os.Remove(rf.path)
+	}
+
+	for index := rf.backupCount(); index > 0; index-- {
+		err := os.Rename(rf.backupPath(index), rf.backupPath(index+1))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+
+	return os.Rename(rf.path, rf.backupPath(1))
+}
+
+// reopen opens the file at path, appending to what it holds.
+func (rf *rotatingFile) reopen() error {
+	file, err := openLogFile(rf.path)
+	if err != nil {
+		return fmt.Errorf(errFmtRotate, rf.path, err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+
+		return fmt.Errorf(errFmtRotate, rf.path, err)
+	}
+
+	rf.file = file
+	rf.size = info.Size()
+
+	return nil
+}
+
+// backupCount returns the number of backups to shift: those up to the first
+// missing one, and at most maxBackups-1, so that the oldest is overwritten.
+// Stopping at the gap keeps rotation cheap with a large maxBackups.
+func (rf *rotatingFile) backupCount() int {
+	count := 0
+	for count < rf.maxBackups-1 {
+		_, err := os.Lstat(rf.backupPath(count + 1))
+		if err != nil {
+			break
+		}
+
+		count++
+	}
+
+	return count
+}
+
+func (rf *rotatingFile) backupPath(index int) string {
+	return rf.path + rotateBackupSeparator + strconv.Itoa(index)
+}
+
+func (rf *rotatingFile) Close() error {
+	if rf.file == nil {
+		return nil
+	}
+
+	return rf.file.Close()
+}
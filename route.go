@@ -0,0 +1,329 @@
+package logger
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"time"
+)
+
+const (
+	// RouteAnyValue matches any value of Route.Field, i.e. the field's presence.
+	RouteAnyValue = "*"
+
+	routeLineEnd      = "\n"
+	errFmtOpenRoute   = "open route %q: %w"
+	errFmtWriteRoute  = "write route: %w"
+	errFmtCloseRoute  = "close route %q: %w"
+	errRouteTargetMsg = "route needs a Filename or a Writer"
+)
+
+// ErrRouteTarget is returned when a route has no destination.
+var ErrRouteTarget = errors.New(errRouteTargetMsg)
+
+// Route directs matching entries to an additional destination, for example
+// component=payment to payment.log or every entry carrying a security field to
+// a syslog writer. An entry matches when it carries Field with Value (or any
+// value when Value is RouteAnyValue) and its message matches Message. Unset
+// criteria are not checked, so a route without criteria receives every entry.
+// Entries below MinLevel are not routed; the zero value is LevelInfo. Matching
+// entries are still written to the logger's regular outputs. Layout, when set,
+// renders the routed lines instead of the logger's layout, and Encoding selects
+// text or JSON lines independently of the logger's encoding. Timezone, when
+// set, renders timestamps in its location instead of the logger's. Entries more
+// sensitive than MaxSensitivity are not routed; unset, file routes accept
+// every entry and Sink and Writer routes, which may leave the machine, accept
+// up to SensitivityInternal, keeping secret entries out of remote sinks.
+//
+// Filename names a file created in the logger's directory and is validated
+// like the main log filename; Writer is used as-is and never closed; Sink is
+// used like Writer and closed with the logger. Filename wins over Sink, which
+// wins over Writer. Name identifies the route in Stats.
+type Route struct {
+	Writer         io.Writer
+	Sink           Sink
+	Message        *regexp.Regexp
+	Layout         *Layout
+	Timezone       *time.Location
+	Name           string
+	Field          string
+	Value          string
+	Filename       string
+	MinLevel       Level
+	Encoding       Encoding
+	MaxSensitivity Sensitivity
+}
+
+// routeTarget is a route bound to its open destination.
+type routeTarget struct {
+	writer  io.Writer
+	closer  io.Closer
+	name    string
+	route   Route
+	queue   *sinkQueue // set with WithSinkWorkers
+	latency latencyHistogram
+	budget  errorBudgetState
+}
+
+// WithRoute adds a routing rule. Routes are evaluated for every entry in the
+// order they were added.
+func WithRoute(route Route) Option {
+	return func(config *options) {
+		config.routes = append(config.routes, route)
+	}
+}
+
+// openRoutes opens the destination of every route under logDir, throttling
+// route files with limiter.
+func openRoutes(logDir string, routes []Route, limiter *DiskLimiter) ([]*routeTarget, error) {
+	targets := make([]*routeTarget, 0, len(routes))
+
+	for index, route := range routes {
+		target, err := openRoute(logDir, route, limiter)
+		if err != nil {
+			closeRouteTargets(targets)
+
+			return nil, err
+		}
+
+		target.name = routeName(&route, index)
+		targets = append(targets, target)
+	}
+
+	return targets, nil
+}
+
+func openRoute(logDir string, route Route, limiter *DiskLimiter) (*routeTarget, error) {
+	if route.Filename == "" {
+		target := streamRouteTarget(route)
+		if target == nil {
+			return nil, ErrRouteTarget
+		}
+
+		return target, nil
+	}
+
+	err := ValidateFilename(route.Filename)
+	if err != nil {
+		return nil, fmt.Errorf(errFmtOpenRoute, route.Filename, err)
+	}
+
+	routePath, err := setupAndValidatePath(logDir, route.Filename)
+	if err != nil {
+		return nil, fmt.Errorf(errFmtOpenRoute, route.Filename, err)
+	}
+
+	file, err := openLogFile(routePath)
+	if err != nil {
+		return nil, fmt.Errorf(errFmtOpenRoute, route.Filename, err)
+	}
+
+	return newRouteTarget(route, limiter.limit(file, routePath), file), nil
+}
+
+func newRouteTarget(route Route, writer io.Writer, closer io.Closer) *routeTarget {
+	return &routeTarget{
+		writer:  writer,
+		closer:  closer,
+		name:    "",
+		route:   route,
+		queue:   nil,
+		latency: latencyHistogram{},
+		budget:  errorBudgetState{disabledUntil: time.Time{}, failures: nil},
+	}
+}
+
+// streamRouteTarget binds a route writing to its Sink or Writer, or returns
+// nil when it has neither.
+func streamRouteTarget(route Route) *routeTarget {
+	if route.Sink != nil {
+		return newRouteTarget(route, route.Sink, route.Sink)
+	}
+
+	if route.Writer != nil {
+		return newRouteTarget(route, route.Writer, nil)
+	}
+
+	return nil
+}
+
+// writerRoutes binds the routes that write to a Sink or an io.Writer, for
+// loggers without a log directory.
+func writerRoutes(routes []Route) []*routeTarget {
+	var targets []*routeTarget
+
+	for index, route := range routes {
+		if route.Filename != "" {
+			continue
+		}
+
+		target := streamRouteTarget(route)
+		if target != nil {
+			target.name = routeName(&route, index)
+			targets = append(targets, target)
+		}
+	}
+
+	return targets
+}
+
+func closeRouteTargets(targets []*routeTarget) {
+	for _, target := range targets {
+		if target.closer != nil {
+			_ = target.closer.Close()
+		}
+	}
+}
+
+// routeMessage writes msg to every route matching logEntry. The caller holds
+// c.mu.
+func (c *loggerCore) routeMessage(logEntry *Entry, msg string) error {
+	var errs []error
+
+	c.routeLines = c.routeLines[:0]
+
+	for _, target := range c.routes {
+		if !target.route.matches(logEntry) {
+			continue
+		}
+
+		if c.routeDisabled(target) {
+			c.recordDrop(dropReasonRouteDisabled)
+
+			continue
+		}
+
+		line := c.renderRouteLine(logEntry, msg, routeRendering{
+			layout:   cmp.Or(target.route.Layout, c.layout),
+			timezone: cmp.Or(target.route.Timezone, c.timezone),
+			encoding: target.route.Encoding,
+		})
+
+		err := c.writeRoute(target, line, logEntry.Time)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// writeRoute writes line to target, or queues it with WithSinkWorkers. The
+// caller holds c.mu.
+func (c *loggerCore) writeRoute(target *routeTarget, line []byte, logged time.Time) error {
+	if target.queue != nil {
+		queued, err := c.sinks.enqueue(target.queue, line, logged)
+		if queued {
+			return c.routeWriteResult(target, err)
+		}
+	}
+
+	_, err := target.writer.Write(line)
+	target.latency.observe(time.Since(logged))
+
+	return c.routeWriteResult(target, err)
+}
+
+// routeWriteResult counts an open circuit or back-pressure as a drop and
+// charges other errors to the route's error budget, returning them. The
+// caller holds c.mu.
+func (c *loggerCore) routeWriteResult(target *routeTarget, err error) error {
+	switch {
+	case errors.Is(err, ErrCircuitOpen):
+		c.recordDrop(dropReasonCircuitOpen)
+	case errors.Is(err, ErrBackPressure):
+		c.recordDrop(dropReasonBackPressure)
+	case err != nil:
+		c.spendErrorBudget(target)
+
+		return fmt.Errorf(errFmtWriteRoute, err)
+	}
+
+	return nil
+}
+
+// routeRendering selects how an entry is rendered for a route.
+type routeRendering struct {
+	layout   *Layout
+	timezone *time.Location
+	encoding Encoding
+}
+
+// routeLine is an entry rendered for the routes sharing a rendering.
+type routeLine struct {
+	line      []byte
+	rendering routeRendering
+}
+
+// renderRouteLine returns logEntry rendered as rendering selects and followed
+// by the line end. Routes sharing a rendering share one buffer, and buffers
+// are reused across entries, so fan-out to many sinks encodes and copies
+// every distinct rendering once. msg is the logger's own rendering. The
+// caller holds c.mu.
+func (c *loggerCore) renderRouteLine(logEntry *Entry, msg string, rendering routeRendering) []byte {
+	for index := range c.routeLines {
+		if c.routeLines[index].rendering == rendering {
+			return c.routeLines[index].line
+		}
+	}
+
+	text := msg
+	if rendering != (routeRendering{layout: c.layout, timezone: c.timezone, encoding: c.encoding}) {
+		text = encodeEntry(inTimezone(logEntry, rendering.timezone), rendering.encoding, rendering.layout)
+	}
+
+	var buf []byte
+	if count := len(c.routeLines); count < cap(c.routeLines) {
+		buf = c.routeLines[:count+1][count].line[:0]
+	}
+
+	buf = append(append(buf, text...), routeLineEnd...)
+	c.routeLines = append(c.routeLines, routeLine{line: buf, rendering: rendering})
+
+	return buf
+}
+
+// closeRoutes closes the route files opened by the logger. The caller holds
+// c.mu.
+func (c *loggerCore) closeRoutes() error {
+	var errs []error
+
+	for _, target := range c.routes {
+		if target.closer == nil {
+			continue
+		}
+
+		err := target.closer.Close()
+		if err != nil {
+			errs = append(errs, fmt.Errorf(errFmtCloseRoute, target.name, err))
+		}
+	}
+
+	c.routes = nil
+
+	return errors.Join(errs...)
+}
+
+func (route *Route) matches(logEntry *Entry) bool {
+	if logEntry.Level < route.MinLevel || entrySensitivity(logEntry) > route.maxSensitivity() {
+		return false
+	}
+
+	if route.Field != "" && !hasField(logEntry.Fields, route.Field, route.Value) {
+		return false
+	}
+
+	return route.Message == nil || route.Message.MatchString(logEntry.Message)
+}
+
+func hasField(fields []Field, key, value string) bool {
+	for _, field := range fields {
+		if field.Key == key && (value == RouteAnyValue || fmt.Sprint(field.Value) == value) {
+			return true
+		}
+	}
+
+	return false
+}
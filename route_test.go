@@ -0,0 +1,157 @@
+package logger_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	routeLogFile       = "app.log"
+	routePaymentFile   = "payment.log"
+	routeComponent     = "component"
+	routePayment       = "payment"
+	routeSearch        = "search"
+	routeSecurity      = "security"
+	routePaymentMsg    = "charge accepted"
+	routeSearchMsg     = "query served"
+	routeTimeoutMsg    = "upstream timeout"
+	routeTemplate      = "[{level}] {msg} {fields}"
+	routeUnexpectedFmt = "route file contains %q:\n%s"
+	routeSecurityLine  = "[WARN] login failed security=auth\n"
+	routeTimeoutLine   = "[ERROR] upstream timeout\n"
+	routeFanOutFirst   = "first"
+	routeFanOutSecond  = "second entry"
+	routeFanOutText    = "[INFO] first\n[INFO] second entry\n"
+	routeFanOutJSON    = `"message":"second entry"`
+	routeFanOutErrFmt  = "route %d: expected %q, got %q"
+)
+
+func TestRoute_FieldValueToFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	loggerInstance, err := logger.New(dir, routeLogFile, logger.WithRoute(logger.Route{
+		Field:    routeComponent,
+		Value:    routePayment,
+		Filename: routePaymentFile,
+	}))
+	if err != nil {
+		t.Fatalf(newLoggerError, err)
+	}
+
+	loggerInstance.With(logger.F(routeComponent, routePayment)).Infof(routePaymentMsg)
+	loggerInstance.With(logger.F(routeComponent, routeSearch)).Infof(routeSearchMsg)
+	closeTestLogger(t, loggerInstance)
+
+	// #nosec G304
+	content, err := os.ReadFile(filepath.Join(dir, routePaymentFile))
+	if err != nil {
+		t.Fatalf(readLogFileErr, err)
+	}
+
+	if !strings.Contains(string(content), routePaymentMsg) {
+		t.Errorf(logFileMissingFmt, routePaymentMsg, content)
+	}
+
+	if strings.Contains(string(content), routeSearchMsg) {
+		t.Errorf(routeUnexpectedFmt, routeSearchMsg, content)
+	}
+
+	verifyLogFileContains(t, filepath.Join(dir, routeLogFile), routePaymentMsg, routeSearchMsg)
+}
+
+func verifyLogFileContains(t *testing.T, path string, want ...string) {
+	t.Helper()
+
+	// #nosec G304
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf(readLogFileErr, err)
+	}
+
+	for _, message := range want {
+		if !strings.Contains(string(content), message) {
+			t.Errorf(logFileMissingFmt, message, content)
+		}
+	}
+}
+
+func TestRoute_AnyValueAndMessagePatternToWriters(t *testing.T) {
+	t.Parallel()
+
+	var security, timeouts, stdout bytes.Buffer
+
+	loggerInstance := logger.NewStreamLogger(
+		&stdout,
+		logger.WithLayout(logger.MustParseLayout(routeTemplate)),
+		logger.WithRoute(logger.Route{
+			Field:  routeSecurity,
+			Value:  logger.RouteAnyValue,
+			Writer: &security,
+		}),
+		logger.WithRoute(logger.Route{
+			Message: regexp.MustCompile(`time(d )?out`),
+			Writer:  &timeouts,
+		}),
+	)
+
+	loggerInstance.With(logger.F(routeSecurity, "auth")).Warnf("login failed")
+	loggerInstance.Errorf(routeTimeoutMsg)
+	loggerInstance.Infof(routeSearchMsg)
+
+	if security.String() != routeSecurityLine {
+		t.Errorf(layoutOutputErrFmt, routeSecurityLine, security.String())
+	}
+
+	if timeouts.String() != routeTimeoutLine {
+		t.Errorf(layoutOutputErrFmt, routeTimeoutLine, timeouts.String())
+	}
+}
+
+func TestRoute_RequiresDestination(t *testing.T) {
+	t.Parallel()
+
+	_, err := logger.New(t.TempDir(), routeLogFile, logger.WithRoute(logger.Route{
+		Field: routeComponent,
+		Value: routePayment,
+	}))
+	if !errors.Is(err, logger.ErrRouteTarget) {
+		t.Errorf(expectedErrFmt, logger.ErrRouteTarget, err)
+	}
+}
+
+func TestRoute_FanOutSharesRendering(t *testing.T) {
+	t.Parallel()
+
+	layout := logger.MustParseLayout(routeTemplate)
+
+	var first, second, jsonRoute bytes.Buffer
+
+	loggerInstance := logger.NewStreamLogger(&bytes.Buffer{},
+		logger.WithRoute(logger.Route{Writer: &first, Layout: layout}),
+		logger.WithRoute(logger.Route{Writer: &second, Layout: layout}),
+		logger.WithRoute(logger.Route{Writer: &jsonRoute, Encoding: logger.EncodingJSON}),
+	)
+
+	// The second, longer entry reuses the buffers of the first.
+	loggerInstance.Infof(routeFanOutFirst)
+	loggerInstance.Infof(routeFanOutSecond)
+
+	for index, buf := range []*bytes.Buffer{&first, &second} {
+		if buf.String() != routeFanOutText {
+			t.Errorf(routeFanOutErrFmt, index, routeFanOutText, buf.String())
+		}
+	}
+
+	if !strings.Contains(jsonRoute.String(), routeFanOutJSON) {
+		t.Errorf(routeFanOutErrFmt, 2, routeFanOutJSON, jsonRoute.String())
+	}
+}
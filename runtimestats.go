@@ -0,0 +1,91 @@
+package logger
+
+import (
+	"os"
+	"runtime"
+	"sync"
+	"time"
+)
+
+const (
+	runtimeStatsMessage = "runtime stats"
+	// procSelfFD lists the open file descriptors of the current process on
+	// Linux. Other platforms report -1.
+	procSelfFD       = "/proc/self/fd"
+	unknownFDCount   = -1
+	statsGoroutines  = "goroutines"
+	statsHeapAlloc   = "heap_alloc_bytes"
+	statsHeapSys     = "heap_sys_bytes"
+	statsGCCount     = "gc_count"
+	statsGCPauseLast = "gc_pause_last"
+	statsGCPauseSum  = "gc_pause_total"
+	statsOpenFDs     = "open_fds"
+	// gcPauseRingSize is the length of runtime.MemStats.PauseNs.
+	gcPauseRingSize = 256
+)
+
+// StartRuntimeStats emits a SYSTEM entry with goroutine count, heap size, GC
+// pauses and open file descriptors every interval, giving lightweight telemetry
+// to services without a metrics stack. It returns a function that stops the
+// reporting; the function is safe to call more than once. A non-positive
+// interval disables reporting.
+func (l *Logger) StartRuntimeStats(interval time.Duration) (stop func()) {
+	if interval <= 0 {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	ticker := time.NewTicker(interval)
+
+	l.core.goTask(ProfileTaskRuntimeStats, func() {
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				l.logRuntimeStats()
+			case <-done:
+				return
+			}
+		}
+	})
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
+
+func (l *Logger) logRuntimeStats() {
+	l.writeEntryf(LevelSystem, runtimeStatsFields(), "", targetAll, runtimeStatsMessage)
+}
+
+func runtimeStatsFields() []Field {
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+
+	var lastPause time.Duration
+	if memStats.NumGC > 0 {
+		lastPause = time.Duration(memStats.PauseNs[(memStats.NumGC+gcPauseRingSize-1)%gcPauseRingSize])
+	}
+
+	return []Field{
+		F(statsGoroutines, runtime.NumGoroutine()),
+		F(statsHeapAlloc, memStats.HeapAlloc),
+		F(statsHeapSys, memStats.HeapSys),
+		F(statsGCCount, memStats.NumGC),
+		F(statsGCPauseLast, lastPause),
+		F(statsGCPauseSum, time.Duration(memStats.PauseTotalNs)),
+		F(statsOpenFDs, openFDCount()),
+	}
+}
+
+func openFDCount() int {
+	entries, err := os.ReadDir(procSelfFD)
+	if err != nil {
+		return unknownFDCount
+	}
+
+	return len(entries)
+}
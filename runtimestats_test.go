@@ -0,0 +1,59 @@
+package logger_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	runtimeStatsInterval = 5 * time.Millisecond
+	runtimeStatsEntry    = "[SYSTEM] runtime stats goroutines="
+	runtimeStatsWaitErr  = "timed out waiting for runtime stats entry"
+)
+
+// syncBuffer is a strings.Builder safe for concurrent writes and reads.
+type syncBuffer struct {
+	builder strings.Builder
+	mu      sync.Mutex
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.builder.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.builder.String()
+}
+
+func TestLogger_StartRuntimeStats(t *testing.T) {
+	t.Parallel()
+
+	var buf syncBuffer
+
+	loggerInstance := logger.NewStreamLogger(&buf)
+	stop := loggerInstance.StartRuntimeStats(runtimeStatsInterval)
+
+	defer stop()
+
+	deadline := time.Now().Add(time.Second)
+	for !strings.Contains(buf.String(), runtimeStatsEntry) {
+		if time.Now().After(deadline) {
+			t.Fatal(runtimeStatsWaitErr)
+		}
+
+		time.Sleep(runtimeStatsInterval)
+	}
+
+	stop()
+	stop()
+}
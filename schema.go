@@ -0,0 +1,134 @@
+package logger
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// SchemaVersion is the version of the JSONEntry schema embedded in every JSON
+// entry as schema_version. It is incremented whenever a field is removed,
+// renamed or changes type; adding an optional field does not change it.
+//
+// Version history:
+//
+//	1: timestamp, level, message, caller, fields.
+const SchemaVersion = 1
+
+const (
+	jsonSchemaDialect   = "https://json-schema.org/draft/2020-12/schema"
+	jsonSchemaTitle     = "book-expert logger JSON entry"
+	textSchemaTitle     = "book-expert logger text line"
+	textSchemaDesc      = "Line rendered with the logger's layout; the pattern matches its start."
+	schemaKeyDialect    = "$schema"
+	schemaKeyTitle      = "title"
+	schemaKeyPattern    = "pattern"
+	jsonTagOmitEmpty    = "omitempty"
+	jsonTagSeparator    = ","
+	jsonSchemaIndent    = "  "
+	jsonTypeString      = "string"
+	jsonTypeInteger     = "integer"
+	jsonTypeObject      = "object"
+	jsonFormatDateTime  = "date-time"
+	schemaDocTag        = "doc"
+	schemaFieldVersion  = "schema_version"
+	schemaKeyType       = "type"
+	schemaKeyFormat     = "format"
+	schemaKeyDesc       = "description"
+	schemaKeyConst      = "const"
+	schemaKeyProps      = "properties"
+	schemaKeyRequired   = "required"
+	schemaKeyAdditional = "additionalProperties"
+)
+
+// JSONEntry is the documented schema of a JSON-encoded log line. Consumers can
+// decode lines into it directly; JSONSchema describes the same structure.
+type JSONEntry struct {
+	Timestamp     time.Time      `doc:"Time the entry was logged, RFC 3339 with nanoseconds."   json:"timestamp"`
+	Fields        map[string]any `doc:"Structured key/value fields attached to the entry."      json:"fields,omitempty"`
+	Level         string         `doc:"Level label, e.g. INFO, or the configured custom label." json:"level"`
+	Message       string         `doc:"Formatted message, truncated to 4096 bytes."             json:"message"`
+	Caller        string         `doc:"file:line of the logging call site."                     json:"caller,omitempty"`
+	EpochUnit     string         `doc:"Unit of epoch: s, ms or ns."                             json:"epoch_unit,omitempty"`
+	SchemaVersion int            `doc:"Version of this schema."                                 json:"schema_version"`
+	Epoch         int64          `doc:"Unix time of the entry with an epoch time format."       json:"epoch,omitempty"`
+}
+
+// JSONSchema returns a JSON Schema (draft 2020-12) document describing
+// JSONEntry, generated from the struct so that the two cannot drift apart.
+func JSONSchema() ([]byte, error) {
+	properties := make(map[string]any)
+
+	var required []string
+
+	entryType := reflect.TypeFor[JSONEntry]()
+	for index := range entryType.NumField() {
+		field := entryType.Field(index)
+		name, options, _ := strings.Cut(field.Tag.Get("json"), jsonTagSeparator)
+		properties[name] = propertySchema(name, field)
+
+		if !strings.Contains(options, jsonTagOmitEmpty) {
+			required = append(required, name)
+		}
+	}
+
+	return json.MarshalIndent(map[string]any{
+		schemaKeyDialect:    jsonSchemaDialect,
+		schemaKeyTitle:      jsonSchemaTitle,
+		schemaKeyType:       jsonTypeObject,
+		schemaKeyProps:      properties,
+		schemaKeyRequired:   required,
+		schemaKeyAdditional: false,
+	}, "", jsonSchemaIndent)
+}
+
+// EntrySchema returns a JSON Schema document describing the lines written to
+// stdout and the log file with the logger's encoding: JSONSchema for JSON
+// output, or a string schema whose pattern matches the layout for text output
+// and the header for CEF, LEEF and syslog output. Ingestion pipelines and
+// contract tests use it to validate log lines.
+func (l *Logger) EntrySchema() ([]byte, error) {
+	desc, pattern := textSchemaDesc, l.core.layout.pattern(l.core.timeFormat)
+
+	switch l.core.encoding {
+	case EncodingJSON:
+		return JSONSchema()
+	case EncodingCEF:
+		desc, pattern = siemSchemaDesc, cefSchemaPattern
+	case EncodingLEEF:
+		desc, pattern = siemSchemaDesc, leefSchemaPattern
+	case EncodingSyslog:
+		desc, pattern = syslogSchemaDesc, syslogSchemaRegex
+	}
+
+	return json.MarshalIndent(map[string]any{
+		schemaKeyDialect: jsonSchemaDialect,
+		schemaKeyTitle:   textSchemaTitle,
+		schemaKeyDesc:    desc,
+		schemaKeyType:    jsonTypeString,
+		schemaKeyPattern: pattern,
+	}, "", jsonSchemaIndent)
+}
+
+func propertySchema(name string, field reflect.StructField) map[string]any {
+	property := map[string]any{schemaKeyDesc: field.Tag.Get(schemaDocTag)}
+
+	switch {
+	case field.Type == reflect.TypeFor[time.Time]():
+		property[schemaKeyType] = jsonTypeString
+		property[schemaKeyFormat] = jsonFormatDateTime
+	case field.Type.Kind() == reflect.Map:
+		property[schemaKeyType] = jsonTypeObject
+	case field.Type.Kind() == reflect.Int, field.Type.Kind() == reflect.Int64:
+		property[schemaKeyType] = jsonTypeInteger
+	default:
+		property[schemaKeyType] = jsonTypeString
+	}
+
+	if name == schemaFieldVersion {
+		property[schemaKeyConst] = SchemaVersion
+	}
+
+	return property
+}
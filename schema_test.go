@@ -0,0 +1,110 @@
+package logger_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	schemaPropertyVersion = "schema_version"
+	schemaPropertyFields  = "fields"
+	schemaErrFmt          = "JSONSchema: %v"
+	schemaRequiredErrFmt  = "required: expected %s, got %v"
+	schemaOptionalErrFmt  = "required: %s should be optional, got %v"
+	schemaConstErrFmt     = "schema_version const: expected %d, got %v"
+	schemaPatternErrFmt   = "pattern %q does not match %q"
+	schemaTypeErrFmt      = "type: expected %q, got %q"
+	schemaTypeString      = "string"
+	schemaTypeObject      = "object"
+	schemaPaddedLayout    = "{time} {level:>7} | {msg} {fields}"
+	schemaMsg             = "rendered"
+)
+
+type jsonSchemaDocument struct {
+	Properties map[string]map[string]any `json:"properties"`
+	Type       string                    `json:"type"`
+	Pattern    string                    `json:"pattern"`
+	Required   []string                  `json:"required"`
+}
+
+func decodeSchema(t *testing.T, data []byte, err error) jsonSchemaDocument {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf(schemaErrFmt, err)
+	}
+
+	var schema jsonSchemaDocument
+
+	err = json.Unmarshal(data, &schema)
+	if err != nil {
+		t.Fatalf(schemaErrFmt, err)
+	}
+
+	return schema
+}
+
+func TestJSONSchema_DescribesJSONEntry(t *testing.T) {
+	t.Parallel()
+
+	data, err := logger.JSONSchema()
+	schema := decodeSchema(t, data, err)
+
+	if !slices.Contains(schema.Required, schemaPropertyVersion) {
+		t.Errorf(schemaRequiredErrFmt, schemaPropertyVersion, schema.Required)
+	}
+
+	if slices.Contains(schema.Required, schemaPropertyFields) {
+		t.Errorf(schemaOptionalErrFmt, schemaPropertyFields, schema.Required)
+	}
+
+	version := schema.Properties[schemaPropertyVersion]["const"]
+	if version != float64(logger.SchemaVersion) {
+		t.Errorf(schemaConstErrFmt, logger.SchemaVersion, version)
+	}
+}
+
+func TestEntrySchema_JSONEncoding(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	loggerInstance := logger.NewStreamLogger(&buf, logger.WithEncoding(logger.EncodingJSON))
+
+	data, err := loggerInstance.EntrySchema()
+	schema := decodeSchema(t, data, err)
+
+	if schema.Type != schemaTypeObject {
+		t.Errorf(schemaTypeErrFmt, schemaTypeObject, schema.Type)
+	}
+}
+
+func TestEntrySchema_TextPatternMatchesLines(t *testing.T) {
+	t.Parallel()
+
+	for _, template := range []string{logger.DefaultLayout, schemaPaddedLayout} {
+		loggerInstance, buf := newLayoutLogger(t, template)
+		loggerInstance.Warnf(schemaMsg)
+		loggerInstance.With(logger.F(encodingFieldKey, encodingFieldValue)).Infof(schemaMsg)
+
+		data, err := loggerInstance.EntrySchema()
+		schema := decodeSchema(t, data, err)
+
+		if schema.Type != schemaTypeString {
+			t.Errorf(schemaTypeErrFmt, schemaTypeString, schema.Type)
+		}
+
+		pattern := regexp.MustCompile(schema.Pattern)
+		for line := range strings.SplitSeq(strings.TrimSpace(buf.String()), "\n") {
+			if !pattern.MatchString(line) {
+				t.Errorf(schemaPatternErrFmt, schema.Pattern, line)
+			}
+		}
+	}
+}
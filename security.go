@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	errNoDurableSinkMsg = "no durable sink for SECURITY entry"
+	errFmtSyncLogFile   = "sync log file: %w"
+)
+
+// ErrNoDurableSink is returned by Securityf when the logger has no log file
+// to make the entry durable.
+var ErrNoDurableSink = errors.New(errNoDurableSinkMsg)
+
+// Securityf logs an intrusion-detection style event that must not be lost.
+// Unlike the other logging methods it returns only once the entry has been
+// written and the log file fsynced, waiting for the writer in async mode, and
+// it returns the error if delivery failed: a write error, ErrLoggerClosed, or
+// ErrNoDurableSink for loggers without a log file. SECURITY entries are
+// mandatory and never suppressed by filters.
+func (l *Logger) Securityf(format string, args ...any) error {
+	fields := l.fields
+	caller := l.resolveDirectCaller()
+	l.core.fingerprints.count(format)
+
+	return l.core.submitAndWait(&Entry{
+		Time:       l.core.clock(),
+		Fields:     fields,
+		Label:      l.core.label(LevelSecurity),
+		Level:      LevelSecurity,
+		Message:    l.core.safeFormat(l.core.validateFormat(format), args...),
+		Caller:     caller,
+		Mandatory:  true,
+		timeText:   "",
+		elapsed:    0,
+		timeFormat: TimeFormatDefault,
+		ingested:   false,
+	}, targetAll)
+}
+
+// syncDurable flushes the log file to stable storage. The caller holds c.mu.
+func (c *loggerCore) syncDurable() error {
+	if c.logFile == nil {
+		return ErrNoDurableSink
+	}
+
+	err := c.flushLines()
+	if err != nil {
+		return err
+	}
+
+	err = c.logFile.Sync()
+	if err != nil {
+		return fmt.Errorf(errFmtWrapCause, ErrWriteLogFile, fmt.Errorf(errFmtSyncLogFile, err))
+	}
+
+	c.syncer.synced()
+
+	return nil
+}
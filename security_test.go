@@ -0,0 +1,128 @@
+package logger_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	securityLogFile      = "security.log"
+	securityMsg          = "repeated login failures from %s"
+	securityAddr         = "10.0.0.9"
+	securityLabel        = "[SECURITY]"
+	securityAsyncBuffer  = 4
+	securityErrFmt       = "Securityf: %v"
+	securityMissingFmt   = "expected %q in %q"
+	securityWantErrFmt   = "expected %v, got %v"
+	securityReadErrFmt   = "read log file: %v"
+	securityPrecedingMsg = "before"
+	securityCallerLayout = "{caller} {msg}"
+	securityCallerFmt    = "security_test.go:%d "
+)
+
+func TestSecurityf_SyncsLogFile(t *testing.T) {
+	t.Parallel()
+
+	logDir := t.TempDir()
+	loggerInstance := createTestLogger(t, logDir, securityLogFile)
+
+	defer closeTestLogger(t, loggerInstance)
+
+	err := loggerInstance.Securityf(securityMsg, securityAddr)
+	if err != nil {
+		t.Fatalf(securityErrFmt, err)
+	}
+
+	// The entry is on disk when Securityf returns, before Close.
+	assertSecurityLogged(t, filepath.Join(logDir, securityLogFile), securityAddr)
+}
+
+func TestSecurityf_AsyncWaitsForWriter(t *testing.T) {
+	t.Parallel()
+
+	logDir := t.TempDir()
+
+	loggerInstance, err := logger.New(logDir, securityLogFile, logger.WithAsync(securityAsyncBuffer))
+	if err != nil {
+		t.Fatalf(newLoggerWithDirErrFmt, err)
+	}
+
+	defer closeTestLogger(t, loggerInstance)
+
+	loggerInstance.Infof(securityPrecedingMsg)
+
+	err = loggerInstance.Securityf(securityMsg, securityAddr)
+	if err != nil {
+		t.Fatalf(securityErrFmt, err)
+	}
+
+	path := filepath.Join(logDir, securityLogFile)
+	assertSecurityLogged(t, path, securityPrecedingMsg)
+	assertSecurityLogged(t, path, securityAddr)
+}
+
+func TestSecurityf_NoDurableSink(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	loggerInstance := logger.NewStreamLogger(&buf)
+
+	err := loggerInstance.Securityf(securityMsg, securityAddr)
+	if !errors.Is(err, logger.ErrNoDurableSink) {
+		t.Fatalf(securityWantErrFmt, logger.ErrNoDurableSink, err)
+	}
+
+	if !strings.Contains(buf.String(), securityLabel) {
+		t.Errorf(securityMissingFmt, securityLabel, buf.String())
+	}
+}
+
+func TestSecurityf_ReportsCaller(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	loggerInstance := logger.NewStreamLogger(&buf, logger.WithLayout(logger.MustParseLayout(securityCallerLayout)))
+
+	_, _, line, _ := runtime.Caller(0)
+	_ = loggerInstance.Securityf(securityMsg, securityAddr)
+
+	want := fmt.Sprintf(securityCallerFmt, line+1)
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf(securityMissingFmt, want, buf.String())
+	}
+}
+
+func TestSecurityf_Closed(t *testing.T) {
+	t.Parallel()
+
+	loggerInstance := createTestLogger(t, t.TempDir(), securityLogFile)
+	closeTestLogger(t, loggerInstance)
+
+	err := loggerInstance.Securityf(securityMsg, securityAddr)
+	if !errors.Is(err, logger.ErrLoggerClosed) {
+		t.Fatalf(securityWantErrFmt, logger.ErrLoggerClosed, err)
+	}
+}
+
+func assertSecurityLogged(t *testing.T, path, want string) {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf(securityReadErrFmt, err)
+	}
+
+	if !strings.Contains(string(data), want) {
+		t.Errorf(securityMissingFmt, want, string(data))
+	}
+}
@@ -0,0 +1,241 @@
+package logger
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	// SelfTestMinFreeBytes is the free space in the log directory below which
+	// SelfTest reports it as nearly full.
+	SelfTestMinFreeBytes = 64 << 20
+
+	selfTestProbeFmt       = "logger self-test probe %s"
+	selfTestProbeBase      = 36
+	selfTestTempPattern    = ".selftest-*"
+	selfTestTailBytes      = 64 << 10
+	errSelfTestProbeMsg    = "self-test probe not found in the log file"
+	errSelfTestSpaceMsg    = "log directory is nearly full"
+	errFmtSelfTestDir      = "log directory %s is not writable: %w"
+	errFmtSelfTestSpace    = "%w: %d bytes free in %s"
+	errFmtSelfTestProbe    = "self-test probe: %w"
+	errFmtSelfTestReadBack = "read back %s: %w"
+	errFmtSelfTestRoute    = "route %q: %w"
+	errRouteDisabledMsg    = "disabled by its error budget"
+)
+
+// Predefined errors reported by SelfTest.
+var (
+	ErrSelfTestProbe = errors.New(errSelfTestProbeMsg)
+	ErrSelfTestSpace = errors.New(errSelfTestSpaceMsg)
+	ErrRouteDisabled = errors.New(errRouteDisabledMsg)
+)
+
+// Pinger is implemented by sinks that can check that their destination is
+// reachable without writing a line. Sinks opened by OpenSink and the sink
+// decorators implement it.
+type Pinger interface {
+	Ping() error
+}
+
+// SelfTest checks that logging works, for services that want to fail fast at
+// boot: the log directory must be writable with at least
+// SelfTestMinFreeBytes free, a SYSTEM probe entry written to the log file
+// must read back, and every route must be enabled and, when its sink is a
+// Pinger, reachable. It returns every problem found joined into one error,
+// or nil.
+func (l *Logger) SelfTest() error {
+	c := l.core
+
+	c.mu.Lock()
+	closed, routes := c.closed, c.routes
+	c.mu.Unlock()
+
+	if closed {
+		return ErrLoggerClosed
+	}
+
+	errs := []error{c.selfTestDir(), c.selfTestProbe()}
+	for _, target := range routes {
+		errs = append(errs, c.selfTestRoute(target))
+	}
+
+	return errors.Join(errs...)
+}
+
+// selfTestDir checks that the log directory is writable and not nearly full.
+func (c *loggerCore) selfTestDir() error {
+	if c.logDir == "" {
+		return nil
+	}
+
+	probe, err := os.CreateTemp(c.logDir, selfTestTempPattern)
+	if err != nil {
+		return fmt.Errorf(errFmtSelfTestDir, c.logDir, err)
+	}
+
+	_ = probe.Close()
+	_ = os.Remove(probe.Name())
+
+	free, ok := freeBytes(c.logDir)
+	if ok && free < SelfTestMinFreeBytes {
+		return fmt.Errorf(errFmtSelfTestSpace, ErrSelfTestSpace, free, c.logDir)
+	}
+
+	return nil
+}
+
+// selfTestProbe writes a probe entry to the log file and reads it back.
+func (c *loggerCore) selfTestProbe() error {
+	message := fmt.Sprintf(selfTestProbeFmt, strconv.FormatInt(time.Now().UnixNano(), selfTestProbeBase))
+
+	err := c.submitAndWait(&Entry{
+		Time:       c.clock(),
+		Fields:     nil,
+		Label:      c.label(LevelSystem),
+		Level:      LevelSystem,
+		Message:    message,
+		Caller:     "",
+		Mandatory:  true,
+		timeText:   "",
+		elapsed:    0,
+		timeFormat: TimeFormatDefault,
+		ingested:   false,
+	}, targetFileOnly)
+	if err != nil {
+		return fmt.Errorf(errFmtSelfTestProbe, err)
+	}
+
+	c.mu.Lock()
+	logFile := c.logFile
+	c.mu.Unlock()
+
+	if logFile == nil {
+		return nil
+	}
+
+	tail, err := readTail(logFile.Name(), selfTestTailBytes)
+	if err != nil {
+		return fmt.Errorf(errFmtSelfTestReadBack, logFile.Name(), err)
+	}
+
+	if !bytes.Contains(tail, []byte(message)) {
+		return ErrSelfTestProbe
+	}
+
+	return nil
+}
+
+// selfTestRoute checks that target is enabled and its sink reachable.
+func (c *loggerCore) selfTestRoute(target *routeTarget) error {
+	c.mu.Lock()
+	disabled := c.routeDisabled(target)
+	c.mu.Unlock()
+
+	if disabled {
+		return fmt.Errorf(errFmtSelfTestRoute, target.name, ErrRouteDisabled)
+	}
+
+	err := pingSink(target.writer)
+	if err != nil {
+		return fmt.Errorf(errFmtSelfTestRoute, target.name, err)
+	}
+
+	return nil
+}
+
+// pingSink pings sink when it is a Pinger.
+func pingSink(sink any) error {
+	pinger, ok := sink.(Pinger)
+	if !ok {
+		return nil
+	}
+
+	return pinger.Ping()
+}
+
+// readTail returns the last limit bytes of the file at path.
+func readTail(path string, limit int64) ([]byte, error) {
+	// #nosec G304 -- path is the logger's own log file.
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = file.Seek(max(info.Size()-limit, 0), io.SeekStart)
+	if err != nil {
+		return nil, err
+	}
+
+	return io.ReadAll(file)
+}
+
+// Ping dials the sink unless it is connected; the connection is kept for the
+// next write.
+func (sink *netSink) Ping() error {
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+
+	if sink.conn != nil {
+		return nil
+	}
+
+	conn, err := net.DialTimeout(sink.network, sink.address, DefaultSinkTimeout)
+	if err != nil {
+		return fmt.Errorf(errFmtSinkDial, err)
+	}
+
+	sink.conn = conn
+
+	return nil
+}
+
+// Ping opens a TCP connection to the sink's host without sending a request.
+func (sink *httpSink) Ping() error {
+	address, err := url.Parse(sink.url)
+	if err != nil {
+		return fmt.Errorf(errFmtSinkAddress, err)
+	}
+
+	conn, err := net.DialTimeout(SinkSchemeTCP,
+		net.JoinHostPort(address.Hostname(), cmp.Or(address.Port(), address.Scheme)), DefaultSinkTimeout)
+	if err != nil {
+		return fmt.Errorf(errFmtSinkDial, err)
+	}
+
+	return conn.Close()
+}
+
+// Ping fails with ErrCircuitOpen while the circuit is open and pings the
+// inner sink otherwise.
+func (breaker *CircuitBreaker) Ping() error {
+	if breaker.Metrics().State == CircuitOpen {
+		return ErrCircuitOpen
+	}
+
+	return pingSink(breaker.inner)
+}
+
+// Ping pings the inner sink.
+func (sink *RetrySink) Ping() error {
+	return pingSink(sink.inner)
+}
+
+// Ping pings the inner sink.
+func (batch *BatchSink) Ping() error {
+	return pingSink(batch.inner)
+}
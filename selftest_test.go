@@ -0,0 +1,120 @@
+package logger_test
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	selfTestFile       = "app.log"
+	selfTestProbe      = "logger self-test probe "
+	selfTestDownRoute  = "collector"
+	selfTestHTTPRoute  = "webhook"
+	selfTestLocalAddr  = "127.0.0.1:0"
+	selfTestErrFmt     = "SelfTest: %v"
+	selfTestMissingFmt = "expected the log file to contain %q, got:\n%s"
+	selfTestRouteFmt   = "expected an error naming route %q, got: %v"
+	selfTestListenFmt  = "listen: %v"
+	selfTestSinkFmt    = "OpenSink: %v"
+	selfTestClosedFmt  = "expected ErrLoggerClosed, got: %v"
+)
+
+// unusedAddress returns a TCP address nothing listens on.
+func unusedAddress(t *testing.T) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", selfTestLocalAddr)
+	if err != nil {
+		t.Fatalf(selfTestListenFmt, err)
+	}
+
+	address := listener.Addr().String()
+	_ = listener.Close()
+
+	return address
+}
+
+func openSelfTestSink(t *testing.T, address string) logger.Sink {
+	t.Helper()
+
+	sink, err := logger.OpenSink(address)
+	if err != nil {
+		t.Fatalf(selfTestSinkFmt, err)
+	}
+
+	return sink
+}
+
+func TestSelfTest_Healthy(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer server.Close()
+
+	dir := t.TempDir()
+
+	loggerInstance, err := logger.New(dir, selfTestFile, logger.WithoutStdout(),
+		logger.WithRoute(logger.Route{Name: selfTestHTTPRoute, Sink: openSelfTestSink(t, server.URL)}))
+	if err != nil {
+		t.Fatalf(newLoggerWithDirErrFmt, err)
+	}
+
+	err = loggerInstance.SelfTest()
+	if err != nil {
+		t.Errorf(selfTestErrFmt, err)
+	}
+
+	closeOutputsLogger(t, loggerInstance)
+
+	content := readOutputsFile(t, filepath.Join(dir, selfTestFile))
+	if !strings.Contains(content, selfTestProbe) {
+		t.Errorf(selfTestMissingFmt, selfTestProbe, content)
+	}
+}
+
+func TestSelfTest_UnreachableSink(t *testing.T) {
+	t.Parallel()
+
+	sink := logger.NewRetrySink(openSelfTestSink(t, "tcp://"+unusedAddress(t)), logger.RetryPolicy{
+		DeadLetter:  nil,
+		Delay:       0,
+		MaxDelay:    0,
+		Jitter:      0,
+		MaxAttempts: 1,
+	})
+
+	loggerInstance, err := logger.New(t.TempDir(), selfTestFile, logger.WithoutStdout(),
+		logger.WithRoute(logger.Route{Name: selfTestDownRoute, Sink: sink}))
+	if err != nil {
+		t.Fatalf(newLoggerWithDirErrFmt, err)
+	}
+	defer closeOutputsLogger(t, loggerInstance)
+
+	err = loggerInstance.SelfTest()
+	if err == nil || !strings.Contains(err.Error(), `route "`+selfTestDownRoute+`"`) {
+		t.Errorf(selfTestRouteFmt, selfTestDownRoute, err)
+	}
+}
+
+func TestSelfTest_ClosedLogger(t *testing.T) {
+	t.Parallel()
+
+	loggerInstance, err := logger.New(t.TempDir(), selfTestFile, logger.WithoutStdout())
+	if err != nil {
+		t.Fatalf(newLoggerWithDirErrFmt, err)
+	}
+
+	closeOutputsLogger(t, loggerInstance)
+
+	err = loggerInstance.SelfTest()
+	if !errors.Is(err, logger.ErrLoggerClosed) {
+		t.Errorf(selfTestClosedFmt, err)
+	}
+}
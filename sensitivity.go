@@ -0,0 +1,202 @@
+package logger
+
+import (
+	"bytes"
+	"unicode"
+)
+
+// Sensitivity classifies who may see an entry. It is carried by the
+// sensitivity field, set per entry with Sensitive or per child logger with
+// WithSensitivity; entries without it are public. Entries above a route's
+// MaxSensitivity are not routed, RecentHandler never serves secret entries
+// and SensitivitySink drops their rendered lines, while the logger's own file
+// and stdout receive every entry.
+type Sensitivity int
+
+// Sensitivity classes, from least to most restricted. The zero value is
+// unset.
+const (
+	SensitivityPublic Sensitivity = iota + 1
+	SensitivityInternal
+	SensitivitySecret
+)
+
+const (
+	// SensitivityField is the field carrying an entry's Sensitivity.
+	SensitivityField = "sensitivity"
+
+	sensitivityPublicName   = "public"
+	sensitivityInternalName = "internal"
+	sensitivitySecretName   = "secret"
+
+	// A rendered field is KEY=VALUE in text, CEF and LEEF, KEY="VALUE" in
+	// syslog and "KEY":"VALUE" in JSON.
+	sensitivityAssign     = "="
+	sensitivityJSONAssign = `":`
+	sensitivityQuote      = `"`
+)
+
+var sensitivityNames = map[Sensitivity]string{
+	SensitivityPublic:   sensitivityPublicName,
+	SensitivityInternal: sensitivityInternalName,
+	SensitivitySecret:   sensitivitySecretName,
+}
+
+// String returns public, internal or secret, or the empty string when unset.
+func (sensitivity Sensitivity) String() string {
+	return sensitivityNames[sensitivity]
+}
+
+// ParseSensitivity returns the Sensitivity named public, internal or secret.
+func ParseSensitivity(name string) (Sensitivity, bool) {
+	for sensitivity, sensitivityName := range sensitivityNames {
+		if sensitivityName == name {
+			return sensitivity, true
+		}
+	}
+
+	return 0, false
+}
+
+// Sensitive returns the field marking an entry with sensitivity, e.g.
+// l.With(logger.Sensitive(logger.SensitivitySecret)).Infof(...).
+func Sensitive(sensitivity Sensitivity) Field {
+	return F(SensitivityField, sensitivity.String())
+}
+
+// WithSensitivity returns a child logger whose entries carry sensitivity.
+func (l *Logger) WithSensitivity(sensitivity Sensitivity) *Logger {
+	return l.With(Sensitive(sensitivity))
+}
+
+// entrySensitivity returns the most restricted sensitivity among the fields
+// of logEntry. Unknown values count as secret, so that a typo never leaks an
+// entry.
+func entrySensitivity(logEntry *Entry) Sensitivity {
+	result := SensitivityPublic
+
+	for _, field := range logEntry.Fields {
+		if field.Key != SensitivityField {
+			continue
+		}
+
+		sensitivity, ok := field.Value.(Sensitivity)
+		if !ok {
+			name, _ := field.Value.(string)
+			sensitivity, ok = ParseSensitivity(name)
+		}
+
+		if !ok {
+			sensitivity = SensitivitySecret
+		}
+
+		result = max(result, sensitivity)
+	}
+
+	return result
+}
+
+// maxSensitivity returns the most sensitive entries route accepts. Unless
+// set, file routes, which stay on the machine, accept every entry and other
+// routes, which may be remote, accept internal entries.
+func (route *Route) maxSensitivity() Sensitivity {
+	switch {
+	case route.MaxSensitivity != 0:
+		return route.MaxSensitivity
+	case route.Filename != "":
+		return SensitivitySecret
+	default:
+		return SensitivityInternal
+	}
+}
+
+// SensitivitySink is a Sink decorator that drops the lines of entries above
+// a sensitivity, for lines that leave the machine as rendered text rather
+// than through a route, such as log files sent by a Shipper. A line's
+// sensitivity is read from its sensitivity field in any encoding. Unknown
+// values count as secret, and so does a message quoting the field, so that
+// a secret line is dropped rather than sent when in doubt.
+type SensitivitySink struct {
+	inner          Sink
+	maxSensitivity Sensitivity
+}
+
+// NewSensitivitySink wraps inner so that it receives only the lines of
+// entries up to maxSensitivity.
+func NewSensitivitySink(inner Sink, maxSensitivity Sensitivity) *SensitivitySink {
+	return &SensitivitySink{inner: inner, maxSensitivity: maxSensitivity}
+}
+
+// Write passes the lines of data up to the sink's sensitivity to the inner
+// sink. Dropped lines count as written, so that a Shipper moves past them.
+func (sink *SensitivitySink) Write(data []byte) (int, error) {
+	kept := data
+	if bytes.Contains(data, []byte(SensitivityField)) {
+		kept = sink.filter(data)
+	}
+
+	if len(kept) > 0 {
+		_, err := sink.inner.Write(kept)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(data), nil
+}
+
+// Close closes the inner sink.
+func (sink *SensitivitySink) Close() error {
+	return sink.inner.Close()
+}
+
+// filter returns the lines of data up to the sink's sensitivity.
+func (sink *SensitivitySink) filter(data []byte) []byte {
+	kept := make([]byte, 0, len(data))
+
+	for line := range bytes.Lines(data) {
+		if lineSensitivity(line) <= sink.maxSensitivity {
+			kept = append(kept, line...)
+		}
+	}
+
+	return kept
+}
+
+// lineSensitivity returns the most restricted sensitivity among the
+// sensitivity fields rendered in line.
+func lineSensitivity(line []byte) Sensitivity {
+	result := SensitivityPublic
+
+	for {
+		index := bytes.Index(line, []byte(SensitivityField))
+		if index < 0 {
+			return result
+		}
+
+		line = line[index+len(SensitivityField):]
+
+		value, found := bytes.CutPrefix(line, []byte(sensitivityAssign))
+		if !found {
+			value, found = bytes.CutPrefix(line, []byte(sensitivityJSONAssign))
+		}
+
+		if !found {
+			continue
+		}
+
+		value = bytes.TrimPrefix(value, []byte(sensitivityQuote))
+
+		end := bytes.IndexFunc(value, func(r rune) bool { return !unicode.IsLetter(r) })
+		if end >= 0 {
+			value = value[:end]
+		}
+
+		sensitivity, ok := ParseSensitivity(string(value))
+		if !ok {
+			sensitivity = SensitivitySecret
+		}
+
+		result = max(result, sensitivity)
+	}
+}
@@ -0,0 +1,136 @@
+package logger_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	sensitivityMainFile   = "app.log"
+	sensitivityLocalFile  = "restricted.log"
+	sensitivityPublicMsg  = "order placed"
+	sensitivityPrivateMsg = "card declined"
+	sensitivitySecretMsg  = "token rotated"
+	sensitivityTypoMsg    = "mislabeled"
+	sensitivityURL        = "/entries"
+	sensitivityMissingFmt = "%s lacks %q:\n%s"
+	sensitivityLeakFmt    = "%s leaks %q:\n%s"
+	sensitivityQuotedMsg  = "mentions sensitivity=public"
+	sensitivityWriteFmt   = "Write = %d, %v, want %d, nil"
+)
+
+func TestRoute_MaxSensitivity(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	var remote, publicOnly bytes.Buffer
+
+	loggerInstance, err := logger.New(dir, sensitivityMainFile,
+		logger.WithoutStdout(),
+		logger.WithRoute(logger.Route{Name: "remote", Writer: &remote}),
+		logger.WithRoute(logger.Route{Name: "public", Writer: &publicOnly, MaxSensitivity: logger.SensitivityPublic}),
+		logger.WithRoute(logger.Route{Filename: sensitivityLocalFile}),
+	)
+	if err != nil {
+		t.Fatalf(newLoggerWithDirErrFmt, err)
+	}
+
+	loggerInstance.Infof(sensitivityPublicMsg)
+	loggerInstance.WithSensitivity(logger.SensitivityInternal).Infof(sensitivityPrivateMsg)
+	loggerInstance.With(logger.Sensitive(logger.SensitivitySecret)).Infof(sensitivitySecretMsg)
+	loggerInstance.With(logger.F(logger.SensitivityField, "secrte")).Infof(sensitivityTypoMsg)
+
+	closeOutputsLogger(t, loggerInstance)
+
+	local := readOutputsFile(t, filepath.Join(dir, sensitivityLocalFile))
+	main := readOutputsFile(t, filepath.Join(dir, sensitivityMainFile))
+
+	expectSensitivity(t, "remote", remote.String(),
+		[]string{sensitivityPublicMsg, sensitivityPrivateMsg}, []string{sensitivitySecretMsg, sensitivityTypoMsg})
+	expectSensitivity(t, "public", publicOnly.String(),
+		[]string{sensitivityPublicMsg}, []string{sensitivityPrivateMsg, sensitivitySecretMsg})
+	expectSensitivity(t, sensitivityLocalFile, local,
+		[]string{sensitivityPublicMsg, sensitivityPrivateMsg, sensitivitySecretMsg + " sensitivity=secret"}, nil)
+	expectSensitivity(t, sensitivityMainFile, main, []string{sensitivitySecretMsg, sensitivityTypoMsg}, nil)
+}
+
+func TestRecentHandler_HidesSecretEntries(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	loggerInstance := logger.NewStreamLogger(&buf, logger.WithRecentWindow(recentWindow))
+	loggerInstance.Infof(sensitivityPublicMsg)
+	loggerInstance.WithSensitivity(logger.SensitivitySecret).Infof(sensitivitySecretMsg)
+
+	recorder := httptest.NewRecorder()
+	loggerInstance.RecentHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, sensitivityURL, nil))
+
+	expectSensitivity(t, sensitivityURL, recorder.Body.String(),
+		[]string{sensitivityPublicMsg}, []string{sensitivitySecretMsg})
+}
+
+func TestSensitivitySink_DropsSecretLines(t *testing.T) {
+	t.Parallel()
+
+	encodings := []struct {
+		name     string
+		encoding logger.Encoding
+	}{
+		{name: "text", encoding: logger.EncodingText},
+		{name: "json", encoding: logger.EncodingJSON},
+		{name: "cef", encoding: logger.EncodingCEF},
+		{name: "leef", encoding: logger.EncodingLEEF},
+		{name: "syslog", encoding: logger.EncodingSyslog},
+	}
+
+	for _, test := range encodings {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var rendered, shipped bytes.Buffer
+
+			loggerInstance := logger.NewStreamLogger(&rendered, logger.WithEncoding(test.encoding))
+			loggerInstance.Infof(sensitivityPublicMsg)
+			loggerInstance.WithSensitivity(logger.SensitivityInternal).Infof(sensitivityPrivateMsg)
+			loggerInstance.WithSensitivity(logger.SensitivitySecret).Infof(sensitivitySecretMsg)
+			loggerInstance.With(logger.F(logger.SensitivityField, "secrte")).Infof(sensitivityTypoMsg)
+			loggerInstance.Infof(sensitivityQuotedMsg)
+			closeOutputsLogger(t, loggerInstance)
+
+			sink := logger.NewSensitivitySink(logger.WriterSink(&shipped), logger.SensitivityInternal)
+
+			written, err := sink.Write(rendered.Bytes())
+			if written != rendered.Len() || err != nil {
+				t.Fatalf(sensitivityWriteFmt, written, err, rendered.Len())
+			}
+
+			expectSensitivity(t, test.name, shipped.String(),
+				[]string{sensitivityPublicMsg, sensitivityPrivateMsg, sensitivityQuotedMsg},
+				[]string{sensitivitySecretMsg, sensitivityTypoMsg})
+		})
+	}
+}
+
+func expectSensitivity(t *testing.T, name, output string, present, absent []string) {
+	t.Helper()
+
+	for _, want := range present {
+		if !strings.Contains(output, want) {
+			t.Errorf(sensitivityMissingFmt, name, want, output)
+		}
+	}
+
+	for _, unwanted := range absent {
+		if strings.Contains(output, unwanted) {
+			t.Errorf(sensitivityLeakFmt, name, unwanted, output)
+		}
+	}
+}
@@ -0,0 +1,331 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+)
+
+const (
+	// DefaultShipBatchBytes is the batch size of a Shipper created with a
+	// non-positive maxBatchBytes.
+	DefaultShipBatchBytes = 256 << 10
+
+	// shipHeadBytes bounds the first line hashed into a cursor's fingerprint.
+	shipHeadBytes = 1024
+
+	shipStateTempPattern = ".tmp-*"
+	shipLineEnd          = '\n'
+	errFmtLoadShipState  = "load ship state: %w"
+	errFmtSaveShipState  = "save ship state: %w"
+	errFmtShipOpen       = "ship %s: %w"
+	errFmtShipBatch      = "ship %s at offset %d: %w"
+)
+
+// ShipCursor is the position up to which a file has been acknowledged. Path
+// is where the file was last seen and Fingerprint a hash of its first line.
+type ShipCursor struct {
+	Path        string `json:"path"`
+	Fingerprint string `json:"fingerprint,omitempty"`
+	Offset      int64  `json:"offset"`
+}
+
+// shipState is the persisted state of a Shipper. Cursors are keyed by file
+// identity, device and inode where available, so that they follow files
+// renamed by rotation.
+type shipState struct {
+	Files map[string]ShipCursor `json:"files"`
+}
+
+// Shipper delivers the lines of log files to a sink at least once. Lines are
+// sent in batches of complete lines; a batch counts as acknowledged when the
+// sink's Write returns without error, for example once an HTTP collector has
+// answered, and only then is the file's cursor advanced and persisted. After
+// a crash or restart shipping resumes from the cursor, so no line is skipped
+// and at most one batch per file is delivered twice. Cursors follow files
+// across renames, so a log rotated to app.log.1 is finished from where it
+// left off while the new app.log starts from the beginning. A cursor also
+// holds the fingerprint of the file's first line, so a file rewritten in
+// place, or a new file that reuses the inode of a deleted one, is shipped
+// from the start rather than from the old file's offset.
+type Shipper struct {
+	sink      Sink
+	state     shipState
+	statePath string
+	buf       []byte
+	batchSize int
+	mu        sync.Mutex
+}
+
+// OpenShipper creates a Shipper sending to sink and persisting its cursors in
+// the JSON file at statePath, which is created on the first acknowledgement.
+// With an empty statePath the cursors are kept in memory only.
+func OpenShipper(statePath string, sink Sink, maxBatchBytes int) (*Shipper, error) {
+	state, err := loadShipState(statePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if maxBatchBytes <= 0 {
+		maxBatchBytes = DefaultShipBatchBytes
+	}
+
+	return &Shipper{
+		sink:      sink,
+		state:     state,
+		statePath: statePath,
+		buf:       make([]byte, maxBatchBytes),
+		batchSize: maxBatchBytes,
+		mu:        sync.Mutex{},
+	}, nil
+}
+
+func loadShipState(statePath string) (shipState, error) {
+	state := shipState{Files: nil}
+
+	if statePath != "" {
+		// #nosec G304 -- the state path is chosen by the operator.
+		data, err := os.ReadFile(statePath)
+		if err == nil {
+			err = json.Unmarshal(data, &state)
+		}
+
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return state, fmt.Errorf(errFmtLoadShipState, err)
+		}
+	}
+
+	if state.Files == nil {
+		state.Files = make(map[string]ShipCursor)
+	}
+
+	return state, nil
+}
+
+// Cursor returns the acknowledged position in the file at path.
+func (shipper *Shipper) Cursor(path string) ShipCursor {
+	info, err := os.Stat(path)
+	if err != nil {
+		return ShipCursor{Path: path, Fingerprint: "", Offset: 0}
+	}
+
+	shipper.mu.Lock()
+	defer shipper.mu.Unlock()
+
+	return shipper.state.Files[fileKey(path, info)]
+}
+
+// ShipGlob ships every file matching pattern, oldest first so that rotated
+// backups are finished before the files that replaced them, and forgets the
+// cursors of files that no longer match. It returns the number of bytes
+// acknowledged and stops at the first error.
+func (shipper *Shipper) ShipGlob(pattern string) (int64, error) {
+	paths, err := filepath.Glob(pattern)
+	if err != nil {
+		return 0, fmt.Errorf(errFmtShipOpen, pattern, err)
+	}
+
+	files := make([]shipFile, 0, len(paths))
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err == nil && info.Mode().IsRegular() {
+			files = append(files, shipFile{info: info, path: path})
+		}
+	}
+
+	slices.SortStableFunc(files, func(a, b shipFile) int {
+		return a.info.ModTime().Compare(b.info.ModTime())
+	})
+
+	shipper.forget(files)
+
+	var shipped int64
+
+	for _, file := range files {
+		count, err := shipper.Ship(file.path)
+		shipped += count
+
+		if err != nil {
+			return shipped, err
+		}
+	}
+
+	return shipped, nil
+}
+
+// shipFile is a file matched by ShipGlob.
+type shipFile struct {
+	info os.FileInfo
+	path string
+}
+
+// forget drops the cursors of files other than files.
+func (shipper *Shipper) forget(files []shipFile) {
+	keys := make(map[string]bool, len(files))
+	for _, file := range files {
+		keys[fileKey(file.path, file.info)] = true
+	}
+
+	shipper.mu.Lock()
+	defer shipper.mu.Unlock()
+
+	maps.DeleteFunc(shipper.state.Files, func(key string, _ ShipCursor) bool {
+		return !keys[key]
+	})
+}
+
+// Ship sends the complete lines of the file at path from its cursor to the
+// end and returns the number of bytes acknowledged. A trailing partial line
+// waits for the next call. A file shorter than its cursor has been truncated
+// and is shipped from the start, as is a file whose first line no longer
+// matches the cursor's fingerprint.
+func (shipper *Shipper) Ship(path string) (int64, error) {
+	shipper.mu.Lock()
+	defer shipper.mu.Unlock()
+
+	// #nosec G304 -- shipped files are chosen by the operator.
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf(errFmtShipOpen, path, err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return 0, fmt.Errorf(errFmtShipOpen, path, err)
+	}
+
+	head, err := headFingerprint(file)
+	if err != nil {
+		return 0, err
+	}
+
+	key := fileKey(path, info)
+	cursor := shipper.state.Files[key]
+
+	offset := cursor.Offset
+	if offset > info.Size() || (cursor.Fingerprint != "" && cursor.Fingerprint != head) {
+		offset = 0
+	}
+
+	start := offset
+
+	for {
+		batch, err := shipper.readBatch(file, offset)
+		if err != nil || len(batch) == 0 {
+			return offset - start, err
+		}
+
+		_, err = shipper.sink.Write(batch)
+		if err != nil {
+			return offset - start, fmt.Errorf(errFmtShipBatch, path, offset, err)
+		}
+
+		offset += int64(len(batch))
+
+		err = shipper.acknowledge(key, ShipCursor{Path: path, Fingerprint: head, Offset: offset})
+		if err != nil {
+			return offset - start, err
+		}
+	}
+}
+
+// headFingerprint hashes the first line of file, at most shipHeadBytes of
+// it. It returns "" while the first line is incomplete.
+func headFingerprint(file *os.File) (string, error) {
+	head := make([]byte, shipHeadBytes)
+
+	n, err := file.ReadAt(head, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf(errFmtShipOpen, file.Name(), err)
+	}
+
+	end := bytes.IndexByte(head[:n], shipLineEnd)
+
+	switch {
+	case end >= 0:
+		head = head[:end]
+	case n < shipHeadBytes:
+		return "", nil
+	}
+
+	return fingerprint(string(head)), nil
+}
+
+// readBatch returns the complete lines starting at offset, up to the batch
+// size. A single line longer than the batch size is returned whole.
+func (shipper *Shipper) readBatch(file *os.File, offset int64) ([]byte, error) {
+	n, err := file.ReadAt(shipper.buf, offset)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf(errFmtShipOpen, file.Name(), err)
+	}
+
+	batch := shipper.buf[:n]
+
+	end := bytes.LastIndexByte(batch, shipLineEnd)
+	if end >= 0 {
+		return batch[:end+1], nil
+	}
+
+	if n < len(shipper.buf) {
+		return nil, nil
+	}
+
+	shipper.buf = make([]byte, 2*len(shipper.buf))
+
+	return shipper.readBatch(file, offset)
+}
+
+// acknowledge advances the cursor of the file with key and persists the
+// state atomically.
+func (shipper *Shipper) acknowledge(key string, cursor ShipCursor) error {
+	shipper.state.Files[key] = cursor
+	if shipper.statePath == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(shipper.state)
+	if err != nil {
+		return fmt.Errorf(errFmtSaveShipState, err)
+	}
+
+	err = writeFileAtomic(shipper.statePath, data)
+	if err != nil {
+		return fmt.Errorf(errFmtSaveShipState, err)
+	}
+
+	return nil
+}
+
+// writeFileAtomic replaces path with data so that readers see either the old
+// or the new content, even after a crash.
+func writeFileAtomic(path string, data []byte) error {
+	temp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+shipStateTempPattern)
+	if err != nil {
+		return err
+	}
+
+	_, err = temp.Write(data)
+	if err == nil {
+		err = temp.Sync()
+	}
+
+	err = errors.Join(err, temp.Close())
+	if err == nil {
+		err = os.Rename(temp.Name(), path)
+	}
+
+	if err != nil {
+		_ = os.Remove(temp.Name())
+	}
+
+	return err
+}
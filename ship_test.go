@@ -0,0 +1,190 @@
+package logger_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	shipLogFile     = "app.log"
+	shipStateFile   = "ship.json"
+	shipContent     = "aaa\nbbb\nccc\nddd\npartial"
+	shipBatchBytes  = 8
+	shipFirstBatch  = 8
+	shipComplete    = 16
+	shipFilePerm    = 0o600
+	shipWriteFmt    = "write log: %v"
+	shipOpenFmt     = "OpenShipper: %v"
+	shipErrFmt      = "Ship error = %v, want %v"
+	shipUnexpectFmt = "unexpected error: %v"
+	shipCursorFmt   = "cursor = %d, want %d"
+	shipShippedFmt  = "shipped %d bytes, want %d"
+	shipRotatedFile = "app.log.1"
+	shipGlob        = "app.log*"
+	shipFirstLine   = "a\n"
+	shipBeforeLine  = "b\n"
+	shipAfterLine   = "c\n"
+	shipRenameFmt   = "rotate: %v"
+	shipBatchFmt    = "batch %d = %q, want %q"
+	shipBatchesFmt  = "got %d batches, want %d"
+	shipRewritten   = "xxx\nyyy\nzzz\n"
+	shipFingerprint = "fingerprint of %q = %q, want one of the first line"
+)
+
+func TestShipper_ResumesFromAcknowledgedCursor(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, shipLogFile)
+	statePath := filepath.Join(dir, shipStateFile)
+
+	err := os.WriteFile(logPath, []byte(shipContent), shipFilePerm)
+	if err != nil {
+		t.Fatalf(shipWriteFmt, err)
+	}
+
+	failing := &limitSink{stubSink: stubSink{writes: 0, fail: false, closed: false}, limit: 1}
+	shipper := openShipper(t, statePath, failing)
+
+	_, err = shipper.Ship(logPath)
+	if !errors.Is(err, errTestWriteFailed) {
+		t.Errorf(shipErrFmt, err, errTestWriteFailed)
+	}
+
+	restarted := openShipper(t, statePath, &stubSink{writes: 0, fail: false, closed: false})
+	if cursor := restarted.Cursor(logPath).Offset; cursor != shipFirstBatch {
+		t.Errorf(shipCursorFmt, cursor, shipFirstBatch)
+	}
+
+	shipped, err := restarted.Ship(logPath)
+	if err != nil {
+		t.Fatalf(shipUnexpectFmt, err)
+	}
+
+	if shipped != shipComplete-shipFirstBatch {
+		t.Errorf(shipShippedFmt, shipped, shipComplete-shipFirstBatch)
+	}
+
+	if cursor := restarted.Cursor(logPath).Offset; cursor != shipComplete {
+		t.Errorf(shipCursorFmt, cursor, shipComplete)
+	}
+}
+
+func TestShipper_FollowsRotation(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, shipLogFile)
+	rotatedPath := filepath.Join(dir, shipRotatedFile)
+	inner := newRecordingSink(nil)
+	shipper := openShipper(t, filepath.Join(dir, shipStateFile), inner)
+
+	writeShipFile(t, logPath, shipFirstLine)
+	shipGlobOnce(t, shipper, dir)
+
+	writeShipFile(t, logPath, shipFirstLine+shipBeforeLine)
+
+	err := os.Rename(logPath, rotatedPath)
+	if err != nil {
+		t.Fatalf(shipRenameFmt, err)
+	}
+
+	writeShipFile(t, logPath, shipAfterLine)
+
+	rotatedAt := time.Now().Add(-time.Minute)
+
+	err = os.Chtimes(rotatedPath, rotatedAt, rotatedAt)
+	if err != nil {
+		t.Fatalf(shipRenameFmt, err)
+	}
+
+	shipGlobOnce(t, shipper, dir)
+
+	want := []string{shipFirstLine, shipBeforeLine, shipAfterLine}
+	if len(inner.batches) != len(want) {
+		t.Fatalf(shipBatchesFmt, len(inner.batches), len(want))
+	}
+
+	for index, line := range want {
+		got := <-inner.batches
+		if got != line {
+			t.Errorf(shipBatchFmt, index, got, line)
+		}
+	}
+}
+
+func TestShipper_RestartsRewrittenFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, shipLogFile)
+	statePath := filepath.Join(dir, shipStateFile)
+
+	writeShipFile(t, logPath, shipFirstLine+shipBeforeLine)
+
+	_, err := openShipper(t, statePath, &stubSink{writes: 0, fail: false, closed: false}).Ship(logPath)
+	if err != nil {
+		t.Fatalf(shipUnexpectFmt, err)
+	}
+
+	// Rewritten in place: same inode, longer than the old cursor.
+	err = os.WriteFile(logPath, []byte(shipRewritten), shipFilePerm)
+	if err != nil {
+		t.Fatalf(shipWriteFmt, err)
+	}
+
+	restarted := openShipper(t, statePath, &stubSink{writes: 0, fail: false, closed: false})
+	if cursor := restarted.Cursor(logPath); cursor.Fingerprint == "" {
+		t.Errorf(shipFingerprint, logPath, cursor.Fingerprint)
+	}
+
+	shipped, err := restarted.Ship(logPath)
+	if err != nil {
+		t.Fatalf(shipUnexpectFmt, err)
+	}
+
+	if shipped != int64(len(shipRewritten)) {
+		t.Errorf(shipShippedFmt, shipped, len(shipRewritten))
+	}
+}
+
+func writeShipFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, shipFilePerm)
+	if err != nil {
+		t.Fatalf(shipWriteFmt, err)
+	}
+
+	_, err = file.WriteString(content)
+	if err != nil {
+		t.Fatalf(shipWriteFmt, err)
+	}
+
+	_ = file.Close()
+}
+
+func shipGlobOnce(t *testing.T, shipper *logger.Shipper, dir string) {
+	t.Helper()
+
+	_, err := shipper.ShipGlob(filepath.Join(dir, shipGlob))
+	if err != nil {
+		t.Fatalf(shipUnexpectFmt, err)
+	}
+}
+
+func openShipper(t *testing.T, statePath string, sink logger.Sink) *logger.Shipper {
+	t.Helper()
+
+	shipper, err := logger.OpenShipper(statePath, sink, shipBatchBytes)
+	if err != nil {
+		t.Fatalf(shipOpenFmt, err)
+	}
+
+	return shipper
+}
@@ -0,0 +1,161 @@
+package logger
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Device identification in CEF and LEEF headers.
+	SIEMVendor  = "book-expert"
+	SIEMProduct = "logger"
+
+	cefPrefix         = "CEF:0|"
+	leefPrefix        = "LEEF:1.0|"
+	siemHeaderSep     = "|"
+	cefExtensionSep   = " "
+	leefAttributeSep  = "\t"
+	siemAssign        = "="
+	cefTimeKey        = "rt"
+	cefMessageKey     = "msg"
+	cefLevelKey       = "cs1"
+	cefLevelLabelKey  = "cs1Label"
+	cefLevelLabel     = "level"
+	leefTimeKey       = "devTime"
+	leefTimeFmtKey    = "devTimeFormat"
+	leefTimeFormat    = "MMM dd yyyy HH:mm:ss.SSS Z"
+	leefGoTimeFormat  = "Jan 02 2006 15:04:05.000 -0700"
+	leefSeverityKey   = "sev"
+	leefCategoryKey   = "cat"
+	leefMessageKey    = "msg"
+	siemSeverityInfo  = 3
+	siemSeverityWarn  = 5
+	siemSeverityError = 7
+	maxSIEMSeverity   = 10
+	siemTimeBase      = 10
+	siemSchemaDesc    = "CEF or LEEF line; the pattern matches its header."
+	cefSchemaPattern  = `^CEF:0\|`
+	leefSchemaPattern = `^LEEF:1\.0\|`
+)
+
+var (
+	// cefHeaderEscaper escapes CEF and LEEF header values.
+	cefHeaderEscaper = strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\n", " ", "\r", " ")
+	// cefValueEscaper escapes CEF extension values.
+	cefValueEscaper = strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\n", `\n`, "\r", `\r`)
+	// leefValueEscaper keeps LEEF attribute values on one line and free of
+	// the attribute delimiter.
+	leefValueEscaper = strings.NewReplacer("\t", " ", "\n", " ", "\r", " ")
+	// siemKeyEscaper replaces the characters that cannot appear in keys.
+	siemKeyEscaper = strings.NewReplacer(" ", "_", "=", "_", `\`, "_", "|", "_", "\t", "_")
+)
+
+// encodeCEF renders logEntry in ArcSight Common Event Format:
+//
+//	CEF:0|book-expert|logger|1|<fingerprint>|<message>|<severity>|rt=... msg=...
+//
+// The signature ID is the message fingerprint reported by Stats, so that
+// entries of one log statement group together, and fields follow as
+// extension key=value pairs.
+func encodeCEF(logEntry *Entry) string {
+	var builder strings.Builder
+
+	builder.WriteString(cefPrefix)
+	writeSIEMHeader(&builder, logEntry)
+	builder.WriteString(cefHeaderEscaper.Replace(logEntry.Message))
+	builder.WriteString(siemHeaderSep)
+	builder.WriteString(strconv.Itoa(siemSeverity(logEntry.Level)))
+	builder.WriteString(siemHeaderSep)
+
+	builder.WriteString(cefTimeKey + siemAssign)
+	builder.WriteString(strconv.FormatInt(logEntry.Time.UnixMilli(), siemTimeBase))
+	writeCEFExtension(&builder, cefMessageKey, logEntry.Message)
+	writeCEFExtension(&builder, cefLevelKey, logEntry.Label)
+	writeCEFExtension(&builder, cefLevelLabelKey, cefLevelLabel)
+
+	for _, field := range logEntry.Fields {
+		writeCEFExtension(&builder, field.Key, formatFieldText(field.Value))
+	}
+
+	return builder.String()
+}
+
+func writeCEFExtension(builder *strings.Builder, key, value string) {
+	builder.WriteString(cefExtensionSep)
+	builder.WriteString(siemKeyEscaper.Replace(key))
+	builder.WriteString(siemAssign)
+	builder.WriteString(cefValueEscaper.Replace(value))
+}
+
+// encodeLEEF renders logEntry in IBM QRadar Log Event Extended Format 1.0:
+//
+//	LEEF:1.0|book-expert|logger|1|<fingerprint>|devTime=...<TAB>sev=...
+//
+// with tab-separated attributes for the time, severity, level, message and
+// fields.
+func encodeLEEF(logEntry *Entry) string {
+	var builder strings.Builder
+
+	builder.WriteString(leefPrefix)
+	writeSIEMHeader(&builder, logEntry)
+
+	builder.WriteString(leefTimeKey + siemAssign)
+	builder.WriteString(logEntry.Time.Format(leefGoTimeFormat))
+	writeLEEFAttribute(&builder, leefTimeFmtKey, leefTimeFormat)
+	writeLEEFAttribute(&builder, leefSeverityKey, strconv.Itoa(siemSeverity(logEntry.Level)))
+	writeLEEFAttribute(&builder, leefCategoryKey, logEntry.Label)
+	writeLEEFAttribute(&builder, leefMessageKey, logEntry.Message)
+
+	for _, field := range logEntry.Fields {
+		writeLEEFAttribute(&builder, field.Key, formatFieldText(field.Value))
+	}
+
+	return builder.String()
+}
+
+func writeLEEFAttribute(builder *strings.Builder, key, value string) {
+	builder.WriteString(leefAttributeSep)
+	builder.WriteString(siemKeyEscaper.Replace(key))
+	builder.WriteString(siemAssign)
+	builder.WriteString(leefValueEscaper.Replace(value))
+}
+
+// writeSIEMHeader writes the vendor, product, version and event ID shared by
+// the CEF and LEEF headers, each followed by the separator.
+func writeSIEMHeader(builder *strings.Builder, logEntry *Entry) {
+	for _, value := range []string{
+		SIEMVendor,
+		SIEMProduct,
+		strconv.Itoa(SchemaVersion),
+		fingerprint(messageTemplate(logEntry.Message)),
+	} {
+		builder.WriteString(cefHeaderEscaper.Replace(value))
+		builder.WriteString(siemHeaderSep)
+	}
+}
+
+// siemSeverity maps level to the 0-10 severity scale of CEF and LEEF: DEBUG
+// is 0, INFO 3, WARN 5, ERROR 7, SECURITY 9 and FATAL 10, with registered
+// levels in between.
+func siemSeverity(level Level) int {
+	switch {
+	case level < LevelInfo:
+		return 0
+	case level < LevelWarn:
+		return siemSeverityInfo + int(level-LevelInfo)/2
+	case level < LevelError:
+		return siemSeverityWarn + int(level-LevelWarn)/2
+	default:
+		return min(siemSeverityError+int(level-LevelError)*2/3, maxSIEMSeverity)
+	}
+}
+
+// formatFieldText renders a field value without the quoting of text output.
+func formatFieldText(value any) string {
+	if err, ok := value.(error); ok {
+		return err.Error()
+	}
+
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,100 @@
+package logger_test
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	siemMsg         = "charge failed | retry=3"
+	siemFieldKey    = "order id"
+	siemFieldValue  = "A=1"
+	siemCEFPrefix   = "CEF:0|book-expert|logger|"
+	siemLEEFPrefix  = "LEEF:1.0|book-expert|logger|"
+	siemMissingFmt  = "%s line lacks %q:\n%s"
+	siemHeaderCount = 8
+	siemSinkFile    = "siem.cef"
+	siemConfigJSON  = `{"dir": %q, "file": "app.log",
+  "sinks": [{"name": "siem", "type": "file", "target": "siem.cef", "encoder": "cef"}]}`
+	siemHeaderFmt = "expected seven header parts before the extension: %q"
+)
+
+func siemLine(t *testing.T, encoding logger.Encoding) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	loggerInstance := logger.NewStreamLogger(&buf, logger.WithEncoding(encoding))
+	loggerInstance.With(logger.F(siemFieldKey, siemFieldValue)).Errorf(siemMsg)
+
+	return strings.TrimSpace(buf.String())
+}
+
+func expectSIEMParts(t *testing.T, name, line string, parts []string) {
+	t.Helper()
+
+	for _, part := range parts {
+		if !strings.Contains(line, part) {
+			t.Errorf(siemMissingFmt, name, part, line)
+		}
+	}
+}
+
+func TestEncoding_CEF(t *testing.T) {
+	t.Parallel()
+
+	line := siemLine(t, logger.EncodingCEF)
+
+	header := strings.SplitN(strings.ReplaceAll(line, `\|`, ""), "|", siemHeaderCount)
+	if len(header) != siemHeaderCount || !strings.HasPrefix(header[siemHeaderCount-1], "rt=") {
+		t.Fatalf(siemHeaderFmt, line)
+	}
+
+	expectSIEMParts(t, "CEF", line, []string{
+		siemCEFPrefix,
+		`|charge failed \| retry=3|7|rt=`,
+		` msg=charge failed | retry\=3`,
+		" cs1=ERROR cs1Label=level",
+		` order_id=A\=1`,
+	})
+}
+
+func TestEncoding_LEEF(t *testing.T) {
+	t.Parallel()
+
+	line := siemLine(t, logger.EncodingLEEF)
+
+	expectSIEMParts(t, "LEEF", line, []string{
+		siemLEEFPrefix,
+		"|devTime=",
+		"\tsev=7\tcat=ERROR\tmsg=" + siemMsg,
+		"\torder_id=" + siemFieldValue,
+	})
+}
+
+func TestLoadConfig_CEFSink(t *testing.T) {
+	t.Parallel()
+
+	logDir := t.TempDir()
+
+	config, err := logger.LoadConfig(writeConfigFile(t, fmt.Sprintf(siemConfigJSON, logDir)))
+	if err != nil {
+		t.Fatalf(loadConfigErrFmt, err)
+	}
+
+	loggerInstance, err := logger.NewFromConfig(config)
+	if err != nil {
+		t.Fatalf(configNewLoggerErrF, err)
+	}
+
+	loggerInstance.Errorf(siemMsg)
+	closeOutputsLogger(t, loggerInstance)
+
+	line := readOutputsFile(t, filepath.Join(logDir, siemSinkFile))
+	expectSIEMParts(t, siemSinkFile, line, []string{siemCEFPrefix, " cs1=ERROR"})
+}
@@ -0,0 +1,57 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+)
+
+// Sink is a destination for rendered lines that the logger closes, such as a
+// remote collector client. Each Write receives one or more complete lines,
+// including their line ends. Decorators like CircuitBreaker wrap a Sink to add behavior shared by
+// remote destinations.
+type Sink interface {
+	io.Writer
+	io.Closer
+}
+
+// writerSink is a Sink over a writer with nothing to close.
+type writerSink struct {
+	io.Writer
+}
+
+// WriterSink adapts writer to a Sink whose Close does nothing, so that plain
+// writers can be wrapped by sink decorators.
+func WriterSink(writer io.Writer) Sink {
+	return writerSink{Writer: writer}
+}
+
+func (writerSink) Close() error {
+	return nil
+}
+
+// sinkFlusher is implemented by sinks that buffer lines, such as BatchSink.
+type sinkFlusher interface {
+	Flush() error
+}
+
+// flushSinks flushes the route sinks that buffer lines, recording their
+// errors for Err.
+func (c *loggerCore) flushSinks() {
+	c.mu.Lock()
+	routes := c.routes
+	c.mu.Unlock()
+
+	for _, target := range routes {
+		sink, ok := target.closer.(sinkFlusher)
+		if !ok {
+			continue
+		}
+
+		err := sink.Flush()
+		if err != nil {
+			c.mu.Lock()
+			c.lastErr = fmt.Errorf(errFmtWriteRoute, err)
+			c.mu.Unlock()
+		}
+	}
+}
@@ -0,0 +1,346 @@
+package logger
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// minSinkWorkers is the pool size below which GOMAXPROCS does not shrink
+	// it: workers mostly wait for I/O rather than use a CPU.
+	minSinkWorkers = 4
+
+	metricQueueLengthHelp  = "# HELP logger_sink_queue_length Lines waiting in a route's sink queue.\n"
+	metricQueueLengthType  = "# TYPE logger_sink_queue_length gauge\n"
+	metricQueueLengthFmt   = "logger_sink_queue_length{sink=%q} %d\n"
+	metricQueueWrittenHelp = "# HELP logger_sink_queue_written_total Lines written from a route's sink queue.\n"
+	metricQueueWrittenType = "# TYPE logger_sink_queue_written_total counter\n"
+	metricQueueWrittenFmt  = "logger_sink_queue_written_total{sink=%q} %d\n"
+	metricQueueDroppedHelp = "# HELP logger_sink_queue_dropped_total Lines dropped because a route's sink queue was full.\n"
+	metricQueueDroppedType = "# TYPE logger_sink_queue_dropped_total counter\n"
+	metricQueueDroppedFmt  = "logger_sink_queue_dropped_total{sink=%q} %d\n"
+)
+
+// SinkQueueStats describes the queue of one route with WithSinkWorkers:
+// the lines waiting, the queue's capacity, and the lines written and dropped
+// so far.
+type SinkQueueStats struct {
+	Sink     string
+	Length   int
+	Capacity int
+	Written  uint64
+	Dropped  uint64
+}
+
+// WithSinkWorkers gives every route its own queue of up to queueSize lines,
+// drained by a pool of workers, so that a slow sink such as a network
+// collector does not hold up the log file, stdout or faster routes. The pool
+// has one worker per route, up to GOMAXPROCS or four, whichever is more.
+// Each route is written by one worker at a time, in the order its lines were
+// queued, and the lines queued meanwhile are delivered in one Write. When a
+// route's queue is full, its lines are dropped and counted, as with a full
+// BatchSink. Flush and Close wait for the queues to drain. A queueSize of
+// zero or less writes routes on the logging goroutine.
+func WithSinkWorkers(queueSize int) Option {
+	return func(config *options) {
+		config.sinkQueueSize = queueSize
+	}
+}
+
+// sinkQueue holds the lines waiting for one route. Its fields are guarded by
+// sinkPool.mu.
+type sinkQueue struct {
+	target    *routeTarget
+	pending   []byte
+	spare     []byte
+	logged    []time.Time // log time of each pending line
+	loggedOut []time.Time
+	written   uint64
+	dropped   uint64
+	scheduled bool // waiting in ready or being written
+}
+
+// sinkPool writes the route queues on a pool of worker goroutines. A queue
+// with lines is in ready until a worker takes it; the worker writes
+// everything queued so far and puts the queue back when more arrived in the
+// meantime.
+type sinkPool struct {
+	core     *loggerCore
+	queues   []*sinkQueue
+	ready    []*sinkQueue
+	cond     *sync.Cond
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+	capacity int
+	busy     int // scheduled queues
+	stopped  bool
+}
+
+// startSinkWorkers attaches a queue to every route and starts the workers
+// when WithSinkWorkers is configured.
+func (c *loggerCore) startSinkWorkers(queueSize int) {
+	if queueSize <= 0 {
+		return
+	}
+
+	pool := &sinkPool{
+		core:     c,
+		queues:   nil,
+		ready:    nil,
+		cond:     nil,
+		wg:       sync.WaitGroup{},
+		mu:       sync.Mutex{},
+		capacity: queueSize,
+		busy:     0,
+		stopped:  false,
+	}
+	pool.cond = sync.NewCond(&pool.mu)
+
+	c.mu.Lock()
+
+	for _, target := range c.routes {
+		pool.attach(target)
+	}
+
+	workers := max(min(len(c.routes), max(runtime.GOMAXPROCS(0), minSinkWorkers)), 1)
+	c.sinks = pool
+	c.mu.Unlock()
+
+	for range workers {
+		pool.wg.Go(func() { c.runTask(ProfileTaskSinkWorker, pool.work) })
+	}
+}
+
+// attach gives target a queue. A nil pool leaves target unqueued.
+func (pool *sinkPool) attach(target *routeTarget) {
+	if pool == nil {
+		return
+	}
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	target.queue = &sinkQueue{
+		target:    target,
+		pending:   nil,
+		spare:     nil,
+		logged:    nil,
+		loggedOut: nil,
+		written:   0,
+		dropped:   0,
+		scheduled: false,
+	}
+	pool.queues = append(pool.queues, target.queue)
+}
+
+// detach waits until the queue of target is written and removes it, so that
+// the route can be closed.
+func (pool *sinkPool) detach(target *routeTarget) {
+	if pool == nil || target.queue == nil {
+		return
+	}
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	for target.queue.scheduled {
+		pool.cond.Wait()
+	}
+
+	for index, queue := range pool.queues {
+		if queue == target.queue {
+			pool.queues = append(pool.queues[:index:index], pool.queues[index+1:]...)
+
+			break
+		}
+	}
+}
+
+// enqueue queues line, logged at logged, for queue. It reports false once
+// the pool is stopped, when the caller writes the line itself, and
+// ErrBackPressure when the queue is full.
+func (pool *sinkPool) enqueue(queue *sinkQueue, line []byte, logged time.Time) (bool, error) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	if pool.stopped {
+		return false, nil
+	}
+
+	if len(queue.logged) >= pool.capacity {
+		queue.dropped++
+
+		return true, ErrBackPressure
+	}
+
+	queue.pending = append(queue.pending, line...)
+	queue.logged = append(queue.logged, logged)
+
+	if !queue.scheduled {
+		queue.scheduled = true
+		pool.busy++
+		pool.ready = append(pool.ready, queue)
+		pool.cond.Broadcast()
+	}
+
+	return true, nil
+}
+
+func (pool *sinkPool) work() {
+	for {
+		queue := pool.next()
+		if queue == nil {
+			return
+		}
+
+		pool.write(queue)
+	}
+}
+
+// next waits for a queue with lines. It returns nil once the pool is stopped
+// and every queue is written.
+func (pool *sinkPool) next() *sinkQueue {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	for len(pool.ready) == 0 && !pool.stopped {
+		pool.cond.Wait()
+	}
+
+	if len(pool.ready) == 0 {
+		return nil
+	}
+
+	queue := pool.ready[0]
+	pool.ready = pool.ready[1:]
+
+	return queue
+}
+
+// write delivers the lines of queue in one Write and reschedules the queue
+// when more lines arrived meanwhile.
+func (pool *sinkPool) write(queue *sinkQueue) {
+	pool.mu.Lock()
+	data, logged := queue.pending, queue.logged
+	queue.pending, queue.logged = queue.spare[:0], queue.loggedOut[:0]
+	pool.mu.Unlock()
+
+	_, err := queue.target.writer.Write(data)
+	pool.core.recordQueuedWrite(queue.target, logged, err)
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	queue.written += uint64(len(logged))
+	queue.spare, queue.loggedOut = data[:0], logged[:0]
+
+	if len(queue.logged) > 0 {
+		pool.ready = append(pool.ready, queue)
+		pool.cond.Broadcast()
+
+		return
+	}
+
+	queue.scheduled = false
+	pool.busy--
+	pool.cond.Broadcast()
+}
+
+// flush waits until every queued line is written. A nil pool returns at
+// once.
+func (pool *sinkPool) flush() {
+	if pool == nil {
+		return
+	}
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	for pool.busy > 0 {
+		pool.cond.Wait()
+	}
+}
+
+// stop writes the queued lines and stops the workers. Later lines are
+// written by the logging goroutine.
+func (pool *sinkPool) stop() {
+	if pool == nil {
+		return
+	}
+
+	pool.mu.Lock()
+	pool.stopped = true
+	pool.cond.Broadcast()
+	pool.mu.Unlock()
+
+	pool.wg.Wait()
+}
+
+// stats returns the state of every queue.
+func (pool *sinkPool) stats() []SinkQueueStats {
+	if pool == nil {
+		return nil
+	}
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	stats := make([]SinkQueueStats, 0, len(pool.queues))
+	for _, queue := range pool.queues {
+		stats = append(stats, SinkQueueStats{
+			Sink:     queue.target.name,
+			Length:   len(queue.logged),
+			Capacity: pool.capacity,
+			Written:  queue.written,
+			Dropped:  queue.dropped,
+		})
+	}
+
+	return stats
+}
+
+// recordQueuedWrite records the latencies and the error of a write from the
+// queue of target.
+func (c *loggerCore) recordQueuedWrite(target *routeTarget, logged []time.Time, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, at := range logged {
+		target.latency.observe(time.Since(at))
+	}
+
+	err = c.routeWriteResult(target, err)
+	if err != nil {
+		c.lastErr = err
+	}
+}
+
+func writeSinkQueueMetrics(builder *strings.Builder, queues []SinkQueueStats) {
+	if len(queues) == 0 {
+		return
+	}
+
+	builder.WriteString(metricQueueLengthHelp)
+	builder.WriteString(metricQueueLengthType)
+
+	for _, queue := range queues {
+		fmt.Fprintf(builder, metricQueueLengthFmt, queue.Sink, queue.Length)
+	}
+
+	builder.WriteString(metricQueueWrittenHelp)
+	builder.WriteString(metricQueueWrittenType)
+
+	for _, queue := range queues {
+		fmt.Fprintf(builder, metricQueueWrittenFmt, queue.Sink, queue.Written)
+	}
+
+	builder.WriteString(metricQueueDroppedHelp)
+	builder.WriteString(metricQueueDroppedType)
+
+	for _, queue := range queues {
+		fmt.Fprintf(builder, metricQueueDroppedFmt, queue.Sink, queue.Dropped)
+	}
+}
@@ -0,0 +1,121 @@
+package logger_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	sinkPoolMsg        = "order %d placed"
+	sinkPoolSlow       = "slow"
+	sinkPoolFast       = "fast"
+	sinkPoolQueue      = 16
+	sinkPoolEntries    = 5
+	sinkPoolPoll       = time.Millisecond
+	sinkPoolDeadline   = 5 * time.Second
+	sinkPoolMetric     = `logger_sink_queue_written_total{sink="slow"} 5`
+	sinkPoolFastErrFmt = "expected the fast route to get %d lines while the slow one blocks, got %q"
+	sinkPoolSlowErrFmt = "expected %d lines in the slow route after Flush, got %q"
+	sinkPoolStatsFmt   = "expected %s with %d written and %d dropped lines, got %+v"
+	sinkPoolDropFmt    = "expected the slow route to write a line and drop the rest, got %+v"
+)
+
+// gateWriter blocks writes until open is closed.
+type gateWriter struct {
+	open chan struct{}
+	syncBuffer
+}
+
+func newGateWriter() *gateWriter {
+	return &gateWriter{open: make(chan struct{}), syncBuffer: syncBuffer{builder: strings.Builder{}, mu: sync.Mutex{}}}
+}
+
+func (w *gateWriter) Write(p []byte) (int, error) {
+	<-w.open
+
+	return w.syncBuffer.Write(p)
+}
+
+func newSinkPoolLogger(slow *gateWriter, fast *syncBuffer, queueSize int) (*logger.Logger, *syncBuffer) {
+	var stdout syncBuffer
+
+	return logger.NewStreamLogger(&stdout,
+		logger.WithSinkWorkers(queueSize),
+		logger.WithRoute(logger.Route{Name: sinkPoolSlow, Writer: slow}),
+		logger.WithRoute(logger.Route{Name: sinkPoolFast, Writer: fast}),
+	), &stdout
+}
+
+func TestSinkWorkers_SlowSinkDoesNotBlockOthers(t *testing.T) {
+	t.Parallel()
+
+	slow := newGateWriter()
+
+	var fast syncBuffer
+
+	loggerInstance, stdout := newSinkPoolLogger(slow, &fast, sinkPoolQueue)
+	defer closeTestLogger(t, loggerInstance)
+
+	for order := range sinkPoolEntries {
+		loggerInstance.Infof(sinkPoolMsg, order)
+	}
+
+	deadline := time.Now().Add(sinkPoolDeadline)
+	for strings.Count(fast.String(), "\n") < sinkPoolEntries && time.Now().Before(deadline) {
+		time.Sleep(sinkPoolPoll)
+	}
+
+	if strings.Count(fast.String(), "\n") != sinkPoolEntries || strings.Count(stdout.String(), "\n") != sinkPoolEntries {
+		t.Fatalf(sinkPoolFastErrFmt, sinkPoolEntries, fast.String())
+	}
+
+	close(slow.open)
+	loggerInstance.Flush()
+
+	if strings.Count(slow.String(), "\n") != sinkPoolEntries {
+		t.Errorf(sinkPoolSlowErrFmt, sinkPoolEntries, slow.String())
+	}
+
+	stats := loggerInstance.Stats()
+	for _, queue := range stats.SinkQueues {
+		if queue.Written != sinkPoolEntries || queue.Dropped != 0 || queue.Length != 0 {
+			t.Errorf(sinkPoolStatsFmt, queue.Sink, sinkPoolEntries, 0, queue)
+		}
+	}
+
+	var metrics strings.Builder
+
+	_ = stats.WritePrometheus(&metrics)
+	if !strings.Contains(metrics.String(), sinkPoolMetric) {
+		t.Errorf(statsMetricsErrFmt, sinkPoolMetric, metrics.String())
+	}
+}
+
+func TestSinkWorkers_FullQueueDrops(t *testing.T) {
+	t.Parallel()
+
+	slow := newGateWriter()
+
+	var fast syncBuffer
+
+	loggerInstance, _ := newSinkPoolLogger(slow, &fast, 1)
+	defer closeTestLogger(t, loggerInstance)
+
+	for order := range sinkPoolEntries {
+		loggerInstance.Infof(sinkPoolMsg, order)
+	}
+
+	close(slow.open)
+	loggerInstance.Flush()
+
+	queue := loggerInstance.Stats().SinkQueues[0]
+	// With room for one line, at most one line waits and one is being
+	// written; the rest are dropped.
+	if queue.Sink != sinkPoolSlow || queue.Written == 0 || queue.Dropped < sinkPoolEntries-2 {
+		t.Errorf(sinkPoolDropFmt, queue)
+	}
+}
@@ -0,0 +1,102 @@
+package logger
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+const (
+	// SpillDir is the subdirectory of the log directory holding spill files.
+	SpillDir = "spill"
+	// SpillField is the field naming the spill file of a long message,
+	// relative to the log directory.
+	SpillField = "spill"
+	// SpillHashField is the field holding the SHA-256 of a spilled message,
+	// which is also the name of its spill file.
+	SpillHashField = "spill_sha256"
+
+	spillFileSuffix  = ".txt"
+	spillTempPattern = ".spill-*"
+	spillPathFmt     = SpillDir + "/%s" + spillFileSuffix
+	errFmtSpill      = "spill message: %w"
+)
+
+// WithSpillover writes messages longer than the length limit in full to a
+// spill file under the log directory, spill/<sha256>.txt, instead of losing
+// their tail. The entry itself is still truncated and carries the spill
+// file's path and hash in the spill and spill_sha256 fields. Spill files are
+// content-addressed: a message logged again reuses its file. Loggers without
+// a log directory, such as stream loggers, truncate as before, as does a
+// logger failing to write the file, which reports the error by Err.
+func WithSpillover() Option {
+	return func(config *options) {
+		config.spillover = true
+	}
+}
+
+// spill writes the message of logEntry, when it will be truncated, to its
+// spill file and adds the spill fields to the entry. It runs before the entry
+// is written, without mu, so that other logging goroutines do not wait for
+// the disk; the file is named by its content, so no name needs reserving.
+func (c *loggerCore) spill(logEntry *Entry) {
+	if !c.spillover || c.logDir == "" || len(logEntry.Message) <= maxLogMessageLength {
+		return
+	}
+
+	hash, err := writeSpillFile(filepath.Join(c.logDir, SpillDir), logEntry.Message)
+	if err != nil {
+		c.mu.Lock()
+		c.lastErr = fmt.Errorf(errFmtSpill, err)
+		c.mu.Unlock()
+
+		return
+	}
+
+	logEntry.Fields = append(slices.Clip(logEntry.Fields),
+		F(SpillField, fmt.Sprintf(spillPathFmt, hash)),
+		F(SpillHashField, hash),
+	)
+}
+
+// writeSpillFile stores message in spillDir under its SHA-256 and returns
+// the hash. The file is written to a temporary name and renamed, so that a
+// spill file is never seen half written; an existing file is kept.
+func writeSpillFile(spillDir, message string) (string, error) {
+	sum := sha256.Sum256([]byte(message))
+	hash := hex.EncodeToString(sum[:])
+
+	spillPath, err := setupAndValidatePath(spillDir, hash+spillFileSuffix)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = os.Stat(spillPath)
+	if err == nil {
+		return hash, nil
+	}
+
+	temp, err := os.CreateTemp(spillDir, spillTempPattern)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = temp.WriteString(message)
+	err = errors.Join(err, temp.Close())
+
+	if err == nil {
+		err = os.Rename(temp.Name(), spillPath)
+	}
+
+	if err != nil {
+		_ = os.Remove(temp.Name())
+
+		return "", err
+	}
+
+	return hash, nil
+}
@@ -0,0 +1,152 @@
+package logger_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	spillFile        = "app.log"
+	spillLineLength  = 6000
+	spillFiller      = "x"
+	spillFileSuffix  = ".txt"
+	spillShortMsg    = "short message"
+	spillTruncated   = "[TRUNCATED]"
+	spillMissingFmt  = "log lacks %q:\n%s"
+	spillExtraFmt    = "log holds %q:\n%s"
+	spillNewErrFmt   = "New: %v"
+	spillFileErrFmt  = "spill file holds %d bytes; want the full %d"
+	spillCountErrFmt = "spill directory holds %d files; want %d"
+	spillNoDirErrFmt = "spill directory exists after short messages: %v"
+	spillErrFmt      = "Err() = %v; want nil"
+	spillWriters     = 8
+	spillAsyncQueue  = 64
+)
+
+func TestLogger_WithSpillover(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	loggerInstance := newSpillLogger(t, dir)
+	message := strings.Repeat(spillFiller, spillLineLength)
+
+	loggerInstance.Infof("%s", message)
+	loggerInstance.Infof("%s", message)
+
+	if loggerInstance.Err() != nil {
+		t.Errorf(spillErrFmt, loggerInstance.Err())
+	}
+
+	closeOutputsLogger(t, loggerInstance)
+
+	sum := sha256.Sum256([]byte(message))
+	hash := hex.EncodeToString(sum[:])
+	text := readOutputsFile(t, filepath.Join(dir, spillFile))
+
+	for _, want := range []string{
+		spillTruncated,
+		logger.SpillField + "=" + logger.SpillDir + "/" + hash + spillFileSuffix,
+		logger.SpillHashField + "=" + hash,
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf(spillMissingFmt, want, text)
+		}
+	}
+
+	spilled := readOutputsFile(t, filepath.Join(dir, logger.SpillDir, hash+spillFileSuffix))
+	if spilled != message {
+		t.Errorf(spillFileErrFmt, len(spilled), len(message))
+	}
+
+	entries, err := os.ReadDir(filepath.Join(dir, logger.SpillDir))
+	if err != nil || len(entries) != 1 {
+		t.Errorf(spillCountErrFmt, len(entries), 1)
+	}
+}
+
+func TestLogger_WithSpilloverShortMessages(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	loggerInstance := newSpillLogger(t, dir)
+	loggerInstance.Infof(spillShortMsg)
+	closeOutputsLogger(t, loggerInstance)
+
+	_, err := os.Stat(filepath.Join(dir, logger.SpillDir))
+	if !os.IsNotExist(err) {
+		t.Errorf(spillNoDirErrFmt, err)
+	}
+}
+
+func TestLogger_WithSpilloverConcurrent(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	loggerInstance := newSpillLogger(t, dir, logger.WithAsync(spillAsyncQueue))
+
+	var wg sync.WaitGroup
+
+	for writer := range spillWriters {
+		wg.Go(func() {
+			loggerInstance.Infof("%d%s", writer, strings.Repeat(spillFiller, spillLineLength))
+		})
+	}
+
+	wg.Wait()
+	closeOutputsLogger(t, loggerInstance)
+
+	text := readOutputsFile(t, filepath.Join(dir, spillFile))
+
+	for writer := range spillWriters {
+		sum := sha256.Sum256([]byte(strconv.Itoa(writer) + strings.Repeat(spillFiller, spillLineLength)))
+		want := logger.SpillHashField + "=" + hex.EncodeToString(sum[:])
+
+		if !strings.Contains(text, want) {
+			t.Errorf(spillMissingFmt, want, text)
+		}
+	}
+
+	entries, err := os.ReadDir(filepath.Join(dir, logger.SpillDir))
+	if err != nil || len(entries) != spillWriters {
+		t.Errorf(spillCountErrFmt, len(entries), spillWriters)
+	}
+}
+
+func TestLogger_WithSpilloverWithoutLogDir(t *testing.T) {
+	t.Parallel()
+
+	var buf strings.Builder
+
+	loggerInstance := logger.NewStreamLogger(&buf, logger.WithSpillover())
+	loggerInstance.Infof("%s", strings.Repeat(spillFiller, spillLineLength))
+	closeTestLogger(t, loggerInstance)
+
+	if !strings.Contains(buf.String(), spillTruncated) {
+		t.Errorf(spillMissingFmt, spillTruncated, buf.String())
+	}
+
+	if strings.Contains(buf.String(), logger.SpillHashField) {
+		t.Errorf(spillExtraFmt, logger.SpillHashField, buf.String())
+	}
+}
+
+func newSpillLogger(t *testing.T, dir string, opts ...logger.Option) *logger.Logger {
+	t.Helper()
+
+	opts = append([]logger.Option{logger.WithoutStdout(), logger.WithSpillover()}, opts...)
+
+	loggerInstance, err := logger.New(dir, spillFile, opts...)
+	if err != nil {
+		t.Fatalf(spillNewErrFmt, err)
+	}
+
+	return loggerInstance
+}
@@ -0,0 +1,234 @@
+package logger
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// maxFingerprints bounds the memory used by fingerprint counts when
+	// applications log unbounded messages as formats.
+	maxFingerprints   = 1024
+	maxTemplateLength = 200
+	templateDigit     = '#'
+	fingerprintBase   = 16
+
+	metricsContentType     = "text/plain; version=0.0.4; charset=utf-8"
+	headerContentType      = "Content-Type"
+	metricFingerprintHelp  = "# HELP logger_fingerprint_entries_total Entries logged per message fingerprint.\n"
+	metricFingerprintType  = "# TYPE logger_fingerprint_entries_total counter\n"
+	metricFingerprintFmt   = "logger_fingerprint_entries_total{fingerprint=%q,template=%q} %d\n"
+	metricUntrackedHelp    = "# HELP logger_untracked_entries_total Entries beyond the fingerprint limit.\n"
+	metricUntrackedType    = "# TYPE logger_untracked_entries_total counter\n"
+	metricUntrackedFmt     = "logger_untracked_entries_total %d\n"
+	errFmtWriteMetrics     = "write metrics: %w"
+	templateTruncateSuffix = "..."
+)
+
+// Stats is a snapshot of the logger's counters, returned by Logger.Stats.
+type Stats struct {
+	// Fingerprints counts the entries logged per message template, most
+	// frequent first, to find the noisiest log statements.
+	Fingerprints []FingerprintCount
+	// UntrackedEntries counts entries whose template appeared after
+	// Fingerprints reached its limit of 1024 templates.
+	UntrackedEntries uint64
+	// WriteLatency holds a histogram per sink that received entries: SinkStdout,
+	// SinkFile and each route, named after its Name, its Filename or its
+	// position.
+	WriteLatency []LatencyHistogram
+	// SinkQueues describes the route queues of WithSinkWorkers.
+	SinkQueues []SinkQueueStats
+	// Producers counts the entries and bytes logged by every Named
+	// producer, most bytes first.
+	Producers []ProducerStats
+	// Clients counts the lines of the network clients of every
+	// ClientLimiter.
+	Clients []ClientStats
+	// OutOfOrderEntries counts the ingested entries WithBackfill found out
+	// of order.
+	OutOfOrderEntries uint64
+	// Sync describes the fsync scheduler of WithSyncPolicy.
+	Sync SyncStats
+}
+
+// FingerprintCount is the number of entries logged with one message template.
+// The template is the format string with runs of digits replaced by '#', so
+// that messages built without a format still group together; Fingerprint is
+// a stable hash of it.
+type FingerprintCount struct {
+	Fingerprint string
+	Template    string
+	Count       uint64
+}
+
+// fingerprintStats counts entries per template. It has its own lock so that
+// counting does not contend with writing.
+type fingerprintStats struct {
+	counts    map[string]*FingerprintCount
+	mu        sync.Mutex
+	untracked uint64
+}
+
+// Stats returns a snapshot of the logger's counters. The counters are shared
+// by the logger and its child loggers.
+func (l *Logger) Stats() Stats {
+	return Stats{
+		Fingerprints:      l.core.fingerprints.snapshot(),
+		UntrackedEntries:  l.core.fingerprints.untrackedCount(),
+		WriteLatency:      l.core.writeLatencies(),
+		SinkQueues:        l.core.sinks.stats(),
+		Producers:         l.core.producerStats(),
+		Clients:           l.core.clientStats(),
+		OutOfOrderEntries: l.core.outOfOrderCount(),
+		Sync:              l.core.syncStats(),
+	}
+}
+
+// MetricsHandler serves the logger's Stats in the Prometheus text exposition
+// format.
+func (l *Logger) MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		stats := l.Stats()
+
+		w.Header().Set(headerContentType, metricsContentType)
+		_ = stats.WritePrometheus(w)
+	})
+}
+
+// StatsHandler serves the logger's Stats as a JSON object, for tools that
+// read the counters, such as the last entry time of every producer, rather
+// than scrape them.
+func (l *Logger) StatsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		body, err := json.Marshal(l.Stats())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+
+			return
+		}
+
+		w.Header().Set(headerContentType, jsonContentType)
+		_, _ = w.Write(body)
+	})
+}
+
+// WritePrometheus writes stats in the Prometheus text exposition format.
+func (stats *Stats) WritePrometheus(writer io.Writer) error {
+	var builder strings.Builder
+
+	builder.WriteString(metricFingerprintHelp)
+	builder.WriteString(metricFingerprintType)
+
+	for _, fingerprint := range stats.Fingerprints {
+		fmt.Fprintf(&builder, metricFingerprintFmt, fingerprint.Fingerprint, fingerprint.Template, fingerprint.Count)
+	}
+
+	builder.WriteString(metricUntrackedHelp)
+	builder.WriteString(metricUntrackedType)
+	fmt.Fprintf(&builder, metricUntrackedFmt, stats.UntrackedEntries)
+	writeLatencyMetrics(&builder, stats.WriteLatency)
+	writeSinkQueueMetrics(&builder, stats.SinkQueues)
+	writeProducerMetrics(&builder, stats.Producers)
+	writeClientMetrics(&builder, stats.Clients)
+	writeOutOfOrderMetrics(&builder, stats.OutOfOrderEntries)
+	writeSyncMetrics(&builder, &stats.Sync)
+
+	_, err := io.WriteString(writer, builder.String())
+	if err != nil {
+		return fmt.Errorf(errFmtWriteMetrics, err)
+	}
+
+	return nil
+}
+
+// count records an entry logged with format.
+func (fs *fingerprintStats) count(format string) {
+	template := messageTemplate(format)
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	counter, exists := fs.counts[template]
+	if !exists {
+		if len(fs.counts) >= maxFingerprints {
+			fs.untracked++
+
+			return
+		}
+
+		if fs.counts == nil {
+			fs.counts = make(map[string]*FingerprintCount)
+		}
+
+		counter = &FingerprintCount{Fingerprint: fingerprint(template), Template: template, Count: 0}
+		fs.counts[template] = counter
+	}
+
+	counter.Count++
+}
+
+func (fs *fingerprintStats) snapshot() []FingerprintCount {
+	fs.mu.Lock()
+
+	counts := make([]FingerprintCount, 0, len(fs.counts))
+	for _, counter := range fs.counts {
+		counts = append(counts, *counter)
+	}
+
+	fs.mu.Unlock()
+
+	slices.SortFunc(counts, func(a, b FingerprintCount) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Fingerprint, b.Fingerprint))
+	})
+
+	return counts
+}
+
+func (fs *fingerprintStats) untrackedCount() uint64 {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	return fs.untracked
+}
+
+// messageTemplate replaces runs of digits in format with '#' and truncates it.
+func messageTemplate(format string) string {
+	var builder strings.Builder
+
+	inDigits := false
+
+	for _, char := range format {
+		isDigit := char >= '0' && char <= '9'
+		if !isDigit {
+			builder.WriteRune(char)
+		} else if !inDigits {
+			builder.WriteRune(templateDigit)
+		}
+
+		inDigits = isDigit
+	}
+
+	template := builder.String()
+	if len(template) > maxTemplateLength {
+		template = template[:maxTemplateLength] + templateTruncateSuffix
+	}
+
+	return template
+}
+
+// fingerprint returns the FNV-1a hash of template in hex.
+func fingerprint(template string) string {
+	hash := fnv.New64a()
+	_, _ = io.WriteString(hash, template)
+
+	return strconv.FormatUint(hash.Sum64(), fingerprintBase)
+}
@@ -0,0 +1,86 @@
+package logger_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	statsNoisyFmt      = "retrying job %d"
+	statsQuietFmt      = "job %d done"
+	statsDaemonLine    = "worker 17 restarted"
+	statsDaemonLine2   = "worker 4 restarted"
+	statsDaemonTmpl    = "worker # restarted"
+	statsNoisyCount    = 5
+	statsTopErrFmt     = "expected top template %q with count %d, got %+v"
+	statsCountErrFmt   = "expected %d fingerprints, got %+v"
+	statsFingerprint   = "fingerprint of %q changed between loggers"
+	statsMetricsErrFmt = "expected %q in metrics:\n%s"
+	statsMetricsLine   = `template="retrying job %d"} 5`
+	statsMetricsPath   = "/metrics"
+)
+
+func TestStats_CountsPerFingerprint(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	loggerInstance := logger.NewStreamLogger(&buf)
+
+	for job := range statsNoisyCount {
+		loggerInstance.Warnf(statsNoisyFmt, job)
+	}
+
+	loggerInstance.Infof(statsQuietFmt, 1)
+
+	// Messages logged without a format group by their digit-free template.
+	loggerInstance.Logf(logger.LevelInfo, statsDaemonLine)
+	loggerInstance.Logf(logger.LevelInfo, statsDaemonLine2)
+
+	stats := loggerInstance.Stats()
+
+	const want = 3
+	if len(stats.Fingerprints) != want {
+		t.Fatalf(statsCountErrFmt, want, stats.Fingerprints)
+	}
+
+	top := stats.Fingerprints[0]
+	if top.Template != statsNoisyFmt || top.Count != statsNoisyCount {
+		t.Errorf(statsTopErrFmt, statsNoisyFmt, statsNoisyCount, top)
+	}
+
+	if stats.Fingerprints[1].Template != statsDaemonTmpl || stats.Fingerprints[1].Count != 2 {
+		t.Errorf(statsTopErrFmt, statsDaemonTmpl, 2, stats.Fingerprints[1])
+	}
+
+	other := logger.NewStreamLogger(&buf)
+	other.Warnf(statsNoisyFmt, 0)
+
+	if other.Stats().Fingerprints[0].Fingerprint != top.Fingerprint {
+		t.Errorf(statsFingerprint, statsNoisyFmt)
+	}
+}
+
+func TestMetricsHandler(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	loggerInstance := logger.NewStreamLogger(&buf)
+
+	for job := range statsNoisyCount {
+		loggerInstance.Warnf(statsNoisyFmt, job)
+	}
+
+	recorder := httptest.NewRecorder()
+	loggerInstance.MetricsHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, statsMetricsPath, nil))
+
+	if !strings.Contains(recorder.Body.String(), statsMetricsLine) {
+		t.Errorf(statsMetricsErrFmt, statsMetricsLine, recorder.Body.String())
+	}
+}
@@ -0,0 +1,10 @@
+// Package stress holds the concurrency stress tests of the logger. It has no
+// API; the tests run thousands of goroutines against one logger while its
+// files rotate, reopen and close, and are meant to run under the race
+// detector:
+//
+//	go test -race ./stress
+//
+// With -short the tests run fewer goroutines. See the Concurrency section of
+// the README for the guarantees they check.
+package stress
@@ -0,0 +1,354 @@
+package stress_test
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	stressFile        = "stress.log"
+	stressAccessFile  = "access.log"
+	stressJobID       = "stress-job"
+	stressMsgFmt      = "worker %d entry %d"
+	stressLevelSep    = "] "
+	stressWorkers     = 2000
+	stressShortWorker = 200
+	stressEntries     = 20
+	stressAsyncBuffer = 256
+	stressAccessSize  = 64 << 10
+	stressMaxBackups  = 1000
+	stressReopens     = 200
+	stressDeadline    = time.Minute
+	newLoggerErrFmt   = "New: %v"
+	closeErrFmt       = "Close: %v"
+	readErrFmt        = "read %s: %v"
+	parseErrFmt       = "line %q does not hold a worker entry"
+	countErrFmt       = "expected %d entries, got %d"
+	orderErrFmt       = "worker %d: entry %d follows entry %d"
+	lostErrFmt        = "worker %d: acknowledged entry %d is missing"
+	closedErrFmt      = "expected ErrLoggerClosed after Close, got %v"
+	deadlineErrFmt    = "writers still blocked %v after Close"
+	accessCountFmt    = "expected %d access lines across the rotated files, got %d"
+)
+
+// workers returns the number of concurrent writers, fewer with -short.
+func workers() int {
+	if testing.Short() {
+		return stressShortWorker
+	}
+
+	return stressWorkers
+}
+
+func newFileLogger(t *testing.T, opts ...logger.Option) (*logger.Logger, string) {
+	t.Helper()
+
+	logDir := t.TempDir()
+
+	loggerInstance, err := logger.New(logDir, stressFile, append(opts, logger.WithoutStdout())...)
+	if err != nil {
+		t.Fatalf(newLoggerErrFmt, err)
+	}
+
+	return loggerInstance, filepath.Join(logDir, stressFile)
+}
+
+func closeLogger(t *testing.T, loggerInstance *logger.Logger) {
+	t.Helper()
+
+	err := loggerInstance.Close()
+	if err != nil {
+		t.Fatalf(closeErrFmt, err)
+	}
+}
+
+// readEntries returns the entry numbers of each worker in file order.
+func readEntries(t *testing.T, paths ...string) map[int][]int {
+	t.Helper()
+
+	entries := make(map[int][]int)
+
+	for _, path := range paths {
+		// #nosec G304 -- the files are created by the test.
+		file, err := os.Open(path)
+		if err != nil {
+			t.Fatalf(readErrFmt, path, err)
+		}
+
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			_, message, _ := strings.Cut(scanner.Text(), stressLevelSep)
+
+			var worker, entry int
+
+			_, err := fmt.Sscanf(message, stressMsgFmt, &worker, &entry)
+			if err != nil {
+				t.Fatalf(parseErrFmt, scanner.Text())
+			}
+
+			entries[worker] = append(entries[worker], entry)
+		}
+
+		_ = file.Close()
+	}
+
+	return entries
+}
+
+// checkOrdered fails unless every worker's entries appear in the order it
+// logged them.
+func checkOrdered(t *testing.T, entries map[int][]int) int {
+	t.Helper()
+
+	total := 0
+
+	for worker, numbers := range entries {
+		for index := 1; index < len(numbers); index++ {
+			if numbers[index] <= numbers[index-1] {
+				t.Errorf(orderErrFmt, worker, numbers[index], numbers[index-1])
+			}
+		}
+
+		total += len(numbers)
+	}
+
+	return total
+}
+
+// TestStress_ConcurrentWriters checks that no entry of thousands of
+// concurrent writers is lost, torn or reordered within its goroutine, in
+// both the synchronous and the asynchronous mode.
+func TestStress_ConcurrentWriters(t *testing.T) {
+	t.Parallel()
+
+	modes := map[string][]logger.Option{
+		"sync":  nil,
+		"async": {logger.WithAsync(stressAsyncBuffer)},
+	}
+
+	for name, opts := range modes {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			loggerInstance, logPath := newFileLogger(t, opts...)
+
+			var group sync.WaitGroup
+
+			for worker := range workers() {
+				group.Go(func() {
+					for entry := range stressEntries {
+						loggerInstance.Infof(stressMsgFmt, worker, entry)
+					}
+				})
+			}
+
+			group.Wait()
+			closeLogger(t, loggerInstance)
+
+			total := checkOrdered(t, readEntries(t, logPath))
+			if total != workers()*stressEntries {
+				t.Errorf(countErrFmt, workers()*stressEntries, total)
+			}
+		})
+	}
+}
+
+// TestStress_CloseDuringWrites closes the logger while writers are logging
+// and checks that every writer returns, that entries acknowledged before
+// Close are in the file, and that later ones fail with ErrLoggerClosed.
+func TestStress_CloseDuringWrites(t *testing.T) {
+	t.Parallel()
+
+	modes := map[string][]logger.Option{
+		"sync":  nil,
+		"async": {logger.WithAsync(stressAsyncBuffer)},
+	}
+
+	for name, opts := range modes {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			loggerInstance, logPath := newFileLogger(t, opts...)
+
+			var (
+				group    sync.WaitGroup
+				started  sync.WaitGroup
+				acked    sync.Map // worker -> number of acknowledged entries
+				closeErr atomic.Value
+			)
+
+			started.Add(workers())
+
+			for worker := range workers() {
+				group.Go(func() {
+					started.Done()
+
+					for entry := 0; ; entry++ {
+						err := loggerInstance.Securityf(stressMsgFmt, worker, entry)
+						if err != nil {
+							closeErr.CompareAndSwap(nil, err)
+							acked.Store(worker, entry)
+
+							return
+						}
+					}
+				})
+			}
+
+			started.Wait()
+			closeLogger(t, loggerInstance)
+			waitWithDeadline(t, &group)
+
+			err, _ := closeErr.Load().(error)
+			if !errors.Is(err, logger.ErrLoggerClosed) {
+				t.Errorf(closedErrFmt, err)
+			}
+
+			entries := readEntries(t, logPath)
+			checkOrdered(t, entries)
+
+			acked.Range(func(key, value any) bool {
+				worker, count := key.(int), value.(int)
+				if len(entries[worker]) < count {
+					t.Errorf(lostErrFmt, worker, len(entries[worker]))
+				}
+
+				return true
+			})
+		})
+	}
+}
+
+// TestStress_RotationDuringWrites serves requests concurrently while the
+// access log rotates every few kilobytes and checks that every request has
+// exactly one line across the rotated files.
+func TestStress_RotationDuringWrites(t *testing.T) {
+	t.Parallel()
+
+	loggerInstance, logPath := newFileLogger(t, logger.WithAccessLog(logger.AccessLog{
+		Writer:     nil,
+		Filename:   stressAccessFile,
+		MaxSize:    stressAccessSize,
+		MaxBackups: stressMaxBackups,
+		Format:     logger.AccessLogCommon,
+	}))
+
+	handler := loggerInstance.Middleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	var group sync.WaitGroup
+
+	for range workers() {
+		group.Go(func() {
+			for range stressEntries {
+				handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+			}
+		})
+	}
+
+	group.Wait()
+	closeLogger(t, loggerInstance)
+
+	paths, _ := filepath.Glob(filepath.Join(filepath.Dir(logPath), stressAccessFile+"*"))
+	lines := 0
+
+	for _, path := range paths {
+		// #nosec G304 -- the files are created by the test.
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf(readErrFmt, path, err)
+		}
+
+		lines += strings.Count(string(content), "\n")
+	}
+
+	if lines != workers()*stressEntries {
+		t.Errorf(accessCountFmt, workers()*stressEntries, lines)
+	}
+}
+
+// TestStress_ReopenDuringWrites opens and closes a job file repeatedly while
+// writers log to the job, and checks that the main log file still receives
+// every entry in order, with routes written inline and by sink workers.
+func TestStress_ReopenDuringWrites(t *testing.T) {
+	t.Parallel()
+
+	// The queue holds every entry, so that none is dropped.
+	modes := map[string][]logger.Option{
+		"inline":       nil,
+		"sink workers": {logger.WithSinkWorkers(workers() * stressEntries)},
+	}
+
+	for name, opts := range modes {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			loggerInstance, logPath := newFileLogger(t, opts...)
+
+			var group sync.WaitGroup
+
+			done := make(chan struct{})
+
+			group.Go(func() {
+				for range stressReopens {
+					loggerInstance.ForJob(stressJobID)
+
+					_ = loggerInstance.EndJob(stressJobID)
+				}
+
+				close(done)
+			})
+
+			jobLogger := loggerInstance.ForJob(stressJobID)
+
+			for worker := range workers() {
+				group.Go(func() {
+					for entry := range stressEntries {
+						jobLogger.Infof(stressMsgFmt, worker, entry)
+					}
+				})
+			}
+
+			group.Wait()
+			<-done
+			closeLogger(t, loggerInstance)
+
+			total := checkOrdered(t, readEntries(t, logPath))
+			if total != workers()*stressEntries {
+				t.Errorf(countErrFmt, workers()*stressEntries, total)
+			}
+		})
+	}
+}
+
+// waitWithDeadline fails the test when group does not finish in time, which
+// points to writers blocked forever by Close.
+func waitWithDeadline(t *testing.T, group *sync.WaitGroup) {
+	t.Helper()
+
+	finished := make(chan struct{})
+
+	go func() {
+		group.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+	case <-time.After(stressDeadline):
+		t.Fatalf(deadlineErrFmt, stressDeadline)
+	}
+}
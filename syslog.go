@@ -0,0 +1,163 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// SyslogSDID is the SD-ID of the STRUCTURED-DATA element carrying entry
+	// fields in syslog output. 32473 is the private enterprise number
+	// reserved for documentation by RFC 5612.
+	SyslogSDID = "fields@32473"
+	// SyslogFacility is the facility of syslog output: user-level messages.
+	SyslogFacility = 1
+
+	syslogVersion     = "1"
+	syslogNil         = "-"
+	syslogSep         = " "
+	syslogPriOpen     = "<"
+	syslogPriClose    = ">"
+	syslogSDOpen      = "["
+	syslogSDClose     = "]"
+	syslogParamOpen   = `="`
+	syslogParamClose  = `"`
+	syslogTimeFormat  = "2006-01-02T15:04:05.000000Z07:00"
+	syslogFacilityMul = 8
+	syslogMaxName     = 32
+	syslogMaxHost     = 255
+	syslogMaxAppName  = 48
+	syslogSchemaDesc  = "RFC 5424 syslog line; the pattern matches its header."
+	syslogSchemaRegex = `^<\d{1,3}>1 `
+)
+
+// RFC 5424 severities.
+const (
+	syslogCritical = 2
+	syslogError    = 3
+	syslogWarning  = 4
+	syslogInfo     = 6
+	syslogDebug    = 7
+)
+
+var (
+	// syslogValueEscaper escapes PARAM-VALUE characters.
+	syslogValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+	// syslogOrigin returns the HOSTNAME, APP-NAME and PROCID header fields,
+	// which do not change while the process runs.
+	syslogOrigin = sync.OnceValue(func() string {
+		hostname, _ := os.Hostname()
+
+		return syslogHeaderName(hostname, syslogMaxHost) + syslogSep +
+			syslogHeaderName(filepath.Base(os.Args[0]), syslogMaxAppName) + syslogSep +
+			strconv.Itoa(os.Getpid())
+	})
+)
+
+// encodeSyslog renders logEntry as an RFC 5424 message:
+//
+//	<PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID [fields@32473 key="value"...] MSG
+//
+// The MSGID is the message fingerprint reported by Stats, and fields are
+// parameters of one STRUCTURED-DATA element rather than part of the
+// message, so that syslog pipelines can still read them.
+func encodeSyslog(logEntry *Entry) string {
+	var builder strings.Builder
+
+	builder.WriteString(syslogPriOpen)
+	builder.WriteString(strconv.Itoa(SyslogFacility*syslogFacilityMul + syslogSeverity(logEntry.Level)))
+	builder.WriteString(syslogPriClose + syslogVersion + syslogSep)
+	builder.WriteString(logEntry.Time.Format(syslogTimeFormat))
+	builder.WriteString(syslogSep)
+	builder.WriteString(syslogOrigin())
+	builder.WriteString(syslogSep)
+	builder.WriteString(fingerprint(messageTemplate(logEntry.Message)))
+	builder.WriteString(syslogSep)
+	writeStructuredData(&builder, logEntry.Fields)
+
+	if logEntry.Message != "" {
+		builder.WriteString(syslogSep)
+		builder.WriteString(logEntry.Message)
+	}
+
+	return builder.String()
+}
+
+// writeStructuredData writes fields as one SD-ELEMENT, or the nil value
+// when there are none.
+func writeStructuredData(builder *strings.Builder, fields []Field) {
+	if len(fields) == 0 {
+		builder.WriteString(syslogNil)
+
+		return
+	}
+
+	builder.WriteString(syslogSDOpen + SyslogSDID)
+
+	for _, field := range fields {
+		builder.WriteString(syslogSep)
+		builder.WriteString(syslogParamName(field.Key))
+		builder.WriteString(syslogParamOpen)
+		builder.WriteString(syslogValueEscaper.Replace(formatFieldText(field.Value)))
+		builder.WriteString(syslogParamClose)
+	}
+
+	builder.WriteString(syslogSDClose)
+}
+
+// syslogSeverity maps level to an RFC 5424 severity: DEBUG is debug, INFO
+// and SUCCESS informational, WARN and SYSTEM warning, ERROR error, and
+// SECURITY, PANIC and FATAL critical.
+func syslogSeverity(level Level) int {
+	switch {
+	case level < LevelInfo:
+		return syslogDebug
+	case level < LevelWarn:
+		return syslogInfo
+	case level < LevelError:
+		return syslogWarning
+	case level < LevelSecurity:
+		return syslogError
+	default:
+		return syslogCritical
+	}
+}
+
+// syslogParamName returns key as a PARAM-NAME: at most 32 printable ASCII
+// characters other than '=', ' ', ']' and '"', which are replaced by '_'.
+func syslogParamName(key string) string {
+	name := strings.Map(func(char rune) rune {
+		if char <= ' ' || char > '~' || strings.ContainsRune(`="]`, char) {
+			return '_'
+		}
+
+		return char
+	}, key)
+
+	if name == "" {
+		return syslogNil
+	}
+
+	return name[:min(len(name), syslogMaxName)]
+}
+
+// syslogHeaderName returns name as a header field of at most limit printable
+// ASCII characters, or the nil value when empty.
+func syslogHeaderName(name string, limit int) string {
+	name = strings.Map(func(char rune) rune {
+		if char <= ' ' || char > '~' {
+			return '_'
+		}
+
+		return char
+	}, name)
+
+	if name == "" {
+		return syslogNil
+	}
+
+	return name[:min(len(name), limit)]
+}
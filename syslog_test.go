@@ -0,0 +1,60 @@
+package logger_test
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	syslogMsg        = "upload finished"
+	syslogPathKey    = "path"
+	syslogPathValue  = `C:\docs\"q]"`
+	syslogBadKey     = "size bytes"
+	syslogLineRegex  = `^<11>1 \d{4}-\d\d-\d\dT\d\d:\d\d:\d\d\.\d{6}(Z|[+-]\d\d:\d\d) \S+ \S+ \d+ [0-9a-f]{16} `
+	syslogExpectedSD = `[fields@32473 path="C:\\docs\\\"q\]\"" size_bytes="42"] ` + syslogMsg
+	syslogLineFmt    = "expected an RFC 5424 header, got %q"
+	syslogSuffixFmt  = "expected line ending with %q, got %q"
+	syslogNoSDSuffix = " - " + syslogMsg
+)
+
+func syslogLine(t *testing.T, log func(*logger.Logger)) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	log(logger.NewStreamLogger(&buf, logger.WithEncoding(logger.EncodingSyslog)))
+
+	return strings.TrimSpace(buf.String())
+}
+
+func TestEncoding_SyslogStructuredData(t *testing.T) {
+	t.Parallel()
+
+	line := syslogLine(t, func(loggerInstance *logger.Logger) {
+		loggerInstance.With(logger.F(syslogPathKey, syslogPathValue), logger.F(syslogBadKey, 42)).Errorf(syslogMsg)
+	})
+
+	if !regexp.MustCompile(syslogLineRegex).MatchString(line) {
+		t.Errorf(syslogLineFmt, line)
+	}
+
+	if !strings.HasSuffix(line, syslogExpectedSD) {
+		t.Errorf(syslogSuffixFmt, syslogExpectedSD, line)
+	}
+}
+
+func TestEncoding_SyslogWithoutFields(t *testing.T) {
+	t.Parallel()
+
+	line := syslogLine(t, func(loggerInstance *logger.Logger) {
+		loggerInstance.Infof(syslogMsg)
+	})
+
+	if !strings.HasPrefix(line, "<14>1 ") || !strings.HasSuffix(line, syslogNoSDSuffix) {
+		t.Errorf(syslogSuffixFmt, syslogNoSDSuffix, line)
+	}
+}
@@ -0,0 +1,116 @@
+package logger
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	// SuppressedField carries the number of occurrences an EveryN logger
+	// suppressed at a call site since its previous entry.
+	SuppressedField = "suppressed"
+
+	// callSiteSkip is the number of stack frames between runtime.Callers in
+	// Throttled.logf and the user's call site.
+	callSiteSkip = 3
+	onceEvery    = 0
+)
+
+// Throttled logs only some of the calls made at each call site, so that hot
+// loops can log without flooding the outputs. It is returned by Once and
+// EveryN; the logging methods mirror those of Logger.
+type Throttled struct {
+	logger *Logger
+	every  uint64
+}
+
+// callSiteKey identifies a call site and throttling mode.
+type callSiteKey struct {
+	pc    uintptr
+	every uint64
+}
+
+// callSites counts the calls made at every throttled call site. It is shared
+// by a logger and its child loggers.
+type callSites struct {
+	counts sync.Map // callSiteKey -> *atomic.Uint64
+}
+
+// Once returns a logger that writes only the first entry logged at each call
+// site, for warnings that are worth reporting once per process.
+func (l *Logger) Once() *Throttled {
+	return &Throttled{logger: l, every: onceEvery}
+}
+
+// EveryN returns a logger that writes the first entry logged at each call
+// site and then every nth, adding SuppressedField with the number of calls
+// skipped in between. Values of n below 2 log every call.
+func (l *Logger) EveryN(n int) *Throttled {
+	return &Throttled{logger: l, every: uint64(max(n, 1))}
+}
+
+// Infof is like Logger.Infof, subject to throttling.
+func (t *Throttled) Infof(format string, args ...any) {
+	t.logf(LevelInfo, format, args...)
+}
+
+// Warnf is like Logger.Warnf, subject to throttling.
+func (t *Throttled) Warnf(format string, args ...any) {
+	t.logf(LevelWarn, format, args...)
+}
+
+// Errorf is like Logger.Errorf, subject to throttling.
+func (t *Throttled) Errorf(format string, args ...any) {
+	t.logf(LevelError, format, args...)
+}
+
+// Successf is like Logger.Successf, subject to throttling.
+func (t *Throttled) Successf(format string, args ...any) {
+	t.logf(LevelSuccess, format, args...)
+}
+
+// Systemf is like Logger.Systemf, subject to throttling.
+func (t *Throttled) Systemf(format string, args ...any) {
+	t.logf(LevelSystem, format, args...)
+}
+
+// Debugf is like Logger.Debugf, subject to throttling.
+func (t *Throttled) Debugf(format string, args ...any) {
+	t.logf(LevelDebug, format, args...)
+}
+
+func (t *Throttled) logf(level Level, format string, args ...any) {
+	var pcs [1]uintptr
+
+	runtime.Callers(callSiteSkip, pcs[:])
+
+	count := t.logger.core.callSites.next(callSiteKey{pc: pcs[0], every: t.every})
+
+	var fields []Field
+
+	switch {
+	case t.every == onceEvery:
+		if count > 1 {
+			return
+		}
+	case (count-1)%t.every != 0:
+		return
+	case count > 1 && t.every > 1:
+		fields = []Field{F(SuppressedField, t.every-1)}
+	}
+
+	t.logger.writeEntryf(level, fields, t.logger.resolveCaller(), targetAll, format, args...)
+}
+
+// next counts a call at key and returns the number of calls so far.
+func (sites *callSites) next(key callSiteKey) uint64 {
+	counter, ok := sites.counts.Load(key)
+	if !ok {
+		counter, _ = sites.counts.LoadOrStore(key, new(atomic.Uint64))
+	}
+
+	count, _ := counter.(*atomic.Uint64)
+
+	return count.Add(1)
+}
@@ -0,0 +1,73 @@
+package logger_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	throttleMsg        = "cache miss"
+	throttleCalls      = 10
+	throttleEvery      = 4
+	throttleSuppressed = "suppressed=3"
+	throttleCountFmt   = "expected %d entries, got %d: %q"
+	throttleFieldFmt   = "entry %d: expected %q: %q"
+	throttleNoFieldFmt = "first entry should not carry %q: %q"
+)
+
+func TestOnce_LogsFirstCallPerSite(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	loggerInstance := logger.NewStreamLogger(&buf)
+
+	for range throttleCalls {
+		loggerInstance.Once().Warnf(throttleMsg)
+	}
+
+	// A different call site has its own state.
+	loggerInstance.Once().Warnf(throttleMsg)
+
+	lines := throttleLines(&buf)
+	if len(lines) != 2 {
+		t.Fatalf(throttleCountFmt, 2, len(lines), lines)
+	}
+}
+
+func TestEveryN_ReportsSuppressedCount(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	loggerInstance := logger.NewStreamLogger(&buf)
+
+	for range throttleCalls {
+		loggerInstance.EveryN(throttleEvery).Infof(throttleMsg)
+	}
+
+	lines := throttleLines(&buf)
+
+	// Calls 1, 5 and 9 are logged.
+	const want = 3
+	if len(lines) != want {
+		t.Fatalf(throttleCountFmt, want, len(lines), lines)
+	}
+
+	if strings.Contains(lines[0], logger.SuppressedField) {
+		t.Errorf(throttleNoFieldFmt, logger.SuppressedField, lines[0])
+	}
+
+	for index, line := range lines[1:] {
+		if !strings.Contains(line, throttleSuppressed) {
+			t.Errorf(throttleFieldFmt, index+1, throttleSuppressed, line)
+		}
+	}
+}
+
+func throttleLines(buf *bytes.Buffer) []string {
+	return strings.Split(strings.TrimSpace(buf.String()), "\n")
+}
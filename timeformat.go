@@ -0,0 +1,124 @@
+package logger
+
+import (
+	"strconv"
+	"time"
+)
+
+// TimeFormat selects how {time} renders in text lines. Epoch formats also add
+// the Unix time as a number to JSON lines, so that analytics tools can sort
+// and join entries without parsing timestamps.
+type TimeFormat int
+
+// Supported time formats.
+const (
+	// TimeFormatDefault renders 2006/01/02 15:04:05.
+	TimeFormatDefault TimeFormat = iota
+	// TimeFormatNano renders 2006/01/02 15:04:05.000000000.
+	TimeFormatNano
+	// TimeFormatRFC3339Nano renders RFC 3339 with nanoseconds.
+	TimeFormatRFC3339Nano
+	// TimeFormatEpochSeconds renders Unix time in seconds.
+	TimeFormatEpochSeconds
+	// TimeFormatEpochMillis renders Unix time in milliseconds.
+	TimeFormatEpochMillis
+	// TimeFormatEpochNanos renders Unix time in nanoseconds.
+	TimeFormatEpochNanos
+)
+
+const (
+	timeFormatDefaultName = "default"
+	timeFormatNanoName    = "nano"
+	timeFormatRFC3339Name = "rfc3339nano"
+	timeFormatSecondsName = "epoch_seconds"
+	timeFormatMillisName  = "epoch_millis"
+	timeFormatNanosName   = "epoch_nanos"
+	layoutTimeFormatNano  = "2006/01/02 15:04:05.000000000"
+	epochUnitSeconds      = "s"
+	epochUnitMillis       = "ms"
+	epochUnitNanos        = "ns"
+	epochBase             = 10
+
+	layoutPatternTimeNano    = `\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2}\.\d{9}`
+	layoutPatternTimeRFC3339 = `\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})`
+	layoutPatternTimeEpoch   = `-?\d+`
+)
+
+var timeFormatNames = map[TimeFormat]string{
+	TimeFormatDefault:      timeFormatDefaultName,
+	TimeFormatNano:         timeFormatNanoName,
+	TimeFormatRFC3339Nano:  timeFormatRFC3339Name,
+	TimeFormatEpochSeconds: timeFormatSecondsName,
+	TimeFormatEpochMillis:  timeFormatMillisName,
+	TimeFormatEpochNanos:   timeFormatNanosName,
+}
+
+// WithTimeFormat selects the time format of every output's {time} token and
+// of the epoch in JSON lines.
+func WithTimeFormat(format TimeFormat) Option {
+	return func(config *options) {
+		config.timeFormat = format
+	}
+}
+
+// String returns the name of format as accepted by ParseTimeFormat.
+func (format TimeFormat) String() string {
+	return timeFormatNames[format]
+}
+
+// ParseTimeFormat returns the TimeFormat named default, nano, rfc3339nano,
+// epoch_seconds, epoch_millis or epoch_nanos.
+func ParseTimeFormat(name string) (TimeFormat, bool) {
+	for format, formatName := range timeFormatNames {
+		if formatName == name {
+			return format, true
+		}
+	}
+
+	return TimeFormatDefault, false
+}
+
+// format renders moment in the format.
+func (format TimeFormat) format(moment time.Time) string {
+	switch format {
+	case TimeFormatNano:
+		return moment.Format(layoutTimeFormatNano)
+	case TimeFormatRFC3339Nano:
+		return moment.Format(time.RFC3339Nano)
+	case TimeFormatEpochSeconds, TimeFormatEpochMillis, TimeFormatEpochNanos:
+		epoch, _ := format.epoch(moment)
+
+		return strconv.FormatInt(epoch, epochBase)
+	default:
+		return moment.Format(layoutTimeFormat)
+	}
+}
+
+// epoch returns moment as Unix time and its unit, or an empty unit for
+// formats that are not epoch formats.
+func (format TimeFormat) epoch(moment time.Time) (int64, string) {
+	switch format {
+	case TimeFormatEpochSeconds:
+		return moment.Unix(), epochUnitSeconds
+	case TimeFormatEpochMillis:
+		return moment.UnixMilli(), epochUnitMillis
+	case TimeFormatEpochNanos:
+		return moment.UnixNano(), epochUnitNanos
+	default:
+		return 0, ""
+	}
+}
+
+// pattern returns a regular expression matching times rendered in the format.
+func (format TimeFormat) pattern() string {
+	switch format {
+	case TimeFormatNano:
+		return layoutPatternTimeNano
+	case TimeFormatRFC3339Nano:
+		return layoutPatternTimeRFC3339
+	case TimeFormatEpochSeconds, TimeFormatEpochMillis, TimeFormatEpochNanos:
+		return layoutPatternTimeEpoch
+	default:
+		return layoutPatternTime
+	}
+}
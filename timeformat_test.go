@@ -0,0 +1,75 @@
+package logger_test
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	timeFormatTemplate  = "{time} {msg}"
+	timeFormatMsg       = "sorted"
+	timeFormatOutputFmt = "%v: expected a line matching %q, got %q"
+	timeFormatEpochFmt  = "expected epoch %d %s near %v, got %d %q"
+	timeFormatParseFmt  = "ParseTimeFormat(%q) = %v, %v"
+)
+
+func TestTimeFormat_TextPresets(t *testing.T) {
+	t.Parallel()
+
+	cases := map[logger.TimeFormat]string{
+		logger.TimeFormatDefault:      `^\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2} sorted\n$`,
+		logger.TimeFormatNano:         `^\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2}\.\d{9} sorted\n$`,
+		logger.TimeFormatRFC3339Nano:  `^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}\.\d+(Z|[+-]\d{2}:\d{2}) sorted\n$`,
+		logger.TimeFormatEpochSeconds: `^\d{10} sorted\n$`,
+		logger.TimeFormatEpochMillis:  `^\d{13} sorted\n$`,
+		logger.TimeFormatEpochNanos:   `^\d{19} sorted\n$`,
+	}
+
+	for format, pattern := range cases {
+		var buf bytes.Buffer
+
+		loggerInstance := logger.NewStreamLogger(&buf,
+			logger.WithLayout(logger.MustParseLayout(timeFormatTemplate)), logger.WithTimeFormat(format))
+		loggerInstance.Infof(timeFormatMsg)
+
+		if !regexp.MustCompile(pattern).MatchString(buf.String()) {
+			t.Errorf(timeFormatOutputFmt, format, pattern, buf.String())
+		}
+	}
+}
+
+func TestTimeFormat_EpochInJSON(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	before := time.Now()
+	loggerInstance := logger.NewStreamLogger(&buf,
+		logger.WithEncoding(logger.EncodingJSON), logger.WithTimeFormat(logger.TimeFormatEpochMillis))
+	loggerInstance.Infof(timeFormatMsg)
+
+	jsonEntry := decodeJSONEntry(t, strings.TrimSpace(buf.String()))
+	if jsonEntry.EpochUnit != "ms" || jsonEntry.Epoch != jsonEntry.Timestamp.UnixMilli() ||
+		jsonEntry.Epoch < before.UnixMilli() {
+		t.Errorf(timeFormatEpochFmt, jsonEntry.Timestamp.UnixMilli(), "ms", before, jsonEntry.Epoch, jsonEntry.EpochUnit)
+	}
+}
+
+func TestParseTimeFormat_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, format := range []logger.TimeFormat{
+		logger.TimeFormatDefault, logger.TimeFormatNano, logger.TimeFormatRFC3339Nano,
+		logger.TimeFormatEpochSeconds, logger.TimeFormatEpochMillis, logger.TimeFormatEpochNanos,
+	} {
+		parsed, ok := logger.ParseTimeFormat(format.String())
+		if !ok || parsed != format {
+			t.Errorf(timeFormatParseFmt, format.String(), parsed, ok)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package logger
+
+import "time"
+
+// WithTimezone renders the timestamps of the log file, stdout and routes
+// without their own Timezone in location, for example time.UTC for files
+// read by machines. Without it, entries keep the local time of time.Now.
+func WithTimezone(location *time.Location) Option {
+	return func(config *options) {
+		config.timezone = location
+	}
+}
+
+// WithStdoutTimezone renders stdout timestamps in location instead of the
+// logger's timezone, so that operators can read local time on the terminal
+// while the log file is written in UTC.
+func WithStdoutTimezone(location *time.Location) Option {
+	return func(config *options) {
+		config.stdoutTimezone = location
+	}
+}
+
+// inTimezone returns logEntry with its time in location, or logEntry itself
+// when location is nil or already the entry's. The copy shares the fields
+// but not the cached time text.
+func inTimezone(logEntry *Entry, location *time.Location) *Entry {
+	if location == nil || logEntry.Time.Location() == location {
+		return logEntry
+	}
+
+	localized := *logEntry
+	localized.Time = localized.Time.In(location)
+	localized.timeText = ""
+
+	return &localized
+}
@@ -0,0 +1,54 @@
+package logger_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	timezoneMsg       = "tick"
+	timezoneStdout    = "+05:30"
+	timezoneRoute     = "-03:00"
+	timezoneUTC       = "Z"
+	timezoneOffsetSec = 5*3600 + 30*60
+	timezoneSuffixFmt = "%s: expected a timestamp ending in %q, got %q"
+)
+
+func TestTimezone_PerSink(t *testing.T) {
+	t.Parallel()
+
+	var stdout, utc, local bytes.Buffer
+
+	loggerInstance := logger.NewStreamLogger(&stdout,
+		logger.WithEncoding(logger.EncodingJSON),
+		logger.WithTimezone(time.UTC),
+		logger.WithStdoutTimezone(time.FixedZone("IST", timezoneOffsetSec)),
+		logger.WithRoute(logger.Route{Name: "utc", Writer: &utc, Encoding: logger.EncodingJSON}),
+		logger.WithRoute(logger.Route{
+			Name:     "local",
+			Writer:   &local,
+			Encoding: logger.EncodingJSON,
+			Timezone: time.FixedZone("BRT", -3*3600),
+		}),
+	)
+	loggerInstance.Infof(timezoneMsg)
+
+	cases := map[string][2]string{
+		"stdout": {stdout.String(), timezoneStdout},
+		"utc":    {utc.String(), timezoneUTC},
+		"local":  {local.String(), timezoneRoute},
+	}
+
+	for name, test := range cases {
+		jsonEntry := decodeJSONEntry(t, strings.TrimSpace(test[0]))
+
+		timestamp := jsonEntry.Timestamp.Format(time.RFC3339Nano)
+		if !strings.HasSuffix(timestamp, test[1]) {
+			t.Errorf(timezoneSuffixFmt, name, test[1], timestamp)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package logger
+
+import (
+	"os"
+	"unsafe"
+)
+
+// lineEndVector ends the vectored lines that lack a line end. It is only read.
+var lineEndVector = []byte{lineEnd}
+
+// vectorFile writes the batches of a lineWriter to a log file with one
+// vectored write, within the budget of an optional DiskLimiter.
+type vectorFile struct {
+	file    *os.File
+	limiter *DiskLimiter
+	name    string
+}
+
+// WithVectoredWrites writes the batches of WithCoalescing to the log file with
+// vectored writes: on Linux, one writev system call of up to 1024 lines hands
+// the kernel the lines where they are instead of copying them into one buffer
+// first. It is meant for loggers writing more than 100k entries per second
+// where that copy shows up in profiles; compare BenchmarkFile_Vectored with
+// BenchmarkFile_Coalesced before enabling it. Other platforms, stdout and
+// entries written on their own keep the buffered write. The log file is
+// opened for appending, so each batch still lands at the end of the file.
+func WithVectoredWrites() Option {
+	return func(config *options) {
+		config.vectoredWrites = true
+	}
+}
+
+// newFileLineWriter returns the lineWriter of the log file f at path,
+// throttled by limiter and vectoring its batches when vectored is set.
+func newFileLineWriter(f *os.File, path string, limiter *DiskLimiter, vectored bool) *lineWriter {
+	writer := newLineWriter(limiter.limit(f, path))
+	if vectored {
+		writer.vectors = &vectorFile{file: f, limiter: limiter, name: path}
+	}
+
+	return writer
+}
+
+// bufferVector keeps line for the next vectored write without copying it:
+// strings are immutable and the write only reads them.
+func (lw *lineWriter) bufferVector(line string) {
+	if len(line) > 0 {
+		lw.lines = append(lw.lines, unsafe.Slice(unsafe.StringData(line), len(line)))
+	}
+
+	if len(line) == 0 || line[len(line)-1] != lineEnd {
+		lw.lines = append(lw.lines, lineEndVector)
+	}
+}
+
+// flushVectors writes the lines kept by bufferVector.
+func (lw *lineWriter) flushVectors() error {
+	err := lw.vectors.write(lw.lines)
+	clear(lw.lines)
+	lw.lines = lw.lines[:0]
+
+	return err
+}
+
+// write waits for the budget of lines and writes them.
+func (vf *vectorFile) write(lines [][]byte) error {
+	if vf.limiter != nil {
+		size := 0
+		for _, line := range lines {
+			size += len(line)
+		}
+
+		vf.limiter.wait(vf.name, size)
+	}
+
+	return writeVectors(vf.file, lines)
+}
@@ -0,0 +1,96 @@
+//go:build linux
+
+package logger
+
+import (
+	"io"
+	"os"
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	// maxIovecs is IOV_MAX, the most vectors one writev accepts.
+	maxIovecs = 1024
+	opWritev  = "writev"
+)
+
+// writeVectors writes vectors to file with writev, at most maxIovecs per
+// call, and writes the rest of a short write with further calls.
+func writeVectors(file *os.File, vectors [][]byte) error {
+	conn, err := file.SyscallConn()
+	if err != nil {
+		return &os.PathError{Op: opWritev, Path: file.Name(), Err: err}
+	}
+
+	iovecs := make([]syscall.Iovec, 0, min(len(vectors), maxIovecs))
+
+	for len(vectors) > 0 {
+		iovecs = iovecs[:0]
+		for _, vector := range vectors[:min(len(vectors), maxIovecs)] {
+			iovec := syscall.Iovec{Base: &vector[0], Len: 0}
+			iovec.SetLen(len(vector))
+			iovecs = append(iovecs, iovec)
+		}
+
+		written, err := writev(conn, iovecs)
+		runtime.KeepAlive(vectors)
+
+		if err != nil {
+			return &os.PathError{Op: opWritev, Path: file.Name(), Err: err}
+		}
+
+		if written == 0 {
+			return &os.PathError{Op: opWritev, Path: file.Name(), Err: io.ErrShortWrite}
+		}
+
+		vectors = advanceVectors(vectors, written)
+	}
+
+	return nil
+}
+
+// writev makes one writev system call, repeating it when interrupted.
+func writev(conn syscall.RawConn, iovecs []syscall.Iovec) (int, error) {
+	var (
+		written uintptr
+		errno   syscall.Errno
+	)
+
+	for {
+		err := conn.Write(func(fd uintptr) bool {
+			written, _, errno = syscall.Syscall(syscall.SYS_WRITEV, fd,
+				uintptr(unsafe.Pointer(&iovecs[0])), uintptr(len(iovecs)))
+
+			return true
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		if errno != syscall.EINTR {
+			break
+		}
+	}
+
+	if errno != 0 {
+		return 0, errno
+	}
+
+	return int(written), nil
+}
+
+// advanceVectors drops the first written bytes of vectors.
+func advanceVectors(vectors [][]byte, written int) [][]byte {
+	for written > 0 && written >= len(vectors[0]) {
+		written -= len(vectors[0])
+		vectors = vectors[1:]
+	}
+
+	if written > 0 {
+		vectors[0] = vectors[0][written:]
+	}
+
+	return vectors
+}
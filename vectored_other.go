@@ -0,0 +1,16 @@
+//go:build !linux
+
+package logger
+
+import (
+	"os"
+	"slices"
+)
+
+// writeVectors writes vectors to file with one Write where writev is not
+// used.
+func writeVectors(file *os.File, vectors [][]byte) error {
+	_, err := file.Write(slices.Concat(vectors...))
+
+	return err
+}
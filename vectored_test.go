@@ -0,0 +1,77 @@
+package logger_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	vectoredFile       = "vectored.log"
+	vectoredGoroutines = 8
+	vectoredLines      = 2000
+	vectoredQueue      = 4096
+	vectoredLayout     = "{msg}"
+	vectoredLineFmt    = "worker %d line %d"
+	vectoredCountFmt   = "log file holds %d lines; want %d"
+	vectoredMissingFmt = "log file lacks %q"
+)
+
+func TestWithVectoredWrites_WritesEveryLine(t *testing.T) {
+	t.Parallel()
+
+	logDir := t.TempDir()
+
+	loggerInstance, err := logger.New(logDir, vectoredFile,
+		logger.WithoutStdout(),
+		logger.WithLayout(logger.MustParseLayout(vectoredLayout)),
+		logger.WithAsync(vectoredQueue),
+		logger.WithCoalescing(vectoredQueue),
+		logger.WithVectoredWrites(),
+	)
+	if err != nil {
+		t.Fatalf(newLoggerError, err)
+	}
+
+	var wg sync.WaitGroup
+
+	for worker := range vectoredGoroutines {
+		wg.Go(func() {
+			for line := range vectoredLines {
+				loggerInstance.Infof(vectoredLineFmt, worker, line)
+			}
+		})
+	}
+
+	wg.Wait()
+	closeTestLogger(t, loggerInstance)
+
+	content, err := os.ReadFile(filepath.Join(logDir, vectoredFile))
+	if err != nil {
+		t.Fatalf(readLogFileErr, err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	if len(lines) != vectoredGoroutines*vectoredLines {
+		t.Fatalf(vectoredCountFmt, len(lines), vectoredGoroutines*vectoredLines)
+	}
+
+	written := make(map[string]bool, len(lines))
+	for _, line := range lines {
+		written[line] = true
+	}
+
+	for worker := range vectoredGoroutines {
+		for line := range vectoredLines {
+			want := fmt.Sprintf(vectoredLineFmt, worker, line)
+			if !written[want] {
+				t.Fatalf(vectoredMissingFmt, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+const (
+	// DefaultWALSize is the size of the write-ahead log used when
+	// WithWriteAheadLog is given a non-positive size.
+	DefaultWALSize = 1 << 20
+	// walHeaderSize holds the little-endian count of used record bytes.
+	walHeaderSize = 8
+	walSuffix     = ".wal"
+	walFilePerm   = 0o600
+	walRecordEnd  = '\n'
+
+	errWALUnsupportedMsg = "write-ahead log is not supported on this platform"
+	errWALTooSmallMsg    = "write-ahead log size too small"
+	errFmtOpenWAL        = "open write-ahead log: %w"
+	errFmtReplayWAL      = "replay write-ahead log: %w"
+	errFmtCheckpointWAL  = "checkpoint write-ahead log: %w"
+	errFmtCloseWAL       = "close write-ahead log: %w"
+)
+
+// Predefined errors for the write-ahead log.
+var (
+	ErrWALUnsupported = errors.New(errWALUnsupportedMsg)
+	ErrWALTooSmall    = errors.New(errWALTooSmallMsg)
+)
+
+// WithWriteAheadLog appends every file entry to a small memory-mapped
+// write-ahead log next to the log file (<filename>.wal) before writing it to
+// the file itself. Mapped pages belong to the kernel, so entries survive a
+// process crash the moment they are copied, and the kernel writes them back
+// independently of the main file. When the WAL fills up the log file is
+// fsynced and the WAL is reset, so durability costs one fsync per size bytes
+// rather than one per entry. Entries that never reached the file are replayed
+// by New on the next startup. The option only affects loggers created by New.
+func WithWriteAheadLog(size int) Option {
+	return func(config *options) {
+		if size <= 0 {
+			size = DefaultWALSize
+		}
+
+		config.walSize = size
+	}
+}
+
+// writeAheadLog is a fixed-size memory-mapped record buffer.
+type writeAheadLog struct {
+	file *os.File
+	data []byte
+	path string
+}
+
+// openWriteAheadLog maps the WAL next to logPath and replays any records that
+// did not reach logFile before the previous process stopped.
+func openWriteAheadLog(logPath string, size int, logFile *os.File) (*writeAheadLog, error) {
+	path := logPath + walSuffix
+	if size <= walHeaderSize {
+		return nil, ErrWALTooSmall
+	}
+
+	// #nosec G304 -- path is derived from the validated log path.
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, walFilePerm)
+	if err != nil {
+		return nil, fmt.Errorf(errFmtOpenWAL, err)
+	}
+
+	wal, err := mapWriteAheadLog(file, path, size)
+	if err != nil {
+		_ = file.Close()
+
+		return nil, err
+	}
+
+	err = wal.replay(logPath, logFile)
+	if err != nil {
+		_ = wal.close()
+
+		return nil, err
+	}
+
+	return wal, nil
+}
+
+func mapWriteAheadLog(file *os.File, path string, size int) (*writeAheadLog, error) {
+	err := file.Truncate(int64(size))
+	if err != nil {
+		return nil, fmt.Errorf(errFmtOpenWAL, err)
+	}
+
+	data, err := mmapFile(file, size)
+	if err != nil {
+		return nil, fmt.Errorf(errFmtOpenWAL, err)
+	}
+
+	return &writeAheadLog{file: file, data: data, path: path}, nil
+}
+
+// pending returns the records written since the last checkpoint.
+func (w *writeAheadLog) pending() []byte {
+	used := binary.LittleEndian.Uint64(w.data[:walHeaderSize])
+	if used > uint64(len(w.data)-walHeaderSize) {
+		return nil
+	}
+
+	return w.data[walHeaderSize : walHeaderSize+int(used)]
+}
+
+// replay appends pending records missing from the end of logFile and resets
+// the WAL.
+func (w *writeAheadLog) replay(logPath string, logFile *os.File) error {
+	pending := w.pending()
+	if len(pending) == 0 {
+		return nil
+	}
+
+	tail, err := readFileTail(logPath, len(pending))
+	if err != nil {
+		return fmt.Errorf(errFmtReplayWAL, err)
+	}
+
+	_, err = logFile.Write(unreplayedRecords(tail, pending))
+	if err != nil {
+		return fmt.Errorf(errFmtReplayWAL, err)
+	}
+
+	return w.checkpoint(logFile)
+}
+
+// append copies record into the WAL. It returns false when the record does
+// not fit in the remaining space.
+func (w *writeAheadLog) append(record string) bool {
+	used := len(w.pending())
+
+	start := walHeaderSize + used
+	if start+len(record)+1 > len(w.data) {
+		return false
+	}
+
+	copy(w.data[start:], record)
+	w.data[start+len(record)] = walRecordEnd
+	binary.LittleEndian.PutUint64(w.data[:walHeaderSize], uint64(used+len(record)+1))
+
+	return true
+}
+
+// checkpoint makes logFile durable and discards the WAL records.
+func (w *writeAheadLog) checkpoint(logFile *os.File) error {
+	err := logFile.Sync()
+	if err != nil {
+		return fmt.Errorf(errFmtCheckpointWAL, err)
+	}
+
+	binary.LittleEndian.PutUint64(w.data[:walHeaderSize], 0)
+
+	return nil
+}
+
+// close unmaps and closes the WAL. The caller checkpoints first on a clean
+// shutdown, in which case the WAL file is removed.
+func (w *writeAheadLog) close() error {
+	clean := len(w.pending()) == 0
+
+	err := errors.Join(munmapFile(w.data), w.file.Close())
+	if err == nil && clean {
+		err = os.Remove(w.path)
+	}
+
+	if err != nil {
+		return fmt.Errorf(errFmtCloseWAL, err)
+	}
+
+	return nil
+}
+
+// writeFileEntry writes msg to the log file through the WAL.
+func (c *loggerCore) writeFileEntry(msg string) error {
+	if c.audit != nil {
+		msg = c.audit.seal(msg)
+	}
+
+	if c.wal != nil && !c.wal.append(msg) {
+		err := c.file.flush()
+		if err == nil {
+			err = c.wal.checkpoint(c.logFile)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		c.wal.append(msg)
+	}
+
+	err := c.file.writeLine(msg)
+	if err != nil {
+		return err
+	}
+
+	c.syncer.wrote(len(msg) + 1) // with the line end
+
+	return nil
+}
+
+// closeWriteAheadLog checkpoints and releases the WAL.
+func (c *loggerCore) closeWriteAheadLog() error {
+	if c.wal == nil {
+		return nil
+	}
+
+	err := errors.Join(c.wal.checkpoint(c.logFile), c.wal.close())
+	c.wal = nil
+
+	return err
+}
+
+// readFileTail returns up to n bytes from the end of the file at path.
+func readFileTail(path string, n int) ([]byte, error) {
+	// #nosec G304 -- path is the validated log path.
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	offset := max(info.Size()-int64(n), 0)
+	tail := make([]byte, info.Size()-offset)
+
+	_, err = file.ReadAt(tail, offset)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	return tail, nil
+}
+
+// unreplayedRecords returns the records of pending that are missing from the
+// end of tail. Records reach the log file in WAL order, so the file ends with
+// some prefix of pending; the longest such prefix ending on a record boundary
+// is skipped.
+func unreplayedRecords(tail, pending []byte) []byte {
+	for end := len(pending); end > 0; {
+		if bytes.HasSuffix(tail, pending[:end]) {
+			return pending[end:]
+		}
+
+		end = bytes.LastIndexByte(pending[:end-1], walRecordEnd) + 1
+	}
+
+	return pending
+}
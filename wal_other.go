@@ -0,0 +1,13 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package logger
+
+import "os"
+
+func mmapFile(*os.File, int) ([]byte, error) {
+	return nil, ErrWALUnsupported
+}
+
+func munmapFile([]byte) error {
+	return nil
+}
@@ -0,0 +1,110 @@
+package logger_test
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/book-expert/logger"
+)
+
+const (
+	walLogFile        = "wal.log"
+	walFileName       = "wal.log.wal"
+	walTestSize       = 4096
+	walHeaderSize     = 8
+	walFirstRecord    = "first entry\n"
+	walSecondRecord   = "second entry\n"
+	walMsg            = "durable"
+	walRemovedErr     = "expected WAL file to be removed after Close, stat err: %v"
+	walWriteErrFmt    = "write fixture: %v"
+	walReplayCountFmt = "expected %q exactly once, got:\n%s"
+)
+
+func writeWALFixture(t *testing.T, dir, logContent, walRecords string) {
+	t.Helper()
+
+	err := os.WriteFile(filepath.Join(dir, walLogFile), []byte(logContent), 0o600)
+	if err != nil {
+		t.Fatalf(walWriteErrFmt, err)
+	}
+
+	data := make([]byte, walTestSize)
+	binary.LittleEndian.PutUint64(data, uint64(len(walRecords)))
+	copy(data[walHeaderSize:], walRecords)
+
+	err = os.WriteFile(filepath.Join(dir, walFileName), data, 0o600)
+	if err != nil {
+		t.Fatalf(walWriteErrFmt, err)
+	}
+}
+
+func openWALLogger(t *testing.T, dir string) string {
+	t.Helper()
+
+	loggerInstance, err := logger.New(dir, walLogFile, logger.WithWriteAheadLog(walTestSize))
+	if err != nil {
+		t.Fatalf(newLoggerError, err)
+	}
+
+	closeTestLogger(t, loggerInstance)
+
+	// #nosec G304
+	content, err := os.ReadFile(filepath.Join(dir, walLogFile))
+	if err != nil {
+		t.Fatalf(readLogFileErr, err)
+	}
+
+	return string(content)
+}
+
+func TestWriteAheadLog_ReplaysMissingEntries(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeWALFixture(t, dir, walFirstRecord, walFirstRecord+walSecondRecord)
+
+	content := openWALLogger(t, dir)
+	for _, record := range []string{walFirstRecord, walSecondRecord} {
+		if strings.Count(content, record) != 1 {
+			t.Errorf(walReplayCountFmt, record, content)
+		}
+	}
+}
+
+func TestWriteAheadLog_SkipsEntriesAlreadyWritten(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	records := walFirstRecord + walSecondRecord
+	writeWALFixture(t, dir, records, records)
+
+	content := openWALLogger(t, dir)
+	if content != records {
+		t.Errorf(layoutOutputErrFmt, records, content)
+	}
+}
+
+func TestWriteAheadLog_RemovedOnClose(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	loggerInstance, err := logger.New(dir, walLogFile, logger.WithWriteAheadLog(walTestSize))
+	if err != nil {
+		t.Fatalf(newLoggerError, err)
+	}
+
+	for range walTestSize / len(walMsg) {
+		loggerInstance.Infof(walMsg)
+	}
+
+	closeTestLogger(t, loggerInstance)
+
+	_, err = os.Stat(filepath.Join(dir, walFileName))
+	if !os.IsNotExist(err) {
+		t.Errorf(walRemovedErr, err)
+	}
+}
@@ -0,0 +1,39 @@
+package logger
+
+// entryWriter delivers submitted entries to the outputs. Two implementations
+// exist: mutexWriter, the default, writes on the calling goroutine under
+// loggerCore.mu, and asyncQueue, selected by WithAsync, hands entries over a
+// channel to a single writer goroutine. The mutex design has the lowest
+// latency with few logging goroutines; the channel design keeps callers off
+// the output lock and scales better when many goroutines log at once. Compare
+// them for a workload with the BenchmarkWriter benchmarks.
+type entryWriter interface {
+	// submit writes logEntry or queues it for writing. Write errors are
+	// recorded for Err.
+	submit(logEntry *Entry, target writeTarget)
+	// submitAndWait writes logEntry and returns its write error, preserving
+	// the order of the caller's earlier entries.
+	submitAndWait(logEntry *Entry, target writeTarget) error
+	// flush waits until every entry submitted before the call is written.
+	flush()
+	// stop writes every queued entry. Entries submitted afterwards are
+	// written synchronously.
+	stop()
+}
+
+// mutexWriter writes every entry on the calling goroutine.
+type mutexWriter struct {
+	core *loggerCore
+}
+
+func (w *mutexWriter) submit(logEntry *Entry, target writeTarget) {
+	_ = w.core.write(logEntry, target) // Recorded for Err.
+}
+
+func (w *mutexWriter) submitAndWait(logEntry *Entry, target writeTarget) error {
+	return w.core.write(logEntry, target)
+}
+
+func (w *mutexWriter) flush() {}
+
+func (w *mutexWriter) stop() {}